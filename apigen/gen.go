@@ -22,199 +22,2025 @@ const (
 	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
 )
 
+// Defines values for ShelfBatchMode.
+const (
+	Atomic     ShelfBatchMode = "atomic"
+	BestEffort ShelfBatchMode = "bestEffort"
+)
+
+// Defines values for ShelfOperationOp.
+const (
+	Create ShelfOperationOp = "create"
+	Delete ShelfOperationOp = "delete"
+	Status ShelfOperationOp = "status"
+	Update ShelfOperationOp = "update"
+)
+
+// APIKey defines model for APIKey.
+type APIKey struct {
+	// CreatedAt APIキーの発行日時
+	CreatedAt string `json:"createdAt,omitempty"`
+
+	// ExpiresAt 有効期限（RFC 3339。省略時は無期限）
+	ExpiresAt string `json:"expiresAt,omitempty"`
+
+	// Id APIキーの識別子
+	Id string `json:"id,omitempty"`
+
+	// Key キー（発行時のレスポンスのみ）
+	Key string `json:"key,omitempty"`
+
+	// LastUsedAt 最後に使用した日時（未使用の場合は省略）
+	LastUsedAt string `json:"lastUsedAt,omitempty"`
+
+	// Name APIキーの名前（用途）
+	Name string `json:"name" validate:"required,lte=100"`
+
+	// Prefix キーの先頭（一覧でキーを見分ける）
+	Prefix string `json:"prefix,omitempty"`
+
+	// Scopes 許可する操作（shelf:read、shelf:write、charts:read、管理者のみadmin）
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+}
+
+// AdminStats defines model for AdminStats.
+type AdminStats struct {
+	// Admins 管理者の数
+	Admins int `json:"admins"`
+
+	// Books 本の冊数
+	Books int `json:"books"`
+
+	// DisabledUsers 無効化したユーザー数
+	DisabledUsers int `json:"disabledUsers"`
+
+	// Spend 通貨ごとの購入額
+	Spend []Spend `json:"spend"`
+
+	// Users ユーザー数
+	Users int `json:"users"`
+}
+
+// AdminUser defines model for AdminUser.
+type AdminUser struct {
+	// AuthUserId ユーザーの識別子
+	AuthUserId string `json:"authUserId"`
+
+	// CreatedAt ユーザーの登録日時
+	CreatedAt string `json:"createdAt"`
+
+	// DisabledAt 無効化した日時（有効な場合は省略）
+	DisabledAt string `json:"disabledAt,omitempty"`
+
+	// Email メールアドレス
+	Email string `json:"email"`
+
+	// EmailVerified メールアドレスを確認済みか
+	EmailVerified bool `json:"emailVerified,omitempty"`
+
+	// Name ユーザー名
+	Name string `json:"name,omitempty"`
+
+	// Role 役割（user、admin）
+	Role string `json:"role"`
+}
+
+// AdminUserDisabled defines model for AdminUserDisabled.
+type AdminUserDisabled struct {
+	Revocation Revocation `json:"revocation"`
+	User       AdminUser  `json:"user"`
+}
+
+// AdminUserList defines model for AdminUserList.
+type AdminUserList struct {
+	// Total 条件に一致する全件数
+	Total int         `json:"total"`
+	Users []AdminUser `json:"users"`
+}
+
+// AdminUserStats defines model for AdminUserStats.
+type AdminUserStats struct {
+	// Books 本の冊数
+	Books int `json:"books"`
+
+	// Bought 購入済み（未読）の冊数
+	Bought int `json:"bought"`
+
+	// Pages ページ数の合計
+	Pages int `json:"pages"`
+
+	// Read 読了の冊数
+	Read int `json:"read"`
+
+	// Reading 読書中の冊数
+	Reading int `json:"reading"`
+
+	// Spend 通貨ごとの購入額
+	Spend []Spend   `json:"spend"`
+	User  AdminUser `json:"user"`
+}
+
+// AuditChange defines model for AuditChange.
+type AuditChange struct {
+	// After 変更後の値（削除時はnull。パスワード、氏名、メールアドレスは[REDACTED]）
+	After interface{} `json:"after"`
+
+	// Before 変更前の値（作成時はnull。パスワード、氏名、メールアドレスは[REDACTED]）
+	Before interface{} `json:"before"`
+}
+
+// AuditLog defines model for AuditLog.
+type AuditLog struct {
+	// Action 操作（create、update、delete、restore、purge）
+	Action string `json:"action"`
+
+	// Actor 操作したユーザー（システムの操作は省略）
+	Actor string `json:"actor,omitempty"`
+
+	// ApiKeyId 個人用APIキーの操作の場合のキーの識別子
+	ApiKeyId string `json:"apiKeyId,omitempty"`
+
+	// AuthUserId 変更したデータの持ち主
+	AuthUserId string `json:"authUserId"`
+
+	// CreatedAt 変更した日時
+	CreatedAt string `json:"createdAt"`
+
+	// Diff 項目（列名）ごとの変更前後の値。変更のない項目は含まない
+	Diff map[string]AuditChange `json:"diff"`
+
+	// Id ログの識別子（新しいほど大きい）
+	Id string `json:"id"`
+
+	// Ip リクエストのIPアドレス
+	Ip string `json:"ip,omitempty"`
+
+	// RequestId リクエストID（X-Request-Id）
+	RequestId string `json:"requestId,omitempty"`
+
+	// TargetId 対象の識別子
+	TargetId string `json:"targetId"`
+
+	// TargetType 対象の種類（book、user）
+	TargetType string `json:"targetType"`
+
+	// Via 操作の経路（app、api_key、system）
+	Via string `json:"via"`
+}
+
+// AuditLogList defines model for AuditLogList.
+type AuditLogList struct {
+	Entries []AuditLog `json:"entries"`
+
+	// NextBeforeId 次のページのbeforeId（最後のページは省略）
+	NextBeforeId string `json:"nextBeforeId,omitempty"`
+}
+
+// Backlog defines model for Backlog.
+type Backlog struct {
+	// Currency 購入額の通貨（ユーザーの基準通貨）
+	Currency string `json:"currency,omitempty"`
+
+	// DaysToClear 現在の読了ペースで積読を解消するまでの日数（読了ペースが0の場合は省略）
+	DaysToClear string `json:"daysToClear,omitempty"`
+
+	// Months 月ごとの積読の推移
+	Months []BacklogMonth `json:"months"`
+
+	// OldestUnread 購入日の古い未読の本（最大5冊）
+	OldestUnread []Book `json:"oldestUnread"`
+
+	// ReadPerDay 直近90日の1日あたりの読了冊数
+	ReadPerDay string `json:"readPerDay,omitempty"`
+
+	// UnreadCosts 未読の本の購入額
+	UnreadCosts string `json:"unreadCosts,omitempty"`
+
+	// UnreadVolumes 未読（bought、reading）の冊数
+	UnreadVolumes string `json:"unreadVolumes,omitempty"`
+}
+
+// BacklogMonth defines model for BacklogMonth.
+type BacklogMonth struct {
+	// Backlog その月末時点の積読冊数（購入冊数の累計 - 読了冊数の累計）
+	Backlog string `json:"backlog,omitempty"`
+
+	// Bought その月の購入冊数
+	Bought string `json:"bought,omitempty"`
+
+	// Month 各データの月
+	Month string `json:"month,omitempty"`
+
+	// Read その月の読了冊数
+	Read string `json:"read,omitempty"`
+
+	// Year 各データの年
+	Year string `json:"year,omitempty"`
+}
+
 // Book defines model for Book.
 type Book struct {
 	// AuthUserId ユーザーの識別子
-	AuthUserId *string `json:"authUserId,omitempty"`
+	AuthUserId string `json:"authUserId,omitempty"`
 
 	// Author 本の著者
-	Author *string `json:"author,omitempty"`
+	Author string `json:"author,omitempty"`
 
 	// BookStatus 本の状態
-	BookStatus *string `json:"bookStatus,omitempty"`
+	BookStatus string `json:"bookStatus,omitempty" validate:"required"`
 
 	// CreatedAt 本の作成日時
-	CreatedAt *string `json:"createdAt,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+
+	// Currency 本の価格の通貨（ISO 4217）
+	Currency string `json:"currency,omitempty"`
+
+	// DeletedAt 本の削除日時（ゴミ箱内のみ）
+	DeletedAt string `json:"deletedAt,omitempty"`
 
 	// Id 本の識別子
-	Id *string `json:"id,omitempty"`
+	Id string `json:"id,omitempty"`
 
 	// ImageURL 本の画像
-	ImageURL *string `json:"imageURL,omitempty"`
+	ImageURL string `json:"imageURL,omitempty"`
 
 	// Isbn10 本のisbn10
-	Isbn10 *string `json:"isbn10,omitempty"`
+	Isbn10 string `json:"isbn10,omitempty"`
 
 	// Page 本のページ数
-	Page *string `json:"page,omitempty"`
+	Page string `json:"page,omitempty"`
 
 	// Price 本の価格
-	Price *string `json:"price,omitempty"`
+	Price string `json:"price,omitempty"`
+
+	// Title 本の書名
+	Title string `json:"title,omitempty"`
+
+	// UpdatedAt 本の更新日時
+	UpdatedAt string `json:"updatedAt,omitempty"`
+
+	// Version 本のバージョン（更新ごとに1増える。If-Matchに指定する）
+	Version string `json:"version,omitempty"`
+}
+
+// BookHistory defines model for BookHistory.
+type BookHistory struct {
+	Revisions []BookRevision `json:"revisions"`
+}
+
+// BookPatch 本の部分更新（JSON Merge Patch）。省略した項目は変更しない
+type BookPatch struct {
+	// Author 本の著者
+	Author string `json:"author"`
+
+	// BookStatus 本の状態（nullは指定できない）
+	BookStatus string `json:"bookStatus"`
+
+	// CreatedAt 本の購入日時（RFC3339。nullは指定できない）
+	CreatedAt string `json:"createdAt"`
+
+	// Currency 本の価格の通貨（ISO 4217）。変更する場合はpriceも指定する
+	Currency string `json:"currency"`
+
+	// ImageURL 本の画像
+	ImageURL string `json:"imageURL"`
+
+	// Isbn10 本のisbn10
+	Isbn10 string `json:"isbn10"`
+
+	// Page 本のページ数
+	Page string `json:"page"`
+
+	// Price 本の価格（通貨はcurrency、省略時は登録済みの通貨）
+	Price string `json:"price"`
+
+	// Title 本の書名
+	Title string `json:"title"`
+}
+
+// BookRevision defines model for BookRevision.
+type BookRevision struct {
+	Book Book `json:"book"`
+
+	// Changed この版から次の更新で値の変わった項目
+	Changed []string `json:"changed"`
+
+	// CreatedAt この版が更新された日時
+	CreatedAt string `json:"createdAt"`
+
+	// Id 版の識別子（戻す際に指定する）
+	Id string `json:"id"`
+
+	// Version この版の本のバージョン
+	Version string `json:"version"`
+}
+
+// Chart defines model for Chart.
+type Chart struct {
+	// Data 各データ内容
+	Data string `json:"data,omitempty"`
+
+	// Id 図表の識別子
+	Id string `json:"id,omitempty"`
+
+	// Label チャートを分類する識別子
+	Label string `json:"label,omitempty"`
+
+	// Month 各データの月
+	Month string `json:"month,omitempty"`
+
+	// Year 各データの年
+	Year string `json:"year,omitempty"`
+}
+
+// EmailVerification defines model for EmailVerification.
+type EmailVerification struct {
+	// Token メールに記載したトークン
+	Token string `json:"token" validate:"required"`
+}
+
+// Event Server-Sent Eventsのdataの内容
+type Event struct {
+	// AuthUserId ユーザーの識別子
+	AuthUserId string `json:"authUserId,omitempty"`
+
+	// BookId 本の識別子（本のイベントのみ）
+	BookId string `json:"bookId,omitempty"`
+
+	// CreatedAt イベントの発生日時
+	CreatedAt string `json:"createdAt,omitempty"`
+
+	// GoalId 目標の識別子（goal.reachedのみ）
+	GoalId string `json:"goalId,omitempty"`
+
+	// Id イベントの識別子（Last-Event-IDに指定する）
+	Id string `json:"id,omitempty"`
+
+	// Type イベントの種類（book.created, book.updated, book.deleted, goal.reached）
+	Type string `json:"type,omitempty"`
+
+	// Version 変更後の本のバージョン（book.created, book.updatedのみ）
+	Version string `json:"version,omitempty"`
+}
+
+// ExchangeRate defines model for ExchangeRate.
+type ExchangeRate struct {
+	// Currency 通貨コード（ISO 4217）
+	Currency string `json:"currency,omitempty" validate:"required"`
+
+	// Rate 1単位あたりの円換算額
+	Rate string `json:"rate,omitempty" validate:"required"`
+
+	// UpdatedAt レートの更新日時
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	// Code 検証の種類（例.required、email、oneof）
+	Code string `json:"code"`
+
+	// Field 項目名（jsonのキー、クエリ名）
+	Field string `json:"field"`
+
+	// Message エラーメッセージ（Accept-Languageの言語）
+	Message string `json:"message"`
+}
+
+// Goal defines model for Goal.
+type Goal struct {
+	// Currency 購入額の上限の通貨（spendのみ。省略時はユーザーの基準通貨）
+	Currency string `json:"currency,omitempty"`
+
+	// Id 目標の識別子
+	Id string `json:"id,omitempty"`
+
+	// Kind 目標の種類（books, pages, spend）
+	Kind string `json:"kind,omitempty" validate:"required,oneof=books pages spend"`
+
+	// Period 目標の期間（yearly, monthly）
+	Period string `json:"period,omitempty" validate:"required,oneof=yearly monthly"`
+
+	// Target 目標値（spendの場合は購入額の上限）
+	Target string `json:"target,omitempty" validate:"required"`
+}
+
+// GoalProgress defines model for GoalProgress.
+type GoalProgress struct {
+	// Currency 購入額の上限の通貨（spendのみ。省略時はユーザーの基準通貨）
+	Currency string `json:"currency,omitempty"`
+
+	// Current 期間内の実績
+	Current string `json:"current,omitempty"`
+
+	// DaysLeft 期間終了までの残り日数
+	DaysLeft string `json:"daysLeft,omitempty"`
+
+	// Expected 期間の経過割合から見た現時点の目安
+	Expected string `json:"expected,omitempty"`
+
+	// Id 目標の識別子
+	Id string `json:"id,omitempty"`
+
+	// Kind 目標の種類（books, pages, spend）
+	Kind string `json:"kind,omitempty" validate:"required,oneof=books pages spend"`
+
+	// PacePerDay 期間内に達成するために必要な1日あたりのペース
+	PacePerDay string `json:"pacePerDay,omitempty"`
+
+	// Period 目標の期間（yearly, monthly）
+	Period string `json:"period,omitempty" validate:"required,oneof=yearly monthly"`
+
+	// PeriodEnd 期間の終了日時
+	PeriodEnd string `json:"periodEnd,omitempty"`
+
+	// PeriodStart 期間の開始日時
+	PeriodStart string `json:"periodStart,omitempty"`
+
+	// Remaining 目標までの残り（spendの場合は残りの予算）
+	Remaining string `json:"remaining,omitempty"`
+
+	// Status 進捗の状況（achieved, on_track, behind, exceeded）
+	Status string `json:"status,omitempty"`
+
+	// Target 目標値（spendの場合は購入額の上限）
+	Target string `json:"target,omitempty" validate:"required"`
+}
+
+// Login defines model for Login.
+type Login struct {
+	// Email 登録したメールアドレス
+	Email string `json:"email" validate:"required,email"`
+
+	// Password パスワード
+	Password string `json:"password" validate:"required,lte=72"`
+}
+
+// LoginResult defines model for LoginResult.
+type LoginResult struct {
+	// AuthUserId フロントユーザーの識別子
+	AuthUserId string `json:"authUserId"`
+}
+
+// MailSetting defines model for MailSetting.
+type MailSetting struct {
+	// Digest 月次のまとめを受け取るか
+	Digest bool `json:"digest"`
+
+	// Language メールの言語（ja、en）
+	Language string `json:"language" validate:"required,oneof=ja en"`
+}
+
+// PasswordReset defines model for PasswordReset.
+type PasswordReset struct {
+	// Password 新しいパスワード（8～20文字）
+	Password string `json:"password" validate:"required,gte=8,lte=20"`
+
+	// Token メールに記載したトークン
+	Token string `json:"token" validate:"required"`
+}
+
+// PasswordResetRequest defines model for PasswordResetRequest.
+type PasswordResetRequest struct {
+	// Email 登録したメールアドレス
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Problem RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type Problem struct {
+	// Code 機械可読なエラーコード（例.user_not_found、validation_failed）
+	Code string `json:"code"`
+
+	// Detail エラーの詳細（Accept-Languageの言語）
+	Detail string `json:"detail,omitempty"`
+
+	// Errors 項目ごとの入力エラー
+	Errors []FieldError `json:"errors,omitempty"`
+
+	// Instance リクエストのパス
+	Instance string `json:"instance,omitempty"`
+
+	// Message detailと同じ（v1との互換のため）
+	// Deprecated:
+	Message string `json:"message,omitempty"`
+
+	// Status HTTPステータス
+	Status int `json:"status"`
+
+	// Title ステータスの概要（Accept-Languageの言語）
+	Title string `json:"title"`
+
+	// Type エラーの種類のURI（例.urn:bhapi:problem:user_not_found）
+	Type string `json:"type"`
+}
+
+// Record defines model for Record.
+type Record struct {
+	// Costs 購入額の総計
+	Costs string `json:"costs,omitempty"`
+
+	// CostsRead 購入額のうち読了分
+	CostsRead string `json:"costsRead,omitempty"`
+
+	// Currency 購入額の通貨（ユーザーの基準通貨）
+	Currency string `json:"currency,omitempty"`
+
+	// Id 記録の識別子
+	Id string `json:"id,omitempty"`
+
+	// Pages 購入ページ数の総計
+	Pages string `json:"pages,omitempty"`
+
+	// PagesRead 購入ページ数のうち読了分
+	PagesRead string `json:"pagesRead,omitempty"`
+
+	// Volumes 購入冊数の総計
+	Volumes string `json:"volumes,omitempty"`
+
+	// VolumesRead 購入冊数のうち読了分
+	VolumesRead string `json:"volumesRead,omitempty"`
+}
+
+// Revocation defines model for Revocation.
+type Revocation struct {
+	// ApiKeys 削除した個人用APIキーの件数
+	ApiKeys int `json:"apiKeys"`
+
+	// Tokens 無効にした未使用のトークンの件数
+	Tokens int `json:"tokens"`
+}
+
+// ShelfBatch defines model for ShelfBatch.
+type ShelfBatch struct {
+	// Mode atomic（既定）はすべて成功した場合のみ反映、bestEffortは成功した操作のみ反映
+	Mode ShelfBatchMode `json:"mode,omitempty" validate:"omitempty,oneof=atomic bestEffort"`
+
+	// Operations 実行する操作（最大100件、指定した順に実行）
+	Operations []ShelfOperation `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+// ShelfBatchMode atomic（既定）はすべて成功した場合のみ反映、bestEffortは成功した操作のみ反映
+type ShelfBatchMode string
+
+// ShelfBatchResult defines model for ShelfBatchResult.
+type ShelfBatchResult struct {
+	// Committed 変更を反映したか（atomicでいずれかの操作が失敗した場合はfalse）
+	Committed bool `json:"committed"`
+
+	// Results 操作ごとの結果（operationsと同じ順）
+	Results []ShelfOperationResult `json:"results"`
+}
+
+// ShelfOperation defines model for ShelfOperation.
+type ShelfOperation struct {
+	Book *Book `json:"book,omitempty"`
+
+	// BookId 本の識別子（status、deleteのみ）
+	BookId string `json:"bookId,omitempty"`
+
+	// BookStatus 変更後の本の状態（statusのみ）
+	BookStatus string `json:"bookStatus,omitempty"`
+
+	// Op 操作の種類
+	Op ShelfOperationOp `json:"op" validate:"required,oneof=create update status delete"`
+
+	// Version 本のバージョン（statusのみ。指定した場合は一致する場合のみ変更する。updateはbook.versionに指定）
+	Version string `json:"version,omitempty"`
+}
+
+// ShelfOperationOp 操作の種類
+type ShelfOperationOp string
+
+// ShelfOperationResult defines model for ShelfOperationResult.
+type ShelfOperationResult struct {
+	// BookId 本の識別子（createは採番されたid）
+	BookId string `json:"bookId,omitempty"`
+
+	// Code 失敗した場合のエラーコード（Problemのcodeと同じ）
+	Code string `json:"code,omitempty"`
+
+	// Detail 失敗した場合のエラーの詳細（Accept-Languageの言語）
+	Detail string `json:"detail,omitempty"`
+
+	// Op 操作の種類
+	Op string `json:"op"`
+
+	// Status 操作ごとのHTTPステータス（create:201、update、status:200、delete:204、取り消し:424）
+	Status int `json:"status"`
+
+	// Version 操作後の本のバージョン（create、update、statusのみ）
+	Version string `json:"version,omitempty"`
+}
+
+// ShelfWarning defines model for ShelfWarning.
+type ShelfWarning struct {
+	// Goals 上限を超過した目標の進捗
+	Goals []GoalProgress `json:"goals,omitempty"`
+
+	// SpendCapExceeded 購入額の上限を超過したか
+	SpendCapExceeded bool `json:"spendCapExceeded"`
+}
+
+// Spend defines model for Spend.
+type Spend struct {
+	// Amount 購入額の合計
+	Amount int `json:"amount"`
+
+	// Currency 通貨
+	Currency string `json:"currency"`
+}
+
+// Trash defines model for Trash.
+type Trash struct {
+	// Books 削除済みの本
+	Books []Book `json:"books"`
+
+	// RetentionDays 削除から完全に削除されるまでの日数
+	RetentionDays string `json:"retentionDays,omitempty"`
+	User          *User  `json:"user,omitempty"`
+}
+
+// User defines model for User.
+type User struct {
+	// AuthUserId フロントユーザーの識別子
+	AuthUserId string `json:"authUserId,omitempty" validate:"required"`
+
+	// CreatedAt ユーザーの作成日時
+	CreatedAt string `json:"createdAt,omitempty"`
+
+	// DeletedAt ユーザーの削除日時（ゴミ箱内のみ）
+	DeletedAt string `json:"deletedAt,omitempty"`
+
+	// Email ユーザーemail
+	Email string `json:"email,omitempty" validate:"required,email"`
+
+	// HomeCurrency 記録や図表の金額を表示する通貨（ISO 4217）
+	HomeCurrency string `json:"homeCurrency,omitempty"`
+
+	// Id バックユーザーの識別子
+	Id string `json:"id,omitempty"`
+
+	// Name ユーザー名
+	Name string `json:"name,omitempty"`
+
+	// Password パスワード（あれば）。更新時は省略すると変更しない。ハッシュ化済みの値は指定できない
+	Password string `json:"password,omitempty"`
+
+	// UpdatedAt ユーザーの更新日時
+	UpdatedAt string `json:"updatedAt,omitempty"`
+
+	// Version ユーザーのバージョン（更新ごとに1増える）
+	Version string `json:"version,omitempty"`
+}
+
+// UserPatch ユーザーの部分更新（JSON Merge Patch）。省略した項目は変更しない
+type UserPatch struct {
+	// Email ユーザーemail（nullは指定できない）
+	Email string `json:"email"`
+
+	// HomeCurrency 記録や図表の金額を表示する通貨（ISO 4217）
+	HomeCurrency string `json:"homeCurrency"`
+
+	// Name ユーザー名
+	Name string `json:"name"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	// CreatedAt Webhookの登録日時
+	CreatedAt string `json:"createdAt,omitempty"`
+
+	// Events 購読するイベントの種類（省略時はすべて）
+	Events []string `json:"events,omitempty"`
+
+	// Id Webhookの識別子
+	Id string `json:"id,omitempty"`
+
+	// Secret 署名の鍵（登録時のレスポンスのみ）
+	Secret string `json:"secret,omitempty"`
+
+	// Url 配信先のURL（http、https。ループバック、プライベート、リンクローカルなど内部のアドレスは不可）
+	Url string `json:"url" validate:"required,url"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	// Attempts 試行回数
+	Attempts int `json:"attempts,omitempty"`
+
+	// CreatedAt イベントの発生日時
+	CreatedAt string `json:"createdAt,omitempty"`
+
+	// EventId イベントの識別子（X-Bhapi-Event-Id）
+	EventId string `json:"eventId,omitempty"`
+
+	// Id 配信の識別子（再試行に指定する）
+	Id string `json:"id,omitempty"`
+
+	// LastError 最後の試行のエラー（決まった文言、またはステータスコードの標準の理由句）
+	LastError string `json:"lastError,omitempty"`
+
+	// LastStatus 最後の試行のHTTPステータス（接続できない場合は0）
+	LastStatus int `json:"lastStatus,omitempty"`
+
+	// Payload 配信したボディ
+	Payload map[string]interface{} `json:"payload,omitempty"`
+
+	// Type イベントの種類
+	Type string `json:"type,omitempty"`
+
+	// UpdatedAt 最後の試行の日時
+	UpdatedAt string `json:"updatedAt,omitempty"`
+
+	// WebhookId Webhookの識別子
+	WebhookId string `json:"webhookId,omitempty"`
+}
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
+// GetActivityAuthUserIdParams defines parameters for GetActivityAuthUserId.
+type GetActivityAuthUserIdParams struct {
+	// BeforeId このidより古いログのみ（前のページのnextBeforeId）
+	BeforeId *string `form:"beforeId,omitempty" json:"beforeId,omitempty"`
+
+	// Limit 件数（既定50、上限200）
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetAdminAuditParams defines parameters for GetAdminAudit.
+type GetAdminAuditParams struct {
+	// AuthUserId 変更したデータの持ち主
+	AuthUserId *string `form:"authUserId,omitempty" json:"authUserId,omitempty"`
+
+	// Actor 操作したユーザー
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// TargetType 対象の種類（book、user）
+	TargetType *string `form:"targetType,omitempty" json:"targetType,omitempty"`
+
+	// TargetId 対象の識別子（本のid、ユーザーのauthUserId）
+	TargetId *string `form:"targetId,omitempty" json:"targetId,omitempty"`
+
+	// Action 操作（create、update、delete、restore、purge）
+	Action *string `form:"action,omitempty" json:"action,omitempty"`
+
+	// BeforeId このidより古いログのみ（前のページのnextBeforeId）
+	BeforeId *string `form:"beforeId,omitempty" json:"beforeId,omitempty"`
+
+	// Limit 件数（既定50、上限200）
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PutAdminRatesJSONBody defines parameters for PutAdminRates.
+type PutAdminRatesJSONBody = []ExchangeRate
+
+// GetAdminUsersParams defines parameters for GetAdminUsers.
+type GetAdminUsersParams struct {
+	// Q メールアドレス、名前、authUserIdの部分一致（省略時はすべて）
+	Q *string `form:"q,omitempty" json:"q,omitempty"`
+
+	// Limit 件数（既定50、上限200）
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset 読み飛ばす件数
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// GetChartsAuthUserIdParams defines parameters for GetChartsAuthUserId.
+type GetChartsAuthUserIdParams struct {
+	// IfNoneMatch 取得済みのETag。一致する場合は304
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// GetEventsAuthUserIdParams defines parameters for GetEventsAuthUserId.
+type GetEventsAuthUserIdParams struct {
+	// LastEventID 最後に受け取ったイベントのid
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// DeleteGoalsAuthUserIdParams defines parameters for DeleteGoalsAuthUserId.
+type DeleteGoalsAuthUserIdParams struct {
+	// GoalId 目標の識別子
+	GoalId string `form:"goalId" json:"goalId"`
+}
+
+// PostMailUnsubscribeParams defines parameters for PostMailUnsubscribe.
+type PostMailUnsubscribeParams struct {
+	// Token メールに記載した配信停止のトークン
+	Token string `form:"token" json:"token"`
+}
+
+// GetRecordsAuthUserIdParams defines parameters for GetRecordsAuthUserId.
+type GetRecordsAuthUserIdParams struct {
+	// IfNoneMatch 取得済みのETag。一致する場合は304
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// GetSearchParams defines parameters for GetSearch.
+type GetSearchParams struct {
+	// Q 検索文字列
+	Q string `form:"q" json:"q"`
+}
+
+// DeleteShelfAuthUserIdParams defines parameters for DeleteShelfAuthUserId.
+type DeleteShelfAuthUserIdParams struct {
+	// BookId 書籍の識別子
+	BookId []string `form:"bookId" json:"bookId"`
+}
+
+// GetShelfAuthUserIdParams defines parameters for GetShelfAuthUserId.
+type GetShelfAuthUserIdParams struct {
+	// IfNoneMatch 取得済みのETag。一致する場合は304
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// PutShelfAuthUserIdParams defines parameters for PutShelfAuthUserId.
+type PutShelfAuthUserIdParams struct {
+	// IfMatch 更新の前提とするバージョン（ETag）。一致しない場合は412。任意（オプトイン）で、省略した場合、"*"の場合はバージョンを確認せずに上書きする（後の更新が優先）。他の更新を上書きしたくないクライアントは必ず指定する
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchShelfAuthUserIdBookIdParams defines parameters for PatchShelfAuthUserIdBookId.
+type PatchShelfAuthUserIdBookIdParams struct {
+	// IfMatch 更新の前提とするバージョン（ETag）。一致しない場合は412。任意（オプトイン）で、省略した場合、"*"の場合はバージョンを確認せずに上書きする（後の更新が優先）。他の更新を上書きしたくないクライアントは必ず指定する
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PostShelfAuthUserIdBookIdHistoryRevisionIdRevertParams defines parameters for PostShelfAuthUserIdBookIdHistoryRevisionIdRevert.
+type PostShelfAuthUserIdBookIdHistoryRevisionIdRevertParams struct {
+	// IfMatch 更新の前提とするバージョン（ETag）。一致しない場合は412。任意（オプトイン）で、省略した場合、"*"の場合はバージョンを確認せずに上書きする（後の更新が優先）。他の更新を上書きしたくないクライアントは必ず指定する
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PostTrashAuthUserIdBooksRestoreParams defines parameters for PostTrashAuthUserIdBooksRestore.
+type PostTrashAuthUserIdBooksRestoreParams struct {
+	// BookId 書籍の識別子
+	BookId []string `form:"bookId" json:"bookId"`
+}
+
+// PutUsersParams defines parameters for PutUsers.
+type PutUsersParams struct {
+	// IfMatch 更新の前提とするバージョン（ETag）。一致しない場合は412。任意（オプトイン）で、省略した場合、"*"の場合はバージョンを確認せずに上書きする（後の更新が優先）。他の更新を上書きしたくないクライアントは必ず指定する
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// DeleteUsersAuthUserIdParams defines parameters for DeleteUsersAuthUserId.
+type DeleteUsersAuthUserIdParams struct {
+	// Immediate trueの場合はゴミ箱を経由せずに完全に削除
+	Immediate *bool `form:"immediate,omitempty" json:"immediate,omitempty"`
+}
+
+// PatchUsersAuthUserIdParams defines parameters for PatchUsersAuthUserId.
+type PatchUsersAuthUserIdParams struct {
+	// IfMatch 更新の前提とするバージョン（ETag）。一致しない場合は412。任意（オプトイン）で、省略した場合、"*"の場合はバージョンを確認せずに上書きする（後の更新が優先）。他の更新を上書きしたくないクライアントは必ず指定する
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PutAdminRatesJSONRequestBody defines body for PutAdminRates for application/json ContentType.
+type PutAdminRatesJSONRequestBody = PutAdminRatesJSONBody
+
+// PostApikeysAuthUserIdJSONRequestBody defines body for PostApikeysAuthUserId for application/json ContentType.
+type PostApikeysAuthUserIdJSONRequestBody = APIKey
+
+// PostAuthEmailVerifyJSONRequestBody defines body for PostAuthEmailVerify for application/json ContentType.
+type PostAuthEmailVerifyJSONRequestBody = EmailVerification
+
+// PostAuthLoginJSONRequestBody defines body for PostAuthLogin for application/json ContentType.
+type PostAuthLoginJSONRequestBody = Login
+
+// PostAuthPasswordResetJSONRequestBody defines body for PostAuthPasswordReset for application/json ContentType.
+type PostAuthPasswordResetJSONRequestBody = PasswordResetRequest
+
+// PostAuthPasswordResetConfirmJSONRequestBody defines body for PostAuthPasswordResetConfirm for application/json ContentType.
+type PostAuthPasswordResetConfirmJSONRequestBody = PasswordReset
+
+// PostAuthRegisterJSONRequestBody defines body for PostAuthRegister for application/json ContentType.
+type PostAuthRegisterJSONRequestBody = User
+
+// PutGoalsAuthUserIdJSONRequestBody defines body for PutGoalsAuthUserId for application/json ContentType.
+type PutGoalsAuthUserIdJSONRequestBody = Goal
+
+// PutMailAuthUserIdJSONRequestBody defines body for PutMailAuthUserId for application/json ContentType.
+type PutMailAuthUserIdJSONRequestBody = MailSetting
+
+// PostShelfAuthUserIdJSONRequestBody defines body for PostShelfAuthUserId for application/json ContentType.
+type PostShelfAuthUserIdJSONRequestBody = Book
+
+// PutShelfAuthUserIdJSONRequestBody defines body for PutShelfAuthUserId for application/json ContentType.
+type PutShelfAuthUserIdJSONRequestBody = Book
+
+// PostShelfAuthUserIdBatchJSONRequestBody defines body for PostShelfAuthUserIdBatch for application/json ContentType.
+type PostShelfAuthUserIdBatchJSONRequestBody = ShelfBatch
+
+// PatchShelfAuthUserIdBookIdApplicationMergePatchPlusJSONRequestBody defines body for PatchShelfAuthUserIdBookId for application/merge-patch+json ContentType.
+type PatchShelfAuthUserIdBookIdApplicationMergePatchPlusJSONRequestBody = BookPatch
+
+// PutUsersJSONRequestBody defines body for PutUsers for application/json ContentType.
+type PutUsersJSONRequestBody = User
+
+// PatchUsersAuthUserIdApplicationMergePatchPlusJSONRequestBody defines body for PatchUsersAuthUserId for application/merge-patch+json ContentType.
+type PatchUsersAuthUserIdApplicationMergePatchPlusJSONRequestBody = UserPatch
+
+// PostWebhooksAuthUserIdJSONRequestBody defines body for PostWebhooksAuthUserId for application/json ContentType.
+type PostWebhooksAuthUserIdJSONRequestBody = Webhook
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// ユーザーのデータ（本棚、ユーザー情報）への変更の履歴を新しい順に返す
+	// (GET /activity/{authUserId})
+	GetActivityAuthUserId(ctx echo.Context, authUserId string, params GetActivityAuthUserIdParams) error
+	// 監査ログを検索する（新しい順）
+	// (GET /admin/audit)
+	GetAdminAudit(ctx echo.Context, params GetAdminAuditParams) error
+	// 為替レートを登録、更新（全ユーザーの換算に使う）
+	// (PUT /admin/rates)
+	PutAdminRates(ctx echo.Context) error
+	// システム全体の集計を返す（削除済みのユーザー、本を除く）
+	// (GET /admin/stats)
+	GetAdminStats(ctx echo.Context) error
+	// ユーザーを検索する（削除済みを除く、登録順）
+	// (GET /admin/users)
+	GetAdminUsers(ctx echo.Context, params GetAdminUsersParams) error
+	// ユーザーを無効化し、強制的にログアウトさせる
+	// (POST /admin/users/{authUserId}/disable)
+	PostAdminUsersAuthUserIdDisable(ctx echo.Context, authUserId string) error
+	// 無効化したユーザーを有効に戻す
+	// (POST /admin/users/{authUserId}/enable)
+	PostAdminUsersAuthUserIdEnable(ctx echo.Context, authUserId string) error
+	// ユーザーを強制的にログアウトさせる（個人用APIキー、未使用のトークンを無効にする）
+	// (POST /admin/users/{authUserId}/revoke)
+	PostAdminUsersAuthUserIdRevoke(ctx echo.Context, authUserId string) error
+	// ユーザーと本棚の集計を返す（購入額は通貨ごと、換算しない）
+	// (GET /admin/users/{authUserId}/stats)
+	GetAdminUsersAuthUserIdStats(ctx echo.Context, authUserId string) error
+	// ユーザーごとに発行した個人用APIキーを返す（キー自体は含まない）
+	// (GET /apikeys/{authUserId})
+	GetApikeysAuthUserId(ctx echo.Context, authUserId string) error
+	// 個人用APIキーを発行し、キーを返す
+	// (POST /apikeys/{authUserId})
+	PostApikeysAuthUserId(ctx echo.Context, authUserId string) error
+	// 個人用APIキーを削除（以降、そのキーでは認証できない）
+	// (DELETE /apikeys/{authUserId}/{keyId})
+	DeleteApikeysAuthUserIdKeyId(ctx echo.Context, authUserId string, keyId string) error
+	// メールのトークンでメールアドレスを確認済みにする
+	// (POST /auth/email/verify)
+	PostAuthEmailVerify(ctx echo.Context) error
+	// メールアドレスとパスワードでログインする
+	// (POST /auth/login)
+	PostAuthLogin(ctx echo.Context) error
+	// パスワード再設定のメールを送る
+	// (POST /auth/password/reset)
+	PostAuthPasswordReset(ctx echo.Context) error
+	// メールのトークンでパスワードを再設定する
+	// (POST /auth/password/reset/confirm)
+	PostAuthPasswordResetConfirm(ctx echo.Context) error
+	// user情報の登録
+	// (POST /auth/register)
+	PostAuthRegister(ctx echo.Context) error
+	// ユーザーごとに積読の推移、未読の購入額、古い未読の本、解消までの見込み日数を返す
+	// (GET /backlog/{authUserId})
+	GetBacklogAuthUserId(ctx echo.Context, authUserId string) error
+	// ユーザーごとにチャートデータを返す
+	// (GET /charts/{authUserId})
+	GetChartsAuthUserId(ctx echo.Context, authUserId string, params GetChartsAuthUserIdParams) error
+	// 本棚の変更をServer-Sent Eventsで受け取る
+	// (GET /events/{authUserId})
+	GetEventsAuthUserId(ctx echo.Context, authUserId string, params GetEventsAuthUserIdParams) error
+	// ユーザーごとに目標を削除
+	// (DELETE /goals/{authUserId})
+	DeleteGoalsAuthUserId(ctx echo.Context, authUserId string, params DeleteGoalsAuthUserIdParams) error
+	// ユーザーごとに目標の進捗を返す
+	// (GET /goals/{authUserId})
+	GetGoalsAuthUserId(ctx echo.Context, authUserId string) error
+	// ユーザーごとに目標を登録、更新（種類と期間の組み合わせごとに1件）
+	// (PUT /goals/{authUserId})
+	PutGoalsAuthUserId(ctx echo.Context, authUserId string) error
+	// サーバーの監視
+	// (GET /health)
+	GetHealth(ctx echo.Context) error
+	// DBサーバーの監視
+	// (GET /health/db)
+	GetHealthDb(ctx echo.Context) error
+	// メールのリンクのトークンで月次のまとめの配信を停止する
+	// (POST /mail/unsubscribe)
+	PostMailUnsubscribe(ctx echo.Context, params PostMailUnsubscribeParams) error
+	// メールの設定を返す（未登録の場合は既定の設定）
+	// (GET /mail/{authUserId})
+	GetMailAuthUserId(ctx echo.Context, authUserId string) error
+	// メールの設定（言語、月次のまとめの受信）を更新する
+	// (PUT /mail/{authUserId})
+	PutMailAuthUserId(ctx echo.Context, authUserId string) error
+	// 為替レートの一覧を返す
+	// (GET /rates)
+	GetRates(ctx echo.Context) error
+	// ユーザーごとに記録を返す
+	// (GET /records/{authUserId})
+	GetRecordsAuthUserId(ctx echo.Context, authUserId string, params GetRecordsAuthUserIdParams) error
+	// 書籍の検索結果を取得
+	// (GET /search)
+	GetSearch(ctx echo.Context, params GetSearchParams) error
+	// ユーザーごとに本棚の本を複数削除（ゴミ箱へ移動し、保持期間後に完全に削除）
+	// (DELETE /shelf/{authUserId})
+	DeleteShelfAuthUserId(ctx echo.Context, authUserId string, params DeleteShelfAuthUserIdParams) error
+	// ユーザーごとに本棚を取得
+	// (GET /shelf/{authUserId})
+	GetShelfAuthUserId(ctx echo.Context, authUserId string, params GetShelfAuthUserIdParams) error
+	// ユーザーごとに本を本棚に1冊ずつ作成
+	// (POST /shelf/{authUserId})
+	PostShelfAuthUserId(ctx echo.Context, authUserId string) error
+	// ユーザーごとに本棚の本を1冊ずつ更新
+	// (PUT /shelf/{authUserId})
+	PutShelfAuthUserId(ctx echo.Context, authUserId string, params PutShelfAuthUserIdParams) error
+	// 本棚の本を一括で作成、更新、ステータス変更、削除
+	// (POST /shelf/{authUserId}/batch)
+	PostShelfAuthUserIdBatch(ctx echo.Context, authUserId string) error
+	// 本棚の本を部分更新（JSON Merge Patch）
+	// (PATCH /shelf/{authUserId}/{bookId})
+	PatchShelfAuthUserIdBookId(ctx echo.Context, authUserId string, bookId string, params PatchShelfAuthUserIdBookIdParams) error
+	// 本の変更履歴（更新前の版）を新しい順に返す
+	// (GET /shelf/{authUserId}/{bookId}/history)
+	GetShelfAuthUserIdBookIdHistory(ctx echo.Context, authUserId string, bookId string) error
+	// 本を変更履歴の版の内容に戻す
+	// (POST /shelf/{authUserId}/{bookId}/history/{revisionId}/revert)
+	PostShelfAuthUserIdBookIdHistoryRevisionIdRevert(ctx echo.Context, authUserId string, bookId string, revisionId string, params PostShelfAuthUserIdBookIdHistoryRevisionIdRevertParams) error
+	// ユーザーごとにゴミ箱の中身（削除済みのユーザー、本）を返す
+	// (GET /trash/{authUserId})
+	GetTrashAuthUserId(ctx echo.Context, authUserId string) error
+	// ゴミ箱の本を複数復元
+	// (POST /trash/{authUserId}/books/restore)
+	PostTrashAuthUserIdBooksRestore(ctx echo.Context, authUserId string, params PostTrashAuthUserIdBooksRestoreParams) error
+	// ゴミ箱のユーザーを復元
+	// (POST /trash/{authUserId}/user/restore)
+	PostTrashAuthUserIdUserRestore(ctx echo.Context, authUserId string) error
+	// ユーザー情報を更新
+	// (PUT /users)
+	PutUsers(ctx echo.Context, params PutUsersParams) error
+	// ユーザーと本棚、図表、目標をまとめて削除（既定ではゴミ箱へ移動し、保持期間後に完全に削除）
+	// (DELETE /users/{authUserId})
+	DeleteUsersAuthUserId(ctx echo.Context, authUserId string, params DeleteUsersAuthUserIdParams) error
+	// ユーザー情報を返す
+	// (GET /users/{authUserId})
+	GetUsersAuthUserId(ctx echo.Context, authUserId string) error
+	// ユーザー情報を部分更新（JSON Merge Patch）
+	// (PATCH /users/{authUserId})
+	PatchUsersAuthUserId(ctx echo.Context, authUserId string, params PatchUsersAuthUserIdParams) error
+	// メールアドレス確認のメールを送る
+	// (POST /users/{authUserId}/email/verification)
+	PostUsersAuthUserIdEmailVerification(ctx echo.Context, authUserId string) error
+	// ユーザーごとに登録したWebhookを返す（署名の鍵は含まない）
+	// (GET /webhooks/{authUserId})
+	GetWebhooksAuthUserId(ctx echo.Context, authUserId string) error
+	// Webhookを登録し、署名の鍵を返す
+	// (POST /webhooks/{authUserId})
+	PostWebhooksAuthUserId(ctx echo.Context, authUserId string) error
+	// 再試行の上限に達した配信（デッドレター）を新しい順に返す
+	// (GET /webhooks/{authUserId}/deadletters)
+	GetWebhooksAuthUserIdDeadletters(ctx echo.Context, authUserId string) error
+	// デッドレターを配信待ちに戻し、再試行する
+	// (POST /webhooks/{authUserId}/deadletters/{deliveryId}/retry)
+	PostWebhooksAuthUserIdDeadlettersDeliveryIdRetry(ctx echo.Context, authUserId string, deliveryId string) error
+	// Webhookと、その配信（デッドレターを含む）を削除
+	// (DELETE /webhooks/{authUserId}/{webhookId})
+	DeleteWebhooksAuthUserIdWebhookId(ctx echo.Context, authUserId string, webhookId string) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetActivityAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetActivityAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetActivityAuthUserIdParams
+	// ------------- Optional query parameter "beforeId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "beforeId", ctx.QueryParams(), &params.BeforeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter beforeId: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetActivityAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetAdminAudit converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminAudit(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAdminAuditParams
+	// ------------- Optional query parameter "authUserId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "authUserId", ctx.QueryParams(), &params.AuthUserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", ctx.QueryParams(), &params.Actor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter actor: %s", err))
+	}
+
+	// ------------- Optional query parameter "targetType" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "targetType", ctx.QueryParams(), &params.TargetType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter targetType: %s", err))
+	}
+
+	// ------------- Optional query parameter "targetId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "targetId", ctx.QueryParams(), &params.TargetId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter targetId: %s", err))
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "action", ctx.QueryParams(), &params.Action)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter action: %s", err))
+	}
+
+	// ------------- Optional query parameter "beforeId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "beforeId", ctx.QueryParams(), &params.BeforeId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter beforeId: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminAudit(ctx, params)
+	return err
+}
+
+// PutAdminRates converts echo context to params.
+func (w *ServerInterfaceWrapper) PutAdminRates(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutAdminRates(ctx)
+	return err
+}
+
+// GetAdminStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminStats(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminStats(ctx)
+	return err
+}
+
+// GetAdminUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminUsers(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAdminUsersParams
+	// ------------- Optional query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminUsers(ctx, params)
+	return err
+}
+
+// PostAdminUsersAuthUserIdDisable converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminUsersAuthUserIdDisable(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminUsersAuthUserIdDisable(ctx, authUserId)
+	return err
+}
+
+// PostAdminUsersAuthUserIdEnable converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminUsersAuthUserIdEnable(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminUsersAuthUserIdEnable(ctx, authUserId)
+	return err
+}
+
+// PostAdminUsersAuthUserIdRevoke converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAdminUsersAuthUserIdRevoke(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAdminUsersAuthUserIdRevoke(ctx, authUserId)
+	return err
+}
+
+// GetAdminUsersAuthUserIdStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAdminUsersAuthUserIdStats(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetAdminUsersAuthUserIdStats(ctx, authUserId)
+	return err
+}
+
+// GetApikeysAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApikeysAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetApikeysAuthUserId(ctx, authUserId)
+	return err
+}
+
+// PostApikeysAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PostApikeysAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostApikeysAuthUserId(ctx, authUserId)
+	return err
+}
+
+// DeleteApikeysAuthUserIdKeyId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteApikeysAuthUserIdKeyId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	// ------------- Path parameter "keyId" -------------
+	var keyId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "keyId", ctx.Param("keyId"), &keyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter keyId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteApikeysAuthUserIdKeyId(ctx, authUserId, keyId)
+	return err
+}
+
+// PostAuthEmailVerify converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthEmailVerify(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthEmailVerify(ctx)
+	return err
+}
+
+// PostAuthLogin converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthLogin(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthLogin(ctx)
+	return err
+}
+
+// PostAuthPasswordReset converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthPasswordReset(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthPasswordReset(ctx)
+	return err
+}
+
+// PostAuthPasswordResetConfirm converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthPasswordResetConfirm(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthPasswordResetConfirm(ctx)
+	return err
+}
+
+// PostAuthRegister converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthRegister(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthRegister(ctx)
+	return err
+}
+
+// GetBacklogAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBacklogAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetBacklogAuthUserId(ctx, authUserId)
+	return err
+}
+
+// GetChartsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetChartsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetChartsAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetChartsAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetEventsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEventsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEventsAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Last-Event-ID, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Last-Event-ID", valueList[0], &LastEventID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Last-Event-ID: %s", err))
+		}
+
+		params.LastEventID = &LastEventID
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEventsAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// DeleteGoalsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteGoalsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteGoalsAuthUserIdParams
+	// ------------- Required query parameter "goalId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "goalId", ctx.QueryParams(), &params.GoalId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter goalId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteGoalsAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetGoalsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGoalsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGoalsAuthUserId(ctx, authUserId)
+	return err
+}
+
+// PutGoalsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutGoalsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutGoalsAuthUserId(ctx, authUserId)
+	return err
+}
+
+// GetHealth converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealth(ctx)
+	return err
+}
+
+// GetHealthDb converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealthDb(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealthDb(ctx)
+	return err
+}
+
+// PostMailUnsubscribe converts echo context to params.
+func (w *ServerInterfaceWrapper) PostMailUnsubscribe(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostMailUnsubscribeParams
+	// ------------- Required query parameter "token" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "token", ctx.QueryParams(), &params.Token)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostMailUnsubscribe(ctx, params)
+	return err
+}
+
+// GetMailAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMailAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetMailAuthUserId(ctx, authUserId)
+	return err
+}
+
+// PutMailAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutMailAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutMailAuthUserId(ctx, authUserId)
+	return err
+}
+
+// GetRates converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRates(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRates(ctx)
+	return err
+}
+
+// GetRecordsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRecordsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRecordsAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRecordsAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetSearch converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSearch(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSearchParams
+	// ------------- Required query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSearch(ctx, params)
+	return err
+}
+
+// DeleteShelfAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteShelfAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteShelfAuthUserIdParams
+	// ------------- Required query parameter "bookId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "bookId", ctx.QueryParams(), &params.BookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteShelfAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetShelfAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetShelfAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetShelfAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetShelfAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// PostShelfAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PostShelfAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostShelfAuthUserId(ctx, authUserId)
+	return err
+}
+
+// PutShelfAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutShelfAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutShelfAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
 
-	// Title 本の書名
-	Title *string `json:"title,omitempty"`
+		params.IfMatch = &IfMatch
+	}
 
-	// UpdatedAt 本の更新日時
-	UpdatedAt *string `json:"updatedAt,omitempty"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutShelfAuthUserId(ctx, authUserId, params)
+	return err
 }
 
-// Chart defines model for Chart.
-type Chart struct {
-	// Data 各データ内容
-	Data *string `json:"data,omitempty"`
-
-	// Id 図表の識別子
-	Id *string `json:"id,omitempty"`
+// PostShelfAuthUserIdBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) PostShelfAuthUserIdBatch(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
 
-	// Label チャートを分類する識別子
-	Label *string `json:"label,omitempty"`
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
 
-	// Month 各データの月
-	Month *string `json:"month,omitempty"`
+	ctx.Set(ApiKeyAuthScopes, []string{})
 
-	// Year 各データの年
-	Year *string `json:"year,omitempty"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostShelfAuthUserIdBatch(ctx, authUserId)
+	return err
 }
 
-// Error defines model for Error.
-type Error struct {
-	// Message エラーメッセージ
-	Message *string `json:"message,omitempty"`
-}
+// PatchShelfAuthUserIdBookId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchShelfAuthUserIdBookId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
 
-// Record defines model for Record.
-type Record struct {
-	// Costs 購入額の総計
-	Costs *string `json:"costs,omitempty"`
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
 
-	// CostsRead 購入額のうち読了分
-	CostsRead *string `json:"costsRead,omitempty"`
+	// ------------- Path parameter "bookId" -------------
+	var bookId string
 
-	// Id 記録の識別子
-	Id *string `json:"id,omitempty"`
+	err = runtime.BindStyledParameterWithOptions("simple", "bookId", ctx.Param("bookId"), &bookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+	}
 
-	// Pages 購入ページ数の総計
-	Pages *string `json:"pages,omitempty"`
+	ctx.Set(ApiKeyAuthScopes, []string{})
 
-	// PagesRead 購入ページ数のうち読了分
-	PagesRead *string `json:"pagesRead,omitempty"`
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchShelfAuthUserIdBookIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
 
-	// Volumes 購入冊数の総計
-	Volumes *string `json:"volumes,omitempty"`
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
 
-	// VolumesRead 購入冊数のうち読了分
-	VolumesRead *string `json:"volumesRead,omitempty"`
-}
+		params.IfMatch = &IfMatch
+	}
 
-// User defines model for User.
-type User struct {
-	// AuthUserId フロントユーザーの識別子
-	AuthUserId *string `json:"authUserId,omitempty"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchShelfAuthUserIdBookId(ctx, authUserId, bookId, params)
+	return err
+}
 
-	// CreatedAt ユーザーの作成日時
-	CreatedAt *string `json:"createdAt,omitempty"`
+// GetShelfAuthUserIdBookIdHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetShelfAuthUserIdBookIdHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
 
-	// Email ユーザーemail
-	Email *string `json:"email,omitempty"`
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
 
-	// Id バックユーザーの識別子
-	Id *string `json:"id,omitempty"`
+	// ------------- Path parameter "bookId" -------------
+	var bookId string
 
-	// Name ユーザー名
-	Name *string `json:"name,omitempty"`
+	err = runtime.BindStyledParameterWithOptions("simple", "bookId", ctx.Param("bookId"), &bookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+	}
 
-	// Password パスワード（あれば）
-	Password *string `json:"password,omitempty"`
+	ctx.Set(ApiKeyAuthScopes, []string{})
 
-	// UpdatedAt ユーザーの更新日時
-	UpdatedAt *string `json:"updatedAt,omitempty"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetShelfAuthUserIdBookIdHistory(ctx, authUserId, bookId)
+	return err
 }
 
-// GetSearchParams defines parameters for GetSearch.
-type GetSearchParams struct {
-	// Query 検索文字列
-	Query string `form:"query" json:"query"`
-}
+// PostShelfAuthUserIdBookIdHistoryRevisionIdRevert converts echo context to params.
+func (w *ServerInterfaceWrapper) PostShelfAuthUserIdBookIdHistoryRevisionIdRevert(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
 
-// DeleteShelfAuthUserIdParams defines parameters for DeleteShelfAuthUserId.
-type DeleteShelfAuthUserIdParams struct {
-	// BookId 書籍の識別子
-	BookId []string `form:"bookId" json:"bookId"`
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
 
-// PostAuthRegisterJSONRequestBody defines body for PostAuthRegister for application/json ContentType.
-type PostAuthRegisterJSONRequestBody = User
+	// ------------- Path parameter "bookId" -------------
+	var bookId string
 
-// PostShelfAuthUserIdJSONRequestBody defines body for PostShelfAuthUserId for application/json ContentType.
-type PostShelfAuthUserIdJSONRequestBody = Book
+	err = runtime.BindStyledParameterWithOptions("simple", "bookId", ctx.Param("bookId"), &bookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+	}
 
-// PutShelfAuthUserIdJSONRequestBody defines body for PutShelfAuthUserId for application/json ContentType.
-type PutShelfAuthUserIdJSONRequestBody = Book
+	// ------------- Path parameter "revisionId" -------------
+	var revisionId string
 
-// PutUsersJSONRequestBody defines body for PutUsers for application/json ContentType.
-type PutUsersJSONRequestBody = User
+	err = runtime.BindStyledParameterWithOptions("simple", "revisionId", ctx.Param("revisionId"), &revisionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter revisionId: %s", err))
+	}
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// user情報の登録
-	// (POST /auth/register)
-	PostAuthRegister(ctx echo.Context) error
-	// ユーザーごとにチャートデータを返す
-	// (GET /charts/{authUserId})
-	GetChartsAuthUserId(ctx echo.Context, authUserId string) error
-	// サーバーの監視
-	// (GET /health)
-	GetHealth(ctx echo.Context) error
-	// DBサーバーの監視
-	// (GET /health/db)
-	GetHealthDb(ctx echo.Context) error
-	// ユーザーごとに記録を返す
-	// (GET /records/{authUserId})
-	GetRecordsAuthUserId(ctx echo.Context, authUserId string) error
-	// 書籍の検索結果を取得
-	// (GET /search)
-	GetSearch(ctx echo.Context, params GetSearchParams) error
-	// ユーザーごとに本棚の本を複数削除
-	// (DELETE /shelf/{authUserId})
-	DeleteShelfAuthUserId(ctx echo.Context, authUserId string, params DeleteShelfAuthUserIdParams) error
-	// ユーザーごとに本棚を取得
-	// (GET /shelf/{authUserId})
-	GetShelfAuthUserId(ctx echo.Context, authUserId string) error
-	// ユーザーごとに本を本棚に1冊ずつ作成
-	// (POST /shelf/{authUserId})
-	PostShelfAuthUserId(ctx echo.Context, authUserId string) error
-	// ユーザーごとに本棚の本を1冊ずつ更新
-	// (PUT /shelf/{authUserId})
-	PutShelfAuthUserId(ctx echo.Context, authUserId string) error
-	// ユーザー情報を更新
-	// (PUT /users)
-	PutUsers(ctx echo.Context) error
-	// ユーザーを削除
-	// (DELETE /users/{authUserId})
-	DeleteUsersAuthUserId(ctx echo.Context, authUserId string) error
-	// ユーザー情報を返す
-	// (GET /users/{authUserId})
-	GetUsersAuthUserId(ctx echo.Context, authUserId string) error
-}
+	ctx.Set(ApiKeyAuthScopes, []string{})
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostShelfAuthUserIdBookIdHistoryRevisionIdRevertParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostShelfAuthUserIdBookIdHistoryRevisionIdRevert(ctx, authUserId, bookId, revisionId, params)
+	return err
 }
 
-// PostAuthRegister converts echo context to params.
-func (w *ServerInterfaceWrapper) PostAuthRegister(ctx echo.Context) error {
+// GetTrashAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTrashAuthUserId(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
 
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostAuthRegister(ctx)
+	err = w.Handler.GetTrashAuthUserId(ctx, authUserId)
 	return err
 }
 
-// GetChartsAuthUserId converts echo context to params.
-func (w *ServerInterfaceWrapper) GetChartsAuthUserId(ctx echo.Context) error {
+// PostTrashAuthUserIdBooksRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTrashAuthUserIdBooksRestore(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "authUserId" -------------
 	var authUserId string
@@ -226,35 +2052,71 @@ func (w *ServerInterfaceWrapper) GetChartsAuthUserId(ctx echo.Context) error {
 
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTrashAuthUserIdBooksRestoreParams
+	// ------------- Required query parameter "bookId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "bookId", ctx.QueryParams(), &params.BookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetChartsAuthUserId(ctx, authUserId)
+	err = w.Handler.PostTrashAuthUserIdBooksRestore(ctx, authUserId, params)
 	return err
 }
 
-// GetHealth converts echo context to params.
-func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
+// PostTrashAuthUserIdUserRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTrashAuthUserIdUserRestore(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
 
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetHealth(ctx)
+	err = w.Handler.PostTrashAuthUserIdUserRestore(ctx, authUserId)
 	return err
 }
 
-// GetHealthDb converts echo context to params.
-func (w *ServerInterfaceWrapper) GetHealthDb(ctx echo.Context) error {
+// PutUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) PutUsers(ctx echo.Context) error {
 	var err error
 
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutUsersParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetHealthDb(ctx)
+	err = w.Handler.PutUsers(ctx, params)
 	return err
 }
 
-// GetRecordsAuthUserId converts echo context to params.
-func (w *ServerInterfaceWrapper) GetRecordsAuthUserId(ctx echo.Context) error {
+// DeleteUsersAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteUsersAuthUserId(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "authUserId" -------------
 	var authUserId string
@@ -266,33 +2128,40 @@ func (w *ServerInterfaceWrapper) GetRecordsAuthUserId(ctx echo.Context) error {
 
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUsersAuthUserIdParams
+	// ------------- Optional query parameter "immediate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "immediate", ctx.QueryParams(), &params.Immediate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter immediate: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetRecordsAuthUserId(ctx, authUserId)
+	err = w.Handler.DeleteUsersAuthUserId(ctx, authUserId, params)
 	return err
 }
 
-// GetSearch converts echo context to params.
-func (w *ServerInterfaceWrapper) GetSearch(ctx echo.Context) error {
+// GetUsersAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUsersAuthUserId(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
 
-	ctx.Set(ApiKeyAuthScopes, []string{})
-
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetSearchParams
-	// ------------- Required query parameter "query" -------------
-
-	err = runtime.BindQueryParameter("form", true, true, "query", ctx.QueryParams(), &params.Query)
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter query: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
 	}
 
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetSearch(ctx, params)
+	err = w.Handler.GetUsersAuthUserId(ctx, authUserId)
 	return err
 }
 
-// DeleteShelfAuthUserId converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteShelfAuthUserId(ctx echo.Context) error {
+// PatchUsersAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchUsersAuthUserId(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "authUserId" -------------
 	var authUserId string
@@ -305,21 +2174,32 @@ func (w *ServerInterfaceWrapper) DeleteShelfAuthUserId(ctx echo.Context) error {
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params DeleteShelfAuthUserIdParams
-	// ------------- Required query parameter "bookId" -------------
+	var params PatchUsersAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
 
-	err = runtime.BindQueryParameter("form", true, true, "bookId", ctx.QueryParams(), &params.BookId)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.DeleteShelfAuthUserId(ctx, authUserId, params)
+	err = w.Handler.PatchUsersAuthUserId(ctx, authUserId, params)
 	return err
 }
 
-// GetShelfAuthUserId converts echo context to params.
-func (w *ServerInterfaceWrapper) GetShelfAuthUserId(ctx echo.Context) error {
+// PostUsersAuthUserIdEmailVerification converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersAuthUserIdEmailVerification(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "authUserId" -------------
 	var authUserId string
@@ -332,12 +2212,12 @@ func (w *ServerInterfaceWrapper) GetShelfAuthUserId(ctx echo.Context) error {
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetShelfAuthUserId(ctx, authUserId)
+	err = w.Handler.PostUsersAuthUserIdEmailVerification(ctx, authUserId)
 	return err
 }
 
-// PostShelfAuthUserId converts echo context to params.
-func (w *ServerInterfaceWrapper) PostShelfAuthUserId(ctx echo.Context) error {
+// GetWebhooksAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetWebhooksAuthUserId(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "authUserId" -------------
 	var authUserId string
@@ -350,12 +2230,12 @@ func (w *ServerInterfaceWrapper) PostShelfAuthUserId(ctx echo.Context) error {
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostShelfAuthUserId(ctx, authUserId)
+	err = w.Handler.GetWebhooksAuthUserId(ctx, authUserId)
 	return err
 }
 
-// PutShelfAuthUserId converts echo context to params.
-func (w *ServerInterfaceWrapper) PutShelfAuthUserId(ctx echo.Context) error {
+// PostWebhooksAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PostWebhooksAuthUserId(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "authUserId" -------------
 	var authUserId string
@@ -368,23 +2248,30 @@ func (w *ServerInterfaceWrapper) PutShelfAuthUserId(ctx echo.Context) error {
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PutShelfAuthUserId(ctx, authUserId)
+	err = w.Handler.PostWebhooksAuthUserId(ctx, authUserId)
 	return err
 }
 
-// PutUsers converts echo context to params.
-func (w *ServerInterfaceWrapper) PutUsers(ctx echo.Context) error {
+// GetWebhooksAuthUserIdDeadletters converts echo context to params.
+func (w *ServerInterfaceWrapper) GetWebhooksAuthUserIdDeadletters(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
 
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PutUsers(ctx)
+	err = w.Handler.GetWebhooksAuthUserIdDeadletters(ctx, authUserId)
 	return err
 }
 
-// DeleteUsersAuthUserId converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteUsersAuthUserId(ctx echo.Context) error {
+// PostWebhooksAuthUserIdDeadlettersDeliveryIdRetry converts echo context to params.
+func (w *ServerInterfaceWrapper) PostWebhooksAuthUserIdDeadlettersDeliveryIdRetry(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "authUserId" -------------
 	var authUserId string
@@ -394,15 +2281,23 @@ func (w *ServerInterfaceWrapper) DeleteUsersAuthUserId(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
 	}
 
+	// ------------- Path parameter "deliveryId" -------------
+	var deliveryId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "deliveryId", ctx.Param("deliveryId"), &deliveryId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter deliveryId: %s", err))
+	}
+
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.DeleteUsersAuthUserId(ctx, authUserId)
+	err = w.Handler.PostWebhooksAuthUserIdDeadlettersDeliveryIdRetry(ctx, authUserId, deliveryId)
 	return err
 }
 
-// GetUsersAuthUserId converts echo context to params.
-func (w *ServerInterfaceWrapper) GetUsersAuthUserId(ctx echo.Context) error {
+// DeleteWebhooksAuthUserIdWebhookId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteWebhooksAuthUserIdWebhookId(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "authUserId" -------------
 	var authUserId string
@@ -412,10 +2307,18 @@ func (w *ServerInterfaceWrapper) GetUsersAuthUserId(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
 	}
 
+	// ------------- Path parameter "webhookId" -------------
+	var webhookId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhookId", ctx.Param("webhookId"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhookId: %s", err))
+	}
+
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetUsersAuthUserId(ctx, authUserId)
+	err = w.Handler.DeleteWebhooksAuthUserIdWebhookId(ctx, authUserId, webhookId)
 	return err
 }
 
@@ -447,56 +2350,269 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/activity/:authUserId", wrapper.GetActivityAuthUserId)
+	router.GET(baseURL+"/admin/audit", wrapper.GetAdminAudit)
+	router.PUT(baseURL+"/admin/rates", wrapper.PutAdminRates)
+	router.GET(baseURL+"/admin/stats", wrapper.GetAdminStats)
+	router.GET(baseURL+"/admin/users", wrapper.GetAdminUsers)
+	router.POST(baseURL+"/admin/users/:authUserId/disable", wrapper.PostAdminUsersAuthUserIdDisable)
+	router.POST(baseURL+"/admin/users/:authUserId/enable", wrapper.PostAdminUsersAuthUserIdEnable)
+	router.POST(baseURL+"/admin/users/:authUserId/revoke", wrapper.PostAdminUsersAuthUserIdRevoke)
+	router.GET(baseURL+"/admin/users/:authUserId/stats", wrapper.GetAdminUsersAuthUserIdStats)
+	router.GET(baseURL+"/apikeys/:authUserId", wrapper.GetApikeysAuthUserId)
+	router.POST(baseURL+"/apikeys/:authUserId", wrapper.PostApikeysAuthUserId)
+	router.DELETE(baseURL+"/apikeys/:authUserId/:keyId", wrapper.DeleteApikeysAuthUserIdKeyId)
+	router.POST(baseURL+"/auth/email/verify", wrapper.PostAuthEmailVerify)
+	router.POST(baseURL+"/auth/login", wrapper.PostAuthLogin)
+	router.POST(baseURL+"/auth/password/reset", wrapper.PostAuthPasswordReset)
+	router.POST(baseURL+"/auth/password/reset/confirm", wrapper.PostAuthPasswordResetConfirm)
 	router.POST(baseURL+"/auth/register", wrapper.PostAuthRegister)
+	router.GET(baseURL+"/backlog/:authUserId", wrapper.GetBacklogAuthUserId)
 	router.GET(baseURL+"/charts/:authUserId", wrapper.GetChartsAuthUserId)
+	router.GET(baseURL+"/events/:authUserId", wrapper.GetEventsAuthUserId)
+	router.DELETE(baseURL+"/goals/:authUserId", wrapper.DeleteGoalsAuthUserId)
+	router.GET(baseURL+"/goals/:authUserId", wrapper.GetGoalsAuthUserId)
+	router.PUT(baseURL+"/goals/:authUserId", wrapper.PutGoalsAuthUserId)
 	router.GET(baseURL+"/health", wrapper.GetHealth)
 	router.GET(baseURL+"/health/db", wrapper.GetHealthDb)
+	router.POST(baseURL+"/mail/unsubscribe", wrapper.PostMailUnsubscribe)
+	router.GET(baseURL+"/mail/:authUserId", wrapper.GetMailAuthUserId)
+	router.PUT(baseURL+"/mail/:authUserId", wrapper.PutMailAuthUserId)
+	router.GET(baseURL+"/rates", wrapper.GetRates)
 	router.GET(baseURL+"/records/:authUserId", wrapper.GetRecordsAuthUserId)
 	router.GET(baseURL+"/search", wrapper.GetSearch)
 	router.DELETE(baseURL+"/shelf/:authUserId", wrapper.DeleteShelfAuthUserId)
 	router.GET(baseURL+"/shelf/:authUserId", wrapper.GetShelfAuthUserId)
 	router.POST(baseURL+"/shelf/:authUserId", wrapper.PostShelfAuthUserId)
 	router.PUT(baseURL+"/shelf/:authUserId", wrapper.PutShelfAuthUserId)
+	router.POST(baseURL+"/shelf/:authUserId/batch", wrapper.PostShelfAuthUserIdBatch)
+	router.PATCH(baseURL+"/shelf/:authUserId/:bookId", wrapper.PatchShelfAuthUserIdBookId)
+	router.GET(baseURL+"/shelf/:authUserId/:bookId/history", wrapper.GetShelfAuthUserIdBookIdHistory)
+	router.POST(baseURL+"/shelf/:authUserId/:bookId/history/:revisionId/revert", wrapper.PostShelfAuthUserIdBookIdHistoryRevisionIdRevert)
+	router.GET(baseURL+"/trash/:authUserId", wrapper.GetTrashAuthUserId)
+	router.POST(baseURL+"/trash/:authUserId/books/restore", wrapper.PostTrashAuthUserIdBooksRestore)
+	router.POST(baseURL+"/trash/:authUserId/user/restore", wrapper.PostTrashAuthUserIdUserRestore)
 	router.PUT(baseURL+"/users", wrapper.PutUsers)
 	router.DELETE(baseURL+"/users/:authUserId", wrapper.DeleteUsersAuthUserId)
 	router.GET(baseURL+"/users/:authUserId", wrapper.GetUsersAuthUserId)
+	router.PATCH(baseURL+"/users/:authUserId", wrapper.PatchUsersAuthUserId)
+	router.POST(baseURL+"/users/:authUserId/email/verification", wrapper.PostUsersAuthUserIdEmailVerification)
+	router.GET(baseURL+"/webhooks/:authUserId", wrapper.GetWebhooksAuthUserId)
+	router.POST(baseURL+"/webhooks/:authUserId", wrapper.PostWebhooksAuthUserId)
+	router.GET(baseURL+"/webhooks/:authUserId/deadletters", wrapper.GetWebhooksAuthUserIdDeadletters)
+	router.POST(baseURL+"/webhooks/:authUserId/deadletters/:deliveryId/retry", wrapper.PostWebhooksAuthUserIdDeadlettersDeliveryIdRetry)
+	router.DELETE(baseURL+"/webhooks/:authUserId/:webhookId", wrapper.DeleteWebhooksAuthUserIdWebhookId)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+ya31MTSxbH/5Wt3n1MkbCyVVbeULZ2rd0HC8oni4chaclIkhm7J2xlqVQ5MxICQhkp",
-	"ARHKFUE2CwZx/XHxmit/TDOT8OS/cKu7J5NJ5kcS1Jh7zYvCMOf06dPn09/unp4DMSklS2mYVjCIzgEc",
-	"S8CUwH68Ikkz9H8ZSTJEigjZUyGjJG5giK7F6W9xiGNIlBVRSoMoIPo+0StEe0//VY9q5cdG4YVRLoIQ",
-	"ULIyBFGAFSSmp0EuxPxIyO3D3H5JTR9u1O7Oe9lNSdLMhCIoGexnW116b87f97KNISgoMD6q+Jme/bJt",
-	"FormxgtzU/NyIMZ9Aw7qq5gSpuGN8X/6Rvzoo6E/8LTEU+nhiJ+d9VcPO1mYhn5WRH/CRunEXDv2tEVi",
-	"zNf47NOO+aziZaaIStLXzNw6MYorXmYZOR48JubWW3P92G9McvYTaeo2jCnU5dWEgBR35cYFRXA3YhTv",
-	"EX2BJeTUyM8bRx86HXhj601tp9Ru7JPCFEx6oaISfZc2qxeItmoU8uc7T4m6SbT7ge5SUlpJBPeC5my7",
-	"4GWchQJqa2t8eNtZmv+KEAe4Oc0piLFn9RGtRPT/sS7vEF0n2kdehp21Ng5jEoq7m4tJWPGYCmpvKsb8",
-	"i/OdFYrXTw9qJc+EMONxKMSDHRA1T9Sd2kH57Oe8Uch3WiC10uPz5f+3KxCKqm8HnKwG9oR5CepJi6cO",
-	"ujQrJTMp/9CM/FK7oCwPQWHZXtoG5FUUVIi6lag1opeJ/oZy14VcBUhHi5t2GgJTgpgM9sJf6bDKiF5k",
-	"NL3qpjtpIQWDY/CZrmUB439ZILYaPyTaB6K/YoQvfq4UiKoRbZmox58ri11O/S196VYDciGAYSyDRCU7",
-	"QRc0vC5GZfEfMDua4ROoSNtJQCEOEagnBIyyVYn4b4HFYfsVmCXIUcdi+pZE7S29Y8ukP0xAAcUSIARm",
-	"IcK8C8NDkaEI7aYkw7QgiyAKLg1Fhi4BmkQlwSIK01INIzgtYsWqZAmzdNB6ZkHQIgbXJazQ0Mbrb4YA",
-	"gncyECtXpHiWT4NpBaaZqSDLSTHGjMO3MY2lvqyjP/0JwVsgCv4Ybqz7wtaiL8xwYp2k3kUE4yCqoAxk",
-	"D7AspTFP5J8jw8FDVt38yGa+Q7NQNJb+Q7MwEol8tTi58rBAm2M4O1kxy7tEPSD6ASVCK7GSLNAA/tKL",
-	"ADyTYOy9Ntc2eFVmUikBZUEUZDBEpj5vPHtNp1D2Kq03YRqD6E02hYFJahGO0dUMDs81ZrUcDW8aetTJ",
-	"36DCFj94tDEF0nJDQgoqEFHXXazZGSG0Vht8CE6/zUUScuSuFdBJVwF1NxaiAlO43aDwZV9jMhAQErLe",
-	"g+RYe6lHxoN149NGfxXrCCfs2wZQO1iplSpEXTZO58+fWe2O9KBda110QNSNHpLZGHRjYb9azPuR2UzE",
-	"I6KWiHrYVDP2allbrZ0+Iuqmg1yOq8VuAgpJrjZ+uP6dv/GFfHS4ApdmOhJPd+60d6znRaJXzPKucXJS",
-	"LVUMfaU1cY7X6KS2tVvbX3ekhmfjagLGZpryE45PtU/R2FSfJ2nsSodpan6xs0QhtvvpXAX4bumHlAFr",
-	"o9iBDtj7s4ECGKfztX3VL5LfqybwRi+gBla0HtO/BaqFLebbggBQ7Y1DIJ3m3nb17XNzfcEobxiFjTqU",
-	"dzIQZRtU1n/tMyDZOXIHOPJOVt8VzafbAyhtKHtGA89/MA3m1kn19QrdkTsHS1vlg+XgwKp8C4METN5y",
-	"aVccJqEC3VSMsecT1KbP9Cvk4rKeDld7LWhOSdJMm8ZslryauDZG1KOzk7u1/f96nr03o+XGesTzdN3c",
-	"fUJBW1w639wbgNYzratnvqdax0f5Alpn14m5/ZIq3t6CuXbMvTl5p7iCyVzIX+n6EOj+0j8byIHy/QBA",
-	"8kYvDqSX6NoQ+h8j9y2GX/84m4N3weNs54WAAYq9pMKZ9q6oINpqneTDYSO/RNQnRN3jvrwpyXhBkhkw",
-	"4sVIJPiCxoCRXsoVUZeZXN3rHZhslL98/dgAk3v0AJNuGTOYsTbny+gN9sL3/g4a+QZt+k8u9vfCAXLf",
-	"/cCyudZ7/CnLcU2jYyCt4tFWXdxx1hzcdXlUw1Ds+53dSJv7LoNTkB8Yqa5PR7RV1xFIHSP/I5DfBijf",
-	"SdMGpx4DTbuwprm+w9ma5rgKyBBzXgK8OUnLH0M06wdgmTV1SPTD6uqx8VwHIZBBSRAFCUWRo+FwUooJ",
-	"yYSElejlyOVIeHYYeHwk2H5ZXTvwsMfRcBgLKTkJh2JSihlP2j2YC7iD4bwzYNHvvDLgDsF9La153mhj",
-	"0sroXdVeQ1hOeLrdXlo+sTcM6t9J3Sb2tfpWE+tmjWeCmw5O3eHxnYXf95vR69eIep9oi9RRvaJaW7c+",
-	"aOUmc78GAAD//1C8HnszMwAA",
+	"H4sIAAAAAAAC/+y9a1MbR9ow/Fcove+n5wYDtvdOwlP7AR92w73JxoWde7cqdqUGqYFZJI12NBCzKVep",
+	"RwYLAwET2xhDgvEBZDDCjh0HAzY/ZpiR+MRfeOrq7jn36IAEKLG+JEbSTF/dfZ2P34fCUiwhxVFcSYY6",
+	"vg/1IyGCZPLPi1eEPvh/BCXDsphQRCke6gjpoyN67p2Gc1p6WkvvaOqmll7R0q+1lGosvNBSWEsvk8/f",
+	"wn/xhudnBzuZvQ/jp66GzlwNHeyMaSm8t5kqLK9oeMPx5iktndbU37T0s1BzKBnuRzEBIFGGEyjUEUoq",
+	"shjvC9240RzqRoo83NLZqyCZB+pk4flyYWlCwysantTUcQ1/IP/O5VdmjHsveS8X4wrqQ3LoBrw+IchC",
+	"DCnsQLp6vxSUcL9/IWP+jXH/pYZz+tikMTWt4ayG52A5397hTMm2Vdj2rTcantXwqoZv6o/e6NMZDW+c",
+	"bT8N325vGzenDnYymrqqpWe1dEZTn5JXjMEGUji/gPP3npHHF9mzKXw19H+uhgAM82XeO1Jn8o+3CquT",
+	"Gp7X8EMNr+1t3jbmN+FwCMAHOxn9w4SGc+aOJvSbq/pIhoG8fd/+Sp1xPAtQaHiKbkVTN7T0c4BXfQyL",
+	"pgESfXdEww+NiVt67iFdK9QcEuHwKMaFmkNxIQYX0NXbQo+5+M139f5diqOAC9Gn7usfZo3NjIZ3NZyD",
+	"U3ccOaxuHdGZtrNFIIE1ygDnhvklwZPOS11/Q8Pwr4QsJZCsiIh8HpaRoKBIp+IHuPNSl6auE4rJ5ee2",
+	"CksTxuwzY04NNXvWag5db+mTWuDDluSAmGiRyCuEaEtCAtSVQx2KPIhuNIfQ9YQooyRvNWNhTL/9zlhY",
+	"3J+bPtjJdP/lfNOZM2c+01IqRStjTtXwRv7mkvmTsSrgECPFt1tYf6Bnnunr01WsMYCG/YvQFQ52MuxA",
+	"YVM5Lf1CU99p6Z8IORBug3er22BUSCpfJ/n3aiykCEGt7b3fzd/NUkqhV3uwkzEWVs3PbZqlV1AdSBSB",
+	"i526Pj2pj03C4dzN7qfuVrEc/EwSEmJLWIqgPhRvQdcVWWhRhD6C9ENCVIwICrxXRv8eFGUUaY4q6M/t",
+	"bW2EjhMy6hWvB90eQDqS2X+0DnKDCYoV9pU6U1ge1zOjGr5DWFc1B5YMSwmU9ENRyL7SpzYoyzB+nNx7",
+	"v3Cwk0n2o2hvh4yEiJbC9I/vZFFBWgqH+wVZSZrf5XNL+enRQmqEopkQiYlxCqeooFiSw0msHQiyLAwf",
+	"4RXExPif2ynnMj8LdXxDEcc6jmsWOFLPv1BYAfg6YROXFUFJ+hkc2SDnFJ3nQMWuR9iWf1E9kjSQ5NHZ",
+	"C0CV0dtVvj4iJoWeKIp8nUQyZ5n8zSX99jt94j4TeQ5Vp8qFkwkU5zDK/dTDwi9ZDd8lOkWu8HpHH3m2",
+	"vzTpRKH/X0a9oY7Q/9dqa3KtTBq1XiavPSRa3WgODfLPoWYb96AfXc57Dc0mYpnXbx5XIH7Ccxz0HFT6",
+	"4ZuuSPEd1UgoFRH3ntXyc9v7E79ULfHNU+tUSmKuQwSBKqDh1ZrKHxQTxChv20uw4fQa0QzHqCyudpn/",
+	"RbLYK6JImctZOrCpHY7bAPRIUhQJ8aoFrfN69enJKnYoS1HO+/X37/SxXw52MkAvWgo7BMvhFvKQoYNQ",
+	"zLtkoDixuij1XWC46KdCGQ1JYYHupDjz6rZ/yVhRqSes5UM8zgKbsF9ZFPwvxKTiB12RFIGD1sZPS3vb",
+	"b4k15TAwRrJ722+rlAoW+y2L2Tt2f0iGH8CO6b6LHliARnDE4rpHGuzr53A7KicphVM9u7C6TmznGiya",
+	"EPoQVyY+pLa2cY+4AqYzhWymmnVAieRsbXV9b2u0JhuBBYBL8NYw5jf3NtdrskwdqzZV8xNTH2GIaJ8p",
+	"/VfIxJaiGstgRFTO9wvxPsTRWQJcW0/HjPk31Fejp56C32bs9v7cU2q5xwejUS2lauk7xNbdIFIQfG3G",
+	"yyl9epI46XiiEW98033xQuf5KxcvXKPiBN4EfNy2M8okTNQrySgQ7rFJC+699wtGZro+4L4RdDtfSH2c",
+	"qwmbQszD3Ew7kYpKLYUHExH6jwiKIvIPGSUVSYZ/JQblPlSdtiWEFUkOgsNvqRCv4m/khEe19COwydgv",
+	"a6L7CQnxb2iYp2PrqfG9ra383azTEWGubTlAcjX1DBXT+Skymgd0ixwQuAuNCazhpb3N7aNR/p3L1kDn",
+	"7+2lpndEpD+55ELSotzNwXhuNHug3H80kp/PAWPJzOrTk0R4MlZtEbHFf7SUau4qR53A9HFw/E6vEa87",
+	"fBjy0ld1XkQtva6pL52IArIe3Naz4IbG2xp+rj8lrn98s0onZoK3/Cq4utUsISXA3K5LNTJsQNKgpNIV",
+	"Kblq14WDncw/W7rpAy1dker2qQhyH+Kuq298KLxaqhFV0mWukKcDF8pnc/tLPx/sZEDIAhdNIrm63Q2J",
+	"QjCbzOV/nSj8tnGwkxESCTCqEuK3A2gYXHzDSQXFamhfiZEQBabZbWo5jsVxFc2mpGHUXtIGYxKLb8Og",
+	"uCKzf5ZnVLC3VaFpxdF15RxRCHiIZbxYIn55pj1rONfDfks0d+o/d35fAynluQ/zTHineU4ID0R54j88",
+	"KMsoHh4Osj72l0DJoVouiFy3y0df3DK27pvfVoPUEWE4eUU6H0UCRwfIT33QF4h6Tc0GdorvNLySfz5Z",
+	"WF0HL/rKE+NthkUszQApCKZ7Lw92Mr4HJ9pqHa2ISXGln2slZiyZw6DFOeOHbH5lu1wjgd3el7BCFRgs",
+	"RSMoqXwdDzDJqLU5+wwOZuqphm9SgxOgXXjBsPjpyp/00dse739R0CVpoAqQAdRLSL4gcBA0P/+msHvn",
+	"szYKcjv5nwpKkHrbQhWv3VfxrQ6S0zovJRXu1doH5DH+qlvvf6XoYAwFrkikCZhqRAcnphrfLVAdQ2EY",
+	"7UGcIuyFIqjffWIzH48WgH8ih5cxFtaMOTWvvrNohO4EKJccKv0Tvn2zUchmmlqanPdrfV4dBQf5YSww",
+	"rTuuGq1i5kl5tIbpmy4VfiFTlfolREpspjY0Msxl2p696O/eVIOXfpQDxnLSwRFYjmu0Un5wZ7aQGqkK",
+	"IaUBcEkOBjof87ffGiPjxxf0Jh6kIlYhhYp5Q6q1CoN1E7bMhyXj0Y5TPem6/FXT2dPtn1SpixD/RpH9",
+	"MS+VGX3S1DdaejGfe6WPjtQiFUOMBC1cC5wVY0If+rr7i0CUurutp6eqWSDZE29vC3o9+/bwrwdXZNDL",
+	"nd7rapaQxTAqjnVVvF0RlWjg2435zepibdRLVwR5aeZb1cQ5hOQk33HIbsKbNGgm41FVeK1dfzyr4Qxo",
+	"6ynVzJbT8Jozua5K44grMz4XwWc5zI3oibCj8o1KeF03e6pWwSobimsBG7hkJgvynWW9QjSJmrl3sp/O",
+	"6plReg8HO5n/ufzV35u+RHIfaiLvpOmRzpRM2/1lO/uYB8wvdsuQg15/9lHLxYOdDCyp4Q0Tq6gbbdXy",
+	"pNUKopIi0bKuqMjo/st5lqt4XABWI0sdjlFX1inhk5qqehJiawVz2ZKqZguWJ7lqtVwFkqxmS5aWbAc7",
+	"GTOouWFijZWqzXJqaaaPmZjs9P/UCtDyhGRtVgsSFRZv5yYElOv5CJPQBNcS+xGQeCyj4XFNHaMeRFNK",
+	"rkA8ggQpNHVKw08sblzztMsSOV42kBMmbPc0daIWUR+enktWcodCMtsantt/OF9D5aCI+uLYcI6rytTW",
+	"fc7AoDItZGNLKff4eUjR9SNmRFCE4qY4rU+p8a3p868LS9kaGShRoQdx0+6wln5C4uYZTZ3RM6P7Sz9T",
+	"VKjFqsfhkTkRR8lFO73QTljzZoQNoHixzEO8Vsg+KOyMUbUQLgCg3aiCGg7levBQEAWbRx0Xh1Ccw8wu",
+	"I3kIyS2XUVxpIj9JajgHJENcp4wuTtShBFygq7T5T7zxL0iWwVMtPWfWJ1XveigmCdxL5ee28nerlwF9",
+	"khDlbTg/nzOyc549w49PyUgI96PIUTlaPNt0Lv+FkFRaCN60dF2oqTRSuDFk74k7I8mn2E01N5G/mNXP",
+	"/mIOrOYm54Edkbx05nAFmf7BAFd/i1yWd52K0W7CQcoPeTK9V31NU7Zq41A8pJNVZrC7IWzXJx/svZ90",
+	"Brr00VFjaj6fm60i8HRIGIv4miB5hIrqGnmcePf8FxFFIxdlWeKUScBOOHz06UIhu+MkJqjjNTelpTDJ",
+	"E9dSWIojqbc6kukF6DhIRtR4koyU+VdSitu5YinMcmLSq+TrqqLRKJnkmpfk/c/J3SyR+uRtSqwHO5nO",
+	"cBgllJYvhHjfoNCHgPtlU4XVn2uYo0DPpJnejg0lT4b/VRKinGstK19hb/P2/ty005tBElcpt/EUiB5Z",
+	"SoNYllirYoEBMV5sCZe8SDY3kRze5iZyEsdbKUmI6c8EDAoFBYJWTiJZlIptA6p37/94sJMB7Tk63NxE",
+	"NPXo8ElsgYJgQkDgpwlOQfDT7GAT+SzHmR9Xj1vA3AiguEuy1CejJHlSiEa/6g11fFPczwFPhW408wmV",
+	"6w+F+6ShMj23mN/8UGXa0BeoN3CZ/K8qqTMw84Fy45p6m2YFVVeUjsIKigStStPw9vEP+tgv+jTz7xSW",
+	"xzW8mJ/6YGc6zOf03FhVobAwCsqOcRzz2j6+a2SmzdyoRU3FGl7Td0cKy1jDq97MGStLqhrICFVfjBc/",
+	"Iribqi0IutRlhflDAhbbvz+ur4xXvZgM+kGcW25iMiwXqvGIn34DlL+Vyedmqyz5DgiD7Kd+MSZnWSTk",
+	"NYZ80HC/iIZAAZfi3yqyEB5obupB/WI80tyErocRilRnI/g5yrUbzaEvpD6R43EIKK2kjmXTy1DDMstD",
+	"cHwKIu2fkkx+J8lcD4CrxuOYmw98ctrvEzHrHC2YeaoVuZRulByMKpWm0NyDdHVqkdba+xFcvsnbw5eC",
+	"GL2MFIXRoscNKvahJJcdZFiiLpBpFhihOqNPzWr4jj51n3DHqgppo0x1LupLs7TqzL8EsDjiJ6HJ/Eto",
+	"QnE/+lgbaDbPkHf2lxhydaMk4mBQML3YZQ1uwjnYyXx68P7n023G/Vv6+uzxHkifgv78KSGo07SZx+/a",
+	"HVqC9F1Xx+otftfsmcv/uDuXpZ4oivl3BY2DPvm07RP9/WN9Z4qY48xEplUUUeY3b03QN/wXWO00Ks5t",
+	"EgV7gABeZlT/xVS6UqrPsxzgoHi+aDx+qU9tkMTiVdtadzilwGUBZSTfxiXl215pMA6OC3ZGohT/tlcQ",
+	"o9X6+yJI4aKADRDOFZ6/zr95eUSOg+YQAsdOMsiBYtdxjTzTb89bcJWbmO7wHR0+cCrGk4oQD6Nyyqoo",
+	"y6uVSycho7BATBBKP+7V6e1pOKtPT2j4wcFOZqidntbe1owxNU8kINgBR6N8fn7lyiWzMpNEtZz7rrz0",
+	"OiAXwLMC6NzLamEZHxlCBnnpbYqgLhcN577u7jIJVY539PQLCbGDsY8ON+nW0LmmsJIrclzW7TBnG48l",
+	"dqMwE9Ne1sQtd3B6LfK/TbkaA1QeaYI1uotUotB1NDyq4SWWqJ4ZPZJs4uOtdOK5BQvZB0Sw1sQtGNDZ",
+	"ge7S09+h6mskixW7Rs+CtbvPoaAaGW/FSLVbZAsV26S1WK22d4NLrc6WMx6jjZSrcw6DpqqzDpu8yvXq",
+	"27sQvTOwzZiG1+jqzjaBTpW5BjB4LUd2GBZoPN53GTrenTNzad2nGeOqZoIixcQwhN5nH+u5h6TiijTV",
+	"w+80vGxkpvXbi65WpiTaoE9NGg8eaSncg5LKxd5eSVbAB+T4tVW6a/061BxC8cEY2QtZNNQcsh8PXTsk",
+	"VpWvZUsx0KESyjCzFikUTQ4Y4MzhyAg+8vAut0ha1rpaDtIKwva2Nmg0lMJm/JymGo+CT5I8VUF5IbnF",
+	"r0w44F5jwvUu+mR7W1tzKCbGzT+Ptythc0y4Du0hmyPiEPLbKY6zK46dQW6asBSLiQrXCc1yddUZik1m",
+	"Y1tIh6YXSTyUN6F3LiTyjTv6V0zoT18Z92bdaLxBcspdUq9y14hM9pEMrlw3S2R/nTZ+BmSxT8jSY/cf",
+	"jR4aN9g51ihN3z59e2eB92jjZ1WJpOXnBlHNz+7UUoNUmWIp9/4UECv9nkFSAwCkRLGmB0TvdrBNmmwS",
+	"MlMUnNowPZNjYKIejxsFqYkC1ETBaWLA3DhMQY3zbKFvuYObWpTLaxZN5IwjnV9LqRQoDW+Q3BwGiZXh",
+	"VEMTRUqUQSZBLK98AqBnDXL2h6X8vVUrZVms0ivC99nweGaO67lhPigN56iTyLLPj8JVUwKsI/fflEex",
+	"tfY9eMQJxxVh4UfH6bZ2Z18r+sqO021tFuvsON12VkthEhy4TfpLzHacPX3WdTCVa8zBpE6AL55M5+/H",
+	"VSsm66dV65gDifYfghznhmAg9ZBzPSwzR50pvB3Zxz9Q7LRyPWjUslwB78pXOLwTjwRozwuJiywIWlZq",
+	"kXsDVYWNPMfuA4d79mY3Qo8ZGJMG40rxDVTf0rFUAmWtENBap9ncGO8orshCsr/snp2sdNssXTIWXhxb",
+	"JxMFxeG7C0IxOx0yRfTchD4CNbLmh0R6+draVFMkXEbXSG7DSHqqvGs4TLPqowsm176zgQfCGrU4KNJq",
+	"wOuAPNqeA4G9rm0g6E9OIgGjX4qh88HuW+o8VW9aVUj7t+4Ar1NnCkvZ/NMtqubWuEkEv5XfNEmp3TiC",
+	"ypCj7tFddpILIB9WiT79kkZBWX43LQ1l5eM01SzrKRwnbVHtsUj6xH2LE5N6R04Z9NG0QvBc0FH3RPAs",
+	"V0FzhFqXRgAzPkwHAc8Ojq6VQLmM6Phq+4+S+9QKxnK5w9EVTP8D9fRzWzIVkansmdrNjkBD5gQ4n/5L",
+	"8ikIVwqoqnLVBJhe/aOYcxMgPOzDqIW4SKKwzE1Lf/8L9KDGuf3JX8lQKXLuRzVUalDmkPL+yOTe7pI+",
+	"kiFx8i8OdjL9igK9SuF/SSIi1oiombXlKfTMnmWpNuk5VlkEH64SYDeIIrmjqWvwLPCA5/royH6axJrc",
+	"Pbb3Nif1qY3jzTCDY/BFAODDa8GEdAFFxSHEa1QjKCQ2w0NzMidQn/+5yojesVeBErrtqqwa858t5yCt",
+	"wizIjNRenaOI6lnWMY6xhgWgMH3NKmTjD1/Lmcu68tOMV1vELIQGEcb9W4VsCsgCPlkEPuZJkTFdkaDy",
+	"ZOeMLZiEmJ8ezd99pU89q34HwT15vFvgu+WMH57l37okueXIbqvS5ZYQhqOSEAnWenhpVCYC0HzHBajS",
+	"V59U0f67gjLfo+oA5r2Iqkn3O8quuo5YovlVDirkBmVRGb4MLgvKHDtJ7L9zkNfL4WroHBJkJDfR5Ier",
+	"IZLB+ZhIllVny37SdH0c/L6+jAkop+sf+BbyO1eIQ4ZQPiM1Rl6z5BUqGZ26ZlXBkigNNhZe7G1twZ9W",
+	"ywd1Rr+zo+HX+q0tmGgKL8y43zbhna7adoZq2EEzPztJPy7xPwLruG2qKeRwqCgS470SUWloch1peNN0",
+	"GQkyGRJqWTKh9lNtp9pCNNofFxJiqCN05lTbqTMk0Zg1PG4Vwoo4JCrDrd/bDp8b8A0rjbNiuYAmob8i",
+	"pZM90OlsHO6cU/tNBc0WyBEAMPYBuBqS21KXEnmRgaz8bjBiRFMzmnqbtkW2GvbTsTx0DIiz87ezUzhT",
+	"IOFd/x4EcW6BaPYHD1UEEE2VsZJQ/tRGZg+DS/p0W1vwYlExJirFBwVfI5HshBRPUko63UY6YYWluMLq",
+	"+Jy50JADDZ/ZLyyn/Tpp5k7Qz1s79cRY3LYOlg6+BQFL0mQA+84WhcaZmV0+VGZGOAcg83I0PLG3OWms",
+	"P6EwtB8nDIXVSVKrPkEr9SgEZ44TAmCO6pqmLpvCacIxgI+GVJfJqORxAO5Px3tFQThDw55UPgzGYoI8",
+	"7OceFvOljVSMJw89I7+N9Ij+6BXh7JvWrBD4x6tnxjqk1liVLDRxqbB7V8NzZN4BmAHfhEyOGLoGgLSS",
+	"2XatApBBUbYIPyPEUoodljP1hccIXIyxAr4TNIYnaBkyzKeiFcqYmMFbyTVt4jDL+ZvqiBHfAPicfW6l",
+	"QDncyR5i0FLAuVOJ35BxDRlXgoEaTxfybx6bYx8/ZjnnnOxMOLVHmeclTjugrWfB5/qxOkPv3PRYZJxS",
+	"jBnYpvyCY3AJL1lQWHnnIEd4XRqkwqub/Mqa/XROigxXRGFlheJd/Z28XliPo81OP/WRvedY1S1jftff",
+	"t+gkSZTSJFh/7nKyBpXWP5UGoxOfUD2/V2dYtW0KW2E2yArxBC5J5y8Y2vt+V8MlaThpzrctqoDSKbhH",
+	"KSrtVTgntz8/WshmgkRkA+nrGOn9V8e3xhyDO2HC9HvSoYU+q85QW8oeBmtmCLhQn3jSNHWG5EdNlcJ7",
+	"awJ1Ubw35/SX8EPxZremsD49CVpyCtuWghUnp1nYJWKMHI323/WiNzdzZitreHf/ybyGX2p4zirg4r1f",
+	"6u1NopNUzF3j0HluDg9PZTpSg+38XthO0AWWdgX5NGIXzzHZC7S/J8K4DDWZsBqXH7w1IiZpzsX3oYTE",
+	"a0vj8Kp5/SukQSFV3p+SYkkaG3t4sJMxowCcKAXEFKzCzxR2l166srkgnJldhyhmwFjq/OOtwuqkt2ZT",
+	"nXFUd9odLjw2gZR0MFbb1X+BnUc9ePyPhfGwDUfKwF0HKpyc2VG4tapnRgu3Vgtba5DTZ8PkyMtrsMWK",
+	"2eLZtrMnxxZXNTx78rzZid/lsGcXa0xhfec3PfM2//AmMB7GFx+TAEUGIhIkfFope0ZxL3cuj41djH9c",
+	"XKwcybswxuVeDTbRYBOVqXALY8XZRDGVSZ2hjxMk3PbE5crhCDIakgYOwRG66XN/dI7gaETCuUnKon3M",
+	"ucEPGvygAiCCsKgcnaEcJQFMPf+pe02lYhbPIQzB8tyvHqZiemM/Ei2j4RZucJQT9U27YM2y7CCee9pR",
+	"VL1hTkwk9VspbIZlZp31SFxWkRAH0HCy/LxJ+vs6S5usljGUFfTtvNRFkld54V73np3tzRrMos7T+LiX",
+	"VQZlskrJ/NwWSWQP6G/nIFf6SeHWKgk3bejTa6RMgkOflMZC0L6e768tkQpOYWJFVXiXQADOi73tZ/tz",
+	"k8Sja9fA0t/s7f6krz+wXKmu/G0t/QB+nU6Rfa/5s9jVGWchCuVAybCUQEky5mFBww+gQZ2ZeU5ZGjQQ",
+	"ggYeHTISIN+N/vGdLCqIepWd8xYhOw0GTibJr8kuc+SQvanrpHqLc03tpOUb7V8Q5CmuX9Z2uFSWcphZ",
+	"Obkq7UeyajAVmiRVf2kvJENzVd/4QJA659J5UhjmrfywbTkPYPQHnavzMfJ8jlK44VQaaTWKo1CsEhFx",
+	"tu2zY1V2GUKS4IM67upeSsLbJyy2LILhii2uVLLFVgpbH/qTyC1JFKQrtn4/gIaZzsh6yfnUxgvkcx97",
+	"/RsarhMe68sucB5uiSUH0HCFq/mV1bOhjmIQmJHURhrg71CbPWZL14E3J2TmcjG3fMZEH4He9VRZBRnx",
+	"k6M2cgWm1zEc8DbcCGBbg0p/K2nZ0ToEU6iHg/Mg3G2hN06fNebU/fs/mt78FS2F2/X5n5nKzNx0TCpA",
+	"vxHCVEmB6xo/TUudcdbNWPWUzLMXpJgOKv32EO3h0NFohP4x3WUph2eLzalxbh3nzEyO+uNjLk3EhQOs",
+	"KoE4ZkGd0zO3gMJTmN4+zdOpDzZ47AGzErccYEPbY6jcJ70SRDH0hWYWJnN+O6kdaq0dpB61Jr5xadzs",
+	"SroRsAHmQeu6xPkYr5Eu+4AvpE/AHQ0vm+9jpq8xccu495L6/nUwOV9DA3I6BTC9zrqJmMle1id7m+tQ",
+	"Vn36M3Osj1vEqDOOZ12cwzSqHfwGr+QXn2nqmGUQBzIVOhvvaNgJfXf5tRC1W9RstM1DWWciW0OdCqZi",
+	"ixe6cvU0PLEP8avRuta6CF154SbnPevoYWD2FoKdnP7sJFh3zuQcE4SX3CRHTmajOrhCqJn1ViC00o0U",
+	"ebils1cJ7p7Jft3q/OmNGychHly0VkIYuJAv67u+FffbSggAs5Ngq2yNBwzQ9gIkGJsyB36c/M0lYK5Q",
+	"mTqm4Yen205bdrKWUmn37ID3bJJ+wpg0cNlop5qkhtfO7KceUkckhHDejoA4wYt6ZpREcTBZh6m0xXi3",
+	"ewTi0fBw7qy+slj6aY7cnZrd237QYLt1o74FpGHjnI3PNgIHEXAZL1FnCFqXT7GtYSneK8qxsu209srM",
+	"tPJo6jwD4hhIqwpLy8Moc44raJhZf0A69Vxx5TaW+23qjONtJcSqjPrEJNM8+GTZFUGxhKRAn9KWv6Fh",
+	"V9yQFyNk4tPyFo1OAqcAE29Dz/zEKNjTFNIRUN3b/cmYwOaEdctbQwWn+90rebDbVmFls42acyL62bbP",
+	"ivGFbnPnR8ML7NzmsiJxwQ5yU3GpVxF7rGo2KW9cIZaxM4Qwoa8/0BeyTlbkRVs8kV97q09nTjgL2bpN",
+	"LpFDdh9tEmRprHza7RHCA1Gpr+wMn3P093+wDJ+igxXojrmRv+eTpNK0kcZT9924fDdVQQ6P+azxQza/",
+	"sk2zcOkndo5dCtPORNZXUP+dwoWVJ2QsD5uMUVgeL3zY0fAuHZHBC6oygmTkSdNagqjTvceLV4Q+j1C1",
+	"cnu6elv+LsVRy5fQ/pwoVP7BWxtn2s46zVefwPsrUs4TeOqvLSIPLWzAWrt6Yfdk86HjSQckB1VONqAz",
+	"j4nDSVwOHrjhUp4d8huyzhmeTeBBhDWr9l8fHdFz7xzN5CBASANnh4agYcEDO95/lGnEofkDpU+smsum",
+	"Of3Wcn56tCKB4CJZR59cHy+n3Juxctr8vzxWThv90SE+rNPgKdb1nGY/Wj13yFespTL9ygqRk6/YBB/4",
+	"CmcLq+vG/KY9zgzfpe+HYWinZCSE++kv1Rl4j9lUkuRoLRKxkqHRa7PxNEsH1adveppERwRF0PAG6X2u",
+	"4dz/JKW4llK/EJKK2Q/9Qknzjwb2rb5/H2hrcXsZv4n3iTH7jGwUplR5YHSMn3T1KqY9vc3U15xnH9BE",
+	"xP0enlAkW0rWe69gs7n2mj41q+E7MDOQtGX3bFmMBHVtdl1fqDp1XEHXFUoRLUlFRkLMTdfeF3K4mwto",
+	"ekl2i3Z1Zm/3J7Y90L0+kDw25yOMlEkvTQIH9LMErDX9kVZmZr2klrqpB08Y916SoUQrdo5Lw+yoM7PD",
+	"g6bFjQ+rlscakH0ZyUNIbrmM4koTZTKQzW+Rr8sfR8ULkzVkuqVP1BTPwIRxlXXPxCzZ5VvP09UJTuAo",
+	"Mi8tABppl420y/JbolKkOSF114+zFTg/6LNm5qWD4RAeQ6qQgnyF9chRjsX4Lzr5t+gFNfyI9d7V33tT",
+	"FZOSOUaaZzDaNBXUxbhuaar2gS/Y6uHbJZuHXe9RrwZ11yF1Fw+uFReU/r7IdGIEqdannopc/tebGt4F",
+	"D4Q6BYm4Vh3q3vZbd7GCyRFAq+9HQpQOjgqSuJ/TX1Qp49zz/GIomRT6OIPBpAHfuCzeHCwOCvxKfGcw",
+	"ZNZYf6JvbuazO3p60teF1/4ZHNn8k8LyfcfJ0NM434/CA67zaY30lD6iCz11fkgXzgUf07HThRsYQPZ7",
+	"L/XNTc+FXThX+ZWRupvBeHKwB9brKdKE1GRdMCGzsIzBnSgmlZav7WdbICHjYCfT/ZfzTZ+2/elTWgdO",
+	"slnoDM5VM0d+xXIZ6XjBWH9McjhV2qM8KNPjS0GMOhYrvw8zXitkHxR2xmhqvntZVwJO0NgWaQDFa2/I",
+	"evZ/YtKRm+l1Ev1X3OfhZPxsjF+o45trwYlU5qRXX1KVsZAxXsAkH+KJzEIGt4l9YFaxBT3ZVUAXThIp",
+	"Nz0DsPQjys2A7V5GihLkJXbckJV62rCx6txbUicNH4uhTsnESvaIIx3RWFi1Kgfs6BPphG/93q36MR4Q",
+	"bAvWK7HX3hT00XlVdaeOG23M0Gnwpd8/Xyo+usf/CJQWZVOF1Z9JLhtHPYFqnN0lmgpgvjxYQ7FGbwVp",
+	"JfbUraP2vJYevVVqDFJdqgcnPhyqxBQ33+8hrWx5hefipMjCEAeFJTlSR+mN3RSgjzu/sXhvZTigYild",
+	"jQzGP6QyEARTQz2ok5xGuvwhshkZ3DxWTbkhY9ZJOny/iJi3xvMXZZlsruv9W/r6rJ6ZLTJbrM6iuuck",
+	"aaAcnYJuMP/rtPHzQl1M0m1UNvtupzidQJLsq0lrVBe7SnWGXqWDQhhNMAKBFqUVJl1dhmfqPnPUPI5S",
+	"SVeQcVxiMYvSeEt0XbBUR3/UxEd4Zbm57aS6Rr5Ww9Ivl0csvKBy3OW3M1PrWa8paJh83zv3k9Z9qTPQ",
+	"vllNWabH3vt7mqraM++cGbvHPbmCAHEIPcGiJLrDwlPoZGUVG2jqGy29mM+90vBmfmVbH7/Hmko7UvRZ",
+	"+nluAgYF4zXzWafPk/BQZ1LZ8duAdcqS667CrWx1yOLADcOwITs+BtlB0P2EzEC6/OHZO0/JtXjyx9PX",
+	"ox6FwBHF1igfP95O/+R4/yHI8YDAvbP+0RIY7pk2rLc6YMbbkX38g6fzZWF9Wb9z24E3Yw2N/2S5dqC2",
+	"/Hvp819HjWA89FERlyfzJ6mMWmvXR29r+KGGn9J38bn+oMJVtkw9iwLDsv7UTS29oqVfA6P+MH7qaujM",
+	"1RCLI/oEAU9Dh5JbZ8QRSnfJn6wA2L2Kps74TAK8EqzeXxr83ar3TtX+JIVAW1C1ujeVoqFcN9j0iTlv",
+	"JhxT4NtPH6uUsBijj1nZPgnLA5Shx23STpZKlJPxDDE+W7VnyBYq9I0cocJ3l7f2EB4XmAJu8fIkVOk/",
+	"IkDmFsmgGiYqYlIEaXhCUKSYGAbnHcmvo4nglmpIMPkXAjopn1dVT5N2+Ad+B83bocj6NmngBOKkByWV",
+	"i729kqw4X0fZHVU/jR8n994vUCmmT00aDx7ZUox9Rc8sR2MKBzuZz69cuUTYzajZR+QdAKhmtfRz8gkd",
+	"kDRGhahMupcnTSFH5vO4mZY5QS5nBqA2Tre1sU0RQI7TXCvTvDpH7v0PbGOR/dJdHnPPe3vl4Mb3e5sp",
+	"Y/wFQ1B1htIUUI8bZQHRp5c1vMGQsO46ZFjUp79/rO9MAZKsPtXU25DlRr4i4xmY4Uitxo+1a0bJ0EXD",
+	"KjsMajroqFSTD3rY9BENrzBbzqwaJCLAJRSY79oMQpUvVL+nUVkSjE6YAtYj+e3mSIv7j0by8zm/IcYK",
+	"qz4589l/U1dbfDAadQgM57Mb+edbjkHkmYTQh7QUTshiGGl4o01L4fCgLMOdOZPg6ZRi+va9D0vGox0i",
+	"3h5SJQqIN4Xpb6CzInEFkY5PGdYaq1OhQpI7REmff11YyoKwh5bGGTL/2JSIJ2PLsjiaLcerM21hB165",
+	"asbj67Id1YvSi5WRUHActnQMyX2ohRDPf1VuV186CblvG/Qfh60OYT5G9vbcN8aPUpg0yvKqBY2OWXVu",
+	"vVvmcRE9xbzEhpFfOyPfo6Psp7N6ZtTqZvA/l7/6e9OXwBGbCGfjJ3CUUEZa+8WkIsnDJTpgWote+vqK",
+	"lsKXOq+c/5wKW4eilbNUpvztt8bIuB3oT+H8WIZwuW0Nz9HfE2eA2cjTVLf0sUlru+SRLLm+ZTOn12x0",
+	"SdnI0zFo22BO4jGvdcP8Mbv38tJLqIj+nJ3GRyWprx2x+DMPlZcERfBDf/XMWH9T1ym6JAHNdfwN8fUH",
+	"FF/HnQMYgP5B0sDiqPQRQEoH28yPZZhVdP8lnaJHfbO+eoIKZUPr9zIaEpOEd95oldEQkouMayNsO0dT",
+	"vQiAm/o0tOUv3JmFkfI+Q9IyMC2LkkkPh2lJ7vE5kRhUhoCdVsSQdMoZTVUtgU+FifvU1zyypZQRWpnV",
+	"SQA5WnuT48Z1yrJu6+666c19bFYoDz1LLGTj+5GavCdgcqozTvq0yQn/QWxPGiD1XHJDVNe7qGZX1rA3",
+	"jzhTqXiDAna+bqWExy+ClAlFFpL9ZfdIugK//oiaJJH98snTqtlodEWq/1bynMuqYGaJ4/G9zfXC1hp4",
+	"TJ31TB62B51BXlC93qfIE3oLpL1W0JeSrTICPdDV0c+vQXpoEVSIZDd7sFELWU0tZGCenv7huT6SbhRC",
+	"NnSkipjOmlUUeWJahIW5fLbn4HCuEkXyVPncC0ZnHop5wX/riXeVxRM8kHCZQ4Mi65Mi66QhGR+BStKo",
+	"60F1piiZAk0SHC5Vi+CBxp/I8deLV5ro61xUr+EcuBVOrFLBh2GL7p1smCvZwe2DnczZtjM0W4ZX6PA1",
+	"OTMfIzrhQoPyp1m3HcGawahrDUtulDE0mkfVS2/JhpfpEMdXfgEDo3mzxaVD9lB545A9Fbb1gR/W/Sw1",
+	"eMBVF2EJZ3Um/+tE/u4rMpriob9nR4DJK8ZiKCIKCuINh+yRpCgS4hX7uv4jJtwo0ivJMUGBN4pxgSxf",
+	"elqkEz3cfYAgq8Nuy7JojXPd20zpO1MHOxkZhZGYUE79i8wwxYAN5r+Jn8P8g856Nf8iYzvIH1Sl+I+Y",
+	"sMS9W6Scp9tuuSAmE1JSpBB7r0pQFCHcH0Nx5f829YpRBAf+56uhnn4hIbag6wlJVlqcGNryPXPtzD4z",
+	"5tQbp/4jJq6Gio7sbMirhrz6vfVCrryVUZaltllR/BS25vY4+h8vW22NzNz0FRd/rKrFkSlbgufm1aP0",
+	"uFYv+nkNuwg1+F2D3/2+er9XpeX6wjg2JzqhuqB+KYbOlygEqsS5czQdJ1xuqpqW69SpmVBH9TOwgROp",
+	"nylHJjUKaeoovakhwxo+prpqj2HJ3YrqZ4p7nloRmQY3hGSxl220eIjSI2EuwuP/63y6Pm2L05x5hWBo",
+	"LTUik7839nK83QDsWUfqYy09xvqS4I38463C6iTNdDrRqU0OsChMhBHaA5osNC8xyqn4e9QZ6NHimtfk",
+	"ZCzfoZ5+kqNVbsrkP9gDH+Mwf7b3clods5820inrP52Sc1UVzPpmgxzBtjVf5OhQm3//C9Qe4dz+5K/Q",
+	"eWJ6jfgWzZ7vNk2adFikuS7EGE6x/hYs5HBqMBFx/klDUdafvWJcTPajCKtKUGcKq+t7W6PE7gaAiVlN",
+	"AhSnZCSE+1EEnJ8pbGr9OdJyapGYsRnqzxyUoxrevPTV5SuWiexoqLvxz5ZzJBBxRYyhpCLEEnBK1vfq",
+	"zNXQqashYk48paeg4UfW/BcN5z7/svN8y+XPO0//6b81vGK+7bLYFxeUQRnBgbMTZYs7D/hgJ5NEYRmR",
+	"3h94g16NMadSw754/VI9s7Xap15YjOx4e/26lg0iQmvgfj1W4BL0n+hXlISWwvC/JEFmWuSNjYVVfeOD",
+	"vkvavqlPtfQc5XeNop+6ZvsWxnHZvs3VbV6fwi7GznGoOvh5oJrVGkFCJIoUhTmJyle5Ljge/Ni0rwso",
+	"Kg5BskEZWhiJ4adN9XiX5SU2FLL6pszit8YlUijAfr5M+n+a3eDx2j6+y4IOZMQ+jKbxvbm8cvVKqbn1",
+	"+wjD0i5Sr67Q9ibBzpmiJH7Belc3eVNdphDRMy69nn0wtXcM2VgAsytmNXxnb/uBhu9AbXEjyNvwV1XC",
+	"cE4qkcVmZEFWqA9WdYYSn/5hRMNLrgYV9ts8M7vL4mjfs4/LSnT0s7B/mE/XJ8eyFcASC37n2Ec1HIsz",
+	"qMqGoTGUsMGvKjZdTohJcbC2qOlEEi40/BOYS8GqmLOxAvzb29zWybJgGRQelEVlmPCTzoT4NzQMrCfU",
+	"8c01oL0kkoeCuA3pX66uaem1/MxL/XE61BwalKOhjhBY9B2trVEpLET7paTS8Wnbp22tQ+0hbruY/L1V",
+	"zvPJjtbWpBBLRNGpsBQjD1+zNuGDRf2V+OmnKefLzz8pLN+3OU8/EqJK//l+FB7ggOBkm9Q2dTPJEo94",
+	"8+jMDnj2S2igwP8Wz4B3+wFzTLT/EZZk6X+EJkvzD9g1LNAPHu37EFSY3XmpS8PjmjoGLzIDsN7V2dRe",
+	"/zvy6pYxvwu4CdeTKQaGLCiIe0qr6wAJTSot8jxJEOeB8HyysLoOWHH7rfEac86uRwgPRKU+3nG7y/nZ",
+	"0A5PfZ4JkFVvx15Ly+2K3YjpH95PPcwvQtvl/NqGsbBGxrDl9OkJY2GRurfZG9EQsJZQMRlsOleyliFB",
+	"WUqg1YZzNNrlk9NJLt7bcbVCdp3kgmWNhYzxYol4h618X8agdLxgrD+2Xw3Bct4xP72/nwasJpMONkBw",
+	"pmcBXVLYpOwf4SvSNH8/9cSYhqS3vfe7Gh7VU+N7W1v5u1lAVHOkQn5uq7A04SDjhDiAhpMlKJkE/Fi1",
+	"goY3nTdktWnLzz8xFreB96kvXXcjhBVxCPgoBwNzS/np0c5LXeQSXOvRcdjQM21+tJCFDdvyMYX1nd/0",
+	"zFu6GJGky9QLCg578k7ow4ZzQiQmxsnR0ckWEAHgHguEam2A4anQjWs3/t8AESth+Ux1AQA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,1312 @@
+// Package apigenv2 provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package apigenv2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
+)
+
+// Defines values for BookBookStatus.
+const (
+	Bought  BookBookStatus = "bought"
+	Read    BookBookStatus = "read"
+	Reading BookBookStatus = "reading"
+)
+
+// Defines values for ChartLabel.
+const (
+	ChartLabelPages   ChartLabel = "pages"
+	ChartLabelPrice   ChartLabel = "price"
+	ChartLabelVolumes ChartLabel = "volumes"
+)
+
+// Defines values for GoalKind.
+const (
+	GoalKindBooks GoalKind = "books"
+	GoalKindPages GoalKind = "pages"
+	GoalKindSpend GoalKind = "spend"
+)
+
+// Defines values for GoalPeriod.
+const (
+	GoalPeriodMonthly GoalPeriod = "monthly"
+	GoalPeriodYearly  GoalPeriod = "yearly"
+)
+
+// Defines values for GoalProgressKind.
+const (
+	Books GoalProgressKind = "books"
+	Pages GoalProgressKind = "pages"
+	Spend GoalProgressKind = "spend"
+)
+
+// Defines values for GoalProgressPeriod.
+const (
+	GoalProgressPeriodMonthly GoalProgressPeriod = "monthly"
+	GoalProgressPeriodYearly  GoalProgressPeriod = "yearly"
+)
+
+// Defines values for GoalProgressStatus.
+const (
+	Achieved GoalProgressStatus = "achieved"
+	Behind   GoalProgressStatus = "behind"
+	Exceeded GoalProgressStatus = "exceeded"
+	OnTrack  GoalProgressStatus = "on_track"
+)
+
+// Backlog defines model for Backlog.
+type Backlog struct {
+	// Currency 購入額の通貨（ユーザーの基準通貨）
+	Currency string `json:"currency"`
+
+	// DaysToClear 現在の読了ペースで積読を解消するまでの日数（読了ペースが0の場合は省略）
+	DaysToClear *int `json:"daysToClear,omitempty"`
+
+	// Months 月ごとの積読の推移
+	Months []BacklogMonth `json:"months"`
+
+	// OldestUnread 購入日の古い未読の本（最大5冊）
+	OldestUnread []Book `json:"oldestUnread"`
+
+	// ReadPerDay 直近90日の1日あたりの読了冊数
+	ReadPerDay float64 `json:"readPerDay"`
+
+	// UnreadCosts 未読の本の購入額（補助単位）
+	UnreadCosts int `json:"unreadCosts"`
+
+	// UnreadVolumes 未読（bought、reading）の冊数
+	UnreadVolumes int `json:"unreadVolumes"`
+}
+
+// BacklogMonth defines model for BacklogMonth.
+type BacklogMonth struct {
+	// Backlog その月末時点の積読冊数（購入冊数の累計 - 読了冊数の累計）
+	Backlog int `json:"backlog"`
+
+	// Bought その月の購入冊数
+	Bought int `json:"bought"`
+
+	// Month 各データの月
+	Month int `json:"month"`
+
+	// Read その月の読了冊数
+	Read int `json:"read"`
+
+	// Year 各データの年
+	Year int `json:"year"`
+}
+
+// Book defines model for Book.
+type Book struct {
+	// AuthUserId ユーザーの識別子
+	AuthUserId string `json:"authUserId,omitempty"`
+
+	// Author 本の著者
+	Author string `json:"author,omitempty"`
+
+	// BookStatus 本の状態
+	BookStatus BookBookStatus `json:"bookStatus" validate:"required,oneof=bought reading read"`
+
+	// CreatedAt 本の作成日時
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+
+	// Currency 本の価格の通貨（ISO 4217）
+	Currency string `json:"currency,omitempty"`
+
+	// DeletedAt 本の削除日時（ゴミ箱内のみ）
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
+	// Id 本の識別子
+	Id int64 `json:"id,omitempty"`
+
+	// ImageURL 本の画像
+	ImageURL string `json:"imageURL,omitempty"`
+
+	// Isbn10 本のisbn10
+	Isbn10 string `json:"isbn10,omitempty"`
+
+	// Page 本のページ数
+	Page int `json:"page" validate:"gte=0"`
+
+	// Price 本の価格（補助単位）
+	Price int `json:"price" validate:"gte=0"`
+
+	// Title 本の書名
+	Title string `json:"title" validate:"required"`
+
+	// UpdatedAt 本の更新日時
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+
+	// Version 本のバージョン（更新ごとに1増える。If-Matchに指定する）
+	Version int64 `json:"version,omitempty"`
+}
+
+// BookBookStatus 本の状態
+type BookBookStatus string
+
+// BookCreated defines model for BookCreated.
+type BookCreated struct {
+	Book Book `json:"book"`
+
+	// Goals 上限を超過した目標の進捗
+	Goals []GoalProgress `json:"goals"`
+
+	// SpendCapExceeded 購入額の上限を超過したか
+	SpendCapExceeded bool `json:"spendCapExceeded"`
+}
+
+// Chart defines model for Chart.
+type Chart struct {
+	// Currency 購入額の通貨（priceのみ。ユーザーの基準通貨）
+	Currency string `json:"currency,omitempty"`
+
+	// Data 各データ内容（priceの場合は補助単位の金額）
+	Data int `json:"data"`
+
+	// Label チャートの種類（price：購入額、volumes：購入冊数、pages：購入ページ数）
+	Label ChartLabel `json:"label"`
+
+	// Month 各データの月
+	Month int `json:"month"`
+
+	// Year 各データの年
+	Year int `json:"year"`
+}
+
+// ChartLabel チャートの種類（price：購入額、volumes：購入冊数、pages：購入ページ数）
+type ChartLabel string
+
+// ExchangeRate defines model for ExchangeRate.
+type ExchangeRate struct {
+	// Currency 通貨コード（ISO 4217）
+	Currency string `json:"currency" validate:"required"`
+
+	// Rate 1単位あたりの円換算額
+	Rate float64 `json:"rate" validate:"required,gt=0"`
+
+	// UpdatedAt レートの更新日時
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	// Code 検証の種類（例.required、email、oneof）
+	Code string `json:"code"`
+
+	// Field 項目名（jsonのキー、クエリ名）
+	Field string `json:"field"`
+
+	// Message エラーメッセージ（Accept-Languageの言語）
+	Message string `json:"message"`
+}
+
+// Goal defines model for Goal.
+type Goal struct {
+	// Currency 購入額の上限の通貨（spendのみ。省略時はユーザーの基準通貨）
+	Currency string `json:"currency,omitempty"`
+
+	// Id 目標の識別子
+	Id int64 `json:"id,omitempty"`
+
+	// Kind 目標の種類
+	Kind GoalKind `json:"kind" validate:"required,oneof=books pages spend"`
+
+	// Period 目標の期間
+	Period GoalPeriod `json:"period" validate:"required,oneof=yearly monthly"`
+
+	// Target 目標値（spendの場合は補助単位の購入額の上限）
+	Target int `json:"target" validate:"required,gt=0"`
+}
+
+// GoalKind 目標の種類
+type GoalKind string
+
+// GoalPeriod 目標の期間
+type GoalPeriod string
+
+// GoalProgress defines model for GoalProgress.
+type GoalProgress struct {
+	// Currency 購入額の上限の通貨（spendのみ。省略時はユーザーの基準通貨）
+	Currency string `json:"currency,omitempty"`
+
+	// Current 期間内の実績
+	Current int `json:"current"`
+
+	// DaysLeft 期間終了までの残り日数
+	DaysLeft int `json:"daysLeft"`
+
+	// Expected 期間の経過割合から見た現時点の目安
+	Expected int `json:"expected"`
+
+	// Id 目標の識別子
+	Id int64 `json:"id,omitempty"`
+
+	// Kind 目標の種類
+	Kind GoalProgressKind `json:"kind" validate:"required,oneof=books pages spend"`
+
+	// PacePerDay 期間内に達成するために必要な1日あたりのペース
+	PacePerDay float64 `json:"pacePerDay"`
+
+	// Period 目標の期間
+	Period GoalProgressPeriod `json:"period" validate:"required,oneof=yearly monthly"`
+
+	// PeriodEnd 期間の終了日時
+	PeriodEnd time.Time `json:"periodEnd"`
+
+	// PeriodStart 期間の開始日時
+	PeriodStart time.Time `json:"periodStart"`
+
+	// Remaining 目標までの残り（spendの場合は残りの予算）
+	Remaining int `json:"remaining"`
+
+	// Status 進捗の状況
+	Status GoalProgressStatus `json:"status"`
+
+	// Target 目標値（spendの場合は補助単位の購入額の上限）
+	Target int `json:"target" validate:"required,gt=0"`
+}
+
+// GoalProgressKind 目標の種類
+type GoalProgressKind string
+
+// GoalProgressPeriod 目標の期間
+type GoalProgressPeriod string
+
+// GoalProgressStatus 進捗の状況
+type GoalProgressStatus string
+
+// Health defines model for Health.
+type Health struct {
+	// Message ok
+	Message string `json:"message"`
+}
+
+// Problem RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type Problem struct {
+	// Code 機械可読なエラーコード（例.user_not_found、validation_failed）
+	Code string `json:"code"`
+
+	// Detail エラーの詳細（Accept-Languageの言語）
+	Detail string `json:"detail,omitempty"`
+
+	// Errors 項目ごとの入力エラー
+	Errors []FieldError `json:"errors,omitempty"`
+
+	// Instance リクエストのパス
+	Instance string `json:"instance,omitempty"`
+
+	// Message detailと同じ（v1との互換のため）
+	// Deprecated:
+	Message string `json:"message,omitempty"`
+
+	// Status HTTPステータス
+	Status int `json:"status"`
+
+	// Title ステータスの概要（Accept-Languageの言語）
+	Title string `json:"title"`
+
+	// Type エラーの種類のURI（例.urn:bhapi:problem:user_not_found）
+	Type string `json:"type"`
+}
+
+// Record defines model for Record.
+type Record struct {
+	// Costs 購入額の総計（補助単位）
+	Costs int `json:"costs"`
+
+	// CostsRead 購入額のうち読了分（補助単位）
+	CostsRead int `json:"costsRead"`
+
+	// Currency 購入額の通貨（ユーザーの基準通貨）
+	Currency string `json:"currency"`
+
+	// Pages 購入ページ数の総計
+	Pages int `json:"pages"`
+
+	// PagesRead 購入ページ数のうち読了分
+	PagesRead int `json:"pagesRead"`
+
+	// Volumes 購入冊数の総計
+	Volumes int `json:"volumes"`
+
+	// VolumesRead 購入冊数のうち読了分
+	VolumesRead int `json:"volumesRead"`
+}
+
+// SearchResult defines model for SearchResult.
+type SearchResult struct {
+	// Author 本の著者
+	Author string `json:"author,omitempty"`
+
+	// Currency 本の価格の通貨（ISO 4217）
+	Currency string `json:"currency"`
+
+	// ImageURL 本の画像
+	ImageURL string `json:"imageURL,omitempty"`
+
+	// Isbn10 本のisbn10
+	Isbn10 string `json:"isbn10,omitempty"`
+
+	// Page 本のページ数
+	Page int `json:"page"`
+
+	// Price 本の価格（補助単位）
+	Price int `json:"price"`
+
+	// Title 本の書名
+	Title string `json:"title"`
+}
+
+// Trash defines model for Trash.
+type Trash struct {
+	// Books 削除済みの本
+	Books []Book `json:"books"`
+
+	// RetentionDays 削除から完全に削除されるまでの日数
+	RetentionDays int   `json:"retentionDays"`
+	User          *User `json:"user,omitempty"`
+}
+
+// User defines model for User.
+type User struct {
+	// AuthUserId フロントユーザーの識別子
+	AuthUserId string `json:"authUserId" validate:"required"`
+
+	// CreatedAt ユーザーの作成日時
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+
+	// DeletedAt ユーザーの削除日時（ゴミ箱内のみ）
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
+	// Email ユーザーemail
+	Email openapi_types.Email `json:"email" validate:"required,email"`
+
+	// HomeCurrency 記録や図表の金額を表示する通貨（ISO 4217。省略時はJPY）
+	HomeCurrency string `json:"homeCurrency,omitempty"`
+
+	// Id バックユーザーの識別子
+	Id int64 `json:"id,omitempty"`
+
+	// Name ユーザー名
+	Name string `json:"name,omitempty"`
+
+	// Password パスワード（あれば）
+	Password string `json:"password,omitempty" validate:"omitempty,gte=8,lte=20"`
+
+	// UpdatedAt ユーザーの更新日時
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+
+	// Version ユーザーのバージョン（更新ごとに1増える）
+	Version int64 `json:"version,omitempty"`
+}
+
+// AuthUserId defines model for AuthUserId.
+type AuthUserId = string
+
+// BookIds defines model for BookIds.
+type BookIds = []int64
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
+// BadRequest RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type BadRequest = Problem
+
+// Conflict RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type Conflict = Problem
+
+// Forbidden RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type Forbidden = Problem
+
+// InternalServerError RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type InternalServerError = Problem
+
+// NotFound RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type NotFound = Problem
+
+// PreconditionFailed RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type PreconditionFailed = Problem
+
+// Unauthorized RFC 7807形式のエラー（application/problem+json）。クライアントはcodeで分岐する。
+type Unauthorized = Problem
+
+// GetChartsAuthUserIdParams defines parameters for GetChartsAuthUserId.
+type GetChartsAuthUserIdParams struct {
+	// IfNoneMatch 取得済みのETag。一致する場合は304
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// GetRecordsAuthUserIdParams defines parameters for GetRecordsAuthUserId.
+type GetRecordsAuthUserIdParams struct {
+	// IfNoneMatch 取得済みのETag。一致する場合は304
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// GetSearchParams defines parameters for GetSearch.
+type GetSearchParams struct {
+	// Q 検索文字列
+	Q string `form:"q" json:"q"`
+}
+
+// DeleteShelfAuthUserIdParams defines parameters for DeleteShelfAuthUserId.
+type DeleteShelfAuthUserIdParams struct {
+	// BookId 本の識別子の一覧
+	BookId BookIds `form:"bookId" json:"bookId"`
+}
+
+// GetShelfAuthUserIdParams defines parameters for GetShelfAuthUserId.
+type GetShelfAuthUserIdParams struct {
+	// IfNoneMatch 取得済みのETag。一致する場合は304
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// PutShelfAuthUserIdBookIdParams defines parameters for PutShelfAuthUserIdBookId.
+type PutShelfAuthUserIdBookIdParams struct {
+	// IfMatch 更新の前提とするバージョン（ETag）。一致しない場合は412。任意（オプトイン）で、省略した場合、"*"の場合はバージョンを確認せずに上書きする（後の更新が優先）。他の更新を上書きしたくないクライアントは必ず指定する
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PostTrashAuthUserIdBooksRestoreParams defines parameters for PostTrashAuthUserIdBooksRestore.
+type PostTrashAuthUserIdBooksRestoreParams struct {
+	// BookId 本の識別子の一覧
+	BookId BookIds `form:"bookId" json:"bookId"`
+}
+
+// DeleteUsersAuthUserIdParams defines parameters for DeleteUsersAuthUserId.
+type DeleteUsersAuthUserIdParams struct {
+	// Immediate trueの場合はゴミ箱を経由せずに完全に削除
+	Immediate *bool `form:"immediate,omitempty" json:"immediate,omitempty"`
+}
+
+// PutUsersAuthUserIdParams defines parameters for PutUsersAuthUserId.
+type PutUsersAuthUserIdParams struct {
+	// IfMatch 更新の前提とするバージョン（ETag）。一致しない場合は412。任意（オプトイン）で、省略した場合、"*"の場合はバージョンを確認せずに上書きする（後の更新が優先）。他の更新を上書きしたくないクライアントは必ず指定する
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PostAuthRegisterJSONRequestBody defines body for PostAuthRegister for application/json ContentType.
+type PostAuthRegisterJSONRequestBody = User
+
+// PutGoalsAuthUserIdJSONRequestBody defines body for PutGoalsAuthUserId for application/json ContentType.
+type PutGoalsAuthUserIdJSONRequestBody = Goal
+
+// PostShelfAuthUserIdJSONRequestBody defines body for PostShelfAuthUserId for application/json ContentType.
+type PostShelfAuthUserIdJSONRequestBody = Book
+
+// PutShelfAuthUserIdBookIdJSONRequestBody defines body for PutShelfAuthUserIdBookId for application/json ContentType.
+type PutShelfAuthUserIdBookIdJSONRequestBody = Book
+
+// PutUsersAuthUserIdJSONRequestBody defines body for PutUsersAuthUserId for application/json ContentType.
+type PutUsersAuthUserIdJSONRequestBody = User
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// user情報の登録
+	// (POST /auth/register)
+	PostAuthRegister(ctx echo.Context) error
+	// ユーザーごとに積読の推移、未読の購入額、古い未読の本、解消までの見込み日数を返す
+	// (GET /backlog/{authUserId})
+	GetBacklogAuthUserId(ctx echo.Context, authUserId AuthUserId) error
+	// ユーザーごとにチャートデータを返す
+	// (GET /charts/{authUserId})
+	GetChartsAuthUserId(ctx echo.Context, authUserId AuthUserId, params GetChartsAuthUserIdParams) error
+	// ユーザーごとに目標の進捗を返す
+	// (GET /goals/{authUserId})
+	GetGoalsAuthUserId(ctx echo.Context, authUserId AuthUserId) error
+	// ユーザーごとに目標を登録、更新（種類と期間の組み合わせごとに1件）
+	// (PUT /goals/{authUserId})
+	PutGoalsAuthUserId(ctx echo.Context, authUserId AuthUserId) error
+	// ユーザーごとに目標を削除
+	// (DELETE /goals/{authUserId}/{goalId})
+	DeleteGoalsAuthUserIdGoalId(ctx echo.Context, authUserId AuthUserId, goalId int64) error
+	// サーバーの監視
+	// (GET /health)
+	GetHealth(ctx echo.Context) error
+	// DBサーバーの監視
+	// (GET /health/db)
+	GetHealthDb(ctx echo.Context) error
+	// 為替レートの一覧を返す
+	// (GET /rates)
+	GetRates(ctx echo.Context) error
+	// ユーザーごとに記録を返す
+	// (GET /records/{authUserId})
+	GetRecordsAuthUserId(ctx echo.Context, authUserId AuthUserId, params GetRecordsAuthUserIdParams) error
+	// 書籍の検索結果を取得
+	// (GET /search)
+	GetSearch(ctx echo.Context, params GetSearchParams) error
+	// ユーザーごとに本棚の本を複数削除（ゴミ箱へ移動し、保持期間後に完全に削除）
+	// (DELETE /shelf/{authUserId})
+	DeleteShelfAuthUserId(ctx echo.Context, authUserId AuthUserId, params DeleteShelfAuthUserIdParams) error
+	// ユーザーごとに本棚を取得
+	// (GET /shelf/{authUserId})
+	GetShelfAuthUserId(ctx echo.Context, authUserId AuthUserId, params GetShelfAuthUserIdParams) error
+	// ユーザーごとに本を本棚に1冊ずつ作成
+	// (POST /shelf/{authUserId})
+	PostShelfAuthUserId(ctx echo.Context, authUserId AuthUserId) error
+	// 本棚の本を1冊更新
+	// (PUT /shelf/{authUserId}/{bookId})
+	PutShelfAuthUserIdBookId(ctx echo.Context, authUserId AuthUserId, bookId int64, params PutShelfAuthUserIdBookIdParams) error
+	// ユーザーごとにゴミ箱の中身（削除済みのユーザー、本）を返す
+	// (GET /trash/{authUserId})
+	GetTrashAuthUserId(ctx echo.Context, authUserId AuthUserId) error
+	// ゴミ箱の本を複数復元
+	// (POST /trash/{authUserId}/books/restore)
+	PostTrashAuthUserIdBooksRestore(ctx echo.Context, authUserId AuthUserId, params PostTrashAuthUserIdBooksRestoreParams) error
+	// ゴミ箱のユーザーを復元
+	// (POST /trash/{authUserId}/user/restore)
+	PostTrashAuthUserIdUserRestore(ctx echo.Context, authUserId AuthUserId) error
+	// ユーザーと本棚、図表、目標をまとめて削除（既定ではゴミ箱へ移動し、保持期間後に完全に削除）
+	// (DELETE /users/{authUserId})
+	DeleteUsersAuthUserId(ctx echo.Context, authUserId AuthUserId, params DeleteUsersAuthUserIdParams) error
+	// ユーザー情報を返す
+	// (GET /users/{authUserId})
+	GetUsersAuthUserId(ctx echo.Context, authUserId AuthUserId) error
+	// ユーザー情報を更新（passwordは指定した場合のみ更新）
+	// (PUT /users/{authUserId})
+	PutUsersAuthUserId(ctx echo.Context, authUserId AuthUserId, params PutUsersAuthUserIdParams) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// PostAuthRegister converts echo context to params.
+func (w *ServerInterfaceWrapper) PostAuthRegister(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostAuthRegister(ctx)
+	return err
+}
+
+// GetBacklogAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBacklogAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetBacklogAuthUserId(ctx, authUserId)
+	return err
+}
+
+// GetChartsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetChartsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetChartsAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetChartsAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetGoalsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGoalsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGoalsAuthUserId(ctx, authUserId)
+	return err
+}
+
+// PutGoalsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutGoalsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutGoalsAuthUserId(ctx, authUserId)
+	return err
+}
+
+// DeleteGoalsAuthUserIdGoalId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteGoalsAuthUserIdGoalId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	// ------------- Path parameter "goalId" -------------
+	var goalId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "goalId", ctx.Param("goalId"), &goalId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter goalId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteGoalsAuthUserIdGoalId(ctx, authUserId, goalId)
+	return err
+}
+
+// GetHealth converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealth(ctx)
+	return err
+}
+
+// GetHealthDb converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealthDb(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealthDb(ctx)
+	return err
+}
+
+// GetRates converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRates(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRates(ctx)
+	return err
+}
+
+// GetRecordsAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRecordsAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRecordsAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRecordsAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetSearch converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSearch(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSearchParams
+	// ------------- Required query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSearch(ctx, params)
+	return err
+}
+
+// DeleteShelfAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteShelfAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteShelfAuthUserIdParams
+	// ------------- Required query parameter "bookId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "bookId", ctx.QueryParams(), &params.BookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteShelfAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetShelfAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetShelfAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetShelfAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetShelfAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// PostShelfAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PostShelfAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostShelfAuthUserId(ctx, authUserId)
+	return err
+}
+
+// PutShelfAuthUserIdBookId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutShelfAuthUserIdBookId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	// ------------- Path parameter "bookId" -------------
+	var bookId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "bookId", ctx.Param("bookId"), &bookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutShelfAuthUserIdBookIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutShelfAuthUserIdBookId(ctx, authUserId, bookId, params)
+	return err
+}
+
+// GetTrashAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTrashAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetTrashAuthUserId(ctx, authUserId)
+	return err
+}
+
+// PostTrashAuthUserIdBooksRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTrashAuthUserIdBooksRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTrashAuthUserIdBooksRestoreParams
+	// ------------- Required query parameter "bookId" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "bookId", ctx.QueryParams(), &params.BookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bookId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTrashAuthUserIdBooksRestore(ctx, authUserId, params)
+	return err
+}
+
+// PostTrashAuthUserIdUserRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostTrashAuthUserIdUserRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostTrashAuthUserIdUserRestore(ctx, authUserId)
+	return err
+}
+
+// DeleteUsersAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteUsersAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUsersAuthUserIdParams
+	// ------------- Optional query parameter "immediate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "immediate", ctx.QueryParams(), &params.Immediate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter immediate: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteUsersAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// GetUsersAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUsersAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUsersAuthUserId(ctx, authUserId)
+	return err
+}
+
+// PutUsersAuthUserId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutUsersAuthUserId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "authUserId" -------------
+	var authUserId AuthUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "authUserId", ctx.Param("authUserId"), &authUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter authUserId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutUsersAuthUserIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutUsersAuthUserId(ctx, authUserId, params)
+	return err
+}
+
+// This is a simple interface which specifies echo.Route addition functions which
+// are present on both echo.Echo and echo.Group, since we want to allow using
+// either of them for path registration
+type EchoRouter interface {
+	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterHandlers adds each server route to the EchoRouter.
+func RegisterHandlers(router EchoRouter, si ServerInterface) {
+	RegisterHandlersWithBaseURL(router, si, "")
+}
+
+// Registers handlers, and prepends BaseURL to the paths, so that the paths
+// can be served under a prefix.
+func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+
+	wrapper := ServerInterfaceWrapper{
+		Handler: si,
+	}
+
+	router.POST(baseURL+"/auth/register", wrapper.PostAuthRegister)
+	router.GET(baseURL+"/backlog/:authUserId", wrapper.GetBacklogAuthUserId)
+	router.GET(baseURL+"/charts/:authUserId", wrapper.GetChartsAuthUserId)
+	router.GET(baseURL+"/goals/:authUserId", wrapper.GetGoalsAuthUserId)
+	router.PUT(baseURL+"/goals/:authUserId", wrapper.PutGoalsAuthUserId)
+	router.DELETE(baseURL+"/goals/:authUserId/:goalId", wrapper.DeleteGoalsAuthUserIdGoalId)
+	router.GET(baseURL+"/health", wrapper.GetHealth)
+	router.GET(baseURL+"/health/db", wrapper.GetHealthDb)
+	router.GET(baseURL+"/rates", wrapper.GetRates)
+	router.GET(baseURL+"/records/:authUserId", wrapper.GetRecordsAuthUserId)
+	router.GET(baseURL+"/search", wrapper.GetSearch)
+	router.DELETE(baseURL+"/shelf/:authUserId", wrapper.DeleteShelfAuthUserId)
+	router.GET(baseURL+"/shelf/:authUserId", wrapper.GetShelfAuthUserId)
+	router.POST(baseURL+"/shelf/:authUserId", wrapper.PostShelfAuthUserId)
+	router.PUT(baseURL+"/shelf/:authUserId/:bookId", wrapper.PutShelfAuthUserIdBookId)
+	router.GET(baseURL+"/trash/:authUserId", wrapper.GetTrashAuthUserId)
+	router.POST(baseURL+"/trash/:authUserId/books/restore", wrapper.PostTrashAuthUserIdBooksRestore)
+	router.POST(baseURL+"/trash/:authUserId/user/restore", wrapper.PostTrashAuthUserIdUserRestore)
+	router.DELETE(baseURL+"/users/:authUserId", wrapper.DeleteUsersAuthUserId)
+	router.GET(baseURL+"/users/:authUserId", wrapper.GetUsersAuthUserId)
+	router.PUT(baseURL+"/users/:authUserId", wrapper.PutUsersAuthUserId)
+
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+x9a1PbSLrwX6H07qd9TQyE3Z1waj5kkpnZ7M7sUrmcU3uSnClhN6CNLXkkmQ2Tosot",
+	"B2JuC8MmIQnMZEgIEAgmF5IlCQn/5TSSzSf+wqmnW5IluWUMNoaamS8JttXdT/dzv7VuCDElmVJkJOua",
+	"0HFD6EViHKn0z88vij3wfxxpMVVK6ZIiCx2COTRo5t8SnCfZSZLdJMYGyS6S7CuSMazZZySDSXaBfv8G",
+	"/sVrgcd2N3PbH0dPXBFOXhF2N4dJBm9vZIoLiwSveWaeINksMf5Nsk+EiKDFelFSBEj0/hQSOgRNVyW5",
+	"RxgYGIgIKVEVk0i3QT6d1nsvaUg9Fy8H3A9Xvrh6z8w9MVcnhYggwe8pUe8VIoIsJmENsTRTRFDRt2lJ",
+	"RXGhQ1fTqBJEEeEzRbl2Lq6Vrw+n41mW4DzbubP+t2mk9pcA6KLzVFxc0lGSLtStqElRFzoESdZ/3y5E",
+	"HLAkWUc9SBUGIkJSks+xx1vdn0VVFfsp0Oe6vxb1WC8H6Jl16+5zgvPm8Lg1MUnwEsH3iTFajlWgFopQ",
+	"A7Z1a53gaYKXCb5p/rRuTuYIXmtvbYNf37+3bk7sbuaIsUyy0ySbI8Y8nWKY4EWSwYVZXLjzhA5/aI/N",
+	"4CvCb68IAIYzWZD6jKnCo3fF5XGCZwh+QPDK9saINbNB8DgDeHczZ34cIzjv7GjMvLlsDuZskN/fLf1k",
+	"THnGAhQET7CtEGONZJ8CvMYjWDQLkJhbgwQ/sMZumfkHbC0Ho4yXSig9193MjrkyBZ3r/osioxCEmBN3",
+	"zY/T1kaO4C2C83DqniOH1d0jOtnSXgESWKMKcAaAALWUImuIktpnYvw8+jaNNB0+xRRZRzL9U0ylElJM",
+	"BDCjKVXpSqDk//+7BjDf8Ez/GxV1Cx3C/4uWpE6U/apFO9kotqh/19sb49bqY0BCdhmQYCwR4y3J5oCy",
+	"zyhyd0KKNRQeOGm8SPCKuXrPnF0CqYc/UkpZOxdHyZSiIznW3/xn1E/wWGHljTlJQf1CUbukeBzJDYXV",
+	"eESMFWIsOBQ7Vrg5Z468NcfuEnyHGGMEL1DaHgUQz8k6UmUxcQGpfUj9XFUVtZHAmrcWCpNDcLDzL6w7",
+	"0wDRXxT9CyUtxxsKxtrH4os5yvQODF8rcalbQhzF4mMmKnmAGUHgOArNnB+2ZtbZdLubw0KEp2Z5oNqP",
+	"RekzFM5OFcUUOS7B2l+IUgI19FwcCcZR/3gsIPdB03vFKl5ijAAHMBARLsmgYhVV+q6xWygujxeXNkH+",
+	"bw0WFzCVuPYwJt9i1xIKRUhKVVJI1SUm+GJpVQWmLsd/8dWmOfhkZ26c4PxO5kHx5RJoN7+1YT58Z727",
+	"6/w6LEQCgjYiXG/uUZrhy2btmpRqVujsYqI5pYAWV5n2h92I/dpF5UwCiWo5KIWJjyCOcL64vLr9bohk",
+	"H1Ag3hK8WHg6XlxeJcZUcfGx9SZnq3GQWouAo+kn1p3nu5u5soFjLV61y5SzbweOkeHZgtAh/FaS9X3s",
+	"KqnIei/XaMoRfJuaHXl7Bzhv/XOpsPheiJQMoEq0YGP0a1gBCM9n/FQPoZKII02/JKtIjIfRgDX9BA5r",
+	"Yp7gm9bssg3t7LPdzZw1mzHnF39nDo2ww6sOdEW5VgPIAGonUs+KHKItzKwXt74/1cJAbqX/GaDBjBGX",
+	"fMyhEevOcyFSsi/jSrorgUq4l9PJLqTuA6Q0Pb8ziqZzkV06MgDC4Ssgy8ez5shTc/ze9ofxvaivKhD+",
+	"U0mkkygUiN3NXJeS7unVSQbD45JMjVucd8/kgOsPeA36yw7dB6HyH1SkJHwCdOjD8VUXKKXr7yimA+X4",
+	"iL9MpnWVhF3QvvmBoiFnza5Y942C8dblP3YAgBKKHvYRfl1fKy7lmpqbvLTjfl8jzhguKsDpkkut+LFF",
+	"EcfwnrxJsreoVNxiawoRISlel5LppNDR2kZdLPtDDavzxYtvn37ePPBK/VwNEtim+Xa9brROF3TO18Wp",
+	"veWIS4xcMgZBWEa+4sG8fVjvr3Ki33GoD6qJmf0S6uh/P13MDNag6CEAcEEX9XRoLKEw8sYaBG8TyUB2",
+	"l/1HylaDv4SrB4QCHlPElNQcU+KoB8nN6Lquis262ENh6hMTUlzUYV4HzxFFRkr3pwyQJhsM+j81tGIq",
+	"EnUUP62HbWn7w6yVmwRr5L7hUzyijpp1KYnqiL9wi86G5eOc9dOm16g7d+GvTe1trX9g0iwl6jpS4fn/",
+	"uXy6+b+v3jg58JtaDDuUQBXPxhwe2bk/z86Gxk/WSfZhIf/CHBoEexxvMbBqOzMw3WDMiYtsYLXQS/E9",
+	"I15CpDxQFQLX/qWZlBR70KXzX4Xyyu33ZnaiBvRIWpfc2hI2vf3rwadPiT0obHLHGt9gEt/VMy0HPa/q",
+	"+bpHR5+2UNZNqVIMVWYVrp12NNDqkp4Ihdaa2TAnx4WGCUUKUToVryz6mJvcGNHXh1RNUuQwUMqju44P",
+	"z1yxlVbz0TTBOfAgM0YpKLDijYIGpFGdOT5gW0hxwUG6zUsOxfoUacQf13eOwauYvJgKM0XOsKc5BrVt",
+	"p1Tj1PUoYoKj2rc3RnbuT4Kf/mZwB/+TRaALM3lr6T5VRS+t8elq/ccvFTHRqSo9KtK0GvxILYXk+Bkx",
+	"9fn1GEJxFK8cAuFugODREr91KUoCifJBcU0PmQOVc6Q8rJ3pFVW95qAOpSima0nGOMQQjy5WtsxZZNED",
+	"kRug8QpgAP3W99SBrsn7SohdKMEzsTHJPgaAIKqcLyzld+Z+dGDa3XxQOsIM7mOOrfut7R5mMPBq6Wuv",
+	"pmNAO6atw8x9rodMRx7YtD1yR6/h7hfDYiTohlFi47HM59djvaLcg85TZVY95zAOIMYrShnDh2o2H1AX",
+	"q/aW/IC3OlxTioOZQ0PWxEwhP70zB/YCuh5LpDWpD33tUAADojw+xrF69hcrO4Dr1aPbxk8FU4Nkn7kM",
+	"2ziDI0CJnmgWxcReKvcLCSXibioqQIdKnGfnzc/SIH9JKEHRgQMDyWCUFKUEyWDqrtYmrbsBOg4b/DRY",
+	"mMmbkxC9hOQFqA1jFQ4/g+0UZnaZ/lzL4kmkaVzPgc7/lOJ6jhZTvGeCdXczdzoWQym9+StR7kmLPaA9",
+	"ikuZ4vKPtUASwDA7kwjDTglKHnLBRjmoYrZNDY+GpkaBq6FZtsK6b9CCgT20dZ3deZ5D7JpxjfGJr0ly",
+	"JSAYb/jCR8q1kma1LayjCB4p17QmCkQTA4H6n0iVlErbsWYf7tz9l2c7oOgS/Y6qS/Q3ficMgiZnfdiG",
+	"Lqo9SA/bhpmZ9xBxmFFXzgIBV7v18F3toNbh+WOU/lzMuXsPkwKupwKh3UTir91Cx+W9/RthIMIXHlxH",
+	"G0iExcvM/MPCxkdurRSkWL9C3aETFF4bkCN1c6f5UWKMsAwqdz50PYViOoqHzQe8+HpsB//THH5JET5K",
+	"jOHiwig4fhMfS/mXmbyZH+aukBJjKCzT59n0yg6+beUmndzvQ2JgKLWgiXCCl4NZQDcLXEUK0OXQz+XK",
+	"+4Sjq2xzBEqQnIkv6LYLFzL1zt1Rc3F0n1OrYAjI8CFUsPiwzONO9gvw4rtcIT/NdbbAiw6J5TOX3g7n",
+	"v8IeASbGeiXURz1bRf5GV8UY+L1dqJfxFXIc3zK5xje4dMFDit6teyjeR0ouzH4UeDFdzstXByLCH5GY",
+	"4OUbQ60V6tDXxfKoZGo4VSFly5//4kzTHz5p+YP54ZG5OUEtNdt62t3MhVWjsMpBbkkgSE+CF83ckPnS",
+	"4bYMEGVVtuvTh9aj5+bEGs2FL5cMOY9HBdZsWkPqN7Kif9MN1VHgYzPpLCnyN920MqjGKATSRSlRybLE",
+	"+eLTV4X154dkU0YEpKqKqoXZ1m5hCMQTRmZcuKqNjnncioPHxiRZ00WZFxkPlCpSefo9E6Z1sfZTKoqx",
+	"KCTT3P7VGfYIXjInxwi+t7uZ62tlp7X9bsqamKE2MiiA2jAUJtT+ePFiJ933kB3L8O57/5ZsSDg/sAJI",
+	"6QWjuIAPjSDZuEocwYxqgvOXzp9zGFWVO7p6xZTUYYuPDj/r1tHvopOUAuGu+KaShicSz6OYosZ5bjW3",
+	"QsdreRb+PUELO+pZm0OXPV+hwootTfAQwXN2IURuqM4wHJNCP+aFhQDhDZS6uKhl13S1SicfWDGAglqW",
+	"7gsrxQpWGNW8SXulStt0V6vbBoO2mFPS5ZK6N6jthbDkiJeQ4yFPHjtfQKIa6z2PtHRC59fLHGLBSq31",
+	"FAdd9xeU+j84ex8we19vjV2PBHyZ0uOnfSsyykVV1Hr5+VuOILIrb5ymG2v2WcMqeHUkw29nxf5QuFi8",
+	"wMyPmYOQm3e+pK0dZSXeNVXOakjda8OQWxd4GVtNCG6Hh5dL9hL7KfS7Q7KrtusVXvTXyPRShSq3AISN",
+	"LHerUF8WtGGOc6EZzdxU3gJ7xAOg80Wjor9sPSCFXiWJzoQblEv3dsZeEuOmOfOqOLfk5uuheGJuqTD/",
+	"jsUOyvRlILXxp86/NSyFQUuDsjTqEcprh5fRYO2LlXBfi16BA9S0f9gOUXCN76m3uebGYCBSCs1zdqFC",
+	"Urz+FZJ7IOzV1kJj8c7HT8rh+Ycq6ch7InWmRCUJ2iml90egJO6TSEJHn7ZVkRz2ofRYFKQFYNpHZVrj",
+	"6898tWWOzNl/jRmEVVAsrUp6/wXQqkwHnk5Jf0b90OAOn7hdvafthjoaAyxxgUhHshyNJHcr5YcMoaG8",
+	"dee5mZknGcwwTowp2uX6luAF6+4tc3XazE0TvFjcuk3w/e33d6zFe8SYsm6/hSxCBps/jm6/v0d7pvMw",
+	"jzHFnjzdeY5kjCtyc5NTi2QXLWUwdYFos+BT2lyzZpeO4Hwg4WXdWWc9JxBOaWptO3HqVNOlC2eb/ndo",
+	"qqm17dQpksGtkd+3tzT9qfNv7Mvft7fsbg7Dqj6/NYPdwiPz7TrsdZZG8On88LS9d7wGIeGTJ0+egi9P",
+	"WnPYHHtn5m5BnN+4STJjbTAwM86AB4CpuC7cXmLQmh8eQcqZExteLM6NETx0Rb4i97XBM5imRdZumjMv",
+	"aTThHsmuUEmDibFoi9oMLtx/D5oicLtCBtsaJINtDQI7emY9fgB/zM8W1h/BWOOdNbPlrfxgTeQwfHnV",
+	"mtmwMx0ZbHfYZbCr8llXvnlrvPB6klby2a3CFKXlE7tgOly5RkmrkJ8rTA6d7oSIWOeli03RvtaoGE9K",
+	"clQVdaSxRdjB0Inp0Gm60hI9uGGS/YFdE0CyOXpMq8R4bn+TwQHhbA6NF5dWoRA1g3eyS2ZuyAYng1kn",
+	"rvniibW6zu6esEafWf8a3/4wS7c9T7L3bVRl8H+hrl5FuUYXmKNTr5AMBoJ2CjncjXmpmG6Zegorxa0P",
+	"5shPbgbgiuwG5zpoIWkTc9g9YqJDaDvRcqJFGIgISgrJYkoSOoSTJ1pOnGT6nTVJRkHURFXUI2m6bTAr",
+	"GkeiB5vRgbqyJJuhJDTlrdYF6ZnBLFhsbw/Kn8Z3oLV9Ba45yP1gzvxIRfAzetg/0GsX3rqMDuy59YM1",
+	"hp2M3Fpbu3Xf2Ln7L5Yp8c+9WIAoyzKsnJ2FkjfjsTen1t5yimVMwA2g8gxsf6FT0XSQcuednTMpjDT9",
+	"MyXeX6GNeH/tw7YTMxC8fCN4GUJbS2tljWXzA16xcpPmyEPAantLS9jy7txRzy0LdEjr3kN8/dR00Km9",
+	"B7l3JwxEhN9VAxjvegCqsNLJpKj2Cx3USbSyg+ZPL1x5QFP/YKNcpipSuAojona/V/RGSW0OAAB2eYQf",
+	"8V8i3W5lPO2/mMWHjZa6UYC9GK+B3G1DtqWoH7kHwtTJvQeVro6oH6r8tpVtQAW6rKlGsdtyvQW95U3O",
+	"oE7s1nI74lBcGC1+3CR4i4UeXEnhoQe36S9wlU9I4UfpkaiHDmjOORqDEm8tjJz8OKTXpviFIQUNdGLg",
+	"OoexsLtV3O3wBNWXSKcl55qPXPe3Q+89MANXayT2qqJVFOSycBWHBwKl30FOqOF+C7i0Zk/S9l7H0Tiu",
+	"a68SNHZbySGzqQ8DbsU4h8MYW9SDwWh3RdXiGmqztPoJ69r7XXii3Kkd/JmKcn/XEI867I6Z2ogjIqTS",
+	"HCLoTHOJoP7mGisDrMZca6lUDnuUttoxJBtjKuDN7W7mnOqGJU9B302Ct0ApGhP07jUnDrP9/o2dQgqQ",
+	"Gl+URG/Ad7ZMYaHqcoo6S78PENWXdFy5fGmvgGsnR9J4fj9WSsTFNDuPOsuFSFXl75y7F3tKGA27+nDP",
+	"Gw+Zyup1CxHD1JRdqniIroS9AvdmttdUgUNI01p9bG5sFJY2zey4LwQodFy+6kOkZxAtCn5cXLjrwRzb",
+	"8pleFLtmMxv7Jhrv2vscznYdzUmc/Sz8LGplgZBzPPvZ/k+SRqsqneJ5+kAjTB1fd141pk5YELBmGVgn",
+	"AVUOoH0xLMdqYXiwcUIr2I6R38dK6o6N41eJiBio3FvyWFz5V9fuCLSykxXm0D0jrXo4cxoLPFeQZW5o",
+	"OrBSWadlYf2RmyAKucz424rK3JMrbS1vZWhI5MNXOFeFOGXbLryetH6cDZOljfEd6kSJkAZ6QXNt3p0Z",
+	"U2xnHhq0CYcJX60XJbrLRG9l8/0CjKlBOjoXbHMIo51bcAbpsBCT/7i6d8dKHrlHCEFeY6o4fwtSxfQ4",
+	"vdVBBG8UFt+bo3eYOvXmgujN2yuBCjW/d0gpiQq2I1PctRJm4+O13OpCnrRymeBXbX5E3MOTpC7J1xZ7",
+	"+8VkfssZ9DCiiYypqk7+1m1N51IpPv+6xaIu88K1//Y3cMMSfQYwueddTO5Rs1tFQ6PEx1xDHk16O4zH",
+	"galsKbvSag6N0JdQzDMEcXiebzxFb7C3flArqo6Bt/KLGDlRtypeOFJF1M3NBYRdHL8SdsWc77UwPBHF",
+	"0+tQZ+PUGtlFNuwje9lH+VtCygwJp6KNK3TSQZnzmXNI+zUNvGbBUcqslkNYM/w2w7pYGj8jM729tW3v",
+	"AZxXPNTRz/Mb8yCqGKpChJQOjTJVJ31pW01jKnToUvyouuuQ/Ezzut4dbm+sFt+twMtG/D1KgTpR+hKC",
+	"YV5MiSK4HhGlckqJ0r4f2K+uqKg2neYxdMsNwwDZgWTSztur1jG8ENoCaH58ag5mf40tVCZlD9X6Ign0",
+	"8DgUGUJUUGDYeJqCf0sktTdhBFuqeBTyi0G37zCMqYoYB/Rq+wwpwoMVEy5+3IBl5HuXnAuqMVV4PVa4",
+	"/cJ9i1wgZhUS3paSSRSX2FWGZa9Sc2673W8k+zsp5deGrvXdJckiXZ7zsrYKnVH+ACj45fY39IJep4Zs",
+	"eyMDd+AYU99JKdc49ptvZxjAzWclLaVoks5t3BF1XYz1JpGs/0dTt5RAcFSfXhHoRRjN6HpKUfVmL5ab",
+	"b3jbDgdOfCelrgiVX033S4soLbm9HW63h1vBQYtul+iFXgtubNaafkR9qEUfkdcUp6Xs6Y3TltmA5cx4",
+	"aDZgqU4/nOzdWvQ6xj5/QVRnHx/HcCxRQj1KB0PDBXs2AH75+UX7RZjHKnCwp0464pBB9T0uR8Svv0YQ",
+	"jmcEgSsd3ApRp4MauiptRvTGnEtsx1crgYotf9vr5avADBqFjGfX0XsgNuFlp9mVwtRz81FWiAhpNSF0",
+	"CL26nuqIRhNKTEz0Kpre8UnLJy3RvjZ+sLRwZ5kzXuuIRjUxmUqgEzElSQdfdXdwo0Jtn7e4zLYYvbVl",
+	"5SCUt5D5X0y9x5CgynMKeEuTsOMunyVQ9lMa4FSelA9xry4IDrEbD7gH7MtGloPHAlCckbRIgbZbwpUn",
+	"MJFDgMHV7TKF8jkqdMMGwWBlZpxT8nbNVhjPimg5IDgtVuzGT87ZOW1RnOMuu4qm7JXrJYBcR8uelvlZ",
+	"A1cH/m8AoBfQ6PZ9AAA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,350 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/taimats/bhapi/domain"
+)
+
+// 設定ファイルの既定のパス。既定のパスにファイルがない場合は環境変数、フラグのみで設定する
+const DefaultFile = ".env"
+
+// GoogleBooksAPIの既定のURL
+const DefaultGoogleBooksAPIURL = "https://www.googleapis.com/books/v1/volumes"
+
+// 表示時に伏せた値
+const redacted = "[REDACTED]"
+
+// 秘密の設定値（パスワード、鍵など）。fmt、slog、JSONで表示する場合は値を伏せる。値はValueで取り出す
+type Secret string
+
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(s.String())), nil
+}
+
+// アプリケーションの設定。Loadで読み込み、必要な値を各コンストラクタに渡す
+type Config struct {
+	Env               string //devの場合は失敗したクエリのみログに出力する
+	Server            Server
+	DB                DB
+	TokenSeed         Secret //アプリのキー（Bearer）の照合に使う種
+	FrontURL          string //フロントエンドのURL（CORSの許可、メールのリンク）
+	GoogleBooks       GoogleBooks
+	Mail              Mail
+	ExchangeRatesFile string //起動時に読み込む為替レートのファイル（任意）
+	TrashRetention    time.Duration
+	RateLimit         RateLimit
+}
+
+type Server struct {
+	Host      string
+	Port      string
+	PublicURL string //メールのリンクなど、外部から呼び出すAPIのURL
+}
+
+type DB struct {
+	User     string
+	Password Secret
+	Name     string
+	Host     string
+	Port     string
+}
+
+type GoogleBooks struct {
+	APIURL string
+	APIKey Secret
+}
+
+type Mail struct {
+	Mailer      string //送信方法（smtp、file、log）
+	From        string
+	Dir         string //fileの保存先
+	SMTP        SMTP
+	TokenSecret Secret //配信停止、パスワード再設定などのトークンの署名の鍵。未設定の場合は月次のまとめを送らない
+}
+
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password Secret
+}
+
+type RateLimit struct {
+	Default domain.RateLimit
+	Search  domain.RateLimit
+	APIKey  domain.RateLimit
+}
+
+// 設定項目（環境変数名、設定ファイルのキー）と説明。フラグ名は小文字、"-"区切り（例.POSTGRES_HOSTは-postgres-host）
+var keys = []struct {
+	name  string
+	usage string
+}{
+	{"Env", "実行環境（devの場合は失敗したクエリのみログに出力）"},
+	{"BACK_API_HOST", "APIサーバーのホスト"},
+	{"BACK_API_PORT", "APIサーバーのポート（必須）"},
+	{"BACK_API_PUBLIC_URL", "外部から呼び出すAPIのURL（メールの配信停止のリンク。MAIL_TOKEN_SECRETを設定する場合は必須）"},
+	{"POSTGRES_USER", "DBのユーザー（必須）"},
+	{"POSTGRES_PASSWORD", "DBのパスワード"},
+	{"POSTGRES_DB", "DBの名前（必須）"},
+	{"POSTGRES_HOST", "DBのホスト（必須）"},
+	{"POSTGRES_PORT", "DBのポート（必須）"},
+	{"TOKEN_SEED", "アプリのキーの照合に使う種（必須）"},
+	{"FRONT_API_BASE_URL", "フロントエンドのURL（必須）"},
+	{"GOOGLE_BOOKS_API_URL", "GoogleBooksAPIのURL"},
+	{"GOOGL_BOOKS_API_URL", "GOOGLE_BOOKS_API_URLの以前の名前（GOOGLE_BOOKS_API_URLが未設定の場合のみ）"},
+	{"GOOGLE_BOOKS_API_KEY", "GoogleBooksAPIのキー"},
+	{"MAILER", "メールの送信方法（smtp、file、log）"},
+	{"MAIL_FROM", "メールの送信元"},
+	{"MAIL_DIR", "MAILER=fileの保存先"},
+	{"SMTP_HOST", "SMTPサーバーのホスト（MAILER=smtpの場合は必須）"},
+	{"SMTP_PORT", "SMTPサーバーのポート（MAILER=smtpの場合は必須）"},
+	{"SMTP_USERNAME", "SMTPサーバーのユーザー"},
+	{"SMTP_PASSWORD", "SMTPサーバーのパスワード"},
+	{"MAIL_TOKEN_SECRET", "メールのトークンの署名の鍵"},
+	{"EXCHANGE_RATES_FILE", "起動時に読み込む為替レートのファイル"},
+	{"TRASH_RETENTION_DAYS", "ゴミ箱の保持期間（日数）"},
+	{"RATE_LIMIT_DEFAULT", "レート制限（件数/期間、例.180/1m）"},
+	{"RATE_LIMIT_SEARCH", "書籍の検索のレート制限（件数/期間）"},
+	{"RATE_LIMIT_API_KEY", "個人用APIキーごとのレート制限（件数/期間）"},
+}
+
+// 設定ファイル、環境変数、フラグ（args、os.Args[1:]）の順に読み込み、後のものを優先する。
+// 設定ファイル（KEY=VALUE形式）は-configで指定し、指定がない場合はDefaultFileがあれば読み込む。
+// 値の誤りはまとめてエラーで返す。
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("bhapi", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	file := fs.String("config", "", "設定ファイル（KEY=VALUE形式）。省略時は"+DefaultFile+"があれば読み込む")
+	flags := make(map[string]*string, len(keys))
+	for _, k := range keys {
+		flags[k.name] = fs.String(flagName(k.name), "", k.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("フラグの読み込みに失敗:%w", err)
+	}
+
+	values := map[string]string{}
+	path := *file
+	if path == "" {
+		path = DefaultFile
+	}
+	fromFile, err := godotenv.Read(path)
+	if err != nil && (*file != "" || !errors.Is(err, os.ErrNotExist)) {
+		return nil, fmt.Errorf("設定ファイル（%s）の読み込みに失敗:%w", path, err)
+	}
+	for _, k := range keys {
+		if v, ok := fromFile[k.name]; ok {
+			values[k.name] = v
+		}
+		if v, ok := os.LookupEnv(k.name); ok {
+			values[k.name] = v
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		for _, k := range keys {
+			if f.Name == flagName(k.name) {
+				values[k.name] = *flags[k.name]
+			}
+		}
+	})
+
+	return Parse(values)
+}
+
+// 環境変数名からフラグ名を返す（例.POSTGRES_HOSTは"postgres-host"）
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// 設定項目ごとの値から設定を生成し、検証する。誤りはすべての項目についてまとめて返す
+func Parse(values map[string]string) (*Config, error) {
+	p := &parser{values: values}
+	c := &Config{
+		Env: values["Env"],
+		Server: Server{
+			Host:      values["BACK_API_HOST"],
+			Port:      p.port("BACK_API_PORT", true),
+			PublicURL: p.url("BACK_API_PUBLIC_URL", false),
+		},
+		DB: DB{
+			User:     p.required("POSTGRES_USER"),
+			Password: Secret(values["POSTGRES_PASSWORD"]),
+			Name:     p.required("POSTGRES_DB"),
+			Host:     p.required("POSTGRES_HOST"),
+			Port:     p.port("POSTGRES_PORT", true),
+		},
+		TokenSeed: Secret(p.required("TOKEN_SEED")),
+		FrontURL:  p.url("FRONT_API_BASE_URL", true),
+		GoogleBooks: GoogleBooks{
+			APIURL: p.googleBooksAPIURL(),
+			APIKey: Secret(values["GOOGLE_BOOKS_API_KEY"]),
+		},
+		Mail: Mail{
+			Mailer:      p.mailer(),
+			From:        values["MAIL_FROM"],
+			Dir:         values["MAIL_DIR"],
+			TokenSecret: Secret(values["MAIL_TOKEN_SECRET"]),
+		},
+		ExchangeRatesFile: values["EXCHANGE_RATES_FILE"],
+		TrashRetention:    p.days("TRASH_RETENTION_DAYS", domain.DefaultTrashRetention),
+		RateLimit: RateLimit{
+			Default: p.rateLimit("RATE_LIMIT_DEFAULT", domain.DefaultRateLimit),
+			Search:  p.rateLimit("RATE_LIMIT_SEARCH", domain.DefaultSearchRateLimit),
+			APIKey:  p.rateLimit("RATE_LIMIT_API_KEY", domain.DefaultAPIKeyRateLimit),
+		},
+	}
+	smtp := c.Mail.Mailer == "smtp"
+	c.Mail.SMTP = SMTP{
+		Host:     values["SMTP_HOST"],
+		Port:     p.port("SMTP_PORT", smtp),
+		Username: values["SMTP_USERNAME"],
+		Password: Secret(values["SMTP_PASSWORD"]),
+	}
+	if smtp {
+		c.Mail.SMTP.Host = p.required("SMTP_HOST")
+	}
+	//月次のまとめ（MAIL_TOKEN_SECRETがある場合に送る）の配信停止のリンクは絶対URLが必要
+	if c.Mail.TokenSecret != "" && c.Server.PublicURL == "" {
+		p.fail("BACK_API_PUBLIC_URL", "MAIL_TOKEN_SECRETを設定する場合は必須です")
+	}
+
+	if err := errors.Join(p.errs...); err != nil {
+		return nil, fmt.Errorf("設定に誤りがあります:\n%w", err)
+	}
+	return c, nil
+}
+
+// 開発環境か（開発環境では失敗したクエリのみログに出力する）
+func (c *Config) IsDev() bool {
+	return c.Env == "dev"
+}
+
+// 検証の誤りを項目ごとに集める
+type parser struct {
+	values map[string]string
+	errs   []error
+}
+
+func (p *parser) fail(key string, format string, args ...any) {
+	p.errs = append(p.errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (p *parser) required(key string) string {
+	v := p.values[key]
+	if v == "" {
+		p.fail(key, "未設定です")
+	}
+	return v
+}
+
+func (p *parser) port(key string, required bool) string {
+	v := p.values[key]
+	if v == "" {
+		if required {
+			p.fail(key, "未設定です")
+		}
+		return v
+	}
+	if n, err := strconv.Atoi(v); err != nil || n < 1 || n > 65535 {
+		p.fail(key, "1〜65535のポート番号で指定ください（%s）", v)
+	}
+	return v
+}
+
+// http、httpsの絶対URL。末尾の"/"は除く（パスを連結するため）
+func (p *parser) url(key string, required bool) string {
+	v := p.values[key]
+	if v == "" {
+		if required {
+			p.fail(key, "未設定です")
+		}
+		return v
+	}
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		p.fail(key, "http、httpsのURLで指定ください（%s）", v)
+	}
+	return strings.TrimSuffix(v, "/")
+}
+
+// GOOGLE_BOOKS_API_URL。未設定の場合は以前の名前（GOOGL_BOOKS_API_URL）、既定値の順に使う
+func (p *parser) googleBooksAPIURL() string {
+	key := "GOOGLE_BOOKS_API_URL"
+	if p.values[key] == "" && p.values["GOOGL_BOOKS_API_URL"] != "" {
+		key = "GOOGL_BOOKS_API_URL"
+	}
+	if p.values[key] == "" {
+		return DefaultGoogleBooksAPIURL
+	}
+	return p.url(key, false)
+}
+
+func (p *parser) mailer() string {
+	v := p.values["MAILER"]
+	switch v {
+	case "":
+		return "log"
+	case "smtp", "file", "log":
+		return v
+	default:
+		p.fail("MAILER", "smtp、file、logのいずれかで指定ください（%s）", v)
+		return v
+	}
+}
+
+func (p *parser) days(key string, def time.Duration) time.Duration {
+	v := p.values[key]
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		p.fail(key, "正の整数で指定ください（%s）", v)
+		return def
+	}
+	return time.Duration(n) * 24 * time.Hour
+}
+
+func (p *parser) rateLimit(key string, def domain.RateLimit) domain.RateLimit {
+	v := p.values[key]
+	if v == "" {
+		return def
+	}
+	rl, err := domain.ParseRateLimit(v)
+	if err != nil {
+		p.fail(key, "\"件数/期間\"（例.180/1m）で指定ください（%s）", v)
+		return def
+	}
+	return rl
+}
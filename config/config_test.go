@@ -0,0 +1,164 @@
+package config_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/config"
+	"github.com/taimats/bhapi/domain"
+)
+
+// 必須の項目のみの設定
+func validValues() map[string]string {
+	return map[string]string{
+		"BACK_API_PORT":      "8080",
+		"POSTGRES_USER":      "bhapi",
+		"POSTGRES_PASSWORD":  "db-password",
+		"POSTGRES_DB":        "bhapi",
+		"POSTGRES_HOST":      "localhost",
+		"POSTGRES_PORT":      "5432",
+		"TOKEN_SEED":         "token-seed-value",
+		"FRONT_API_BASE_URL": "http://localhost:3000/",
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		values  map[string]string
+		check   func(a *assert.Assertions, c *config.Config)
+		wantErr []string
+	}{
+		"OK:必須の項目のみ（既定値）": {
+			values: map[string]string{},
+			check: func(a *assert.Assertions, c *config.Config) {
+				a.Equal("http://localhost:3000", c.FrontURL) //末尾の"/"は除く
+				a.Equal(config.DefaultGoogleBooksAPIURL, c.GoogleBooks.APIURL)
+				a.Equal("log", c.Mail.Mailer)
+				a.Equal(domain.DefaultTrashRetention, c.TrashRetention)
+				a.Equal(domain.DefaultRateLimit, c.RateLimit.Default)
+				a.Equal("db-password", c.DB.Password.Value())
+			},
+		},
+		"OK:以前の名前のGOOGL_BOOKS_API_URL": {
+			values: map[string]string{"GOOGL_BOOKS_API_URL": "http://localhost:9000/books"},
+			check: func(a *assert.Assertions, c *config.Config) {
+				a.Equal("http://localhost:9000/books", c.GoogleBooks.APIURL)
+			},
+		},
+		"OK:GOOGLE_BOOKS_API_URLを優先": {
+			values: map[string]string{"GOOGL_BOOKS_API_URL": "http://localhost:9000/old", "GOOGLE_BOOKS_API_URL": "http://localhost:9000/new"},
+			check: func(a *assert.Assertions, c *config.Config) {
+				a.Equal("http://localhost:9000/new", c.GoogleBooks.APIURL)
+			},
+		},
+		"OK:期間とレート制限": {
+			values: map[string]string{"TRASH_RETENTION_DAYS": "7", "RATE_LIMIT_SEARCH": "10/1m", "MAILER": "smtp", "SMTP_HOST": "smtp.example.com", "SMTP_PORT": "587"},
+			check: func(a *assert.Assertions, c *config.Config) {
+				a.Equal(7*24*time.Hour, c.TrashRetention)
+				a.Equal(domain.RateLimit{Limit: 10, Window: time.Minute}, c.RateLimit.Search)
+				a.Equal("smtp.example.com", c.Mail.SMTP.Host)
+			},
+		},
+		"NG:必須の項目が未設定": {
+			values:  map[string]string{"TOKEN_SEED": "", "POSTGRES_HOST": ""},
+			wantErr: []string{"TOKEN_SEED: 未設定です", "POSTGRES_HOST: 未設定です"},
+		},
+		"NG:値の誤り": {
+			values:  map[string]string{"BACK_API_PORT": "80800", "FRONT_API_BASE_URL": "localhost:3000", "MAILER": "sendmail", "TRASH_RETENTION_DAYS": "0", "RATE_LIMIT_DEFAULT": "180"},
+			wantErr: []string{"BACK_API_PORT", "FRONT_API_BASE_URL", "MAILER", "TRASH_RETENTION_DAYS", "RATE_LIMIT_DEFAULT"},
+		},
+		"NG:smtpはホストが必須": {
+			values:  map[string]string{"MAILER": "smtp"},
+			wantErr: []string{"SMTP_HOST: 未設定です", "SMTP_PORT: 未設定です"},
+		},
+		"NG:月次のまとめは公開URLが必須": {
+			values:  map[string]string{"MAIL_TOKEN_SECRET": "mail-secret", "TOKEN_SEED": ""},
+			wantErr: []string{"BACK_API_PUBLIC_URL: MAIL_TOKEN_SECRETを設定する場合は必須です", "TOKEN_SEED: 未設定です"},
+		},
+		"OK:月次のまとめと公開URL": {
+			values: map[string]string{"MAIL_TOKEN_SECRET": "mail-secret", "BACK_API_PUBLIC_URL": "https://api.example.com/"},
+			check: func(a *assert.Assertions, c *config.Config) {
+				a.Equal("https://api.example.com", c.Server.PublicURL)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+			values := validValues()
+			for k, v := range test.values {
+				values[k] = v
+			}
+
+			got, err := config.Parse(values)
+
+			if test.wantErr != nil {
+				a.Nil(got)
+				a.ErrorContains(err, "設定に誤りがあります")
+				for _, want := range test.wantErr {
+					a.ErrorContains(err, want)
+				}
+				return
+			}
+			a.Nil(err)
+			test.check(a, got)
+		})
+	}
+}
+
+func TestSecret(t *testing.T) {
+	//Arrange
+	c, err := config.Parse(validValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act
+	printed := fmt.Sprintf("%+v %#v", *c, *c)
+	j, err := json.Marshal(c)
+
+	//Assert
+	a.Nil(err)
+	a.NotContains(printed, "db-password")
+	a.NotContains(printed, "token-seed-value")
+	a.Contains(printed, "[REDACTED]")
+	a.NotContains(string(j), "db-password")
+	a.Equal("", config.Secret("").String()) //未設定は未設定と分かるように
+}
+
+func TestLoad(t *testing.T) {
+	//Arrange
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bhapi.env")
+	file := "BACK_API_PORT=8080\nPOSTGRES_USER=file\nPOSTGRES_DB=bhapi\nPOSTGRES_HOST=file-host\nPOSTGRES_PORT=5432\nTOKEN_SEED=seed\nFRONT_API_BASE_URL=http://localhost:3000\n"
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("POSTGRES_USER", "env")
+	t.Setenv("POSTGRES_HOST", "env-host")
+	a := assert.New(t)
+
+	//Act
+	got, err := config.Load([]string{"-config", path, "-postgres-host", "flag-host"})
+	_, errMissing := config.Load([]string{"-config", filepath.Join(dir, "missing.env")})
+	_, errFlag := config.Load([]string{"-config", path, "-unknown", "x"})
+
+	//Assert
+	a.Nil(err)
+	a.Equal("bhapi", got.DB.Name)     //設定ファイル
+	a.Equal("env", got.DB.User)       //環境変数はファイルより優先
+	a.Equal("flag-host", got.DB.Host) //フラグは環境変数より優先
+	a.ErrorContains(errMissing, "missing.env")
+	a.Error(errFlag)
+}
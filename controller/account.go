@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/mailer"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+var errTokenSecretNotSet = errors.New("トークンの署名の鍵が未設定です")
+
+// 登録のないメールアドレスでもパスワードの照合と同じ時間をかけるためのハッシュ（応答時間で登録の有無を推測させない）
+var dummyPassword = sync.OnceValue(func() domain.Password {
+	hashed, _ := new(domain.User).HashedPassword("dummy-password")
+	return hashed
+})
+
+// ログイン、パスワードの再設定とメールアドレスの確認。
+// トークンは平文で保存しないため、ジョブを経由せずにリクエストの中でメールを送る。
+type Account struct {
+	ur        *repository.User
+	tr        *repository.Token
+	mr        *repository.Mail
+	lc        *Lockout
+	m         mailer.Mailer
+	cl        utils.Clock
+	secret    string
+	resetURL  string
+	verifyURL string
+}
+
+// secretはトークンの署名の鍵。resetURL、verifyURLはメールのリンク先（フロントエンドのページ）で、クエリtokenにトークンを付けて送る。
+// lcはログインの失敗を数える先。
+func NewAccount(ur *repository.User, tr *repository.Token, mr *repository.Mail, lc *Lockout, m mailer.Mailer, cl utils.Clock, secret string, resetURL string, verifyURL string) *Account {
+	return &Account{ur: ur, tr: tr, mr: mr, lc: lc, m: m, cl: cl, secret: secret, resetURL: resetURL, verifyURL: verifyURL}
+}
+
+// メールアドレスとパスワードでログインし、ユーザーを返す。違う場合はdomain.ErrInvalidCredentials、無効化されている場合はdomain.ErrAccountDisabled。
+// 失敗はメールアドレスごと、IPアドレス（ip）ごとに数え、ロック中は照合せずに*domain.LockedErrorを返す。
+// アカウントをロックした場合は本人にメールで知らせる。
+func (ac *Account) Login(ctx context.Context, email domain.Email, password domain.Password, ip string) (*domain.User, error) {
+	key := domain.LockoutEmailKey(email)
+	if err := ac.lc.Check(ctx, domain.LockoutIP, ip); err != nil {
+		return nil, err
+	}
+	if err := ac.lc.Check(ctx, domain.LockoutAccount, key); err != nil {
+		return nil, err
+	}
+
+	user, err := ac.ur.FindUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if user != nil && ac.validatePassword(ctx, user, password) {
+		if err := ac.lc.Reset(ctx, domain.LockoutAccount, key); err != nil {
+			return nil, err
+		}
+		//無効化されたアカウントは、パスワードが正しい場合のみ知らせる
+		if user.Disabled() {
+			return nil, domain.ErrAccountDisabled
+		}
+		return user, nil
+	}
+	if user == nil {
+		(&domain.User{Password: dummyPassword()}).ValidatePassword(password)
+	}
+
+	if _, _, err := ac.lc.Fail(ctx, domain.LockoutIP, ip); err != nil {
+		return nil, err
+	}
+	f, locked, err := ac.lc.Fail(ctx, domain.LockoutAccount, key)
+	if err != nil {
+		return nil, err
+	}
+	if locked && user != nil {
+		//通知に失敗してもロックは有効なため、ログインの結果は変えない
+		err := sendAccountMail(ctx, ac, user, mailer.RenderAccountLocked, &mailer.LockedMail{Name: user.Name, Until: f.LockedUntil, ResetURL: ac.resetURL})
+		if err != nil {
+			log.Printf("アカウントのロックの通知に失敗:%s", err)
+		}
+	}
+	return nil, domain.ErrInvalidCredentials
+}
+
+// パスワード再設定のメールを送る。登録のないメールアドレスと、送信数の上限（domain.PasswordResetLimit）を超えた場合は
+// 送らずに成功扱いにする（メールアドレスの登録の有無を推測させないため）。
+func (ac *Account) RequestPasswordReset(ctx context.Context, email domain.Email) error {
+	if ac.secret == "" {
+		return errTokenSecretNotSet
+	}
+	user, err := ac.ur.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, hash, err := domain.NewUserToken(ac.secret, domain.TokenPasswordReset)
+	if err != nil {
+		return err
+	}
+	t := &domain.UserToken{AuthUserId: user.AuthUserId, Purpose: domain.TokenPasswordReset, TokenHash: hash, Email: user.Email}
+	created, err := ac.tr.CreateTokenWithinLimit(ctx, t, domain.PasswordResetTTL, domain.PasswordResetLimit, domain.PasswordResetWindow)
+	if err != nil || !created {
+		return err
+	}
+
+	return sendAccountMail(ctx, ac, user, mailer.RenderPasswordReset, &mailer.LinkMail{
+		Name: user.Name,
+		URL:  ac.resetURL + "?token=" + url.QueryEscape(token),
+		TTL:  domain.PasswordResetTTL,
+	})
+}
+
+// トークンを確認してパスワードを再設定する
+func (ac *Account) ResetPassword(ctx context.Context, token string, password domain.Password) error {
+	hash, err := domain.VerifyUserToken(ac.secret, domain.TokenPasswordReset, token)
+	if err != nil {
+		return err
+	}
+	hashed, err := new(domain.User).HashedPassword(password)
+	if err != nil {
+		return fmt.Errorf("パスワードのハッシュ化に失敗:%w", err)
+	}
+	return ac.tr.ResetPassword(ctx, hash, hashed)
+}
+
+// メールアドレス確認のメールを送る。確認済みの場合はdomain.ErrEmailAlreadyVerified
+func (ac *Account) SendVerification(ctx context.Context, authUserId string) error {
+	if ac.secret == "" {
+		return errTokenSecretNotSet
+	}
+	user, err := ac.ur.FindUserByAuthUserId(ctx, authUserId)
+	if err != nil {
+		return err
+	}
+	if !user.EmailVerifiedAt.IsZero() {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	token, hash, err := domain.NewUserToken(ac.secret, domain.TokenEmailVerification)
+	if err != nil {
+		return err
+	}
+	t := &domain.UserToken{AuthUserId: user.AuthUserId, Purpose: domain.TokenEmailVerification, TokenHash: hash, Email: user.Email}
+	if err := ac.tr.CreateToken(ctx, t, domain.EmailVerificationTTL); err != nil {
+		return err
+	}
+
+	return sendAccountMail(ctx, ac, user, mailer.RenderEmailVerification, &mailer.LinkMail{
+		Name: user.Name,
+		URL:  ac.verifyURL + "?token=" + url.QueryEscape(token),
+		TTL:  domain.EmailVerificationTTL,
+	})
+}
+
+// トークンを確認してメールアドレスを確認済みにする
+func (ac *Account) VerifyEmail(ctx context.Context, token string) error {
+	hash, err := domain.VerifyUserToken(ac.secret, domain.TokenEmailVerification, token)
+	if err != nil {
+		return err
+	}
+	return ac.tr.VerifyEmail(ctx, hash)
+}
+
+// パスワードを照合する。平文で保存した（ハッシュ化の導入前に登録した）パスワードは、一致した場合にハッシュ化して保存し直す
+func (ac *Account) validatePassword(ctx context.Context, user *domain.User, password domain.Password) bool {
+	if user.Password.IsHashed() {
+		return user.ValidatePassword(password)
+	}
+	if user.Password == "" || subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) != 1 {
+		return false
+	}
+
+	hashed, err := user.HashedPassword(password)
+	if err != nil {
+		log.Printf("パスワードのハッシュ化に失敗:%s", err)
+		return true
+	}
+	user.Password = hashed
+	if err := ac.ur.PatchUser(ctx, user, []string{"password"}); err != nil {
+		log.Printf("パスワードのハッシュ化に失敗:%s", err)
+	}
+	return true
+}
+
+// ユーザーのメールの言語でメールを作成して送る
+func sendAccountMail[T any](ctx context.Context, ac *Account, user *domain.User, render func(domain.MailLanguage, T) (*mailer.Message, error), data T) error {
+	setting, err := ac.mr.FindMailSetting(ctx, user.AuthUserId)
+	if err != nil {
+		return err
+	}
+	msg, err := render(setting.Language, data)
+	if err != nil {
+		return err
+	}
+	msg.To = string(user.Email)
+	return ac.m.Send(ctx, msg)
+}
@@ -0,0 +1,179 @@
+package controller_test
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+	"github.com/uptrace/bun"
+)
+
+var tokenInURL = regexp.MustCompile(`\?token=(\S+)`)
+
+func newLockout(db *bun.DB) *controller.Lockout {
+	return controller.NewLockout(repository.NewLockout(db, cl), cl, domain.DefaultAuthFailureRetention)
+}
+
+func TestAccountLogin(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	//ハッシュ化の導入前に平文で保存したパスワード
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", Password: "password123", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	ur := repository.NewUser(bundb, cl)
+	m := new(recordingMailer)
+	sut := controller.NewAccount(ur, repository.NewToken(bundb, cl), repository.NewMail(bundb, cl), newLockout(bundb), m, cl, "secret", "https://front.example.com/password-reset", "")
+	threshold := domain.LockoutPolicies[domain.LockoutAccount].Threshold
+	a := assert.New(t)
+
+	//Act ***************
+	got, errLogin := sut.Login(ctx, "tanaka@example.com", "password123", "192.0.2.1")
+	user, err := ur.FindUserByAuthUserId(ctx, authUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, errUnknown := sut.Login(ctx, "unknown@example.com", "password123", "192.0.2.1")
+	errs := []error{}
+	for range threshold {
+		_, err := sut.Login(ctx, "tanaka@example.com", "wrong-password", "192.0.2.2")
+		errs = append(errs, err)
+	}
+	_, errLocked := sut.Login(ctx, "tanaka@example.com", "password123", "192.0.2.3")
+
+	//Assert ***************
+	a.Nil(errLogin)
+	a.Equal(authUserId, got.AuthUserId)
+	a.True(user.Password.IsHashed()) //ログインに成功した時点でハッシュ化して保存し直す
+	a.True(user.ValidatePassword("password123"))
+	a.ErrorIs(errUnknown, domain.ErrInvalidCredentials)
+	for _, err := range errs {
+		a.ErrorIs(err, domain.ErrInvalidCredentials)
+	}
+	var le *domain.LockedError
+	if a.ErrorAs(errLocked, &le) { //正しいパスワードでもロック中は照合しない
+		a.Equal(time.Minute, le.RetryAfter)
+	}
+	if a.Len(m.sent, 1) { //ロックした時点で本人に知らせる
+		a.Equal("tanaka@example.com", m.sent[0].To)
+		a.Equal("ログインを一時的に制限しました", m.sent[0].Subject)
+		a.Contains(m.sent[0].Text, "https://front.example.com/password-reset")
+	}
+}
+
+func TestAccountPasswordReset(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	ur := repository.NewUser(bundb, cl)
+	m := new(recordingMailer)
+	sut := controller.NewAccount(ur, repository.NewToken(bundb, cl), repository.NewMail(bundb, cl), newLockout(bundb), m, cl, "secret", "https://front.example.com/password-reset", "")
+	a := assert.New(t)
+
+	//Act ***************
+	errUnknown := sut.RequestPasswordReset(ctx, "unknown@example.com")
+	errs := []error{}
+	for range domain.PasswordResetLimit + 1 {
+		errs = append(errs, sut.RequestPasswordReset(ctx, "tanaka@example.com"))
+	}
+	var errReset, errReused error
+	if a.Len(m.sent, domain.PasswordResetLimit) { //上限を超えた分は送らない
+		token, err := url.QueryUnescape(tokenInURL.FindStringSubmatch(m.sent[0].Text)[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		errReset = sut.ResetPassword(ctx, token, "newpassword")
+		errReused = sut.ResetPassword(ctx, token, "newpassword")
+	}
+	user, err := ur.FindUserByAuthUserId(ctx, authUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert ***************
+	a.Nil(errUnknown)
+	for _, err := range errs {
+		a.Nil(err)
+	}
+	a.Equal("tanaka@example.com", m.sent[0].To)
+	a.Equal("パスワードの再設定", m.sent[0].Subject)
+	a.Nil(errReset)
+	a.ErrorIs(errReused, domain.ErrInvalidToken)
+	a.True(user.ValidatePassword("newpassword")) //bcryptでハッシュ化して保存する
+}
+
+func TestAccountVerifyEmail(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "Tanaka", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	mr := repository.NewMail(bundb, cl)
+	if err := mr.SaveMailSetting(ctx, &domain.MailSetting{AuthUserId: authUserId, Language: domain.MailEnglish, Digest: true}); err != nil {
+		t.Fatal(err)
+	}
+	m := new(recordingMailer)
+	sut := controller.NewAccount(repository.NewUser(bundb, cl), repository.NewToken(bundb, cl), mr, newLockout(bundb), m, cl, "secret", "", "https://front.example.com/verify-email")
+	a := assert.New(t)
+
+	//Act ***************
+	errSend := sut.SendVerification(ctx, authUserId)
+	var errVerify error
+	if a.Len(m.sent, 1) {
+		token, err := url.QueryUnescape(tokenInURL.FindStringSubmatch(m.sent[0].Text)[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		errVerify = sut.VerifyEmail(ctx, token)
+	}
+	errAgain := sut.SendVerification(ctx, authUserId)
+	errNotFound := sut.SendVerification(ctx, "unknown")
+
+	//Assert ***************
+	a.Nil(errSend)
+	a.Equal("Confirm your email address", m.sent[0].Subject) //ユーザーのメールの言語で送る
+	a.Nil(errVerify)
+	a.ErrorIs(errAgain, domain.ErrEmailAlreadyVerified)
+	a.ErrorIs(errNotFound, domain.ErrNotFound)
+}
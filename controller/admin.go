@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+var ErrDisableSelf = domain.NewError(domain.ErrValidation, "自分自身は無効化できません")
+
+// 管理API。サポートの問い合わせのために、ユーザーの検索、集計、無効化、強制ログアウトを行う
+type Admin struct {
+	ar *repository.Admin
+	ur *repository.User
+}
+
+func NewAdmin(ar *repository.Admin, ur *repository.User) *Admin {
+	return &Admin{ar: ar, ur: ur}
+}
+
+// authUserIdのユーザーが役割roleを持ち、無効化されていないか
+func (adc *Admin) HasRole(ctx context.Context, authUserId string, role domain.Role) (bool, error) {
+	user, err := adc.ur.FindUserByAuthUserId(ctx, authUserId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("ユーザーの取得に失敗:%w", err)
+	}
+	return user.HasRole(role) && !user.Disabled(), nil
+}
+
+// ユーザーが無効化されているか。未登録、削除済みのユーザーはfalse
+func (adc *Admin) Disabled(ctx context.Context, authUserId string) (bool, error) {
+	user, err := adc.ur.FindUserByAuthUserId(ctx, authUserId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("ユーザーの取得に失敗:%w", err)
+	}
+	return user.Disabled(), nil
+}
+
+// 条件に一致するユーザーと、条件に一致する全件数を返す
+func (adc *Admin) SearchUsers(ctx context.Context, s domain.UserSearch) ([]*domain.User, int, error) {
+	return adc.ar.SearchUsers(ctx, s.Normalize())
+}
+
+// ユーザーと本棚の集計を返す。未登録、削除済みのユーザーはErrNotFound。
+func (adc *Admin) GetUserStats(ctx context.Context, authUserId string) (*domain.User, *domain.ShelfStats, error) {
+	user, err := adc.ur.FindUserByAuthUserId(ctx, authUserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	stats, err := adc.ar.FindShelfStats(ctx, authUserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, stats, nil
+}
+
+func (adc *Admin) GetSystemStats(ctx context.Context) (*domain.SystemStats, error) {
+	return adc.ar.FindSystemStats(ctx)
+}
+
+// ユーザーを無効化し、強制的にログアウトさせる。管理者（actor）自身は無効化できない（ErrDisableSelf）。
+func (adc *Admin) DisableUser(ctx context.Context, actor string, authUserId string) (*domain.User, *domain.Revocation, error) {
+	if actor == authUserId {
+		return nil, nil, ErrDisableSelf
+	}
+	return adc.ar.DisableUser(ctx, authUserId)
+}
+
+func (adc *Admin) EnableUser(ctx context.Context, authUserId string) (*domain.User, error) {
+	return adc.ar.EnableUser(ctx, authUserId)
+}
+
+// ユーザーを強制的にログアウトさせる（個人用APIキー、未使用のトークンを無効にする）
+func (adc *Admin) RevokeTokens(ctx context.Context, authUserId string) (*domain.Revocation, error) {
+	return adc.ar.RevokeTokens(ctx, authUserId)
+}
@@ -0,0 +1,63 @@
+package controller_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestAdminDisableUser(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	admin := &domain.User{AuthUserId: "9b0b1f4e-4e0c-4b8e-8f3a-1f7f0c2d3e4a", Email: "admin@example.com", Role: domain.RoleAdmin, CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Email: "tanaka@example.com", Password: "password123", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, admin, user)
+	ur := repository.NewUser(bundb, cl)
+	ac := controller.NewAccount(ur, repository.NewToken(bundb, cl), repository.NewMail(bundb, cl), newLockout(bundb), new(recordingMailer), cl, "secret", "", "")
+	sut := controller.NewAdmin(repository.NewAdmin(bundb, cl), ur)
+	a := assert.New(t)
+
+	//Act ***************
+	isAdmin, errAdmin := sut.HasRole(ctx, admin.AuthUserId, domain.RoleAdmin)
+	isUserAdmin, errUser := sut.HasRole(ctx, user.AuthUserId, domain.RoleAdmin)
+	isUnknownAdmin, errUnknown := sut.HasRole(ctx, "unknown", domain.RoleAdmin)
+	_, _, errSelf := sut.DisableUser(ctx, admin.AuthUserId, admin.AuthUserId)
+	disabled, _, errDisable := sut.DisableUser(ctx, admin.AuthUserId, user.AuthUserId)
+	_, errLogin := ac.Login(ctx, user.Email, "password123", "192.0.2.1")
+	_, errWrong := ac.Login(ctx, user.Email, "wrong-password", "192.0.2.1")
+	_, errEnable := sut.EnableUser(ctx, user.AuthUserId)
+	_, errEnabledLogin := ac.Login(ctx, user.Email, "password123", "192.0.2.1")
+
+	//Assert ***************
+	a.Nil(errAdmin)
+	a.True(isAdmin)
+	a.Nil(errUser)
+	a.False(isUserAdmin)
+	a.Nil(errUnknown)
+	a.False(isUnknownAdmin)
+	a.ErrorIs(errSelf, controller.ErrDisableSelf)
+	a.Nil(errDisable)
+	a.True(disabled.Disabled())
+	a.ErrorIs(errLogin, domain.ErrAccountDisabled)
+	a.ErrorIs(errWrong, domain.ErrInvalidCredentials) //パスワードが違う場合は無効化を明かさない
+	a.Nil(errEnable)
+	a.Nil(errEnabledLogin)
+}
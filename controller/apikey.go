@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+// 個人用APIキーの発行と、キーによる認証
+type APIKey struct {
+	kr *repository.APIKey
+	ur *repository.User
+	cl utils.Clock
+}
+
+func NewAPIKey(kr *repository.APIKey, ur *repository.User, cl utils.Clock) *APIKey {
+	return &APIKey{kr: kr, ur: ur, cl: cl}
+}
+
+// APIキーを発行し、キーを返す（キーを返すのは発行時のみ。以降はハッシュのみ保存する）。
+// adminスコープは管理者のみ発行できる（domain.ErrAdminScope）。
+func (kc *APIKey) CreateAPIKey(ctx context.Context, k *domain.APIKey) (string, error) {
+	if err := k.Validate(kc.cl.Now()); err != nil {
+		return "", err
+	}
+	if k.HasScope(domain.ScopeAdmin) {
+		user, err := kc.ur.FindUserByAuthUserId(ctx, k.AuthUserId)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return "", err
+		}
+		if user == nil || !user.HasRole(domain.RoleAdmin) {
+			return "", domain.ErrAdminScope
+		}
+	}
+	token, err := domain.NewAPIKeyToken(k)
+	if err != nil {
+		return "", fmt.Errorf("APIキーの生成に失敗:%w", err)
+	}
+	if err := kc.kr.CreateAPIKey(ctx, k, domain.MaxAPIKeysPerUser); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (kc *APIKey) GetAPIKeys(ctx context.Context, authUserId string) ([]*domain.APIKey, error) {
+	return kc.kr.FindAPIKeysByAuthUserID(ctx, authUserId)
+}
+
+func (kc *APIKey) DeleteAPIKey(ctx context.Context, authUserId string, id int64) error {
+	return kc.kr.DeleteAPIKey(ctx, authUserId, id)
+}
+
+// キーに一致するAPIキーを返す。未登録、削除済み、期限切れのキーはdomain.ErrAPIKeyRejected。
+// 最終使用日時はdomain.APIKeyTouchIntervalごとに更新し、更新の失敗は認証の結果に影響させない。
+func (kc *APIKey) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	now := kc.cl.Now()
+	k, err := kc.kr.FindAPIKeyByHash(ctx, domain.HashAPIKeyToken(token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrAPIKeyRejected
+	}
+	if err != nil {
+		return nil, fmt.Errorf("APIキーの取得に失敗:%w", err)
+	}
+	if k.Expired(now) {
+		return nil, domain.ErrAPIKeyRejected
+	}
+
+	if k.ShouldTouch(now) {
+		if err := kc.kr.TouchAPIKey(ctx, k.ID, now); err != nil {
+			log.Printf("APIキーの最終使用日時の更新に失敗:%s", err)
+		} else {
+			k.LastUsedAt = now
+		}
+	}
+	return k, nil
+}
@@ -0,0 +1,76 @@
+package controller_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+	kr := repository.NewAPIKey(bundb, cl)
+	sut := controller.NewAPIKey(kr, repository.NewUser(bundb, cl), cl)
+	key := &domain.APIKey{AuthUserId: user.AuthUserId, Name: "script", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}}
+	token, err := sut.CreateAPIKey(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//発行後に期限切れになったキー
+	expired := &domain.APIKey{AuthUserId: user.AuthUserId, Name: "old", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}, ExpiresAt: cl.Now().Add(-time.Hour)}
+	expiredToken, err := domain.NewAPIKeyToken(expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kr.CreateAPIKey(ctx, expired, domain.MaxAPIKeysPerUser); err != nil {
+		t.Fatal(err)
+	}
+	_, errInvalid := sut.CreateAPIKey(ctx, &domain.APIKey{AuthUserId: user.AuthUserId, Name: "script", Scopes: []domain.APIKeyScope{"shelf:delete"}})
+	_, errAdminScope := sut.CreateAPIKey(ctx, &domain.APIKey{AuthUserId: user.AuthUserId, Name: "script", Scopes: []domain.APIKeyScope{domain.ScopeAdmin}})
+	a := assert.New(t)
+
+	//Act ***************
+	got, errAuth := sut.Authenticate(ctx, token)
+	_, errExpired := sut.Authenticate(ctx, expiredToken)
+	_, errUnknown := sut.Authenticate(ctx, domain.APIKeyTokenPrefix+"unknown")
+	keys, errGet := sut.GetAPIKeys(ctx, user.AuthUserId)
+	errDelete := sut.DeleteAPIKey(ctx, user.AuthUserId, key.ID)
+	_, errDeleted := sut.Authenticate(ctx, token)
+
+	//Assert ***************
+	a.ErrorIs(errInvalid, domain.ErrInvalidAPIKey)
+	a.ErrorIs(errAdminScope, domain.ErrAdminScope) //管理者でないユーザー
+	a.Nil(errAuth)
+	a.Equal(key.ID, got.ID)
+	a.True(got.LastUsedAt.Equal(cl.Now()))
+	a.ErrorIs(errExpired, domain.ErrAPIKeyRejected)
+	a.ErrorIs(errUnknown, domain.ErrAPIKeyRejected)
+	a.Nil(errGet)
+	if a.Len(keys, 2) {
+		a.Equal(key.Prefix, keys[0].Prefix)
+		a.NotEqual(token, keys[0].KeyHash) //キー自体は保存しない
+	}
+	a.Nil(errDelete)
+	a.ErrorIs(errDeleted, domain.ErrAPIKeyRejected)
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+// 監査ログの検索（管理API）と、ユーザーごとの利用履歴
+type Audit struct {
+	ar *repository.Audit
+}
+
+func NewAudit(ar *repository.Audit) *Audit {
+	return &Audit{ar: ar}
+}
+
+// 条件に一致する監査ログを新しい順に返す。条件が不正な場合はdomain.ErrInvalidAuditQuery
+func (auc *Audit) GetAuditLogs(ctx context.Context, q domain.AuditQuery) ([]*domain.AuditLog, error) {
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return auc.ar.FindAuditLogs(ctx, q)
+}
+
+// ユーザーのデータへの変更（利用履歴）を新しい順に返す
+func (auc *Audit) GetActivity(ctx context.Context, authUserId string, beforeId int64, limit int) ([]*domain.AuditLog, error) {
+	return auc.GetAuditLogs(ctx, domain.AuditQuery{AuthUserId: authUserId, BeforeId: beforeId, Limit: limit})
+}
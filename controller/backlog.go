@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+type Backlog struct {
+	cr *repository.Chart
+	sr *repository.Shelf
+	ur *repository.User
+	rr *repository.Rate
+	cl utils.Clock
+}
+
+func NewBacklog(cr *repository.Chart, sr *repository.Shelf, ur *repository.User, rr *repository.Rate, cl utils.Clock) *Backlog {
+	return &Backlog{cr: cr, sr: sr, ur: ur, rr: rr, cl: cl}
+}
+
+// 積読の推移と現状を返す。未読の本の購入額はユーザーの基準通貨に換算。
+func (bc *Backlog) GetBacklog(ctx context.Context, authUserId string) (*domain.Backlog, error) {
+	volumes, err := bc.cr.FindMonthlyVolumesByAuthUserId(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	books, err := bc.sr.FindBooksByAuthUserID(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	home, rates, err := homeCurrencyWithRates(ctx, bc.ur, bc.rr, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := rates.ConvertBooks(books, home)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewBacklog(volumes, converted, home, bc.cl.Now()), nil
+}
@@ -9,17 +9,29 @@ import (
 
 type Chart struct {
 	cr *repository.Chart
+	ur *repository.User
+	rr *repository.Rate
 }
 
-func NewChart(cr *repository.Chart) *Chart {
-	return &Chart{cr: cr}
+func NewChart(cr *repository.Chart, ur *repository.User, rr *repository.Rate) *Chart {
+	return &Chart{cr: cr, ur: ur, rr: rr}
 }
 
+// 購入額のチャートはユーザーの基準通貨に換算して返す
 func (cc *Chart) GetCharts(ctx context.Context, authUserId string) ([]*domain.Chart, error) {
 	charts, err := cc.cr.FindChartsByAuthUserId(ctx, authUserId)
 	if err != nil {
 		return nil, err
 	}
 
-	return charts, nil
+	home, rates, err := homeCurrencyWithRates(ctx, cc.ur, cc.rr, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := rates.ConvertCharts(charts, home)
+	if err != nil {
+		return nil, err
+	}
+
+	return converted, nil
 }
@@ -92,10 +92,11 @@ func TestGetCharts(t *testing.T) {
 
 	want := []*domain.Chart{
 		{
-			Label: domain.ChartPrice,
-			Year:  2025,
-			Month: 2,
-			Data:  1960,
+			Label:    domain.ChartPrice,
+			Year:     2025,
+			Month:    2,
+			Data:     1960,
+			Currency: domain.JPY,
 		},
 		{
 			Label: domain.ChartVolumes,
@@ -112,7 +113,9 @@ func TestGetCharts(t *testing.T) {
 	}
 
 	cr := repository.NewChart(bundb, cl)
-	sut := controller.NewChart(cr)
+	ur := repository.NewUser(bundb, cl)
+	rr := repository.NewRate(bundb, cl)
+	sut := controller.NewChart(cr, ur, rr)
 	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
 
 	a := assert.New(t)
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+// 一度に配信するイベントの最大件数
+const eventBatchSize = 100
+
+// 変更イベントの配信。PostgresのLISTEN/NOTIFYで他のAPIサーバーの変更も受け取り、ユーザーごとの購読者に知らせる。
+type Event struct {
+	er        *repository.Event
+	cl        utils.Clock
+	retention time.Duration
+
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// retentionはイベントを保持する期間
+func NewEvent(er *repository.Event, cl utils.Clock, retention time.Duration) *Event {
+	return &Event{er: er, cl: cl, retention: retention, subs: map[string]map[chan struct{}]struct{}{}, done: make(chan struct{})}
+}
+
+// authUserIdのイベントを購読する。イベントが登録されると返したチャネルに通知する（通知はまとめられることがある）。
+// 購読をやめる場合は返した関数を呼び出す。
+func (ec *Event) Subscribe(authUserId string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.subs[authUserId] == nil {
+		ec.subs[authUserId] = map[chan struct{}]struct{}{}
+	}
+	ec.subs[authUserId][ch] = struct{}{}
+
+	return ch, func() {
+		ec.mu.Lock()
+		defer ec.mu.Unlock()
+		delete(ec.subs[authUserId], ch)
+		if len(ec.subs[authUserId]) == 0 {
+			delete(ec.subs, authUserId)
+		}
+	}
+}
+
+// authUserIdの購読者に通知する。受け取り待ちの通知がある場合は重ねない。
+func (ec *Event) notify(authUserId string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	for ch := range ec.subs[authUserId] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// すべての購読者に通知する（再接続後に取りこぼしを確認させる）
+func (ec *Event) notifyAll() {
+	ec.mu.Lock()
+	users := make([]string, 0, len(ec.subs))
+	for u := range ec.subs {
+		users = append(users, u)
+	}
+	ec.mu.Unlock()
+
+	for _, u := range users {
+		ec.notify(u)
+	}
+}
+
+// Runの終了（サーバーのシャットダウン）時に閉じるチャネル。配信中の接続はこれを受けて終了する。
+func (ec *Event) Done() <-chan struct{} {
+	return ec.done
+}
+
+// ctxがキャンセルされるまでイベントの通知を受け取り、購読者に知らせる。
+// 接続に失敗した場合はretryの間隔で再接続する。
+func (ec *Event) Run(ctx context.Context, retry time.Duration) {
+	defer ec.doneOnce.Do(func() { close(ec.done) })
+
+	for {
+		err := ec.er.Listen(ctx, ec.notify)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("イベントの購読に失敗:%s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		ec.notifyAll()
+	}
+}
+
+// authUserIdのイベントのうちlastIdより後のものを、古い順に返す
+func (ec *Event) EventsAfter(ctx context.Context, authUserId string, lastId int64) ([]*domain.Event, error) {
+	return ec.er.FindEventsAfter(ctx, authUserId, lastId, eventBatchSize)
+}
+
+// authUserIdの最新のイベントのid。Last-Event-IDがない場合はここから配信する。
+func (ec *Event) LatestEventID(ctx context.Context, authUserId string) (int64, error) {
+	return ec.er.LatestEventID(ctx, authUserId)
+}
+
+// 保持期間を過ぎたイベントを削除
+func (ec *Event) Purge(ctx context.Context) error {
+	n, err := ec.er.PurgeEvents(ctx, ec.cl.Now().Add(-ec.retention))
+	if err != nil {
+		return fmt.Errorf("イベントの削除に失敗:%w", err)
+	}
+
+	if n > 0 {
+		log.Printf("保持期間を過ぎたイベントを削除しました（%d件）", n)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+type Goal struct {
+	gr *repository.Goal
+	sr *repository.Shelf
+	ur *repository.User
+	rr *repository.Rate
+	er *repository.Event
+	cl utils.Clock
+}
+
+func NewGoal(gr *repository.Goal, sr *repository.Shelf, ur *repository.User, rr *repository.Rate, er *repository.Event, cl utils.Clock) *Goal {
+	return &Goal{gr: gr, sr: sr, ur: ur, rr: rr, er: er, cl: cl}
+}
+
+// 目標ごとに現在の進捗を返す
+func (gc *Goal) GetGoalProgress(ctx context.Context, authUserId string) ([]*domain.GoalProgress, error) {
+	goals, err := gc.gr.FindGoalsByAuthUserId(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	if len(goals) == 0 {
+		return []*domain.GoalProgress{}, nil
+	}
+
+	books, err := gc.sr.FindBooksByAuthUserID(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	home, rates, err := homeCurrencyWithRates(ctx, gc.ur, gc.rr, authUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := gc.cl.Now()
+	progress := make([]*domain.GoalProgress, len(goals))
+	for i, g := range goals {
+		if g.Kind == domain.GoalSpend && g.Currency == "" {
+			g.Currency = home
+		}
+		converted, err := rates.ConvertBooks(books, g.Currency.OrDefault())
+		if err != nil {
+			return nil, err
+		}
+		progress[i] = domain.NewGoalProgress(g, converted, now)
+	}
+
+	return progress, nil
+}
+
+// 目標を登録、更新する。購入額の目標で通貨の指定がない場合は、ユーザーの基準通貨を目標の通貨として保存する
+func (gc *Goal) SetGoal(ctx context.Context, goal *domain.Goal) error {
+	if err := goal.Validate(); err != nil {
+		return err
+	}
+	if goal.Kind != domain.GoalSpend {
+		goal.Currency = ""
+	} else if goal.Currency == "" {
+		home, err := gc.HomeCurrency(ctx, goal.AuthUserId)
+		if err != nil {
+			return err
+		}
+		goal.Currency = home
+	}
+	if err := gc.gr.UpsertGoal(ctx, goal); err != nil {
+		return err
+	}
+	return nil
+}
+
+// 購入額の目標の通貨の既定値（ユーザーの基準通貨）。金額を通貨の補助単位に変換する前に通貨を決めるために使う
+func (gc *Goal) HomeCurrency(ctx context.Context, authUserId string) (domain.Currency, error) {
+	return homeCurrency(ctx, gc.ur, authUserId)
+}
+
+func (gc *Goal) DeleteGoal(ctx context.Context, authUserId string, goalId int64) error {
+	if err := gc.gr.DeleteGoal(ctx, authUserId, goalId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// 購入額の上限を超過している目標を返す。本の登録後の警告に使用。
+func (gc *Goal) ExceededSpendCaps(ctx context.Context, authUserId string) ([]*domain.GoalProgress, error) {
+	progress, err := gc.GetGoalProgress(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	exceeded := []*domain.GoalProgress{}
+	for _, p := range progress {
+		if p.Status == domain.GoalExceeded {
+			exceeded = append(exceeded, p)
+		}
+	}
+	return exceeded, nil
+}
+
+// 期間内に達成した目標のうち、まだ記録していないものを達成イベントとして記録する。本の登録、更新後に呼び出す。
+func (gc *Goal) RecordReachedGoals(ctx context.Context, authUserId string) error {
+	progress, err := gc.GetGoalProgress(ctx, authUserId)
+	if err != nil {
+		return err
+	}
+
+	events := []*domain.Event{}
+	for _, p := range progress {
+		if p.Status != domain.GoalAchieved {
+			continue
+		}
+		//同じ期間に達成済みの場合は記録しない
+		exists, err := gc.er.ExistsGoalReached(ctx, authUserId, p.Goal.ID, p.PeriodStart)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			events = append(events, &domain.Event{AuthUserId: authUserId, Type: domain.EventGoalReached, GoalId: p.Goal.ID})
+		}
+	}
+	return gc.er.CreateEvents(ctx, events...)
+}
@@ -0,0 +1,164 @@
+package controller_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestExceededSpendCaps(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	goals := []*domain.Goal{
+		{
+			AuthUserId: authUserId,
+			Kind:       domain.GoalSpend,
+			Period:     domain.GoalMonthly,
+			Target:     2000,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			AuthUserId: authUserId,
+			Kind:       domain.GoalSpend,
+			Period:     domain.GoalYearly,
+			Target:     30000,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, goals...)
+	books := []*domain.Book{
+		{
+			Title:      "容疑者Xの献身",
+			Author:     "東野圭吾",
+			Page:       330,
+			Price:      1640,
+			BookStatus: domain.Bought,
+			AuthUserId: authUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			Title:      "ガリレオの苦悩",
+			Author:     "東野圭吾",
+			Page:       890,
+			Price:      1240,
+			BookStatus: domain.Bought,
+			AuthUserId: authUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, books...)
+
+	gr := repository.NewGoal(bundb, cl)
+	sr := repository.NewShelf(bundb, cl)
+	ur := repository.NewUser(bundb, cl)
+	rr := repository.NewRate(bundb, cl)
+	er := repository.NewEvent(bundb, cl)
+	sut := controller.NewGoal(gr, sr, ur, rr, er, cl)
+
+	a := assert.New(t)
+
+	//Act ***************
+	got, err := sut.ExceededSpendCaps(ctx, authUserId)
+
+	//Assert ***************
+	a.Nil(err)
+	a.Len(got, 1)
+	a.Equal(domain.GoalMonthly, got[0].Goal.Period)
+	a.Equal(2880, got[0].Current)
+	a.Equal(domain.JPY, got[0].Goal.Currency)
+}
+
+func TestRecordReachedGoals(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	goals := []*domain.Goal{
+		{
+			AuthUserId: authUserId,
+			Kind:       domain.GoalBooks,
+			Period:     domain.GoalMonthly,
+			Target:     1,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			AuthUserId: authUserId,
+			Kind:       domain.GoalBooks,
+			Period:     domain.GoalYearly,
+			Target:     12,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, goals...)
+	book := &domain.Book{
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Read,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+
+	gr := repository.NewGoal(bundb, cl)
+	sr := repository.NewShelf(bundb, cl)
+	ur := repository.NewUser(bundb, cl)
+	rr := repository.NewRate(bundb, cl)
+	er := repository.NewEvent(bundb, cl)
+	sut := controller.NewGoal(gr, sr, ur, rr, er, cl)
+
+	a := assert.New(t)
+
+	//Act ***************
+	errFirst := sut.RecordReachedGoals(ctx, authUserId)
+	//同じ期間に達成済みの目標は重ねて記録しない
+	errSecond := sut.RecordReachedGoals(ctx, authUserId)
+	events, err := er.FindEventsAfter(ctx, authUserId, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert ***************
+	a.Nil(errFirst)
+	a.Nil(errSecond)
+	a.Len(events, 1)
+	a.Equal(domain.EventGoalReached, events[0].Type)
+	a.Equal(goals[0].ID, events[0].GoalId)
+}
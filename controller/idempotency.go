@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+var (
+	ErrIdempotencyMismatch   = domain.NewError(domain.ErrConflict, "同じIdempotency-Keyで異なるリクエストです")
+	ErrIdempotencyInProgress = domain.NewError(domain.ErrConflict, "同じIdempotency-Keyのリクエストを処理中です")
+)
+
+// 登録が競合した（確認までの間にキーが削除された）場合の再試行の回数
+const claimRetries = 3
+
+type Idempotency struct {
+	ir  *repository.Idempotency
+	cl  utils.Clock
+	ttl time.Duration
+}
+
+// ttlはレスポンスを保存する期間
+func NewIdempotency(ir *repository.Idempotency, cl utils.Clock, ttl time.Duration) *Idempotency {
+	return &Idempotency{ir: ir, cl: cl, ttl: ttl}
+}
+
+// キーを処理中として確保する。確保できた場合は(nil, nil)を返し、呼び出し元はリクエストを処理する。
+// 同じリクエストのレスポンスが保存済みの場合は、そのレコードを返す（再送への応答に使う）。
+// 同じキーで指紋が異なる場合はErrIdempotencyMismatch、処理中の場合はErrIdempotencyInProgressを返す。
+func (ic *Idempotency) Begin(ctx context.Context, key string, fingerprint string) (*domain.IdempotencyRecord, error) {
+	var err error
+	for range claimRetries {
+		rec := &domain.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			ExpiresAt:   ic.cl.Now().Add(domain.DefaultIdempotencyLockTimeout),
+		}
+		var existing *domain.IdempotencyRecord
+		existing, err = ic.ir.Claim(ctx, rec)
+		if errors.Is(err, domain.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, nil
+		}
+
+		if existing.Fingerprint != fingerprint {
+			return nil, utils.NewErrChains(ErrIdempotencyMismatch, nil)
+		}
+		if existing.InProgress() {
+			return nil, utils.NewErrChains(ErrIdempotencyInProgress, nil)
+		}
+		return existing, nil
+	}
+
+	return nil, fmt.Errorf("キーの確保に失敗:%w", err)
+}
+
+// 処理が済んだキーにレスポンスを保存する
+func (ic *Idempotency) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	rec := &domain.IdempotencyRecord{
+		Key:         key,
+		Status:      status,
+		ContentType: contentType,
+		Body:        body,
+		ExpiresAt:   ic.cl.Now().Add(ic.ttl),
+	}
+	return ic.ir.Complete(ctx, rec)
+}
+
+// 処理に失敗したキーを解放し、同じキーで再試行できるようにする
+func (ic *Idempotency) Release(ctx context.Context, key string) error {
+	return ic.ir.Release(ctx, key)
+}
+
+// 有効期限を過ぎたキーを削除
+func (ic *Idempotency) Purge(ctx context.Context) error {
+	n, err := ic.ir.PurgeExpired(ctx, ic.cl.Now())
+	if err != nil {
+		return fmt.Errorf("冪等キーの削除に失敗:%w", err)
+	}
+
+	if n > 0 {
+		log.Printf("有効期限を過ぎた冪等キーを削除しました（%d件）", n)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+// ジョブの種類
+const (
+	JobTrashPurge       = "trash.purge"
+	JobIdempotencyPurge = "idempotency.purge"
+	JobEventPurge       = "events.purge"
+	JobWebhookPurge     = "webhooks.purge"
+	JobJobPurge         = "jobs.purge"
+	JobDigestMonthly    = "digest.monthly" //月次のまとめの送信ジョブを送信先ごとに登録する
+	JobDigestSend       = "digest.send"    //1人分の月次のまとめを送信する
+	JobLockoutPurge     = "lockout.purge"
+	JobRateLimitPurge   = "ratelimit.purge"
+)
+
+// ジョブの処理。エラーを返すと、試行回数の上限まで間隔を空けて再実行する。
+// 占有期間を過ぎると他のサーバーでも実行されるため、同じジョブを複数回実行しても問題ない処理にすること。
+type JobFunc func(ctx context.Context, job *domain.Job) error
+
+var errJobTimedOut = errors.New("占有期間内にジョブが完了しませんでした")
+
+// Postgresのjobsテーブルをキューとするジョブの実行と、cron形式の定期実行
+type Jobs struct {
+	jr          *repository.Job
+	cl          utils.Clock
+	concurrency int
+	visibility  time.Duration
+	retention   time.Duration
+
+	mu        sync.Mutex
+	funcs     map[string]JobFunc
+	schedules []*scheduledJob
+
+	running sync.WaitGroup
+	slots   chan struct{}
+	// 実行中のジョブのcontext。Runのctxとは別にし、シャットダウン時は実行中のジョブの完了を待つ（Drain）
+	jobCtx     context.Context
+	cancelJobs context.CancelFunc
+}
+
+type scheduledJob struct {
+	kind     string
+	schedule *domain.Schedule
+	next     time.Time
+}
+
+// concurrencyは同時に実行するジョブの上限、visibilityは実行中のジョブを占有する期間、retentionは終了したジョブの保持期間
+func NewJobs(jr *repository.Job, cl utils.Clock, concurrency int, visibility time.Duration, retention time.Duration) *Jobs {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if visibility <= 0 {
+		visibility = domain.DefaultJobVisibilityTimeout
+	}
+	jobCtx, cancel := context.WithCancel(context.Background())
+	return &Jobs{
+		jr:          jr,
+		cl:          cl,
+		concurrency: concurrency,
+		visibility:  visibility,
+		retention:   retention,
+		funcs:       map[string]JobFunc{},
+		slots:       make(chan struct{}, concurrency),
+		jobCtx:      jobCtx,
+		cancelJobs:  cancel,
+	}
+}
+
+// kindのジョブの処理を登録する。登録した種類のジョブのみ取得して実行する。
+func (jc *Jobs) Register(kind string, fn JobFunc) {
+	jc.mu.Lock()
+	defer jc.mu.Unlock()
+	jc.funcs[kind] = fn
+}
+
+// 登録済みのkindのジョブを、cron形式（例."0 3 * * *"、"@hourly"）の予定で定期実行する。予定は日本時間で判定する。
+// 予定の時刻ごとのジョブは複数のサーバーで登録しても1件になり、いずれかのサーバーで1回実行される。
+func (jc *Jobs) Schedule(kind string, spec string) error {
+	s, err := domain.ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("%s(%s):%w", kind, spec, err)
+	}
+
+	jc.mu.Lock()
+	defer jc.mu.Unlock()
+	if _, ok := jc.funcs[kind]; !ok {
+		return fmt.Errorf("%s:ジョブの処理が登録されていません", kind)
+	}
+	jc.schedules = append(jc.schedules, &scheduledJob{kind: kind, schedule: s, next: s.Next(jc.cl.Now().In(utils.JST))})
+	return nil
+}
+
+// kindのジョブをrunAt（ゼロ値の場合はすぐ）に実行するよう登録する。payloadはJsonにして渡す。
+func (jc *Jobs) Enqueue(ctx context.Context, kind string, payload any, runAt time.Time) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("ジョブのペイロードの変換に失敗:%w", err)
+	}
+	_, err = jc.jr.EnqueueJob(ctx, &domain.Job{Kind: kind, Payload: data, RunAt: runAt})
+	return err
+}
+
+// keyが同じジョブが登録済みでなければ登録する（1度だけ実行したいジョブ）。登録した場合はtrueを返す
+func (jc *Jobs) EnqueueUnique(ctx context.Context, kind string, key string, payload any, runAt time.Time) (bool, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return false, fmt.Errorf("ジョブのペイロードの変換に失敗:%w", err)
+	}
+	return jc.jr.EnqueueJob(ctx, &domain.Job{Kind: kind, Payload: data, UniqueKey: key, RunAt: runAt})
+}
+
+// 予定の時刻を過ぎた定期実行のジョブを登録する
+func (jc *Jobs) enqueueScheduled(ctx context.Context) error {
+	now := jc.cl.Now().In(utils.JST)
+
+	jc.mu.Lock()
+	due := []*domain.Job{}
+	for _, s := range jc.schedules {
+		if s.next.IsZero() || s.next.After(now) {
+			continue
+		}
+		due = append(due, &domain.Job{Kind: s.kind, UniqueKey: domain.ScheduledJobKey(s.kind, s.next), RunAt: s.next})
+		s.next = s.schedule.Next(now)
+	}
+	jc.mu.Unlock()
+
+	for _, j := range due {
+		if _, err := jc.jr.EnqueueJob(ctx, j); err != nil {
+			return fmt.Errorf("定期実行のジョブの登録に失敗(%s):%w", j.Kind, err)
+		}
+	}
+	return nil
+}
+
+// 実行できる枠の数だけジョブを取得し、それぞれ別のgoroutineで実行する。取得したジョブの件数を返す。
+func (jc *Jobs) Poll(ctx context.Context) (int, error) {
+	if err := jc.enqueueScheduled(ctx); err != nil {
+		return 0, err
+	}
+
+	jc.mu.Lock()
+	kinds := make([]string, 0, len(jc.funcs))
+	for k := range jc.funcs {
+		kinds = append(kinds, k)
+	}
+	jc.mu.Unlock()
+
+	free := jc.concurrency - len(jc.slots)
+	jobs, err := jc.jr.ClaimJobs(ctx, kinds, free, jc.visibility)
+	if err != nil {
+		return 0, fmt.Errorf("ジョブの取得に失敗:%w", err)
+	}
+	for _, j := range jobs {
+		jc.slots <- struct{}{}
+		jc.running.Add(1)
+		go func() {
+			defer func() {
+				<-jc.slots
+				jc.running.Done()
+			}()
+			jc.run(j)
+		}()
+	}
+	return len(jobs), nil
+}
+
+// ジョブを1回実行し、結果を保存する
+func (jc *Jobs) run(j *domain.Job) {
+	jc.mu.Lock()
+	fn := jc.funcs[j.Kind]
+	jc.mu.Unlock()
+
+	var err error
+	if j.Attempts > j.MaxAttempts {
+		//占有期間を過ぎて取得し直したジョブが、試行回数の上限を超えた
+		err = errJobTimedOut
+	} else {
+		ctx, cancel := context.WithTimeout(jc.jobCtx, jc.visibility)
+		err = runJobFunc(ctx, fn, j)
+		cancel()
+	}
+
+	now := jc.cl.Now()
+	if err != nil {
+		log.Printf("ジョブの実行に失敗(id=%d, kind=%s, 試行:%d/%d):%s", j.ID, j.Kind, j.Attempts, j.MaxAttempts, err)
+		j.Fail(now, err)
+	} else {
+		j.Status = domain.JobSucceeded
+		j.LockedUntil = time.Time{}
+		j.LastError = ""
+		j.UpdatedAt = now
+	}
+
+	//シャットダウン中でも結果を保存する
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = jc.jr.SaveJobResult(ctx, j)
+	if errors.Is(err, domain.ErrJobLeaseLost) {
+		log.Printf("ジョブの結果を破棄(id=%d, 試行:%d):他の実行に取得されています", j.ID, j.Attempts)
+		return
+	}
+	if err != nil {
+		log.Printf("ジョブの結果の保存に失敗(id=%d):%s", j.ID, err)
+	}
+}
+
+// ジョブの処理のpanicはエラーとして扱い、他のジョブの実行を止めない
+func runJobFunc(ctx context.Context, fn JobFunc, j *domain.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic:%v", r)
+		}
+	}()
+	return fn(ctx, j)
+}
+
+// ctxがキャンセルされるまでintervalごとにPollを実行する。キャンセル後は新しいジョブを取得しない（実行中のジョブはDrainで待つ）。
+func (jc *Jobs) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := jc.Poll(ctx); err != nil && ctx.Err() == nil {
+			log.Println(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 実行中のジョブの完了を待つ。ctxの期限までに完了しない場合はジョブのcontextをキャンセルし、ctxのエラーを返す。
+// キャンセルしたジョブは失敗として記録され、他のサーバー（または再起動後）で再実行される。
+func (jc *Jobs) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		jc.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		jc.cancelJobs()
+		//キャンセルを受けたジョブが結果を保存するまで少しだけ待つ
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+		return ctx.Err()
+	}
+}
+
+// 保持期間を過ぎた終了済みのジョブを削除
+func (jc *Jobs) Purge(ctx context.Context) error {
+	n, err := jc.jr.PurgeJobs(ctx, jc.cl.Now().Add(-jc.retention))
+	if err != nil {
+		return fmt.Errorf("ジョブの削除に失敗:%w", err)
+	}
+
+	if n > 0 {
+		log.Printf("保持期間を過ぎたジョブを削除しました（%d件）", n)
+	}
+	return nil
+}
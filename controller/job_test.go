@@ -0,0 +1,131 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+func TestJobsPoll(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := controller.NewJobs(repository.NewJob(bundb, cl), cl, 2, time.Minute, domain.DefaultJobRetention)
+	got := make(chan string, 10)
+	sut.Register("ok", func(ctx context.Context, job *domain.Job) error {
+		got <- string(job.Payload)
+		return nil
+	})
+	sut.Register("panic", func(ctx context.Context, job *domain.Job) error {
+		panic("想定外のエラー")
+	})
+	sut.Register("failing", func(ctx context.Context, job *domain.Job) error {
+		return errors.New("失敗")
+	})
+	for _, kind := range []string{"ok", "panic", "failing"} {
+		if err := sut.Enqueue(ctx, kind, map[string]string{"kind": kind}, time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := assert.New(t)
+
+	//Act ***************
+	first, errFirst := sut.Poll(ctx)
+	second, errSecond := sut.Poll(ctx)
+	errDrain := sut.Drain(ctx)
+	third, errThird := sut.Poll(ctx)
+	errDrainAgain := sut.Drain(ctx)
+	//失敗したジョブは再試行を待つ
+	fourth, errFourth := sut.Poll(ctx)
+
+	//Assert ***************
+	a.Nil(errFirst)
+	a.Nil(errSecond)
+	a.Nil(errDrain)
+	a.Nil(errThird)
+	a.Nil(errDrainAgain)
+	a.Nil(errFourth)
+	a.Equal(2, first) //同時に実行するのは2件まで
+	a.LessOrEqual(second, 1)
+	a.Equal(3, first+second+third)
+	a.Zero(fourth)
+	a.Equal(`{"kind": "ok"}`, <-got)
+}
+
+func TestJobsDrainWithTimeout(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := controller.NewJobs(repository.NewJob(bundb, cl), cl, 1, time.Minute, domain.DefaultJobRetention)
+	canceled := make(chan struct{})
+	sut.Register("slow", func(ctx context.Context, job *domain.Job) error {
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	})
+	if err := sut.Enqueue(ctx, "slow", nil, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sut.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	drainCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	//Act ***************
+	err = sut.Drain(drainCtx)
+
+	//Assert ***************
+	a := assert.New(t)
+	a.ErrorIs(err, context.DeadlineExceeded)
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("実行中のジョブがキャンセルされません")
+	}
+}
+
+func TestJobsSchedule(t *testing.T) {
+	//Arrange ***************
+	sut := controller.NewJobs(nil, cl, 1, time.Minute, domain.DefaultJobRetention)
+	sut.Register("trash.purge", func(ctx context.Context, job *domain.Job) error { return nil })
+
+	//Act ***************
+	errOK := sut.Schedule("trash.purge", "@hourly")
+	errSpec := sut.Schedule("trash.purge", "every hour")
+	errKind := sut.Schedule("unknown", "@hourly")
+
+	//Assert ***************
+	a := assert.New(t)
+	a.Nil(errOK)
+	a.ErrorIs(errSpec, domain.ErrInvalidSchedule)
+	a.NotNil(errKind)
+}
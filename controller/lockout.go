@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+// 認証の失敗を単位（domain.LockoutScope）とキーごとに数え、続けて失敗したキーをロックする。
+// ロックの方針はdomain.LockoutPoliciesに従う。
+type Lockout struct {
+	lr        *repository.Lockout
+	cl        utils.Clock
+	retention time.Duration
+}
+
+// retentionはロックしていない失敗の記録を保持する期間（最後の失敗から）
+func NewLockout(lr *repository.Lockout, cl utils.Clock, retention time.Duration) *Lockout {
+	return &Lockout{lr: lr, cl: cl, retention: retention}
+}
+
+// キーがロック中の場合は*domain.LockedErrorを返す
+func (lc *Lockout) Check(ctx context.Context, scope domain.LockoutScope, key string) error {
+	f, err := lc.lr.FindAuthFailure(ctx, scope, key)
+	if err != nil {
+		return fmt.Errorf("認証の失敗の記録の取得に失敗:%w", err)
+	}
+	if d := f.RetryAfter(lc.cl.Now()); d > 0 {
+		return &domain.LockedError{RetryAfter: d}
+	}
+	return nil
+}
+
+// 失敗を1回記録し、更新後の記録とロックしたかを返す
+func (lc *Lockout) Fail(ctx context.Context, scope domain.LockoutScope, key string) (*domain.AuthFailure, bool, error) {
+	f, locked, err := lc.lr.RecordFailure(ctx, scope, key, domain.LockoutPolicies[scope])
+	if err != nil {
+		return nil, false, fmt.Errorf("認証の失敗の記録に失敗:%w", err)
+	}
+	return f, locked, nil
+}
+
+// 認証に成功したキーの失敗の回数を0に戻す
+func (lc *Lockout) Reset(ctx context.Context, scope domain.LockoutScope, key string) error {
+	return lc.lr.ResetFailures(ctx, scope, key)
+}
+
+// 保持期間を過ぎた失敗の記録を削除する
+func (lc *Lockout) Purge(ctx context.Context) error {
+	n, err := lc.lr.PurgeAuthFailures(ctx, lc.cl.Now().Add(-lc.retention))
+	if err != nil {
+		return fmt.Errorf("認証の失敗の記録の削除に失敗:%w", err)
+	}
+
+	if n > 0 {
+		log.Printf("保持期間を過ぎた認証の失敗の記録を削除しました（%d件）", n)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/mailer"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+// 一度に取得する月次のまとめの送信先の件数
+const digestBatchSize = 100
+
+// 1人分の月次のまとめの送信ジョブのペイロード
+type DigestJob struct {
+	AuthUserId string `json:"authUserId"`
+	Year       int    `json:"year"`
+	Month      int    `json:"month"`
+}
+
+// メールの設定と月次のまとめの送信
+type Mail struct {
+	mr     *repository.Mail
+	ur     *repository.User
+	rc     *Record
+	cc     *Chart
+	bc     *Backlog
+	jc     *Jobs
+	m      mailer.Mailer
+	cl     utils.Clock
+	secret string
+	page   string
+	click  string
+}
+
+// secretは配信停止のトークンの署名の鍵。pageはメール本文の配信停止のリンク先（フロントエンドのページ）、
+// clickはワンクリックでの配信停止（RFC 8058）のURLで、それぞれクエリtokenにトークンを付けて送る。
+func NewMail(mr *repository.Mail, ur *repository.User, rc *Record, cc *Chart, bc *Backlog, jc *Jobs, m mailer.Mailer, cl utils.Clock, secret string, page string, click string) *Mail {
+	return &Mail{mr: mr, ur: ur, rc: rc, cc: cc, bc: bc, jc: jc, m: m, cl: cl, secret: secret, page: page, click: click}
+}
+
+func (mc *Mail) GetMailSetting(ctx context.Context, authUserId string) (*domain.MailSetting, error) {
+	if _, err := mc.ur.FindUserByAuthUserId(ctx, authUserId); err != nil {
+		return nil, err
+	}
+	return mc.mr.FindMailSetting(ctx, authUserId)
+}
+
+func (mc *Mail) UpdateMailSetting(ctx context.Context, s *domain.MailSetting) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if _, err := mc.ur.FindUserByAuthUserId(ctx, s.AuthUserId); err != nil {
+		return err
+	}
+	return mc.mr.SaveMailSetting(ctx, s)
+}
+
+// メールのリンクのトークンで月次のまとめの配信を停止する
+func (mc *Mail) Unsubscribe(ctx context.Context, token string) error {
+	authUserId, err := domain.ParseUnsubscribeToken(mc.secret, token)
+	if err != nil {
+		return err
+	}
+	return mc.mr.Unsubscribe(ctx, authUserId)
+}
+
+// year年month月のまとめを作成する。金額はユーザーの基準通貨に換算する
+func (mc *Mail) BuildDigest(ctx context.Context, authUserId string, year int, month int) (*domain.Digest, error) {
+	record, err := mc.rc.GetRecord(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	charts, err := mc.cc.GetCharts(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	backlog, err := mc.bc.GetBacklog(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewDigest(record, charts, backlog, year, month), nil
+}
+
+// 先月分のまとめの送信ジョブを送信先ごとに登録する（JobDigestMonthly）。
+// ジョブは送信先と月ごとに1つだけ登録するため、再実行しても同じメールを2度送らない
+func (mc *Mail) EnqueueMonthlyDigests(ctx context.Context) error {
+	now := mc.cl.Now().In(utils.JST)
+	last := now.AddDate(0, 0, -now.Day()) //先月の末日
+	year, month := last.Year(), int(last.Month())
+
+	var afterId int64
+	for {
+		recipients, err := mc.mr.FindDigestRecipients(ctx, afterId, digestBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, r := range recipients {
+			key := fmt.Sprintf("%s:%s:%04d-%02d", JobDigestSend, r.AuthUserId, year, month)
+			payload := &DigestJob{AuthUserId: r.AuthUserId, Year: year, Month: month}
+			if _, err := mc.jc.EnqueueUnique(ctx, JobDigestSend, key, payload, now); err != nil {
+				return err
+			}
+			afterId = r.ID
+		}
+		if len(recipients) < digestBatchSize {
+			return nil
+		}
+	}
+}
+
+// 1人分の月次のまとめを送信する（JobDigestSend）。登録後に配信を停止したユーザーと、何も記録がない月は送らない
+func (mc *Mail) SendDigest(ctx context.Context, j *domain.Job) error {
+	payload := new(DigestJob)
+	if err := json.Unmarshal(j.Payload, payload); err != nil {
+		return fmt.Errorf("ジョブのペイロードの変換に失敗:%w", err)
+	}
+
+	setting, err := mc.mr.FindMailSetting(ctx, payload.AuthUserId)
+	if err != nil {
+		return err
+	}
+	if !setting.Digest {
+		return nil
+	}
+	user, err := mc.ur.FindUserByAuthUserId(ctx, payload.AuthUserId)
+	if err != nil {
+		return err
+	}
+	digest, err := mc.BuildDigest(ctx, payload.AuthUserId, payload.Year, payload.Month)
+	if err != nil {
+		return err
+	}
+	if digest.IsEmpty() {
+		return nil
+	}
+
+	token := url.QueryEscape(domain.NewUnsubscribeToken(mc.secret, payload.AuthUserId))
+	msg, err := mailer.RenderDigest(setting.Language, &mailer.DigestMail{
+		Name:           user.Name,
+		Digest:         digest,
+		UnsubscribeURL: mc.page + "?token=" + token,
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = string(user.Email)
+	msg.Headers = map[string]string{
+		"List-Unsubscribe":      "<" + mc.click + "?token=" + token + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return mc.m.Send(ctx, msg)
+}
@@ -0,0 +1,140 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/mailer"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+// 送信したメールを記録する
+type recordingMailer struct {
+	sent []*mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestMailMonthlyDigest(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	cr := repository.NewChart(bundb, cl)
+	sr := repository.NewShelf(bundb, cl)
+	ur := repository.NewUser(bundb, cl)
+	rr := repository.NewRate(bundb, cl)
+	jr := repository.NewJob(bundb, cl)
+	book := &domain.Book{Title: "容疑者Xの献身", Author: "東野圭吾", Page: 247, Price: 980, BookStatus: domain.Bought, AuthUserId: authUserId}
+	if err := sr.CreateBookWithCharts(ctx, book, domain.NewChartsFromBook(book)); err != nil {
+		t.Fatal(err)
+	}
+	m := new(recordingMailer)
+	jc := controller.NewJobs(jr, cl, 1, time.Minute, domain.DefaultJobRetention)
+	sut := controller.NewMail(repository.NewMail(bundb, cl), ur,
+		controller.NewRecord(sr, ur, rr), controller.NewChart(cr, ur, rr), controller.NewBacklog(cr, sr, ur, rr, cl),
+		jc, m, cl, "secret", "https://front.example.com/unsubscribe", "https://api.example.com/v1/mail/unsubscribe")
+	a := assert.New(t)
+
+	//Act ***************
+	errEnqueue := sut.EnqueueMonthlyDigests(ctx)
+	errAgain := sut.EnqueueMonthlyDigests(ctx) //再実行しても同じ月のジョブは登録しない
+	jobs, err := jr.ClaimJobs(ctx, []string{controller.JobDigestSend}, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errSend := sut.SendDigest(ctx, jobs[0])
+
+	//Assert ***************
+	a.Nil(errEnqueue)
+	a.Nil(errAgain)
+	a.Len(jobs, 1)
+	var payload controller.DigestJob
+	if err := json.Unmarshal(jobs[0].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	a.Equal(controller.DigestJob{AuthUserId: authUserId, Year: 2024, Month: 1}, payload)
+	a.Nil(errSend)
+	if a.Len(m.sent, 1) {
+		msg := m.sent[0]
+		token := domain.NewUnsubscribeToken("secret", authUserId)
+		a.Equal("tanaka@example.com", msg.To)
+		a.Equal("2024年1月の読書のまとめ", msg.Subject)
+		a.Contains(msg.Text, "積読: 1冊（980 JPY）")
+		a.Contains(msg.Text, "https://front.example.com/unsubscribe?token="+token)
+		a.Equal("<https://api.example.com/v1/mail/unsubscribe?token="+token+">", msg.Headers["List-Unsubscribe"])
+		a.Equal("List-Unsubscribe=One-Click", msg.Headers["List-Unsubscribe-Post"])
+	}
+}
+
+func TestMailUnsubscribe(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	cr := repository.NewChart(bundb, cl)
+	sr := repository.NewShelf(bundb, cl)
+	ur := repository.NewUser(bundb, cl)
+	rr := repository.NewRate(bundb, cl)
+	book := &domain.Book{Title: "容疑者Xの献身", Author: "東野圭吾", Page: 247, Price: 980, BookStatus: domain.Bought, AuthUserId: authUserId}
+	if err := sr.CreateBookWithCharts(ctx, book, domain.NewChartsFromBook(book)); err != nil {
+		t.Fatal(err)
+	}
+	m := new(recordingMailer)
+	sut := controller.NewMail(repository.NewMail(bundb, cl), ur,
+		controller.NewRecord(sr, ur, rr), controller.NewChart(cr, ur, rr), controller.NewBacklog(cr, sr, ur, rr, cl),
+		nil, m, cl, "secret", "", "")
+	token := domain.NewUnsubscribeToken("secret", authUserId)
+	job := &domain.Job{Kind: controller.JobDigestSend, Payload: json.RawMessage(`{"authUserId":"` + authUserId + `","year":2024,"month":2}`)}
+	a := assert.New(t)
+
+	//Act ***************
+	errInvalid := sut.Unsubscribe(ctx, strings.Replace(token, ".", ".x", 1))
+	errUnsubscribe := sut.Unsubscribe(ctx, token)
+	setting, err := sut.GetMailSetting(ctx, authUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errSend := sut.SendDigest(ctx, job)
+
+	//Assert ***************
+	a.ErrorIs(errInvalid, domain.ErrInvalidUnsubscribeToken)
+	a.Nil(errUnsubscribe)
+	a.False(setting.Digest)
+	a.Nil(errSend)
+	a.Empty(m.sent) //配信停止後は送らない
+}
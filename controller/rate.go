@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+type Rate struct {
+	rr *repository.Rate
+}
+
+func NewRate(rr *repository.Rate) *Rate {
+	return &Rate{rr: rr}
+}
+
+func (rtc *Rate) GetRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	rates, err := rtc.rr.FindRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (rtc *Rate) UpdateRates(ctx context.Context, rates []*domain.ExchangeRate) error {
+	if err := rtc.rr.UpsertRates(ctx, rates); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ローカルの為替レートファイルを読み込んでデータベースに反映
+func (rtc *Rate) LoadRatesFromFile(ctx context.Context, path string) error {
+	rates, err := domain.LoadExchangeRatesFile(path)
+	if err != nil {
+		return err
+	}
+	return rtc.UpdateRates(ctx, rates)
+}
+
+// ユーザーの基準通貨と為替レート表を返すヘルパー関数。
+// ユーザーが未登録の場合はDefaultCurrencyを基準通貨とする。
+func homeCurrencyWithRates(ctx context.Context, ur *repository.User, rr *repository.Rate, authUserId string) (domain.Currency, domain.ExchangeRates, error) {
+	home, err := homeCurrency(ctx, ur, authUserId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rates, err := rr.FindRates(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return home, domain.NewExchangeRates(rates), nil
+}
+
+// ユーザーの基準通貨。ユーザーがない場合は既定の通貨
+func homeCurrency(ctx context.Context, ur *repository.User, authUserId string) (domain.Currency, error) {
+	user, err := ur.FindUserByAuthUserId(ctx, authUserId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultCurrency, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.HomeCurrency.OrDefault(), nil
+}
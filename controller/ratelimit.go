@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+// キーごとのリクエスト数を数え、レート制限の結果を返す
+type RateLimiter struct {
+	rr *repository.RateLimit
+	cl utils.Clock
+}
+
+func NewRateLimiter(rr *repository.RateLimit, cl utils.Clock) *RateLimiter {
+	return &RateLimiter{rr: rr, cl: cl}
+}
+
+// キーのリクエストを1件数え、上限（rl）に対する結果を返す
+func (rc *RateLimiter) Allow(ctx context.Context, key string, rl domain.RateLimit) (*domain.RateLimitResult, error) {
+	now := rc.cl.Now()
+	count, err := rc.rr.Increment(ctx, key, rl, now)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト数の更新に失敗:%w", err)
+	}
+	return domain.NewRateLimitResult(rl, count, now), nil
+}
+
+// 区間の終了したリクエスト数を削除する
+func (rc *RateLimiter) Purge(ctx context.Context) error {
+	n, err := rc.rr.PurgeExpired(ctx, rc.cl.Now())
+	if err != nil {
+		return fmt.Errorf("リクエスト数の削除に失敗:%w", err)
+	}
+
+	if n > 0 {
+		log.Printf("区間の終了したリクエスト数を削除しました（%d件）", n)
+	}
+	return nil
+}
@@ -9,19 +9,32 @@ import (
 
 type Record struct {
 	sr *repository.Shelf
+	ur *repository.User
+	rr *repository.Rate
 }
 
-func NewRecord(sr *repository.Shelf) *Record {
-	return &Record{sr: sr}
+func NewRecord(sr *repository.Shelf, ur *repository.User, rr *repository.Rate) *Record {
+	return &Record{sr: sr, ur: ur, rr: rr}
 }
 
+// 記録の金額はユーザーの基準通貨に換算して返す
 func (rc *Record) GetRecord(ctx context.Context, authUserId string) (*domain.Record, error) {
 	books, err := rc.sr.FindBooksByAuthUserID(ctx, authUserId)
 	if err != nil {
 		return nil, err
 	}
 
-	record := domain.NewRecordFromBooks(books)
+	home, rates, err := homeCurrencyWithRates(ctx, rc.ur, rc.rr, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := rates.ConvertBooks(books, home)
+	if err != nil {
+		return nil, err
+	}
+
+	record := domain.NewRecordFromBooks(converted)
+	record.Currency = home
 
 	return record, nil
 }
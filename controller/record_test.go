@@ -88,10 +88,13 @@ func TestGetRecord(t *testing.T) {
 		VolumesRead: 2,
 		Pages:       1723,
 		PagesRead:   1220,
+		Currency:    domain.JPY,
 	}
 
 	sr := repository.NewShelf(bundb, cl)
-	sut := controller.NewRecord(sr)
+	ur := repository.NewUser(bundb, cl)
+	rr := repository.NewRate(bundb, cl)
+	sut := controller.NewRecord(sr, ur, rr)
 	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
 
 	a := assert.New(t)
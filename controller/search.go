@@ -6,14 +6,18 @@ import (
 	"github.com/taimats/bhapi/domain"
 )
 
-type SearchBooks struct{}
+// 書籍の検索。apiURLはGoogleBooksAPIのURL、apiKeyはそのキー（空の場合はキーなし）
+type SearchBooks struct {
+	apiURL string
+	apiKey string
+}
 
-func NewSearchBooks() *SearchBooks {
-	return &SearchBooks{}
+func NewSearchBooks(apiURL string, apiKey string) *SearchBooks {
+	return &SearchBooks{apiURL: apiURL, apiKey: apiKey}
 }
 
-func (sb *SearchBooks) SearchBooks(ctx context.Context, q string, baseURL string) ([]*domain.BookResult, error) {
-	books, err := domain.SearchForGoogleBooks(q, baseURL)
+func (sb *SearchBooks) SearchBooks(ctx context.Context, q string) ([]*domain.BookResult, error) {
+	books, err := domain.SearchForGoogleBooks(q, sb.apiURL, sb.apiKey)
 	if err != nil {
 		return nil, err
 	}
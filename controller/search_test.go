@@ -19,40 +19,45 @@ func TestSearchBooks(t *testing.T) {
 			ImageURL: "http://books.google.com/books/content?id=TL3APAAACAAJ&printsec=frontcover&img=1&zoom=1&source=gbs_api",
 			Title:    "容疑者Xの献身",
 			Author:   "東野圭吾",
-			Page:     "0",
-			Price:    "0",
+			Page:     0,
+			Price:    0,
+			Currency: domain.JPY,
 		},
 		{
 			ISBN10:   "",
 			ImageURL: "http://books.google.com/books/content?id=eNjdDwAAQBAJ&printsec=frontcover&img=1&zoom=1&edge=curl&source=gbs_api",
 			Title:    "容疑者Xの献身",
 			Author:   "東野圭吾",
-			Page:     "234",
-			Price:    "770",
+			Page:     234,
+			Price:    770,
+			Currency: domain.JPY,
 		},
 		{
 			ISBN10:   "",
 			ImageURL: "http://books.google.com/books/content?id=hQDeDwAAQBAJ&printsec=frontcover&img=1&zoom=1&edge=curl&source=gbs_api",
 			Title:    "容疑者Xの献身　無料試し読み版",
 			Author:   "東野圭吾",
-			Page:     "49",
-			Price:    "0",
+			Page:     49,
+			Price:    0,
+			Currency: domain.JPY,
 		},
 		{
 			ISBN10:   "416711013X",
 			ImageURL: "http://books.google.com/books/content?id=1LcsAwEACAAJ&printsec=frontcover&img=1&zoom=1&source=gbs_api",
 			Title:    "ガリレオの苦悩",
 			Author:   "東野圭吾",
-			Page:     "0",
-			Price:    "0",
+			Page:     0,
+			Price:    0,
+			Currency: domain.JPY,
 		},
 		{
 			ISBN10:   "4167110083",
 			ImageURL: "http://books.google.com/books/content?id=xdM9ywAACAAJ&printsec=frontcover&img=1&zoom=1&source=gbs_api",
 			Title:    "予知夢",
 			Author:   "東野圭吾",
-			Page:     "0",
-			Price:    "0",
+			Page:     0,
+			Price:    0,
+			Currency: domain.JPY,
 		},
 	}
 	q := "容疑者の献身"
@@ -67,12 +72,12 @@ func TestSearchBooks(t *testing.T) {
 	testURL := u.JoinPath("books", "v1", "volumes").String()
 
 	ctx := context.Background()
-	sut := controller.NewSearchBooks()
+	sut := controller.NewSearchBooks(testURL, "")
 
 	a := assert.New(t)
 
 	//Act ***************
-	got, err := sut.SearchBooks(ctx, q, testURL)
+	got, err := sut.SearchBooks(ctx, q)
 
 	//Assert ***************
 	a.Nil(err)
@@ -2,11 +2,13 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
 )
 
 type Shelf struct {
@@ -33,6 +35,10 @@ func (sc *Shelf) GetShelf(ctx context.Context, authUserId string) ([]*domain.Boo
 	if err != nil {
 		return nil, err
 	}
+	//本が1冊もない場合は、API仕様のとおり本棚なし（404）とする
+	if len(books) == 0 {
+		return nil, utils.NewErrChains(domain.ErrNotFound, nil)
+	}
 
 	return books, nil
 }
@@ -46,13 +52,61 @@ func (sc *Shelf) UpdateShelf(ctx context.Context, book *domain.Book) error {
 	return err
 }
 
-func (sc *Shelf) DeleteShelf(ctx context.Context, bookIds []string) error {
+// 本の変更履歴（更新前の版）を新しい順に返す
+func (sc *Shelf) GetBookHistory(ctx context.Context, authUserId string, bookId int64) ([]*domain.BookRevision, error) {
+	return sc.sr.FindBookRevisions(ctx, authUserId, bookId)
+}
+
+// 本を変更履歴の版の内容に戻し、更新後の本を返す。チャートも再計算する。
+// versionが0以外の場合は、そのバージョンの本にのみ適用する（不一致はErrPreconditionFailed）。
+func (sc *Shelf) RevertBook(ctx context.Context, authUserId string, bookId int64, revisionId int64, version int64) (*domain.Book, error) {
+	return sc.sr.RevertBookWithCharts(ctx, authUserId, bookId, revisionId, version)
+}
+
+// 部分更新（PATCH）で他の更新と競合した場合に、取得からやり直す回数
+const patchRetries = 3
+
+// 本の部分更新。登録済みの本にpatchを適用し、patchが返す列（bunの列名）のみ更新して更新後の本を返す。
+// versionが0以外の場合は、そのバージョンの本にのみ適用する（不一致はErrPreconditionFailed）。
+// versionが0の場合は取得時のバージョンで更新し、他の更新と競合した場合は取得からやり直す。
+func (sc *Shelf) PatchShelf(ctx context.Context, authUserId string, bookId int64, version int64, patch func(*domain.Book) ([]string, error)) (*domain.Book, error) {
+	for i := 0; ; i++ {
+		book, err := sc.sr.FindBookByID(ctx, authUserId, bookId)
+		if err != nil {
+			return nil, err
+		}
+		if version > 0 && book.Version != version {
+			return nil, utils.NewErrChains(domain.ErrPreconditionFailed, nil)
+		}
+
+		columns, err := patch(book)
+		if err != nil {
+			return nil, err
+		}
+		//変更がない場合は更新しない（バージョンも変わらない）
+		if len(columns) == 0 {
+			return book, nil
+		}
+
+		err = sc.sr.PatchBookWithCharts(ctx, book, columns)
+		if errors.Is(err, domain.ErrPreconditionFailed) && version == 0 && i < patchRetries {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return book, nil
+	}
+}
+
+// authUserIdの本を複数削除する。他のユーザーの本を含む場合はErrNotFound
+func (sc *Shelf) DeleteShelf(ctx context.Context, authUserId string, bookIds []string) error {
 	books, err := newBooksFromBookIds(bookIds)
 	if err != nil {
 		return err
 	}
 
-	err = sc.sr.DleteBooksWithCharts(ctx, books)
+	err = sc.sr.DleteBooksWithCharts(ctx, authUserId, books)
 	if err != nil {
 		return err
 	}
@@ -73,3 +127,57 @@ func newBooksFromBookIds(bookIds []string) ([]*domain.Book, error) {
 	}
 	return books, nil
 }
+
+// atomicの一括操作で、いずれかの操作が失敗したためトランザクションを取り消す
+var errBatchAborted = errors.New("一括操作を取り消しました")
+
+// 本棚の一括操作。opsをひとつのトランザクションで順に実行し、操作ごとの結果を返す。
+// 各操作はセーブポイント内で実行するため、失敗した操作の変更のみ取り消される。
+// bestEffortがfalseの場合は、いずれかの操作が失敗するとすべて取り消す（成功した操作はAborted）。
+// 操作ごとの失敗は結果に含め、エラーはトランザクション自体の失敗の場合のみ返す。
+func (sc *Shelf) BatchShelf(ctx context.Context, ops []*domain.ShelfOperation, bestEffort bool) (*domain.ShelfBatchResult, error) {
+	res := &domain.ShelfBatchResult{Results: make([]*domain.ShelfOperationResult, len(ops))}
+
+	err := sc.sr.RunInTx(ctx, func(ctx context.Context, st *repository.ShelfTx) error {
+		failed := false
+		for i, op := range ops {
+			err := st.Savepoint(ctx, func(ctx context.Context, st *repository.ShelfTx) error {
+				return runShelfOperation(ctx, st, op)
+			})
+			res.Results[i] = &domain.ShelfOperationResult{Kind: op.Kind, Book: op.Book, Err: err}
+			if err != nil {
+				failed = true
+			}
+		}
+		if failed && !bestEffort {
+			return errBatchAborted
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchAborted) {
+		for _, r := range res.Results {
+			r.Aborted = r.Err == nil
+		}
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Committed = true
+	return res, nil
+}
+
+func runShelfOperation(ctx context.Context, st *repository.ShelfTx, op *domain.ShelfOperation) error {
+	switch op.Kind {
+	case domain.ShelfOpCreate:
+		return st.CreateBookWithCharts(ctx, op.Book, domain.NewChartsFromBook(op.Book))
+	case domain.ShelfOpUpdate:
+		return st.UpdateBookWithCharts(ctx, op.Book)
+	case domain.ShelfOpStatus:
+		return st.UpdateBookStatus(ctx, op.Book)
+	case domain.ShelfOpDelete:
+		return st.DeleteBookWithCharts(ctx, op.Book.AuthUserId, op.Book.ID)
+	}
+	return utils.NewErrChains(domain.ErrValidation, fmt.Errorf("未対応の操作:%s", op.Kind))
+}
@@ -145,6 +145,32 @@ func TestGetShelf(t *testing.T) {
 	a.NotEmpty(got)
 }
 
+func TestGetShelfEmpty(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sr := repository.NewShelf(bundb, cl)
+	sut := controller.NewShelf(sr)
+	a := assert.New(t)
+
+	//Act ***************
+	got, err := sut.GetShelf(ctx, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
+
+	//Assert ***************
+	a.ErrorIs(err, domain.ErrNotFound)
+	a.Nil(got)
+}
+
 func TestDeleteShelf(t *testing.T) {
 	//Arrange ***************
 	ctx := context.Background()
@@ -204,7 +230,7 @@ func TestDeleteShelf(t *testing.T) {
 	a := assert.New(t)
 
 	//Act ***************
-	err = sut.DeleteShelf(ctx, bookIds)
+	err = sut.DeleteShelf(ctx, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", bookIds)
 
 	//Assert ***************
 	a.Nil(err)
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+type Trash struct {
+	sr        *repository.Shelf
+	ur        *repository.User
+	cl        utils.Clock
+	retention time.Duration
+}
+
+// retentionが0以下の場合はdomain.DefaultTrashRetentionを使用
+func NewTrash(sr *repository.Shelf, ur *repository.User, cl utils.Clock, retention time.Duration) *Trash {
+	if retention <= 0 {
+		retention = domain.DefaultTrashRetention
+	}
+	return &Trash{sr: sr, ur: ur, cl: cl, retention: retention}
+}
+
+// 削除から完全に削除されるまでの保持期間
+func (tc *Trash) Retention() time.Duration {
+	return tc.retention
+}
+
+// 削除済みのユーザーと本を返す
+func (tc *Trash) GetTrash(ctx context.Context, authUserId string) (*domain.Trash, error) {
+	trash := &domain.Trash{Retention: tc.retention}
+
+	user, err := tc.ur.FindDeletedUserByAuthUserId(ctx, authUserId)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		trash.User = user
+	}
+
+	books, err := tc.sr.FindDeletedBooksByAuthUserID(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	trash.Books = books
+
+	return trash, nil
+}
+
+func (tc *Trash) RestoreBooks(ctx context.Context, authUserId string, bookIds []string) error {
+	ids := make([]int64, len(bookIds))
+	for i, bi := range bookIds {
+		id, err := strconv.ParseInt(bi, 10, 64)
+		if err != nil {
+			return fmt.Errorf("idの数値変換に失敗:%w", err)
+		}
+		ids[i] = id
+	}
+
+	if err := tc.sr.RestoreBooksWithCharts(ctx, authUserId, ids); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tc *Trash) RestoreUser(ctx context.Context, authUserId string) error {
+	if err := tc.ur.RestoreUser(ctx, authUserId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// 保持期間を過ぎた削除済みの本とユーザーを完全に削除
+func (tc *Trash) Purge(ctx context.Context) error {
+	before := tc.cl.Now().Add(-tc.retention)
+
+	books, err := tc.sr.PurgeBooksWithCharts(ctx, before)
+	if err != nil {
+		return fmt.Errorf("本の完全削除に失敗:%w", err)
+	}
+	users, err := tc.ur.PurgeUsers(ctx, before)
+	if err != nil {
+		return fmt.Errorf("ユーザーの完全削除に失敗:%w", err)
+	}
+
+	if books > 0 || users > 0 {
+		log.Printf("ゴミ箱を完全削除しました（本:%d冊、ユーザー:%d件）", books, users)
+	}
+	return nil
+}
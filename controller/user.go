@@ -2,6 +2,10 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
 
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/infra/repository"
@@ -19,9 +23,23 @@ func NewUser(ur *repository.User) *User {
 func (uc *User) RegisterUser(ctx context.Context, user *domain.User) error {
 	_, err := uc.ur.FindUserByAuthUserId(ctx, user.AuthUserId)
 	if err == nil {
-		return utils.NewErrChains(utils.ErrAlrExists, nil)
+		return utils.NewErrChains(domain.ErrConflict, nil)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	//削除済み（ゴミ箱内）のユーザーは再登録ではなく復元で対応する
+	_, err = uc.ur.FindDeletedUserByAuthUserId(ctx, user.AuthUserId)
+	if err == nil {
+		return utils.NewErrChains(domain.ErrConflict, nil)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return err
 	}
 
+	if err := hashPassword(user); err != nil {
+		return err
+	}
 	_, err = uc.ur.CreateUser(ctx, user)
 	if err != nil {
 		return err
@@ -38,24 +56,78 @@ func (uc *User) GetUser(ctx context.Context, authUserId string) (*domain.User, e
 	return user, nil
 }
 
-func (uc *User) DeleteUser(ctx context.Context, authUserId string) error {
-	user, err := uc.ur.FindUserByAuthUserId(ctx, authUserId)
-	if err != nil {
+// ユーザーと本棚、チャート、目標をまとめて削除し、削除したデータ一式のzipアーカイブをwに書き込んで返す。
+// immediateがfalseの場合はゴミ箱の保持期間（retention）後に完全に削除される。
+// アーカイブは削除のトランザクション内で作成し、失敗した場合（domain.ErrExportFailed）は削除しない。
+func (uc *User) DeleteUser(ctx context.Context, authUserId string, immediate bool, retention time.Duration, w io.Writer) (*domain.UserExport, error) {
+	return uc.ur.DeleteUserWithData(ctx, authUserId, immediate, func(export *domain.UserExport) error {
+		export.PurgeAt = export.DeletedAt
+		if !immediate {
+			export.PurgeAt = export.DeletedAt.Add(retention)
+		}
+		if err := export.WriteArchive(w); err != nil {
+			return utils.NewErrChains(domain.ErrExportFailed, err)
+		}
+		return nil
+	})
+}
+
+func (uc *User) UpdateUser(ctx context.Context, user *domain.User) error {
+	if err := hashPassword(user); err != nil {
 		return err
 	}
-
-	err = uc.ur.DleteUser(ctx, user)
-	if err != nil {
+	if err := uc.ur.UpdateUser(ctx, user); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (uc *User) UpdateUser(ctx context.Context, user *domain.User) error {
-	if err := uc.ur.UpdateUser(ctx, user); err != nil {
-		return err
+// ユーザー情報の部分更新。登録済みのユーザーにpatchを適用し、patchが返す列（bunの列名）のみ更新して更新後のユーザーを返す。
+// versionの扱いはShelf.PatchShelfと同じ。
+func (uc *User) PatchUser(ctx context.Context, authUserId string, version int64, patch func(*domain.User) ([]string, error)) (*domain.User, error) {
+	for i := 0; ; i++ {
+		user, err := uc.ur.FindUserByAuthUserId(ctx, authUserId)
+		if err != nil {
+			return nil, err
+		}
+		if version > 0 && user.Version != version {
+			return nil, utils.NewErrChains(domain.ErrPreconditionFailed, nil)
+		}
+
+		columns, err := patch(user)
+		if err != nil {
+			return nil, err
+		}
+		//変更がない場合は更新しない（バージョンも変わらない）
+		if len(columns) == 0 {
+			return user, nil
+		}
+
+		err = uc.ur.PatchUser(ctx, user, columns)
+		if errors.Is(err, domain.ErrPreconditionFailed) && version == 0 && i < patchRetries {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
 	}
+}
 
+// 平文のパスワードをbcryptでハッシュ化する。空（変更しない場合）はそのまま。
+// ハッシュ化済みのパスワードはErrPasswordHashed（任意のハッシュを保存させない。パスワードの変更は再設定で行う）
+func hashPassword(user *domain.User) error {
+	if user.Password == "" {
+		return nil
+	}
+	if user.Password.IsHashed() {
+		return domain.ErrPasswordHashed
+	}
+	hashed, err := user.HashedPassword(user.Password)
+	if err != nil {
+		return fmt.Errorf("パスワードのハッシュ化に失敗:%w", err)
+	}
+	user.Password = hashed
 	return nil
 }
@@ -1,7 +1,9 @@
 package controller_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"log"
 	"testing"
 
@@ -119,9 +121,12 @@ func TestUpdateUser(t *testing.T) {
 
 	//Act ***************
 	err = sut.UpdateUser(ctx, updatedUser)
+	hashed, _ := new(domain.User).HashedPassword("password")
+	errHashed := sut.UpdateUser(ctx, &domain.User{ID: 1, AuthUserId: user.AuthUserId, Email: user.Email, Password: hashed})
 
 	//Assert ***************
 	a.Nil(err)
+	a.ErrorIs(errHashed, domain.ErrPasswordHashed) //ハッシュを直接保存させない
 }
 
 func TestDeleteUser(t *testing.T) {
@@ -151,8 +156,54 @@ func TestDeleteUser(t *testing.T) {
 	a := assert.New(t)
 
 	//Act ***************
-	err = sut.DeleteUser(ctx, user.AuthUserId)
+	var buf bytes.Buffer
+	got, err := sut.DeleteUser(ctx, user.AuthUserId, false, domain.DefaultTrashRetention, &buf)
 
 	//Assert ***************
 	a.Nil(err)
+	a.Equal(user.AuthUserId, got.User.AuthUserId)
+	a.Equal(cl.Now().Add(domain.DefaultTrashRetention), got.PurgeAt)
+	a.NotZero(buf.Len())
+	_, err = ur.FindDeletedUserByAuthUserId(ctx, user.AuthUserId)
+	a.Nil(err)
+}
+
+func TestDeleteUserWithExportError(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		Email:      domain.Email("example@example.com"),
+	}
+	testutils.InsertTestData(ctx, t, bundb, user)
+
+	ur := repository.NewUser(bundb, cl)
+	sut := controller.NewUser(ur)
+	a := assert.New(t)
+
+	//Act ***************
+	_, err = sut.DeleteUser(ctx, user.AuthUserId, true, domain.DefaultTrashRetention, failingWriter{})
+
+	//Assert ***************
+	a.ErrorIs(err, domain.ErrExportFailed)
+	_, err = ur.FindUserByAuthUserId(ctx, user.AuthUserId)
+	a.Nil(err) //アーカイブを作成できない場合は削除しない
+}
+
+// 書き込みに必ず失敗するio.Writer
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("書き込みに失敗")
 }
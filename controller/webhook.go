@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/utils"
+)
+
+const (
+	// 一度に振り分けるアウトボックス、配信する件数
+	webhookBatchSize = 50
+	// 配信中とみなす期間。これを過ぎても結果を保存していない配信は再試行する
+	webhookLease = time.Minute
+	// 配信のタイムアウト
+	webhookTimeout = 10 * time.Second
+)
+
+// Webhookのヘッダー
+const (
+	HeaderWebhookEvent     = "X-Bhapi-Event"     //イベントの種類
+	HeaderWebhookEventId   = "X-Bhapi-Event-Id"  //イベントのid（再試行でも同じ。受信側の重複排除に使う）
+	HeaderWebhookDelivery  = "X-Bhapi-Delivery"  //配信のid
+	HeaderWebhookTimestamp = "X-Bhapi-Timestamp" //送信時刻（UNIX秒）
+	HeaderWebhookSignature = "X-Bhapi-Signature" //domain.SignWebhookの署名
+)
+
+// Webhookの登録と、アウトボックスのイベントの配信（ディスパッチャ）
+type Webhook struct {
+	wr        *repository.Webhook
+	cl        utils.Clock
+	client    *http.Client
+	retention time.Duration
+}
+
+// clientがnilの場合はnewWebhookClient（内部のアドレスに接続しない）を使用。指定した場合はそのまま使う（テスト用）。
+// retentionは配信済みのイベントを保持する期間。
+func NewWebhook(wr *repository.Webhook, cl utils.Clock, client *http.Client, retention time.Duration) *Webhook {
+	if client == nil {
+		client = newWebhookClient()
+	}
+	return &Webhook{wr: wr, cl: cl, client: client, retention: retention}
+}
+
+// 配信のクライアント。接続時に接続先のアドレスを確認し（DNSリバインディングで登録時の確認を回避させない）、
+// リダイレクトは追わない（3xxは失敗）。プロキシは接続先を確認できないため使わない。
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout, Control: controlWebhookAddr}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// net.Dialer.Control。名前解決後の接続先が内部のアドレスの場合は接続しない
+func controlWebhookAddr(network string, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !domain.WebhookAddrAllowed(ap.Addr()) {
+		return domain.ErrWebhookAddrNotAllowed
+	}
+	return nil
+}
+
+// Webhookを登録する。署名の鍵を生成してw.Secretに設定する（鍵を返すのは登録時のみ）。
+// 送信先のホストが内部のアドレス（名前解決の結果を含む）の場合はErrInvalidWebhook。
+func (wc *Webhook) CreateWebhook(ctx context.Context, w *domain.Webhook) error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.ErrInvalidWebhook
+	}
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	if err := checkWebhookHost(ctx, u.Hostname()); err != nil {
+		return utils.NewErrChains(domain.ErrInvalidWebhook, err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("署名の鍵の生成に失敗:%w", err)
+	}
+	w.Secret = "whsec_" + hex.EncodeToString(secret)
+
+	return wc.wr.CreateWebhook(ctx, w)
+}
+
+func (wc *Webhook) GetWebhooks(ctx context.Context, authUserId string) ([]*domain.Webhook, error) {
+	return wc.wr.FindWebhooksByAuthUserID(ctx, authUserId)
+}
+
+func (wc *Webhook) DeleteWebhook(ctx context.Context, authUserId string, webhookId int64) error {
+	return wc.wr.DeleteWebhook(ctx, authUserId, webhookId)
+}
+
+// 送信先のホストが内部のアドレスでないかを確認する。名前解決できない場合は登録を受け付け、配信時の接続で確認する
+func checkWebhookHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !domain.WebhookAddrAllowed(addr) {
+			return domain.ErrWebhookAddrNotAllowed
+		}
+		return nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return domain.ErrWebhookAddrNotAllowed
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !domain.WebhookAddrAllowed(addr) {
+			return domain.ErrWebhookAddrNotAllowed
+		}
+	}
+	return nil
+}
+
+// 再試行の上限に達した配信（デッドレター）を返す
+func (wc *Webhook) GetDeadLetters(ctx context.Context, authUserId string) ([]*domain.WebhookDelivery, error) {
+	return wc.wr.FindDeadDeliveries(ctx, authUserId)
+}
+
+// デッドレターを次の配信で再試行する
+func (wc *Webhook) RetryDeadLetter(ctx context.Context, authUserId string, deliveryId int64) error {
+	return wc.wr.RetryDeadDelivery(ctx, authUserId, deliveryId)
+}
+
+// アウトボックスを配信に振り分け、配信時刻を過ぎた配信を送る。振り分けと配信がなくなるまで繰り返す。
+func (wc *Webhook) Dispatch(ctx context.Context) error {
+	for {
+		n, err := wc.wr.DispatchOutbox(ctx, webhookBatchSize)
+		if err != nil {
+			return fmt.Errorf("アウトボックスの振り分けに失敗:%w", err)
+		}
+		if n < webhookBatchSize {
+			break
+		}
+	}
+
+	for {
+		deliveries, err := wc.wr.ClaimDueDeliveries(ctx, webhookBatchSize, webhookLease)
+		if err != nil {
+			return fmt.Errorf("配信の取得に失敗:%w", err)
+		}
+
+		var wg sync.WaitGroup
+		for _, d := range deliveries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				wc.deliver(ctx, d)
+				err := wc.wr.SaveDeliveryResult(ctx, d)
+				if errors.Is(err, domain.ErrDeliveryLeaseLost) {
+					log.Printf("配信結果を破棄(id=%d, 試行:%d):他の配信処理に取得されています", d.ID, d.Attempts)
+					return
+				}
+				if err != nil {
+					log.Printf("配信結果の保存に失敗(id=%d):%s", d.ID, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+}
+
+// 配信を1回試行し、結果をdに反映する。2xxのレスポンスを成功とする。
+func (wc *Webhook) deliver(ctx context.Context, d *domain.WebhookDelivery) {
+	now := wc.cl.Now()
+	if d.Webhook == nil {
+		d.Fail(now, 0, "Webhookが削除されています")
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Webhook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		d.Fail(now, 0, "送信先のURLが不正です")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "bhapi-webhook")
+	req.Header.Set(HeaderWebhookEvent, string(d.Type))
+	req.Header.Set(HeaderWebhookEventId, strconv.FormatInt(d.OutboxId, 10))
+	req.Header.Set(HeaderWebhookDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderWebhookSignature, domain.SignWebhook(d.Webhook.Secret, now, d.Payload))
+
+	res, err := wc.client.Do(req)
+	if err != nil {
+		//送信先の内部の情報を含む場合があるため、エラーの詳細はログのみに出力する
+		log.Printf("Webhookの配信に失敗(id=%d):%s", d.ID, err)
+		d.Fail(now, 0, deliveryError(err))
+		return
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		//送信先の返した理由句ではなく、ステータスコードの標準の理由句を記録する
+		d.Fail(now, res.StatusCode, fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)))
+		return
+	}
+	d.Status = domain.DeliverySucceeded
+	d.Attempts++
+	d.LastStatus = res.StatusCode
+	d.LastError = ""
+	d.UpdatedAt = now
+}
+
+// 配信の失敗（接続できない場合）の理由。デッドレターとしてユーザーに返すため、決まった文言のみ返す
+func deliveryError(err error) string {
+	var ne net.Error
+	switch {
+	case errors.Is(err, domain.ErrWebhookAddrNotAllowed):
+		return "送信先のアドレスは許可されていません"
+	case errors.As(err, &ne) && ne.Timeout():
+		return "送信先の応答がタイムアウトしました"
+	default:
+		return "送信先に接続できません"
+	}
+}
+
+// ctxがキャンセルされるまでintervalごとにDispatchを実行する
+func (wc *Webhook) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := wc.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			log.Println(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 保持期間を過ぎた配信済みのイベントと成功した配信を削除
+func (wc *Webhook) Purge(ctx context.Context) error {
+	n, err := wc.wr.PurgeDispatched(ctx, wc.cl.Now().Add(-wc.retention))
+	if err != nil {
+		return fmt.Errorf("配信済みのイベントの削除に失敗:%w", err)
+	}
+
+	if n > 0 {
+		log.Printf("保持期間を過ぎた配信済みのイベントを削除しました（%d件）", n)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package controller_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+func TestWebhookDispatch(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 10)
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	wr := repository.NewWebhook(bundb, cl)
+	sr := repository.NewShelf(bundb, cl)
+	sut := controller.NewWebhook(wr, cl, ok.Client(), domain.DefaultOutboxRetention)
+	//テストサーバーはループバックのため、CreateWebhookの確認を通さずに登録する
+	hook := &domain.Webhook{AuthUserId: authUserId, URL: ok.URL, Secret: "whsec_ok", Events: []domain.EventType{domain.EventBookFinished}}
+	down := &domain.Webhook{AuthUserId: authUserId, URL: failing.URL, Secret: "whsec_down", Events: []domain.EventType{domain.EventBookFinished}}
+	for _, w := range []*domain.Webhook{hook, down} {
+		if err := wr.CreateWebhook(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	book := &domain.Book{Title: "容疑者Xの献身", Author: "東野圭吾", Page: 247, Price: 980, BookStatus: domain.Read, AuthUserId: authUserId}
+	if err := sr.CreateBookWithCharts(ctx, book, domain.NewChartsFromBook(book)); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.Dispatch(ctx)
+	dead, errDead := sut.GetDeadLetters(ctx, authUserId)
+
+	//Assert ***************
+	a.Nil(err)
+	a.Nil(errDead)
+	a.Empty(dead) //失敗した配信は再試行を待つ
+	select {
+	case r := <-got:
+		ts, err := strconv.ParseInt(r.header.Get(controller.HeaderWebhookTimestamp), 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.Equal(string(domain.EventBookFinished), r.header.Get(controller.HeaderWebhookEvent))
+		a.Equal(domain.SignWebhook(hook.Secret, time.Unix(ts, 0), r.body), r.header.Get(controller.HeaderWebhookSignature))
+		a.Contains(string(r.body), `"title":"容疑者Xの献身"`)
+	default:
+		t.Fatal("Webhookが届きません")
+	}
+	a.Len(got, 0)
+}
+
+func TestCreateWebhookWithError(t *testing.T) {
+	//Arrange ***************
+	sut := controller.NewWebhook(nil, cl, nil, domain.DefaultOutboxRetention)
+	tests := map[string]struct {
+		webhook *domain.Webhook
+		wantErr error
+	}{
+		"NG:httpでないURL":   {webhook: &domain.Webhook{URL: "ftp://example.com/hook"}, wantErr: domain.ErrInvalidWebhook},
+		"NG:ホストなし":        {webhook: &domain.Webhook{URL: "https://"}, wantErr: domain.ErrInvalidWebhook},
+		"NG:未対応のイベント":     {webhook: &domain.Webhook{URL: "https://example.com/hook", Events: []domain.EventType{"book.read"}}, wantErr: domain.ErrInvalidWebhook},
+		"NG:ループバック":       {webhook: &domain.Webhook{URL: "http://127.0.0.1:8080/hook"}, wantErr: domain.ErrWebhookAddrNotAllowed},
+		"NG:localhost":    {webhook: &domain.Webhook{URL: "http://localhost/hook"}, wantErr: domain.ErrWebhookAddrNotAllowed},
+		"NG:メタデータ":        {webhook: &domain.Webhook{URL: "http://169.254.169.254/latest/meta-data"}, wantErr: domain.ErrWebhookAddrNotAllowed},
+		"NG:プライベート（IPv6）": {webhook: &domain.Webhook{URL: "http://[fd00::1]/hook"}, wantErr: domain.ErrWebhookAddrNotAllowed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act ***************
+			err := sut.CreateWebhook(context.Background(), test.webhook)
+
+			//Assert ***************
+			assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+			assert.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
+// 既定のクライアントは登録後に内部のアドレスになった送信先に接続せず、エラーの詳細を記録しないことを確認する
+func TestWebhookDispatchWithInternalAddress(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	called := make(chan struct{}, 10)
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called <- struct{}{}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer internal.Close()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	wr := repository.NewWebhook(bundb, cl)
+	sr := repository.NewShelf(bundb, cl)
+	sut := controller.NewWebhook(wr, cl, nil, domain.DefaultOutboxRetention)
+	//登録後に名前解決の結果が内部のアドレスに変わった場合（DNSリバインディング）と同じ状態
+	hook := &domain.Webhook{AuthUserId: authUserId, URL: internal.URL, Secret: "whsec_test"}
+	if err := wr.CreateWebhook(ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+	book := &domain.Book{Title: "容疑者Xの献身", Author: "東野圭吾", Page: 247, Price: 980, BookStatus: domain.Read, AuthUserId: authUserId}
+	if err := sr.CreateBookWithCharts(ctx, book, domain.NewChartsFromBook(book)); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.Dispatch(ctx)
+	var deliveries []*domain.WebhookDelivery
+	errFind := bundb.NewSelect().Model(&deliveries).Scan(ctx)
+
+	//Assert ***************
+	a.Nil(err)
+	a.Nil(errFind)
+	a.Len(called, 0)
+	a.NotEmpty(deliveries)
+	for _, d := range deliveries {
+		a.Equal(domain.DeliveryPending, d.Status) //再試行を待つ
+		a.Equal(0, d.LastStatus)
+		a.Equal("送信先のアドレスは許可されていません", d.LastError)
+	}
+}
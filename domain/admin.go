@@ -0,0 +1,83 @@
+package domain
+
+import "strings"
+
+// ユーザーの役割
+type Role string
+
+const (
+	RoleUser  = Role("user")  //通常のユーザー（既定）
+	RoleAdmin = Role("admin") //管理APIを呼び出せるユーザー
+)
+
+// 管理APIを呼び出す個人用APIキーのスコープ。管理者のみ発行できる
+const ScopeAdmin = APIKeyScope("admin")
+
+const (
+	// ユーザーの検索で一度に返す件数の既定値と上限
+	DefaultUserSearchLimit = 50
+	MaxUserSearchLimit     = 200
+)
+
+var (
+	ErrAccountDisabled = NewError(ErrForbidden, "アカウントは無効化されています")
+	ErrAdminRequired   = NewError(ErrForbidden, "管理者のadminスコープのAPIキーが必要です")
+	ErrAdminScope      = NewError(ErrForbidden, "adminスコープのAPIキーは管理者のみ発行できます")
+)
+
+func (u *User) HasRole(r Role) bool {
+	return u.Role == r
+}
+
+func (u *User) Disabled() bool {
+	return !u.DisabledAt.IsZero()
+}
+
+// ユーザーの検索条件。Queryはメールアドレス、名前、authUserIdの部分一致（空の場合はすべて）
+type UserSearch struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// 件数の指定を範囲内に収める（0以下は既定値、上限を超える場合は上限）
+func (s UserSearch) Normalize() UserSearch {
+	s.Query = strings.TrimSpace(s.Query)
+	if s.Limit <= 0 {
+		s.Limit = DefaultUserSearchLimit
+	}
+	s.Limit = min(s.Limit, MaxUserSearchLimit)
+	s.Offset = max(s.Offset, 0)
+	return s
+}
+
+// 通貨ごとの購入額の合計（換算しない）
+type Spend struct {
+	Currency Currency `bun:"currency"`
+	Amount   int      `bun:"amount"`
+}
+
+// ユーザーの本棚の集計
+type ShelfStats struct {
+	Books   int
+	Bought  int
+	Reading int
+	Read    int
+	Pages   int
+	Spend   []Spend
+}
+
+// システム全体の集計（削除済みのユーザー、本を除く）
+type SystemStats struct {
+	Users         int
+	DisabledUsers int
+	Admins        int
+	Books         int
+	Spend         []Spend
+}
+
+// 強制ログアウトで無効にした件数
+type Revocation struct {
+	APIKeys int //削除した個人用APIキー
+	Tokens  int //無効にした未使用のパスワード再設定、メールアドレス確認のトークン
+}
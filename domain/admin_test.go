@@ -0,0 +1,30 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+)
+
+func TestUserSearchNormalize(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		search domain.UserSearch
+		want   domain.UserSearch
+	}{
+		"指定なし":  {search: domain.UserSearch{}, want: domain.UserSearch{Limit: domain.DefaultUserSearchLimit}},
+		"指定あり":  {search: domain.UserSearch{Query: " tanaka ", Limit: 10, Offset: 20}, want: domain.UserSearch{Query: "tanaka", Limit: 10, Offset: 20}},
+		"上限を超過": {search: domain.UserSearch{Limit: 1000}, want: domain.UserSearch{Limit: domain.MaxUserSearchLimit}},
+		"負の値":   {search: domain.UserSearch{Limit: -1, Offset: -1}, want: domain.UserSearch{Limit: domain.DefaultUserSearchLimit}},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.want, test.search.Normalize())
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 個人用APIキーのスコープ（許可する操作）
+type APIKeyScope string
+
+const (
+	ScopeShelfRead  = APIKeyScope("shelf:read")  //本棚、読書記録の取得
+	ScopeShelfWrite = APIKeyScope("shelf:write") //本棚の本の登録、更新、削除
+	ScopeChartsRead = APIKeyScope("charts:read") //チャートデータの取得
+)
+
+// 個人用APIキーに付与できるスコープ（ScopeAdminは管理者のみ）
+var APIKeyScopes = []APIKeyScope{ScopeShelfRead, ScopeShelfWrite, ScopeChartsRead, ScopeAdmin}
+
+const (
+	// 個人用APIキーの接頭辞。Authorizationヘッダーのキーが個人用APIキーかを判別する。
+	APIKeyTokenPrefix = "bhk_"
+	// 一覧に表示するキーの先頭の文字数（接頭辞を含む）
+	apiKeyDisplayLen = len(APIKeyTokenPrefix) + 8
+	// ユーザーごとに発行できる個人用APIキーの上限
+	MaxAPIKeysPerUser = 10
+	// 最終使用日時を更新する間隔（リクエストごとに書き込まない）
+	APIKeyTouchInterval = time.Minute
+)
+
+var (
+	ErrInvalidAPIKey  = NewError(ErrValidation, "APIキーの名前、スコープ、有効期限が不正です")
+	ErrAPIKeyLimit    = NewError(ErrConflict, "発行できるAPIキーの上限に達しています")
+	ErrAPIKeyRejected = NewError(ErrUnauthorized, "APIキーが不正、または期限切れです")
+	ErrAPIKeyScope    = NewError(ErrForbidden, "APIキーにこの操作のスコープがありません")
+	ErrAPIKeyOwner    = NewError(ErrForbidden, "APIキーで他のユーザーのデータは操作できません")
+)
+
+// ユーザーが発行した個人用APIキー。キー自体は保存せず、ハッシュと表示用の先頭のみ保存する。
+type APIKey struct {
+	bun.BaseModel `bun:"table:api_keys,alias:ak"`
+
+	ID         int64         `bun:"id,pk,autoincrement"`
+	AuthUserId string        `bun:"auth_user_id,notnull"`
+	Name       string        `bun:"name,notnull"`
+	Prefix     string        `bun:"prefix,notnull"` //キーの先頭（一覧でキーを見分けるため）
+	KeyHash    string        `bun:"key_hash,notnull,unique"`
+	Scopes     []APIKeyScope `bun:"scopes,array,notnull"`
+	ExpiresAt  time.Time     `bun:"expires_at,nullzero"` //ゼロ値は無期限
+	LastUsedAt time.Time     `bun:"last_used_at,nullzero"`
+	CreatedAt  time.Time     `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// 名前、スコープ（1つ以上、重複なし）、有効期限（nowより後）を検証
+func (k *APIKey) Validate(now time.Time) error {
+	if strings.TrimSpace(k.Name) == "" || len(k.Scopes) == 0 {
+		return ErrInvalidAPIKey
+	}
+	for i, s := range k.Scopes {
+		if !slices.Contains(APIKeyScopes, s) || slices.Contains(k.Scopes[:i], s) {
+			return ErrInvalidAPIKey
+		}
+	}
+	if !k.ExpiresAt.IsZero() && !k.ExpiresAt.After(now) {
+		return ErrInvalidAPIKey
+	}
+	return nil
+}
+
+func (k *APIKey) HasScope(s APIKeyScope) bool {
+	return slices.Contains(k.Scopes, s)
+}
+
+func (k *APIKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !k.ExpiresAt.After(now)
+}
+
+// 最終使用日時を更新するか（前回の更新からAPIKeyTouchInterval以上経過）
+func (k *APIKey) ShouldTouch(now time.Time) bool {
+	return k.LastUsedAt.IsZero() || now.Sub(k.LastUsedAt) >= APIKeyTouchInterval
+}
+
+// 個人用APIキー（"bhk_乱数"）を生成し、キーを設定したk.Prefix、k.KeyHashとともに返す
+func NewAPIKeyToken(k *APIKey) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := APIKeyTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	k.Prefix = token[:apiKeyDisplayLen]
+	k.KeyHash = HashAPIKeyToken(token)
+	return token, nil
+}
+
+// 個人用APIキーの形式か（接頭辞で判別する）
+func IsAPIKeyToken(token string) bool {
+	return strings.HasPrefix(token, APIKeyTokenPrefix)
+}
+
+func HashAPIKeyToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestNewAPIKeyToken(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	k := &domain.APIKey{}
+
+	//Act
+	token, err := domain.NewAPIKeyToken(k)
+
+	//Assert
+	a.NoError(err)
+	a.True(domain.IsAPIKeyToken(token))
+	a.True(strings.HasPrefix(token, k.Prefix))
+	a.Len(k.Prefix, len(domain.APIKeyTokenPrefix)+8)
+	a.Equal(domain.HashAPIKeyToken(token), k.KeyHash)
+	a.NotContains(k.KeyHash, token)
+
+	other, _ := domain.NewAPIKeyToken(&domain.APIKey{})
+	a.NotEqual(token, other)
+}
+
+func TestAPIKeyValidate(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+	tests := map[string]struct {
+		key     *domain.APIKey
+		wantErr error
+	}{
+		"正常なキー": {
+			key: &domain.APIKey{Name: "script", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead, domain.ScopeChartsRead}},
+		},
+		"有効期限付き": {
+			key: &domain.APIKey{Name: "script", Scopes: []domain.APIKeyScope{domain.ScopeShelfWrite}, ExpiresAt: now.Add(time.Hour)},
+		},
+		"名前が空": {
+			key:     &domain.APIKey{Name: " ", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}},
+			wantErr: domain.ErrInvalidAPIKey,
+		},
+		"スコープなし": {
+			key:     &domain.APIKey{Name: "script"},
+			wantErr: domain.ErrInvalidAPIKey,
+		},
+		"未対応のスコープ": {
+			key:     &domain.APIKey{Name: "script", Scopes: []domain.APIKeyScope{"shelf:delete"}},
+			wantErr: domain.ErrInvalidAPIKey,
+		},
+		"スコープの重複": {
+			key:     &domain.APIKey{Name: "script", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead, domain.ScopeShelfRead}},
+			wantErr: domain.ErrInvalidAPIKey,
+		},
+		"有効期限が過去": {
+			key:     &domain.APIKey{Name: "script", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}, ExpiresAt: now},
+			wantErr: domain.ErrInvalidAPIKey,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := test.key.Validate(now)
+			assert.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestAPIKeyExpiredAndTouch(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+
+	a.False((&domain.APIKey{}).Expired(now))
+	a.False((&domain.APIKey{ExpiresAt: now.Add(time.Second)}).Expired(now))
+	a.True((&domain.APIKey{ExpiresAt: now}).Expired(now))
+
+	a.True((&domain.APIKey{}).ShouldTouch(now))
+	a.False((&domain.APIKey{LastUsedAt: now.Add(-time.Second)}).ShouldTouch(now))
+	a.True((&domain.APIKey{LastUsedAt: now.Add(-domain.APIKeyTouchInterval)}).ShouldTouch(now))
+}
@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 監査ログの操作の種類
+type AuditAction string
+
+const (
+	AuditCreate  = AuditAction("create")  //作成
+	AuditUpdate  = AuditAction("update")  //更新（部分更新、ステータスの変更を含む）
+	AuditDelete  = AuditAction("delete")  //論理削除（ゴミ箱への移動）
+	AuditRestore = AuditAction("restore") //ゴミ箱からの復元
+	AuditPurge   = AuditAction("purge")   //完全削除
+)
+
+// 監査ログの対象の種類
+type AuditTarget string
+
+const (
+	AuditTargetBook = AuditTarget("book")
+	AuditTargetUser = AuditTarget("user")
+)
+
+// 操作を行った経路
+type AuditVia string
+
+const (
+	AuditViaApp    = AuditVia("app")     //アプリのキー（パスのユーザーの操作として記録する）
+	AuditViaAPIKey = AuditVia("api_key") //個人用APIキー（キーの持ち主の操作として記録する）
+	AuditViaSystem = AuditVia("system")  //ジョブなど、リクエストによらない操作
+)
+
+const (
+	DefaultAuditLimit = 50
+	MaxAuditLimit     = 200
+)
+
+// 変更前後の値に記録しない項目（変更の有無のみ記録する）。
+// 監査ログは追記のみのため、個人情報（氏名、メールアドレス）も値を残さない。
+var auditSecretFields = []string{"password", "name", "email"}
+
+// 記録しない値の代わりに記録する文字列
+const AuditRedacted = "[REDACTED]"
+
+var ErrInvalidAuditQuery = NewError(ErrValidation, "監査ログの検索条件が不正です")
+
+// 項目ごとの変更前後の値。作成時のBefore、削除時のAfterはnil
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// 項目（列名）ごとの変更
+type AuditDiff map[string]AuditChange
+
+// 本、ユーザーへの変更の監査ログ。変更と同じトランザクションで追記し、更新、削除はしない（DBのトリガーで拒否する）。
+type AuditLog struct {
+	bun.BaseModel `bun:"table:audit_logs,alias:al"`
+
+	ID         int64       `bun:"id,pk,autoincrement"`
+	Actor      string      `bun:"actor"` //操作したユーザーのauthUserId（システムの操作は空）
+	Via        AuditVia    `bun:"via,notnull"`
+	APIKeyId   int64       `bun:"api_key_id,nullzero"`  //個人用APIキーの操作のみ
+	AuthUserId string      `bun:"auth_user_id,notnull"` //変更したデータの持ち主（利用履歴の対象）
+	TargetType AuditTarget `bun:"target_type,notnull"`
+	TargetId   string      `bun:"target_id,notnull"`
+	Action     AuditAction `bun:"action,notnull"`
+	Diff       AuditDiff   `bun:"diff,type:jsonb,notnull"`
+	RequestId  string      `bun:"request_id"`
+	IP         string      `bun:"ip"`
+	CreatedAt  time.Time   `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// 監査ログに記録できるデータ。列名ごとの値を返す
+type Auditable interface {
+	auditTarget() (AuditTarget, string, string) //種類、識別子、持ち主のauthUserId
+	auditFields() map[string]any
+}
+
+func (b *Book) auditTarget() (AuditTarget, string, string) {
+	return AuditTargetBook, strconv.FormatInt(b.ID, 10), b.AuthUserId
+}
+
+func (b *Book) auditFields() map[string]any {
+	return map[string]any{
+		"isbn_10":     b.ISBN10,
+		"image_url":   b.ImageURL,
+		"title":       b.Title,
+		"author":      b.Author,
+		"page":        b.Page,
+		"price":       b.Price,
+		"currency":    b.Currency,
+		"book_status": b.BookStatus,
+		"created_at":  auditTime(b.CreatedAt), //購入日
+	}
+}
+
+func (u *User) auditTarget() (AuditTarget, string, string) {
+	return AuditTargetUser, u.AuthUserId, u.AuthUserId
+}
+
+func (u *User) auditFields() map[string]any {
+	return map[string]any{
+		"name":              u.Name,
+		"email":             u.Email,
+		"password":          u.Password,
+		"home_currency":     u.HomeCurrency,
+		"email_verified_at": auditTime(u.EmailVerifiedAt),
+		"role":              u.Role,
+		"disabled_at":       auditTime(u.DisabledAt),
+	}
+}
+
+// 時刻はUTCで比較、記録する（読み出したタイムゾーンの違いを変更とみなさない）
+func auditTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// beforeからafterへの変更の監査ログを作成する。作成はbeforeが、削除、完全削除はafterがnil（完全削除は変更を記録しない）。
+// 変更のない項目は記録せず、auditSecretFieldsの項目は値の代わりにAuditRedactedを記録する。
+func NewAuditLog(ctx context.Context, action AuditAction, before Auditable, after Auditable) *AuditLog {
+	target := after
+	if isNilAuditable(target) {
+		target = before
+	}
+	typ, id, owner := target.auditTarget()
+
+	var bf, af map[string]any
+	if !isNilAuditable(before) {
+		bf = before.auditFields()
+	}
+	if !isNilAuditable(after) {
+		af = after.auditFields()
+	}
+	if action == AuditPurge {
+		bf, af = nil, nil //削除時に記録済みのため、識別子のみ記録する
+	}
+	diff := diffFields(bf, af)
+
+	meta := AuditMetaFrom(ctx)
+	return &AuditLog{
+		Actor:      meta.Actor,
+		Via:        meta.Via,
+		APIKeyId:   meta.APIKeyId,
+		AuthUserId: owner,
+		TargetType: typ,
+		TargetId:   id,
+		Action:     action,
+		Diff:       diff,
+		RequestId:  meta.RequestId,
+		IP:         meta.IP,
+	}
+}
+
+// 列名ごとの値bfからafへの変更を返す。どちらかがnil（作成、削除）の場合はすべての項目を記録する。
+func diffFields(bf, af map[string]any) AuditDiff {
+	keys := slices.Sorted(maps.Keys(bf))
+	if bf == nil {
+		keys = slices.Sorted(maps.Keys(af))
+	}
+	diff := AuditDiff{}
+	for _, k := range keys {
+		b, a := bf[k], af[k]
+		if bf != nil && af != nil && reflect.DeepEqual(b, a) {
+			continue
+		}
+		if slices.Contains(auditSecretFields, k) {
+			b, a = redact(b), redact(a)
+		}
+		diff[k] = AuditChange{Before: b, After: a}
+	}
+	return diff
+}
+
+// 値をAuditRedactedに置き換えた変更を返す（完全削除したユーザーの監査ログの匿名化）。変更した項目は残す
+func (d AuditDiff) Anonymize() AuditDiff {
+	anonymized := AuditDiff{}
+	for k, c := range d {
+		anonymized[k] = AuditChange{Before: redact(c.Before), After: redact(c.After)}
+	}
+	return anonymized
+}
+
+// nil、型付きのnil（(*Book)(nil)など）か
+func isNilAuditable(a Auditable) bool {
+	return a == nil || reflect.ValueOf(a).IsNil()
+}
+
+// 記録しない値はAuditRedactedに置き換える（値がない場合はnil）
+func redact(v any) any {
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return nil
+	}
+	return AuditRedacted
+}
+
+// 監査ログに記録するリクエストの情報
+type AuditMeta struct {
+	Actor     string
+	Via       AuditVia
+	APIKeyId  int64
+	RequestId string
+	IP        string
+}
+
+type auditMetaKey struct{}
+
+// ctxにリクエストの情報を設定する（presenterのミドルウェアで設定し、repositoryで監査ログに記録する）
+func WithAuditMeta(ctx context.Context, meta AuditMeta) context.Context {
+	return context.WithValue(ctx, auditMetaKey{}, meta)
+}
+
+// ctxのリクエストの情報を返す。ない場合（ジョブなど）はシステムの操作とする
+func AuditMetaFrom(ctx context.Context) AuditMeta {
+	meta, ok := ctx.Value(auditMetaKey{}).(AuditMeta)
+	if !ok {
+		return AuditMeta{Via: AuditViaSystem}
+	}
+	return meta
+}
+
+// 監査ログの検索条件。空の項目は条件にしない
+type AuditQuery struct {
+	AuthUserId string
+	Actor      string
+	TargetType AuditTarget
+	TargetId   string
+	Action     AuditAction
+	BeforeId   int64 //このidより前（古い）のログのみ（前のページの最後のid）
+	Limit      int
+}
+
+// 件数を既定値、上限に収め、検索条件を検証する
+func (q AuditQuery) Normalize() (AuditQuery, error) {
+	if q.BeforeId < 0 {
+		return q, ErrInvalidAuditQuery
+	}
+	switch q.TargetType {
+	case "", AuditTargetBook, AuditTargetUser:
+	default:
+		return q, ErrInvalidAuditQuery
+	}
+	switch q.Action {
+	case "", AuditCreate, AuditUpdate, AuditDelete, AuditRestore, AuditPurge:
+	default:
+		return q, ErrInvalidAuditQuery
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultAuditLimit
+	}
+	q.Limit = min(q.Limit, MaxAuditLimit)
+	return q, nil
+}
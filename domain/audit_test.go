@@ -0,0 +1,131 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+)
+
+func TestNewAuditLog(t *testing.T) {
+	t.Parallel()
+	book := &domain.Book{ID: 1, Title: "容疑者Xの献身", Page: 330, Price: 1640, Currency: domain.JPY, BookStatus: domain.Bought, AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"}
+	read := *book
+	read.BookStatus = domain.Read
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Name: "田中", Email: "tanaka@example.com", Password: "hashed-1"}
+	changed := *user
+	changed.Password = "hashed-2"
+	renamed := *user
+	renamed.Name = "佐藤"
+	renamed.Email = "sato@example.com"
+	tests := map[string]struct {
+		action domain.AuditAction
+		before domain.Auditable
+		after  domain.Auditable
+		want   domain.AuditDiff
+	}{
+		"更新は変更した項目のみ": {
+			action: domain.AuditUpdate, before: book, after: &read,
+			want: domain.AuditDiff{"book_status": {Before: domain.Bought, After: domain.Read}},
+		},
+		"変更なし": {
+			action: domain.AuditUpdate, before: book, after: book,
+			want: domain.AuditDiff{},
+		},
+		"パスワードは変更の有無のみ": {
+			action: domain.AuditUpdate, before: user, after: &changed,
+			want: domain.AuditDiff{"password": {Before: domain.AuditRedacted, After: domain.AuditRedacted}},
+		},
+		"氏名、メールアドレスは変更の有無のみ": {
+			action: domain.AuditUpdate, before: user, after: &renamed,
+			want: domain.AuditDiff{"name": {Before: domain.AuditRedacted, After: domain.AuditRedacted}, "email": {Before: domain.AuditRedacted, After: domain.AuditRedacted}},
+		},
+		"完全削除は識別子のみ": {
+			action: domain.AuditPurge, before: book, after: nil,
+			want: domain.AuditDiff{},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := domain.NewAuditLog(context.Background(), test.action, test.before, test.after)
+			assert.Equal(t, test.want, got.Diff)
+			assert.Equal(t, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", got.AuthUserId)
+			assert.Equal(t, domain.AuditViaSystem, got.Via) //リクエストの情報がない場合
+		})
+	}
+}
+
+func TestNewAuditLogCreate(t *testing.T) {
+	//Arrange
+	ctx := domain.WithAuditMeta(context.Background(), domain.AuditMeta{Actor: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Via: domain.AuditViaAPIKey, APIKeyId: 3, RequestId: "req-1", IP: "192.0.2.1"})
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Name: "田中", Email: "tanaka@example.com", Password: "hashed", HomeCurrency: domain.JPY, Role: domain.RoleUser}
+
+	//Act
+	got := domain.NewAuditLog(ctx, domain.AuditCreate, nil, user)
+
+	//Assert
+	assert.Equal(t, domain.AuditTargetUser, got.TargetType)
+	assert.Equal(t, user.AuthUserId, got.TargetId)
+	assert.Equal(t, domain.AuditChange{Before: nil, After: domain.AuditRedacted}, got.Diff["name"])
+	assert.Equal(t, domain.AuditChange{Before: nil, After: domain.AuditRedacted}, got.Diff["email"])
+	assert.Equal(t, domain.AuditChange{Before: nil, After: domain.AuditRedacted}, got.Diff["password"])
+	assert.Equal(t, domain.AuditChange{Before: nil, After: nil}, got.Diff["disabled_at"])
+	assert.Equal(t, domain.AuditVia("api_key"), got.Via)
+	assert.Equal(t, int64(3), got.APIKeyId)
+	assert.Equal(t, "req-1", got.RequestId)
+	assert.Equal(t, "192.0.2.1", got.IP)
+}
+
+func TestAuditDiffAnonymize(t *testing.T) {
+	//Arrange
+	diff := domain.AuditDiff{
+		"title": {Before: nil, After: "容疑者Xの献身"},
+		"email": {Before: "tanaka@example.com", After: "sato@example.com"},
+		"page":  {Before: float64(0), After: float64(330)},
+	}
+
+	//Act
+	got := diff.Anonymize()
+
+	//Assert
+	assert.Equal(t, domain.AuditDiff{
+		"title": {Before: nil, After: domain.AuditRedacted},
+		"email": {Before: domain.AuditRedacted, After: domain.AuditRedacted},
+		"page":  {Before: nil, After: domain.AuditRedacted},
+	}, got)
+	assert.Equal(t, "tanaka@example.com", diff["email"].Before) //元の変更は変えない
+}
+
+func TestAuditQueryNormalize(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		query   domain.AuditQuery
+		want    domain.AuditQuery
+		wantErr error
+	}{
+		"指定なし":       {query: domain.AuditQuery{}, want: domain.AuditQuery{Limit: domain.DefaultAuditLimit}},
+		"上限を超過":      {query: domain.AuditQuery{Action: domain.AuditUpdate, Limit: 1000}, want: domain.AuditQuery{Action: domain.AuditUpdate, Limit: domain.MaxAuditLimit}},
+		"未対応の操作":     {query: domain.AuditQuery{Action: "read"}, wantErr: domain.ErrInvalidAuditQuery},
+		"未対応の対象":     {query: domain.AuditQuery{TargetType: "goal"}, wantErr: domain.ErrInvalidAuditQuery},
+		"負のbeforeId": {query: domain.AuditQuery{BeforeId: -1}, wantErr: domain.ErrInvalidAuditQuery},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := test.query.Normalize()
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	BacklogReadRateDays = 90 //読了ペースの算出に用いる直近の日数
+	BacklogOldestLimit  = 5  //積読の古い順に返す冊数
+)
+
+// 月ごとの積読の推移
+type BacklogMonth struct {
+	Year    int
+	Month   int
+	Bought  int //その月に購入した冊数
+	Read    int //その月に読了した冊数
+	Backlog int //その月末時点の積読冊数（購入冊数の累計 - 読了冊数の累計）
+}
+
+type Backlog struct {
+	Months        []*BacklogMonth
+	UnreadVolumes int      //未読（bought、reading）の冊数
+	UnreadCosts   int      //未読の本の購入額
+	Currency      Currency //UnreadCostsの通貨
+	OldestUnread  []*Book  //購入日の古い順の未読の本
+	ReadPerDay    float64  //直近の1日あたりの読了冊数
+	DaysToClear   int      //現在の読了ペースで積読を解消するまでの日数（ペースが0の場合は-1）
+}
+
+// 未読の本か（購入済み、読書中）
+func (b *Book) IsUnread() bool {
+	return b.BookStatus == Bought || b.BookStatus == Reading
+}
+
+// 月ごとの購入冊数のチャートと本棚から積読の状況を算出する。
+// 読了日は記録していないため、読了本の更新日時（updated_at）を読了日とみなす。
+// booksの価格はあらかじめcurrencyに換算しておくこと。
+func NewBacklog(volumes []*Chart, books []*Book, currency Currency, now time.Time) *Backlog {
+	type ym struct{ year, month int }
+	bought := map[ym]int{}
+	read := map[ym]int{}
+
+	first := ym{now.Year(), int(now.Month())}
+	before := func(a, b ym) bool { return a.year < b.year || (a.year == b.year && a.month < b.month) }
+
+	for _, c := range volumes {
+		if c.Label != ChartVolumes {
+			continue
+		}
+		k := ym{c.Year, c.Month}
+		bought[k] += c.Data
+		if before(k, first) {
+			first = k
+		}
+	}
+
+	backlog := &Backlog{Currency: currency.OrDefault(), DaysToClear: -1}
+	unread := []*Book{}
+	recentFrom := now.AddDate(0, 0, -BacklogReadRateDays)
+	recentRead := 0
+
+	for _, b := range books {
+		if b.IsUnread() {
+			unread = append(unread, b)
+			backlog.UnreadVolumes += 1
+			backlog.UnreadCosts += b.Price
+			continue
+		}
+		if b.BookStatus != Read {
+			continue
+		}
+		k := ym{b.UpdatedAt.Year(), int(b.UpdatedAt.Month())}
+		read[k] += 1
+		if before(k, first) {
+			first = k
+		}
+		if b.UpdatedAt.After(recentFrom) && !b.UpdatedAt.After(now) {
+			recentRead += 1
+		}
+	}
+
+	//購入、読了のない月も0で埋めて連続した推移にする
+	cumulative := 0
+	last := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	for t := time.Date(first.year, time.Month(first.month), 1, 0, 0, 0, 0, now.Location()); !t.After(last); t = t.AddDate(0, 1, 0) {
+		k := ym{t.Year(), int(t.Month())}
+		cumulative += bought[k] - read[k]
+		backlog.Months = append(backlog.Months, &BacklogMonth{
+			Year:    k.year,
+			Month:   k.month,
+			Bought:  bought[k],
+			Read:    read[k],
+			Backlog: cumulative,
+		})
+	}
+
+	sort.SliceStable(unread, func(i, j int) bool { return unread[i].CreatedAt.Before(unread[j].CreatedAt) })
+	if len(unread) > BacklogOldestLimit {
+		unread = unread[:BacklogOldestLimit]
+	}
+	backlog.OldestUnread = unread
+
+	backlog.ReadPerDay = math.Round(float64(recentRead)/BacklogReadRateDays*100) / 100
+	switch {
+	case backlog.UnreadVolumes == 0:
+		backlog.DaysToClear = 0
+	case recentRead > 0:
+		backlog.DaysToClear = int(math.Ceil(float64(backlog.UnreadVolumes) * BacklogReadRateDays / float64(recentRead)))
+	}
+
+	return backlog
+}
@@ -0,0 +1,76 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestNewBacklog(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+	nov := time.Date(2023, 11, 10, 0, 0, 0, 0, utils.JST)
+	jan := time.Date(2024, 1, 20, 0, 0, 0, 0, utils.JST)
+
+	volumes := []*domain.Chart{
+		{Label: domain.ChartVolumes, Year: 2023, Month: 11, Data: 3},
+		{Label: domain.ChartVolumes, Year: 2024, Month: 1, Data: 1},
+		{Label: domain.ChartVolumes, Year: 2024, Month: 2, Data: 1},
+	}
+	books := []*domain.Book{
+		{ID: 1, Price: 1640, BookStatus: domain.Read, CreatedAt: nov, UpdatedAt: jan},
+		{ID: 2, Price: 770, BookStatus: domain.Reading, CreatedAt: nov, UpdatedAt: jan},
+		{ID: 3, Price: 980, BookStatus: domain.Bought, CreatedAt: nov.AddDate(0, 0, -1), UpdatedAt: nov},
+		{ID: 4, Price: 1240, BookStatus: domain.Read, CreatedAt: jan, UpdatedAt: now},
+		{ID: 5, Price: 220, BookStatus: domain.Bought, CreatedAt: now, UpdatedAt: now},
+	}
+
+	got := domain.NewBacklog(volumes, books, domain.JPY, now)
+
+	a.Equal([]*domain.BacklogMonth{
+		{Year: 2023, Month: 11, Bought: 3, Read: 0, Backlog: 3},
+		{Year: 2023, Month: 12, Bought: 0, Read: 0, Backlog: 3},
+		{Year: 2024, Month: 1, Bought: 1, Read: 1, Backlog: 3},
+		{Year: 2024, Month: 2, Bought: 1, Read: 1, Backlog: 3},
+	}, got.Months)
+	a.Equal(3, got.UnreadVolumes)
+	a.Equal(1970, got.UnreadCosts)
+	a.Equal(domain.JPY, got.Currency)
+	a.Equal([]int64{3, 2, 5}, []int64{got.OldestUnread[0].ID, got.OldestUnread[1].ID, got.OldestUnread[2].ID})
+	a.Equal(0.02, got.ReadPerDay)
+	a.Equal(135, got.DaysToClear)
+}
+
+func TestNewBacklogWithoutRecentRead(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+
+	books := []*domain.Book{
+		{Price: 980, BookStatus: domain.Bought, CreatedAt: now, UpdatedAt: now},
+	}
+
+	got := domain.NewBacklog(nil, books, "", now)
+
+	a.Len(got.Months, 1)
+	a.Equal(domain.JPY, got.Currency)
+	a.Equal(float64(0), got.ReadPerDay)
+	a.Equal(-1, got.DaysToClear)
+}
+
+func TestNewBacklogEmpty(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+
+	got := domain.NewBacklog(nil, nil, domain.JPY, now)
+
+	a.Equal(0, got.UnreadVolumes)
+	a.Empty(got.OldestUnread)
+	a.Equal(0, got.DaysToClear)
+}
@@ -0,0 +1,34 @@
+package domain
+
+// 本棚の一括操作（バッチ）で1回に指定できる操作数の上限
+const MaxShelfBatchSize = 100
+
+// 一括操作の種類
+type ShelfOpKind string
+
+const (
+	ShelfOpCreate ShelfOpKind = "create" //本の作成（チャートも作成）
+	ShelfOpUpdate ShelfOpKind = "update" //本の更新（PUTと同じ、チャートも更新）
+	ShelfOpStatus ShelfOpKind = "status" //本の状態のみ変更
+	ShelfOpDelete ShelfOpKind = "delete" //本の削除（ゴミ箱へ移動）
+)
+
+// 一括操作のひとつ。Bookはcreate、updateでは本全体、status、deleteでは対象の本（ID、AuthUserId、statusはBookStatusとVersion）。
+type ShelfOperation struct {
+	Kind ShelfOpKind
+	Book *Book
+}
+
+// 一括操作ひとつの結果。ErrがnilでAbortedがtrue の場合は、他の操作が失敗したため取り消された。
+type ShelfOperationResult struct {
+	Kind    ShelfOpKind
+	Book    *Book
+	Err     error
+	Aborted bool
+}
+
+// 一括操作の結果。Committedは変更を反映したか（すべて取り消した場合はfalse）。
+type ShelfBatchResult struct {
+	Committed bool
+	Results   []*ShelfOperationResult
+}
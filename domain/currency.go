@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// ISO 4217の通貨コード
+type Currency string
+
+const (
+	JPY = Currency("JPY")
+	USD = Currency("USD")
+	EUR = Currency("EUR")
+	GBP = Currency("GBP")
+	KRW = Currency("KRW")
+	CNY = Currency("CNY")
+)
+
+// 通貨の指定がない場合に使用する通貨。既存データはすべてこの通貨とみなす。
+const DefaultCurrency = JPY
+
+// 為替レートの基準通貨。ExchangeRate.Rateは1単位あたりの基準通貨額を表す。
+const BaseCurrency = JPY
+
+var (
+	ErrInvalidCurrency = NewError(ErrValidation, "不正な通貨コードです")
+	ErrNoExchangeRate  = errors.New("為替レートが登録されていません")
+	ErrInvalidPrice    = NewError(ErrValidation, "不正な価格です")
+)
+
+var currencyCodeRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// 補助単位の桁数が2桁以外の通貨。記載のない通貨は2桁として扱う。
+var currencyExponents = map[Currency]int{
+	JPY: 0,
+	KRW: 0,
+}
+
+// 通貨コードの文字列をCurrency型に変換。空文字の場合はDefaultCurrencyを返す。
+func ParseCurrency(s string) (Currency, error) {
+	if s == "" {
+		return DefaultCurrency, nil
+	}
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if !currencyCodeRe.MatchString(code) {
+		return "", fmt.Errorf("%w:%s", ErrInvalidCurrency, s)
+	}
+	return Currency(code), nil
+}
+
+// 空の場合はDefaultCurrencyを返す
+func (c Currency) OrDefault() Currency {
+	if c == "" {
+		return DefaultCurrency
+	}
+	return c
+}
+
+// 補助単位の桁数（例. USDなら2、JPYなら0）
+func (c Currency) Exponent() int {
+	if exp, ok := currencyExponents[c.OrDefault()]; ok {
+		return exp
+	}
+	return 2
+}
+
+// 金額の文字列（例. "1,234"、"12.99"）を補助単位の整数に変換。
+// 3桁区切りのカンマは取り除き、小数点以下は通貨の桁数までのみ受け付ける。符号（"-1,200"、"+5"）は受け付けない。
+func ParsePrice(s string, c Currency) (int, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		return 0, fmt.Errorf("%w:%s", ErrInvalidPrice, s)
+	}
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+
+	exp := c.Exponent()
+	if hasFrac && len(fracPart) > exp {
+		return 0, fmt.Errorf("%w:%s", ErrInvalidPrice, s)
+	}
+	fracPart += strings.Repeat("0", exp-len(fracPart))
+
+	amount, err := strconv.Atoi(intPart + fracPart)
+	if err != nil {
+		return 0, fmt.Errorf("%w:%w", ErrInvalidPrice, err)
+	}
+	if amount < 0 {
+		return 0, fmt.Errorf("%w:%s", ErrInvalidPrice, s)
+	}
+	return amount, nil
+}
+
+// 補助単位の整数を3桁カンマ区切りの金額文字列に変換（例. 123456 USD → "1,234.56"）
+func FormatPrice(amount int, c Currency) string {
+	//3桁カンマ区切りで出力するためのfmt拡張
+	fmtx := message.NewPrinter(language.Japanese)
+
+	exp := c.Exponent()
+	if exp == 0 {
+		return fmtx.Sprint(amount)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := int(math.Pow10(exp))
+	return fmt.Sprintf("%s%s.%0*d", sign, fmtx.Sprint(amount/unit), exp, amount%unit)
+}
+
+// 外部APIなどの小数表記の金額を補助単位の整数に変換
+func PriceFromFloat(amount float64, c Currency) int {
+	return int(math.Round(amount * math.Pow10(c.Exponent())))
+}
+
+type ExchangeRate struct {
+	bun.BaseModel `bun:"table:exchange_rates,alias:er"`
+
+	Currency  Currency  `bun:"currency,pk" json:"currency"`
+	Rate      float64   `bun:"rate,notnull" json:"rate"`
+	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"createdAt,omitempty"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt,omitempty"`
+}
+
+// 通貨ごとの為替レート表。値は1単位あたりの基準通貨額。
+type ExchangeRates map[Currency]float64
+
+func NewExchangeRates(rates []*ExchangeRate) ExchangeRates {
+	er := make(ExchangeRates, len(rates))
+	for _, r := range rates {
+		er[r.Currency] = r.Rate
+	}
+	return er
+}
+
+// 為替レートを記述したjsonファイル（例. {"USD": 150.5, "EUR": 162.3}）を読み込む
+func LoadExchangeRatesFile(path string) ([]*ExchangeRate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("為替レートファイルの読み込みに失敗:%w", err)
+	}
+	raw := map[string]float64{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("為替レートファイルのパースに失敗:%w", err)
+	}
+
+	rates := make([]*ExchangeRate, 0, len(raw))
+	for code, rate := range raw {
+		c, err := ParseCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("為替レートは正の数で指定ください:%s", code)
+		}
+		rates = append(rates, &ExchangeRate{Currency: c, Rate: rate})
+	}
+	return rates, nil
+}
+
+func (er ExchangeRates) rate(c Currency) (float64, error) {
+	if c == BaseCurrency {
+		return 1, nil
+	}
+	rate, ok := er[c]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("%w:%s", ErrNoExchangeRate, c)
+	}
+	return rate, nil
+}
+
+// 補助単位の金額をfromからtoの通貨に換算する。端数は四捨五入。
+func (er ExchangeRates) Convert(amount int, from Currency, to Currency) (int, error) {
+	from, to = from.OrDefault(), to.OrDefault()
+	if from == to {
+		return amount, nil
+	}
+	fromRate, err := er.rate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := er.rate(to)
+	if err != nil {
+		return 0, err
+	}
+	major := float64(amount) / math.Pow10(from.Exponent())
+	converted := major * fromRate / toRate
+	return int(math.Round(converted * math.Pow10(to.Exponent()))), nil
+}
+
+// 本の価格をすべてtoの通貨に換算した複製を返す（引数のbooksは変更しない）
+func (er ExchangeRates) ConvertBooks(books []*Book, to Currency) ([]*Book, error) {
+	converted := make([]*Book, len(books))
+	for i, b := range books {
+		price, err := er.Convert(b.Price, b.Currency, to)
+		if err != nil {
+			return nil, err
+		}
+		cb := *b
+		cb.Price = price
+		cb.Currency = to.OrDefault()
+		converted[i] = &cb
+	}
+	return converted, nil
+}
+
+// 通貨ごとに集計されたチャートのうち購入額をtoの通貨に換算し、
+// 同じ年月・ラベルのデータを合算する。並び順は引数の順序を維持。
+func (er ExchangeRates) ConvertCharts(charts []*Chart, to Currency) ([]*Chart, error) {
+	type key struct {
+		label ChartLabel
+		year  int
+		month int
+	}
+	merged := make(map[key]*Chart, len(charts))
+	result := make([]*Chart, 0, len(charts))
+
+	for _, c := range charts {
+		data := c.Data
+		var currency Currency
+		if c.Label == ChartPrice {
+			converted, err := er.Convert(c.Data, c.Currency, to)
+			if err != nil {
+				return nil, err
+			}
+			data = converted
+			currency = to.OrDefault()
+		}
+
+		k := key{label: c.Label, year: c.Year, month: c.Month}
+		if m, ok := merged[k]; ok {
+			m.Data += data
+			continue
+		}
+		mc := *c
+		mc.Data = data
+		mc.Currency = currency
+		merged[k] = &mc
+		result = append(result, &mc)
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+)
+
+func TestParsePrice(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		price    string
+		currency domain.Currency
+		want     int
+		isErr    bool
+	}{
+		"OK:円のカンマ区切り": {price: "1,234", currency: domain.JPY, want: 1234},
+		"OK:ドルの小数点":   {price: "1,234.5", currency: domain.USD, want: 123450},
+		"OK:ドルの整数":    {price: "12", currency: domain.USD, want: 1200},
+		"NG:円に小数点":    {price: "12.5", currency: domain.JPY, isErr: true},
+		"NG:数値以外":     {price: "abc", currency: domain.JPY, isErr: true},
+		"NG:ドルの桁数超過":  {price: "1.234", currency: domain.USD, isErr: true},
+		"NG:負の金額":     {price: "-1,200", currency: domain.JPY, isErr: true},
+		"NG:正の符号":     {price: "+5", currency: domain.USD, isErr: true},
+		"NG:小数部の符号":   {price: "1.-5", currency: domain.USD, isErr: true},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := domain.ParsePrice(test.price, test.currency)
+			if test.isErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidPrice)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal("8,800", domain.FormatPrice(8800, domain.JPY))
+	a.Equal("8,800", domain.FormatPrice(8800, ""))
+	a.Equal("1,234.05", domain.FormatPrice(123405, domain.USD))
+	a.Equal("-0.99", domain.FormatPrice(-99, domain.USD))
+}
+
+func TestExchangeRatesConvert(t *testing.T) {
+	t.Parallel()
+	rates := domain.ExchangeRates{domain.USD: 150, domain.EUR: 160}
+	tests := map[string]struct {
+		amount int
+		from   domain.Currency
+		to     domain.Currency
+		want   int
+		isErr  bool
+	}{
+		"OK:同じ通貨":    {amount: 980, from: domain.JPY, to: domain.JPY, want: 980},
+		"OK:通貨の指定なし": {amount: 980, from: "", to: domain.JPY, want: 980},
+		"OK:ドルから円":   {amount: 1299, from: domain.USD, to: domain.JPY, want: 1949},
+		"OK:円からドル":   {amount: 1500, from: domain.JPY, to: domain.USD, want: 1000},
+		"OK:ユーロからドル": {amount: 1500, from: domain.EUR, to: domain.USD, want: 1600},
+		"NG:レートが未登録": {amount: 1500, from: domain.GBP, to: domain.JPY, isErr: true},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := rates.Convert(test.amount, test.from, test.to)
+			if test.isErr {
+				assert.ErrorIs(t, err, domain.ErrNoExchangeRate)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestExchangeRatesConvertCharts(t *testing.T) {
+	t.Parallel()
+	//Arrange
+	rates := domain.ExchangeRates{domain.USD: 150}
+	charts := []*domain.Chart{
+		{Label: domain.ChartPrice, Year: 2025, Month: 2, Data: 980, Currency: domain.JPY},
+		{Label: domain.ChartPrice, Year: 2025, Month: 2, Data: 1000, Currency: domain.USD},
+		{Label: domain.ChartVolumes, Year: 2025, Month: 2, Data: 2},
+		{Label: domain.ChartPages, Year: 2025, Month: 2, Data: 494},
+	}
+	want := []*domain.Chart{
+		{Label: domain.ChartPrice, Year: 2025, Month: 2, Data: 2480, Currency: domain.JPY},
+		{Label: domain.ChartVolumes, Year: 2025, Month: 2, Data: 2},
+		{Label: domain.ChartPages, Year: 2025, Month: 2, Data: 494},
+	}
+
+	//Act
+	got, err := rates.ConvertCharts(charts, domain.JPY)
+
+	//Assert
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// メールの言語
+type MailLanguage string
+
+const (
+	MailJapanese = MailLanguage("ja")
+	MailEnglish  = MailLanguage("en")
+)
+
+var ErrInvalidMailSetting = NewError(ErrValidation, "メールの設定が不正です")
+var ErrInvalidUnsubscribeToken = NewError(ErrValidation, "配信停止のトークンが不正です")
+
+// ユーザーごとのメールの設定。未登録のユーザーは日本語で月次のまとめを送る（DefaultMailSetting）。
+type MailSetting struct {
+	bun.BaseModel `bun:"table:mail_settings,alias:ms"`
+
+	AuthUserId string       `bun:"auth_user_id,pk"`
+	Language   MailLanguage `bun:"language,notnull,default:'ja'"`
+	Digest     bool         `bun:"digest,notnull"` //月次のまとめを受け取るか
+	UpdatedAt  time.Time    `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func DefaultMailSetting(authUserId string) *MailSetting {
+	return &MailSetting{AuthUserId: authUserId, Language: MailJapanese, Digest: true}
+}
+
+func (s *MailSetting) Validate() error {
+	if s.Language != MailJapanese && s.Language != MailEnglish {
+		return ErrInvalidMailSetting
+	}
+	return nil
+}
+
+// 月次のまとめの送信先
+type DigestRecipient struct {
+	ID         int64 //usersのid（送信先の一覧のページングに使う）
+	AuthUserId string
+	Name       string
+	Email      Email
+	Language   MailLanguage
+}
+
+// 1か月分の読書のまとめ。金額はCurrencyに換算済み。
+type Digest struct {
+	Year          int
+	Month         int
+	Bought        int //その月に購入した冊数
+	Spent         int //その月の購入額
+	Read          int //その月に読了した冊数
+	UnreadVolumes int //送信時点の積読の冊数
+	UnreadCosts   int //送信時点の積読の購入額
+	Total         *Record
+	Currency      Currency
+}
+
+// 記録（全期間）、月ごとのチャート、積読の状況からyear年month月のまとめを作成する。
+// chartsの購入額とbacklogの金額はあらかじめ同じ通貨に換算しておくこと。
+func NewDigest(record *Record, charts []*Chart, backlog *Backlog, year int, month int) *Digest {
+	d := &Digest{
+		Year:          year,
+		Month:         month,
+		UnreadVolumes: backlog.UnreadVolumes,
+		UnreadCosts:   backlog.UnreadCosts,
+		Total:         record,
+		Currency:      backlog.Currency,
+	}
+	for _, c := range charts {
+		if c.Year != year || c.Month != month {
+			continue
+		}
+		switch c.Label {
+		case ChartVolumes:
+			d.Bought += c.Data
+		case ChartPrice:
+			d.Spent += c.Data
+		}
+	}
+	for _, m := range backlog.Months {
+		if m.Year == year && m.Month == month {
+			d.Read = m.Read
+		}
+	}
+	return d
+}
+
+// 何も記録がない月か（まとめを送らない）
+func (d *Digest) IsEmpty() bool {
+	return d.Bought == 0 && d.Read == 0 && d.UnreadVolumes == 0
+}
+
+// 配信停止のトークン（"authUserIdのbase64url.署名"）。ログインなしでメールのリンクから配信を停止するために使う。
+func NewUnsubscribeToken(secret string, authUserId string) string {
+	id := base64.RawURLEncoding.EncodeToString([]byte(authUserId))
+	return id + "." + signUnsubscribe(secret, id)
+}
+
+// 配信停止のトークンを検証し、authUserIdを返す
+func ParseUnsubscribeToken(secret string, token string) (string, error) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok || secret == "" || !hmac.Equal([]byte(sig), []byte(signUnsubscribe(secret, id))) {
+		return "", ErrInvalidUnsubscribeToken
+	}
+	authUserId, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil || len(authUserId) == 0 {
+		return "", ErrInvalidUnsubscribeToken
+	}
+	return string(authUserId), nil
+}
+
+func signUnsubscribe(secret string, id string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("unsubscribe:" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
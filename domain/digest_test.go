@@ -0,0 +1,87 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+)
+
+func TestNewDigest(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	record := &domain.Record{Costs: 5000, Volumes: 4, VolumesRead: 2, PagesRead: 600, Currency: "JPY"}
+	charts := []*domain.Chart{
+		{Label: domain.ChartPrice, Year: 2024, Month: 1, Data: 1500},
+		{Label: domain.ChartVolumes, Year: 2024, Month: 1, Data: 1},
+		{Label: domain.ChartPrice, Year: 2024, Month: 1, Data: 2000},
+		{Label: domain.ChartVolumes, Year: 2024, Month: 1, Data: 1},
+		{Label: domain.ChartPages, Year: 2024, Month: 1, Data: 300},
+		{Label: domain.ChartPrice, Year: 2023, Month: 12, Data: 1500},
+		{Label: domain.ChartVolumes, Year: 2023, Month: 12, Data: 2},
+	}
+	backlog := &domain.Backlog{
+		Months: []*domain.BacklogMonth{
+			{Year: 2023, Month: 12, Bought: 2, Read: 1, Backlog: 1},
+			{Year: 2024, Month: 1, Bought: 2, Read: 1, Backlog: 2},
+		},
+		UnreadVolumes: 2,
+		UnreadCosts:   3500,
+		Currency:      "JPY",
+	}
+
+	got := domain.NewDigest(record, charts, backlog, 2024, 1)
+
+	a.Equal(&domain.Digest{
+		Year:          2024,
+		Month:         1,
+		Bought:        2,
+		Spent:         3500,
+		Read:          1,
+		UnreadVolumes: 2,
+		UnreadCosts:   3500,
+		Total:         record,
+		Currency:      "JPY",
+	}, got)
+	a.False(got.IsEmpty())
+	a.True(domain.NewDigest(&domain.Record{}, nil, &domain.Backlog{}, 2024, 1).IsEmpty())
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	t.Parallel()
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	token := domain.NewUnsubscribeToken("secret", authUserId)
+
+	tests := map[string]struct {
+		secret  string
+		token   string
+		want    string
+		wantErr error
+	}{
+		"正常なトークン":  {secret: "secret", token: token, want: authUserId},
+		"鍵が異なる":    {secret: "other", token: token, wantErr: domain.ErrInvalidUnsubscribeToken},
+		"鍵が未設定":    {secret: "", token: token, wantErr: domain.ErrInvalidUnsubscribeToken},
+		"ユーザーを改ざん": {secret: "secret", token: "YWJj" + token[len("YWJj"):], wantErr: domain.ErrInvalidUnsubscribeToken},
+		"署名なし":     {secret: "secret", token: "YWJj", wantErr: domain.ErrInvalidUnsubscribeToken},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := domain.ParseUnsubscribeToken(test.secret, test.token)
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestMailSettingValidate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Nil(domain.DefaultMailSetting("user").Validate())
+	a.Nil((&domain.MailSetting{Language: domain.MailEnglish}).Validate())
+	a.ErrorIs((&domain.MailSetting{Language: "fr"}).Validate(), domain.ErrInvalidMailSetting)
+}
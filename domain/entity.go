@@ -24,6 +24,9 @@ const (
 	ChartPages   = ChartLabel("購入ページ数")
 )
 
+// APIから指定したパスワードがハッシュ化済み（ハッシュを直接保存させない）
+var ErrPasswordHashed = NewError(ErrValidation, "ハッシュ化済みのパスワードは指定できません")
+
 func (p Password) String() string {
 	return "xxxxxxxxx"
 }
@@ -40,13 +43,19 @@ func (e Email) GoString() string {
 type User struct {
 	bun.BaseModel `bun:"table:users,alias:u"`
 
-	ID         int64     `bun:",pk,autoincrement"`
-	AuthUserId string    `bun:"auth_user_id,nullzero,notnull,unique"`
-	Name       string    `bun:"name"`
-	Email      Email     `bun:"email,notnull,unique"`
-	Password   Password  `bun:"password"`
-	CreatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp"`
-	UpdatedAt  time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	ID              int64     `bun:",pk,autoincrement"`
+	AuthUserId      string    `bun:"auth_user_id,nullzero,notnull,unique"`
+	Name            string    `bun:"name"`
+	Email           Email     `bun:"email,notnull,unique"`
+	Password        Password  `bun:"password"`
+	HomeCurrency    Currency  `bun:"home_currency,nullzero,notnull,default:'JPY'"`
+	Version         int64     `bun:"version,nullzero,notnull,default:1"` //更新ごとに1増える（ETag）
+	EmailVerifiedAt time.Time `bun:"email_verified_at,nullzero"`         //メールアドレスを確認した日時（未確認、変更後はゼロ値）
+	Role            Role      `bun:"role,nullzero,notnull,default:'user'"`
+	DisabledAt      time.Time `bun:"disabled_at,nullzero"` //管理者が無効化した日時（有効な場合はゼロ値）
+	CreatedAt       time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+	UpdatedAt       time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	DeletedAt       time.Time `bun:",soft_delete,nullzero"`
 }
 
 type Book struct {
@@ -59,19 +68,23 @@ type Book struct {
 	Author     string     `bun:"author" json:"author,omitempty"`
 	Page       int        `bun:"page,type:integer" json:"page,omitempty"`
 	Price      int        `bun:"price,type:integer" json:"price,omitempty"`
+	Currency   Currency   `bun:"currency,nullzero,notnull,default:'JPY'" json:"currency,omitempty"`
 	BookStatus BookStatus `bun:"book_status,nullzero,notnull" json:"bookStatus,omitempty"`
 	AuthUserId string     `bun:"auth_user_id,nullzero,notnull" json:"authUserId,omitempty"`
+	Version    int64      `bun:"version,nullzero,notnull,default:1" json:"version,omitempty"` //更新ごとに1増える（ETag）
 	CreatedAt  time.Time  `bun:",nullzero,notnull,default:current_timestamp" json:"createdAt,omitempty"`
 	UpdatedAt  time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt,omitempty"`
+	DeletedAt  time.Time  `bun:",soft_delete,nullzero" json:"deletedAt,omitempty"`
 }
 
 type Record struct {
-	Costs       int `json:"costs,omitempty"`
-	CostsRead   int `json:"costsRead,omitempty"`
-	Volumes     int `json:"volumes,omitempty"`
-	VolumesRead int `json:"volumesRead,omitempty"`
-	Pages       int `json:"pages,omitempty"`
-	PagesRead   int `json:"pagesRead,omitempty"`
+	Costs       int      `json:"costs,omitempty"`
+	CostsRead   int      `json:"costsRead,omitempty"`
+	Volumes     int      `json:"volumes,omitempty"`
+	VolumesRead int      `json:"volumesRead,omitempty"`
+	Pages       int      `json:"pages,omitempty"`
+	PagesRead   int      `json:"pagesRead,omitempty"`
+	Currency    Currency `json:"currency,omitempty"`
 }
 
 type Chart struct {
@@ -82,10 +95,12 @@ type Chart struct {
 	Year       int        `bun:"year,nullzero,type:integer" json:"year,omitempty"`
 	Month      int        `bun:"month,nullzero,type:integer" json:"month,omitempty"`
 	Data       int        `bun:"data,nullzero,type:integer" json:"data,omitempty"`
+	Currency   Currency   `bun:"currency,nullzero" json:"currency,omitempty"`
 	AuthUserId string     `bun:"auth_user_id,nullzero,notnull" json:"authUserId,omitempty"`
 	BookId     int64      `bun:"book_id,nullzero,notnull" json:"bookId,omitempty"`
 	CreatedAt  time.Time  `bun:",nullzero,notnull,default:current_timestamp" json:"createdAt,omitempty"`
 	UpdatedAt  time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt,omitempty"`
+	DeletedAt  time.Time  `bun:",soft_delete,nullzero" json:"deletedAt,omitempty"`
 }
 
 // パスワードをハッシュ化する。内部でbcryptパッケージを使用しており、Costはデフォルトの10で固定。
@@ -106,6 +121,12 @@ func (u *User) ValidatePassword(raw Password) bool {
 	return true
 }
 
+// パスワードがbcryptでハッシュ化済みか（入力できるパスワードは20文字以下のため、60文字のハッシュと区別できる）
+func (p Password) IsHashed() bool {
+	_, err := bcrypt.Cost([]byte(p))
+	return err == nil
+}
+
 func NewChartsFromBook(book *Book) []*Chart {
 	year := book.CreatedAt.Year()
 	month := int(book.CreatedAt.Month())
@@ -115,6 +136,7 @@ func NewChartsFromBook(book *Book) []*Chart {
 		Year:       year,
 		Month:      month,
 		Data:       book.Price,
+		Currency:   book.Currency.OrDefault(),
 		AuthUserId: book.AuthUserId,
 	}
 
@@ -148,6 +170,7 @@ func UpdateChartsFromBook(book *Book) []*Chart {
 		Year:       year,
 		Month:      month,
 		Data:       book.Price,
+		Currency:   book.Currency.OrDefault(),
 		AuthUserId: book.AuthUserId,
 		BookId:     book.ID,
 		CreatedAt:  book.CreatedAt,
@@ -178,6 +201,45 @@ func UpdateChartsFromBook(book *Book) []*Chart {
 	return charts
 }
 
+// 本の部分更新（PATCH）で、図表の再計算が必要な列（価格、ページ数、通貨、購入日）
+var chartColumns = map[string]bool{
+	"page":       true,
+	"price":      true,
+	"currency":   true,
+	"created_at": true,
+}
+
+// 更新した列（bunの列名）に、図表の再計算が必要な列が含まれるか
+func ChartColumnsChanged(columns []string) bool {
+	for _, c := range columns {
+		if chartColumns[c] {
+			return true
+		}
+	}
+	return false
+}
+
+// 本の価格、ページ数、通貨、購入日（CreatedAtの年月）を図表に反映する。購入冊数は1冊のまま変わらない。
+func ApplyBookToCharts(book *Book, charts []*Chart, now time.Time) {
+	year := book.CreatedAt.Year()
+	month := int(book.CreatedAt.Month())
+
+	for _, c := range charts {
+		c.Year = year
+		c.Month = month
+		c.UpdatedAt = now
+		switch c.Label {
+		case ChartPrice:
+			c.Data = book.Price
+			c.Currency = book.Currency.OrDefault()
+		case ChartPages:
+			c.Data = book.Page
+		}
+	}
+}
+
+// 本棚から記録を集計する。価格はすべて同じ通貨である前提のため、
+// 異なる通貨が混在する場合はExchangeRates.ConvertBooksで換算してから渡す。
 func NewRecordFromBooks(books []*Book) *Record {
 	record := new(Record)
 
@@ -2,6 +2,7 @@ package domain_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -74,3 +75,60 @@ func TestNewRecordFromBooks(t *testing.T) {
 	//Assert
 	assert.Equal(t, want, got)
 }
+
+func TestApplyBookToCharts(t *testing.T) {
+	t.Parallel()
+	//Arrange
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, jst)
+	book := &domain.Book{
+		ID:         int64(1),
+		Page:       300,
+		Price:      1299,
+		Currency:   domain.USD,
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		CreatedAt:  time.Date(2025, 1, 31, 23, 0, 0, 0, jst),
+	}
+	charts := []*domain.Chart{
+		{ID: int64(1), Label: domain.ChartPrice, Year: 2025, Month: 2, Data: 980, Currency: domain.JPY, BookId: book.ID},
+		{ID: int64(2), Label: domain.ChartVolumes, Year: 2025, Month: 2, Data: 1, BookId: book.ID},
+		{ID: int64(3), Label: domain.ChartPages, Year: 2025, Month: 2, Data: 247, BookId: book.ID},
+	}
+
+	want := []*domain.Chart{
+		{ID: int64(1), Label: domain.ChartPrice, Year: 2025, Month: 1, Data: 1299, Currency: domain.USD, BookId: book.ID, UpdatedAt: now},
+		{ID: int64(2), Label: domain.ChartVolumes, Year: 2025, Month: 1, Data: 1, BookId: book.ID, UpdatedAt: now},
+		{ID: int64(3), Label: domain.ChartPages, Year: 2025, Month: 1, Data: 300, BookId: book.ID, UpdatedAt: now},
+	}
+
+	//Act
+	domain.ApplyBookToCharts(book, charts, now)
+
+	//Assert
+	assert.Equal(t, want, charts)
+}
+
+func TestChartColumnsChanged(t *testing.T) {
+	t.Parallel()
+	//Arrange
+	tests := map[string]struct {
+		columns []string
+		want    bool
+	}{
+		"状態のみ":   {columns: []string{"book_status"}, want: false},
+		"書名と著者":  {columns: []string{"title", "author"}, want: false},
+		"価格":     {columns: []string{"price"}, want: true},
+		"状態と購入日": {columns: []string{"book_status", "created_at"}, want: true},
+		"なし":     {columns: nil, want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act
+			got := domain.ChartColumnsChanged(tt.columns)
+
+			//Assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
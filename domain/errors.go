@@ -0,0 +1,40 @@
+package domain
+
+import "errors"
+
+// エラーの種類。リポジトリ、コントローラはこれらの種類のエラーを返し、
+// HTTPステータスへの変換はpresenter/problemの一か所で行う。
+var (
+	ErrNotFound   = errors.New("リソースがありません")
+	ErrConflict   = errors.New("リソースが競合しています")
+	ErrForbidden  = errors.New("操作が許可されていません")
+	ErrValidation = errors.New("入力値が不正です")
+
+	// 認証に失敗した
+	ErrUnauthorized = errors.New("認証に失敗しました")
+
+	// 失敗が続いたため、一時的に受け付けない
+	ErrTooManyRequests = errors.New("リクエストが多すぎます")
+
+	// 更新の前提（If-Matchのバージョン）が一致しない
+	ErrPreconditionFailed = errors.New("更新の前提条件が一致しません")
+)
+
+// 種類を持つエラー。errors.Is(err, ErrValidation)のように種類で判定できる。
+type Error struct {
+	Kind error
+	Msg  string
+}
+
+// 種類を持つエラーを作成
+func NewError(kind error, msg string) *Error {
+	return &Error{Kind: kind, Msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
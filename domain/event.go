@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 変更イベントの保持期間の既定値。期間内であればLast-Event-IDから再開できる。
+const DefaultEventRetention = 7 * 24 * time.Hour
+
+// 変更イベントの種類
+type EventType string
+
+const (
+	EventBookCreated = EventType("book.created") //本の作成（ゴミ箱からの復元を含む）
+	EventBookUpdated = EventType("book.updated") //本の更新
+	EventBookDeleted = EventType("book.deleted") //本の削除
+	EventGoalReached = EventType("goal.reached") //読書目標の達成
+)
+
+// ユーザーごとの変更イベント。idの順に配信し、Last-Event-IDとして再開に使う。
+type Event struct {
+	bun.BaseModel `bun:"table:events,alias:ev"`
+
+	ID         int64     `bun:"id,pk,autoincrement"`
+	AuthUserId string    `bun:"auth_user_id,notnull"`
+	Type       EventType `bun:"type,notnull"`
+	BookId     int64     `bun:"book_id,nullzero"` //本のイベントのみ
+	GoalId     int64     `bun:"goal_id,nullzero"` //目標のイベントのみ
+	Version    int64     `bun:"version,nullzero"` //変更後の本のバージョン（作成、更新のみ）
+	CreatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// 本の変更イベントを作成
+func NewBookEvent(typ EventType, book *Book) *Event {
+	ev := &Event{AuthUserId: book.AuthUserId, Type: typ, BookId: book.ID}
+	if typ != EventBookDeleted {
+		ev.Version = book.Version
+	}
+	return ev
+}
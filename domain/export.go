@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// 削除したデータのアーカイブを作成できない（削除は取り消す）
+var ErrExportFailed = errors.New("削除データのアーカイブの作成に失敗")
+
+// アカウント削除時に削除対象となったユーザーのデータ一式
+type UserExport struct {
+	User      *User
+	Books     []*Book
+	Charts    []*Chart
+	Goals     []*Goal
+	DeletedAt time.Time
+	PurgeAt   time.Time //完全に削除される予定日時（即時削除の場合はDeletedAtと同じ）
+}
+
+// エクスポート用のユーザー情報（パスワードは含めない）
+type exportUser struct {
+	AuthUserId   string    `json:"authUserId"`
+	Name         string    `json:"name,omitempty"`
+	Email        string    `json:"email"`
+	HomeCurrency Currency  `json:"homeCurrency"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// 削除の受領証。アーカイブの内容と削除日時を記録する。
+type exportReceipt struct {
+	AuthUserId string    `json:"authUserId"`
+	DeletedAt  time.Time `json:"deletedAt"`
+	PurgeAt    time.Time `json:"purgeAt"`
+	Books      int       `json:"books"`
+	Charts     int       `json:"charts"`
+	Goals      int       `json:"goals"`
+}
+
+// 削除したデータをjsonファイルにまとめたzipアーカイブをwに書き込む
+func (e *UserExport) WriteArchive(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{
+			name: "receipt.json",
+			data: &exportReceipt{
+				AuthUserId: e.User.AuthUserId,
+				DeletedAt:  e.DeletedAt,
+				PurgeAt:    e.PurgeAt,
+				Books:      len(e.Books),
+				Charts:     len(e.Charts),
+				Goals:      len(e.Goals),
+			},
+		},
+		{
+			name: "user.json",
+			data: &exportUser{
+				AuthUserId:   e.User.AuthUserId,
+				Name:         e.User.Name,
+				Email:        string(e.User.Email),
+				HomeCurrency: e.User.HomeCurrency.OrDefault(),
+				CreatedAt:    e.User.CreatedAt,
+				UpdatedAt:    e.User.UpdatedAt,
+			},
+		},
+		{name: "books.json", data: e.Books},
+		{name: "charts.json", data: e.Charts},
+		{name: "goals.json", data: e.Goals},
+	}
+
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: e.DeletedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("アーカイブの作成に失敗:%w", err)
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return fmt.Errorf("%sの書き込みに失敗:%w", f.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("アーカイブの作成に失敗:%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestUserExportWriteArchive(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+
+	export := &domain.UserExport{
+		User: &domain.User{
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			Email:      domain.Email("example@example.com"),
+			Password:   domain.Password("hashed"),
+		},
+		Books:     []*domain.Book{{ID: 1, Title: "容疑者Xの献身"}, {ID: 2, Title: "予知夢"}},
+		Charts:    []*domain.Chart{{ID: 1, Label: domain.ChartVolumes, Data: 1}},
+		Goals:     []*domain.Goal{},
+		DeletedAt: now,
+		PurgeAt:   now.Add(domain.DefaultTrashRetention),
+	}
+
+	var buf bytes.Buffer
+	err := export.WriteArchive(&buf)
+	a.Nil(err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	a.Nil(err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		a.Nil(err)
+		data, err := io.ReadAll(rc)
+		a.Nil(err)
+		rc.Close()
+		files[f.Name] = data
+	}
+	a.Len(files, 5)
+
+	var receipt map[string]any
+	a.Nil(json.Unmarshal(files["receipt.json"], &receipt))
+	a.Equal(float64(2), receipt["books"])
+	a.Equal(float64(1), receipt["charts"])
+	a.Equal("2024-03-06T14:43:00+09:00", receipt["purgeAt"])
+
+	a.Contains(string(files["user.json"]), "example@example.com")
+	a.NotContains(string(files["user.json"]), "hashed")
+	a.Contains(string(files["books.json"]), "予知夢")
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type GoalKind string
+type GoalPeriod string
+type GoalStatus string
+
+const (
+	GoalBooks = GoalKind("books") //読了冊数
+	GoalPages = GoalKind("pages") //読了ページ数
+	GoalSpend = GoalKind("spend") //購入額の上限
+)
+
+const (
+	GoalYearly  = GoalPeriod("yearly")
+	GoalMonthly = GoalPeriod("monthly")
+)
+
+const (
+	GoalAchieved = GoalStatus("achieved") //目標を達成済み
+	GoalOnTrack  = GoalStatus("on_track") //期間の経過に対して順調
+	GoalBehind   = GoalStatus("behind")   //期間の経過に対して遅れ（上限の場合は使いすぎ）
+	GoalExceeded = GoalStatus("exceeded") //購入額の上限を超過
+)
+
+var (
+	ErrInvalidGoal = NewError(ErrValidation, "不正な目標です")
+)
+
+type Goal struct {
+	bun.BaseModel `bun:"table:goals,alias:g"`
+
+	ID         int64      `bun:",pk,autoincrement" json:"id,omitempty"`
+	AuthUserId string     `bun:"auth_user_id,nullzero,notnull,unique:goals_auth_user_id_kind_period" json:"authUserId,omitempty"`
+	Kind       GoalKind   `bun:"kind,nullzero,notnull,unique:goals_auth_user_id_kind_period" json:"kind,omitempty"`
+	Period     GoalPeriod `bun:"period,nullzero,notnull,unique:goals_auth_user_id_kind_period" json:"period,omitempty"`
+	Target     int        `bun:"target,notnull,type:integer" json:"target,omitempty"`
+	Currency   Currency   `bun:"currency,nullzero" json:"currency,omitempty"`
+	CreatedAt  time.Time  `bun:",nullzero,notnull,default:current_timestamp" json:"createdAt,omitempty"`
+	UpdatedAt  time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updatedAt,omitempty"`
+}
+
+// 目標の種類、期間、目標値の組み合わせが正しいかを検証
+func (g *Goal) Validate() error {
+	switch g.Kind {
+	case GoalBooks, GoalPages, GoalSpend:
+	default:
+		return ErrInvalidGoal
+	}
+	switch g.Period {
+	case GoalYearly, GoalMonthly:
+	default:
+		return ErrInvalidGoal
+	}
+	if g.Target <= 0 {
+		return ErrInvalidGoal
+	}
+	return nil
+}
+
+// nowを含む目標期間の開始日時と終了日時（終了日時は含まない）を返す
+func (p GoalPeriod) Range(now time.Time) (start time.Time, end time.Time) {
+	if p == GoalMonthly {
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+	start = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(1, 0, 0)
+}
+
+type GoalProgress struct {
+	Goal        *Goal
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Current     int        //期間内の実績
+	Expected    int        //期間の経過割合から見た現時点の目安
+	Remaining   int        //目標までの残り（上限の場合は残りの予算）
+	DaysLeft    int        //期間終了までの残り日数（当日を含む）
+	PacePerDay  float64    //期間内に達成するために必要な1日あたりのペース（上限の場合は1日あたりの使用可能額）
+	Status      GoalStatus //進捗の状況
+}
+
+// 本棚のデータから目標の進捗を算出する。
+// 読了の目標は期間内に更新された読了本（updated_at）、購入額の上限は期間内に登録された本（created_at）を対象とする。
+// 購入額の上限については、booksの価格をあらかじめ目標の通貨に換算しておくこと。
+func NewGoalProgress(goal *Goal, books []*Book, now time.Time) *GoalProgress {
+	start, end := goal.Period.Range(now)
+
+	var bought, read []*Book
+	for _, b := range books {
+		if inPeriod(b.CreatedAt, start, end) {
+			bought = append(bought, b)
+		}
+		if b.BookStatus == Read && inPeriod(b.UpdatedAt, start, end) {
+			read = append(read, b)
+		}
+	}
+
+	var current int
+	switch goal.Kind {
+	case GoalBooks:
+		current = NewRecordFromBooks(read).VolumesRead
+	case GoalPages:
+		current = NewRecordFromBooks(read).PagesRead
+	case GoalSpend:
+		current = NewRecordFromBooks(bought).Costs
+	}
+
+	elapsed := now.Sub(start).Seconds() / end.Sub(start).Seconds()
+	daysLeft := int(math.Ceil(end.Sub(now).Hours() / 24))
+	remaining := max(goal.Target-current, 0)
+
+	gp := &GoalProgress{
+		Goal:        goal,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Current:     current,
+		Expected:    int(math.Round(float64(goal.Target) * elapsed)),
+		Remaining:   remaining,
+		DaysLeft:    daysLeft,
+	}
+	if daysLeft > 0 {
+		gp.PacePerDay = math.Round(float64(remaining)/float64(daysLeft)*100) / 100
+	}
+
+	switch {
+	case goal.Kind == GoalSpend && current > goal.Target:
+		gp.Status = GoalExceeded
+	case goal.Kind == GoalSpend && current > gp.Expected:
+		gp.Status = GoalBehind
+	case goal.Kind == GoalSpend:
+		gp.Status = GoalOnTrack
+	case current >= goal.Target:
+		gp.Status = GoalAchieved
+	case current >= gp.Expected:
+		gp.Status = GoalOnTrack
+	default:
+		gp.Status = GoalBehind
+	}
+
+	return gp
+}
+
+func inPeriod(t time.Time, start time.Time, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
@@ -0,0 +1,132 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestGoalValidate(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		goal  domain.Goal
+		isErr bool
+	}{
+		"OK:年間の読了冊数":   {goal: domain.Goal{Kind: domain.GoalBooks, Period: domain.GoalYearly, Target: 50}},
+		"OK:月間の購入額の上限": {goal: domain.Goal{Kind: domain.GoalSpend, Period: domain.GoalMonthly, Target: 5000}},
+		"NG:不正な種類":     {goal: domain.Goal{Kind: "words", Period: domain.GoalYearly, Target: 50}, isErr: true},
+		"NG:不正な期間":     {goal: domain.Goal{Kind: domain.GoalPages, Period: "weekly", Target: 50}, isErr: true},
+		"NG:目標値が0":     {goal: domain.Goal{Kind: domain.GoalPages, Period: domain.GoalYearly, Target: 0}, isErr: true},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := test.goal.Validate()
+			if test.isErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidGoal)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGoalPeriodRange(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+
+	start, end := domain.GoalMonthly.Range(now)
+	a.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, utils.JST), start)
+	a.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, utils.JST), end)
+
+	start, end = domain.GoalYearly.Range(now)
+	a.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, utils.JST), start)
+	a.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, utils.JST), end)
+}
+
+func TestNewGoalProgress(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+	lastYear := time.Date(2023, 12, 20, 0, 0, 0, 0, utils.JST)
+	lastMonth := time.Date(2024, 1, 20, 0, 0, 0, 0, utils.JST)
+
+	books := []*domain.Book{
+		{Page: 330, Price: 1640, BookStatus: domain.Read, CreatedAt: lastYear, UpdatedAt: now},
+		{Page: 234, Price: 770, BookStatus: domain.Read, CreatedAt: lastMonth, UpdatedAt: lastMonth},
+		{Page: 49, Price: 2500, BookStatus: domain.Bought, CreatedAt: now, UpdatedAt: now},
+		{Page: 120, Price: 900, BookStatus: domain.Read, CreatedAt: lastYear, UpdatedAt: lastYear},
+	}
+
+	tests := map[string]struct {
+		goal          *domain.Goal
+		wantCurrent   int
+		wantRemaining int
+		wantStatus    domain.GoalStatus
+	}{
+		"年間の読了冊数は順調": {
+			goal:          &domain.Goal{Kind: domain.GoalBooks, Period: domain.GoalYearly, Target: 12},
+			wantCurrent:   2,
+			wantRemaining: 10,
+			wantStatus:    domain.GoalOnTrack,
+		},
+		"月間の読了ページ数は達成済み": {
+			goal:          &domain.Goal{Kind: domain.GoalPages, Period: domain.GoalMonthly, Target: 300},
+			wantCurrent:   330,
+			wantRemaining: 0,
+			wantStatus:    domain.GoalAchieved,
+		},
+		"年間の読了ページ数は遅れ": {
+			goal:          &domain.Goal{Kind: domain.GoalPages, Period: domain.GoalYearly, Target: 10000},
+			wantCurrent:   564,
+			wantRemaining: 9436,
+			wantStatus:    domain.GoalBehind,
+		},
+		"月間の購入額は上限を超過": {
+			goal:          &domain.Goal{Kind: domain.GoalSpend, Period: domain.GoalMonthly, Target: 2000},
+			wantCurrent:   2500,
+			wantRemaining: 0,
+			wantStatus:    domain.GoalExceeded,
+		},
+		"年間の購入額は上限内で順調": {
+			goal:          &domain.Goal{Kind: domain.GoalSpend, Period: domain.GoalYearly, Target: 50000},
+			wantCurrent:   3270,
+			wantRemaining: 46730,
+			wantStatus:    domain.GoalOnTrack,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			got := domain.NewGoalProgress(test.goal, books, now)
+
+			a.Equal(test.wantCurrent, got.Current)
+			a.Equal(test.wantRemaining, got.Remaining)
+			a.Equal(test.wantStatus, got.Status)
+		})
+	}
+}
+
+func TestNewGoalProgressPace(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+	goal := &domain.Goal{Kind: domain.GoalBooks, Period: domain.GoalMonthly, Target: 5}
+
+	got := domain.NewGoalProgress(goal, nil, now)
+
+	a.Equal(25, got.DaysLeft)
+	a.Equal(0.2, got.PacePerDay)
+	a.Equal(1, got.Expected)
+	a.Equal(domain.GoalBehind, got.Status)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// 冪等キーの保持期間の既定値。期間内の同じキーのリクエストには保存したレスポンスを返す。
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	// 処理中のキーの有効期限の既定値。処理中に落ちたサーバーのキーはこの期間の後に再利用できる。
+	DefaultIdempotencyLockTimeout = time.Minute
+)
+
+// Idempotency-Keyヘッダーのキーごとに、リクエストの指紋とレスポンスを保存する
+type IdempotencyRecord struct {
+	bun.BaseModel `bun:"table:idempotency_records,alias:ir"`
+
+	Key         string    `bun:"key,pk"`
+	Fingerprint string    `bun:"fingerprint,notnull"`
+	Status      int       `bun:"status,notnull,default:0"` //0は処理中
+	ContentType string    `bun:"content_type"`
+	Body        []byte    `bun:"body,type:bytea"`
+	ExpiresAt   time.Time `bun:"expires_at,notnull"` //処理中は占有期間、完了後は保持期間
+	CreatedAt   time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+// 処理中（レスポンスが未保存）かどうか
+func (r *IdempotencyRecord) InProgress() bool {
+	return r.Status == 0
+}
+
+// メソッド、パス、ボディからリクエストの指紋を作成。同じキーで異なるリクエストを判別するために使う。
+func IdempotencyFingerprint(method string, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// 保存に使うキー。クライアントの指定したキーを、操作した主体（principal）とルートごとに分ける。
+// 他の利用者、他のルートが同じキーを指定しても衝突せず、他の利用者の保存したレスポンスを返さない。
+func IdempotencyStoreKey(principal string, route string, key string) string {
+	return principal + "\n" + route + "\n" + key
+}
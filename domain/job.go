@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// ジョブを試行する回数の既定値。超えたジョブはdeadになる。
+	DefaultJobMaxAttempts = 5
+
+	// 実行中のジョブを占有する期間の既定値。期間内に完了しないジョブ（実行中にサーバーが停止した場合など）は再実行される。
+	DefaultJobVisibilityTimeout = 5 * time.Minute
+
+	// 完了したジョブの保持期間
+	DefaultJobRetention = 7 * 24 * time.Hour
+
+	// 失敗したジョブを再実行するまでの間隔の初期値と上限（失敗するごとに2倍）
+	JobBackoffBase = 10 * time.Second
+	JobBackoffMax  = time.Hour
+)
+
+// ジョブの状態
+type JobStatus string
+
+const (
+	JobQueued    = JobStatus("queued")    //実行待ち（再試行待ちを含む）
+	JobRunning   = JobStatus("running")   //実行中（LockedUntilまで占有）
+	JobSucceeded = JobStatus("succeeded") //成功
+	JobDead      = JobStatus("dead")      //試行回数の上限に達した
+)
+
+// 非同期、定期的に実行する処理（jobsテーブル）。Kindごとに登録した処理でPayloadを実行する。
+// UniqueKeyを指定した場合は、同じキーのジョブを重ねて登録しない（定期実行の重複防止）。
+type Job struct {
+	bun.BaseModel `bun:"table:jobs,alias:j"`
+
+	ID          int64           `bun:"id,pk,autoincrement"`
+	Kind        string          `bun:"kind,notnull"`
+	Payload     json.RawMessage `bun:"payload,type:jsonb"`
+	UniqueKey   string          `bun:"unique_key,nullzero"`
+	Status      JobStatus       `bun:"status,notnull,default:'queued'"`
+	Attempts    int             `bun:"attempts,notnull,default:0"`
+	MaxAttempts int             `bun:"max_attempts,notnull"`
+	RunAt       time.Time       `bun:"run_at,notnull"`
+	LockedUntil time.Time       `bun:"locked_until,nullzero"`
+	LastError   string          `bun:"last_error,nullzero"`
+	CreatedAt   time.Time       `bun:",nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time       `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// ジョブの失敗を記録し、試行回数の上限に達した場合はdeadにする。Attemptsは取得時に数えている。
+func (j *Job) Fail(now time.Time, err error) {
+	j.LastError = err.Error()
+	j.LockedUntil = time.Time{}
+	j.UpdatedAt = now
+	if j.Attempts >= j.MaxAttempts {
+		j.Status = JobDead
+		return
+	}
+	j.Status = JobQueued
+	j.RunAt = now.Add(JobBackoff(j.Attempts))
+}
+
+// attempts回失敗した後、再実行するまでの間隔（指数バックオフ）
+func JobBackoff(attempts int) time.Duration {
+	d := JobBackoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= JobBackoffMax {
+			return JobBackoffMax
+		}
+	}
+	return d
+}
+
+var ErrInvalidSchedule = NewError(ErrValidation, "定期実行の指定が不正です")
+
+// 占有期間を過ぎて他のサーバーが取得し直したジョブの結果は保存しない
+var ErrJobLeaseLost = NewError(ErrConflict, "ジョブの占有期間が過ぎ、他の実行に取得されています")
+
+// cron形式（分 時 日 月 曜日）の定期実行の予定。@hourly、@daily、@weekly、@monthly、@yearlyも指定できる。
+// 各項目は*、数値、範囲（1-5）、間隔（*/15、1-30/5）、カンマ区切りの組み合わせ。
+type Schedule struct {
+	minute, hour, dom, month, dow [64]bool
+	domAny, dowAny                bool
+}
+
+var scheduleDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+func ParseSchedule(spec string) (*Schedule, error) {
+	if d, ok := scheduleDescriptors[spec]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, ErrInvalidSchedule
+	}
+
+	s := &Schedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	ranges := []struct {
+		set      *[64]bool
+		min, max int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 7}, //0と7は日曜日
+	}
+	for i, r := range ranges {
+		if err := parseScheduleField(fields[i], r.set, r.min, r.max); err != nil {
+			return nil, err
+		}
+	}
+	if s.dow[7] {
+		s.dow[0] = true
+	}
+	return s, nil
+}
+
+func parseScheduleField(field string, set *[64]bool, min, max int) error {
+	for _, part := range strings.Split(field, ",") {
+		expr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return ErrInvalidSchedule
+			}
+			expr, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case expr == "*":
+		case strings.Contains(expr, "-"):
+			bounds := strings.SplitN(expr, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return ErrInvalidSchedule
+			}
+		default:
+			n, err := strconv.Atoi(expr)
+			if err != nil {
+				return ErrInvalidSchedule
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return ErrInvalidSchedule
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+// tより後で、予定に一致する最初の時刻（分単位）。tのタイムゾーンで判定する。
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	//一致する日がない指定（2月30日など）で無限に探さないよう、5年分で打ち切る
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !s.month[t.Month()] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// 日と曜日の両方を指定した場合は、どちらかに一致すればよい（cronと同じ）
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom, dow := s.dom[t.Day()], s.dow[t.Weekday()]
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// 定期実行のジョブのUniqueKey。同じ予定の時刻のジョブは、複数のサーバーで登録しても1件になる。
+func ScheduledJobKey(kind string, at time.Time) string {
+	return fmt.Sprintf("%s@%s", kind, at.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestScheduleNext(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST) //月曜日
+	tests := map[string]struct {
+		spec string
+		want time.Time
+	}{
+		"毎分":        {spec: "* * * * *", want: time.Date(2024, 2, 5, 14, 44, 0, 0, utils.JST)},
+		"15分ごと":     {spec: "*/15 * * * *", want: time.Date(2024, 2, 5, 14, 45, 0, 0, utils.JST)},
+		"毎時":        {spec: "@hourly", want: time.Date(2024, 2, 5, 15, 0, 0, 0, utils.JST)},
+		"毎日3時":      {spec: "0 3 * * *", want: time.Date(2024, 2, 6, 3, 0, 0, 0, utils.JST)},
+		"平日9時30分":   {spec: "30 9 * * 1-5", want: time.Date(2024, 2, 6, 9, 30, 0, 0, utils.JST)},
+		"日曜日（7）":    {spec: "0 0 * * 7", want: time.Date(2024, 2, 11, 0, 0, 0, 0, utils.JST)},
+		"毎月1日":      {spec: "@monthly", want: time.Date(2024, 3, 1, 0, 0, 0, 0, utils.JST)},
+		"2月29日":     {spec: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, utils.JST)},
+		"日と曜日はどちらか": {spec: "0 0 10 * 3", want: time.Date(2024, 2, 7, 0, 0, 0, 0, utils.JST)},
+		"一致しない日":    {spec: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := domain.ParseSchedule(test.spec)
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, s.Next(now))
+		})
+	}
+}
+
+func TestParseScheduleWithError(t *testing.T) {
+	t.Parallel()
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@every"} {
+		_, err := domain.ParseSchedule(spec)
+		assert.ErrorIs(t, err, domain.ErrInvalidSchedule, spec)
+	}
+}
+
+func TestJobFail(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+	j := &domain.Job{Status: domain.JobRunning, Attempts: 1, MaxAttempts: 3, LockedUntil: now.Add(time.Minute)}
+
+	j.Fail(now, errors.New("失敗"))
+	a.Equal(domain.JobQueued, j.Status)
+	a.Equal(now.Add(domain.JobBackoffBase), j.RunAt)
+	a.Zero(j.LockedUntil)
+	a.Equal("失敗", j.LastError)
+
+	j.Attempts = 3
+	j.Fail(now, errors.New("失敗"))
+	a.Equal(domain.JobDead, j.Status)
+	a.Equal(domain.JobBackoffMax, domain.JobBackoff(20))
+}
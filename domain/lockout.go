@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 認証の失敗を数える単位
+type LockoutScope string
+
+const (
+	LockoutAccount = LockoutScope("account") //メールアドレスごとのログインの失敗
+	LockoutIP      = LockoutScope("ip")      //IPアドレスごとのログインの失敗
+	LockoutAPIKey  = LockoutScope("api_key") //IPアドレスごとのAPIキーの認証の失敗
+)
+
+// 失敗の記録の保持期間の既定値（最後の失敗から。ロック中の記録は削除しない）
+const DefaultAuthFailureRetention = 7 * 24 * time.Hour
+
+var ErrInvalidCredentials = NewError(ErrUnauthorized, "メールアドレスまたはパスワードが違います")
+
+// ロックの方針。Threshold回続けて失敗するとBaseの間ロックし、以降は失敗するごとにロックの期間を2倍にする（上限はMax）。
+// 最後の失敗からWindowが過ぎると回数を0に戻す。
+type LockoutPolicy struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+	Window    time.Duration
+}
+
+// 単位ごとのロックの方針。IPアドレスは共有（NATなど）もあるため、アカウントより多くの失敗を許す。
+var LockoutPolicies = map[LockoutScope]LockoutPolicy{
+	LockoutAccount: {Threshold: 5, Base: time.Minute, Max: 24 * time.Hour, Window: 24 * time.Hour},
+	LockoutIP:      {Threshold: 20, Base: time.Minute, Max: time.Hour, Window: time.Hour},
+	LockoutAPIKey:  {Threshold: 10, Base: time.Minute, Max: time.Hour, Window: time.Hour},
+}
+
+// failures回目の失敗でロックする期間。しきい値未満は0
+func (p LockoutPolicy) LockDuration(failures int) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+	d := p.Base
+	for range failures - p.Threshold {
+		if d >= p.Max/2 {
+			return p.Max
+		}
+		d *= 2
+	}
+	return min(d, p.Max)
+}
+
+// 単位（Scope）とキー（メールアドレス、IPアドレス）ごとの認証の失敗の回数とロック。
+// 複数のAPIサーバーで共有するため、DBに保存する。
+type AuthFailure struct {
+	bun.BaseModel `bun:"table:auth_failures,alias:af"`
+
+	Scope        LockoutScope `bun:"scope,pk"`
+	Key          string       `bun:"key,pk"`
+	Failures     int          `bun:"failures,notnull"`
+	LockedUntil  time.Time    `bun:"locked_until,nullzero"`
+	LastFailedAt time.Time    `bun:"last_failed_at,notnull"`
+}
+
+// メールアドレスを失敗の記録のキーにする（大文字と小文字を区別しない）
+func LockoutEmailKey(email Email) string {
+	return strings.ToLower(strings.TrimSpace(string(email)))
+}
+
+// 失敗を1回記録する。ロックした（ロックの期間を延ばした）場合はtrue
+func (f *AuthFailure) Fail(now time.Time, p LockoutPolicy) bool {
+	if now.Sub(f.LastFailedAt) > p.Window {
+		f.Failures = 0
+	}
+	f.Failures++
+	f.LastFailedAt = now
+
+	d := p.LockDuration(f.Failures)
+	if d == 0 {
+		return false
+	}
+	f.LockedUntil = now.Add(d)
+	return true
+}
+
+// ロックの残りの期間。ロックしていない場合は0
+func (f *AuthFailure) RetryAfter(now time.Time) time.Duration {
+	if f.LockedUntil.After(now) {
+		return f.LockedUntil.Sub(now)
+	}
+	return 0
+}
+
+// ロック中のエラー。errors.Is(err, ErrTooManyRequests)で判定でき、Retry-Afterに残りの期間を返す
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return "認証の失敗が続いたため、一時的にロックしています"
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrTooManyRequests
+}
+
+// Retry-Afterの秒数（切り上げ）
+func (e *LockedError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
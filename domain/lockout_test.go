@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestLockDuration(t *testing.T) {
+	t.Parallel()
+	p := domain.LockoutPolicy{Threshold: 5, Base: time.Minute, Max: time.Hour, Window: time.Hour}
+	tests := map[string]struct {
+		failures int
+		want     time.Duration
+	}{
+		"しきい値未満":    {failures: 4, want: 0},
+		"しきい値":      {failures: 5, want: time.Minute},
+		"しきい値の次の失敗": {failures: 6, want: 2 * time.Minute},
+		"3回超過":      {failures: 8, want: 8 * time.Minute},
+		"上限":        {failures: 100, want: time.Hour},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.want, p.LockDuration(test.failures))
+		})
+	}
+}
+
+func TestAuthFailureFail(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	p := domain.LockoutPolicy{Threshold: 3, Base: time.Minute, Max: time.Hour, Window: time.Hour}
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+	f := &domain.AuthFailure{Scope: domain.LockoutAccount, Key: "tanaka@example.com", LastFailedAt: now}
+
+	a.False(f.Fail(now, p))
+	a.False(f.Fail(now, p))
+	a.Equal(time.Duration(0), f.RetryAfter(now))
+
+	a.True(f.Fail(now, p))
+	a.Equal(3, f.Failures)
+	a.Equal(time.Minute, f.RetryAfter(now))
+
+	//ロックの解除後に再び失敗すると期間が2倍になる
+	later := now.Add(time.Minute)
+	a.Equal(time.Duration(0), f.RetryAfter(later))
+	a.True(f.Fail(later, p))
+	a.Equal(2*time.Minute, f.RetryAfter(later))
+
+	//最後の失敗からWindowが過ぎると回数を0に戻す
+	f.LockedUntil = time.Time{}
+	a.False(f.Fail(later.Add(2*time.Hour), p))
+	a.Equal(1, f.Failures)
+}
+
+func TestLockedError(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	var err error = &domain.LockedError{RetryAfter: 90*time.Second + time.Millisecond}
+
+	a.True(errors.Is(err, domain.ErrTooManyRequests))
+	a.Equal(91, err.(*domain.LockedError).Seconds())
+}
+
+func TestLockoutEmailKey(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "tanaka@example.com", domain.LockoutEmailKey(" Tanaka@Example.com"))
+}
+
+func TestPasswordIsHashed(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	hashed, err := new(domain.User).HashedPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.True(hashed.IsHashed())
+	a.False(domain.Password("password").IsHashed())
+	a.False(domain.Password("").IsHashed())
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// レート制限の方針。Windowごとの固定の区間でLimit件までのリクエストを受け付ける
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+var (
+	// 全体の既定の上限（ユーザー、またはIPアドレスごと）
+	DefaultRateLimit = RateLimit{Limit: 180, Window: time.Minute}
+
+	// 外部のAPIを呼び出す書籍の検索の上限
+	DefaultSearchRateLimit = RateLimit{Limit: 20, Window: time.Minute}
+
+	// APIキーごとのすべてのリクエストの上限
+	DefaultAPIKeyRateLimit = RateLimit{Limit: 3000, Window: time.Minute}
+)
+
+var ErrInvalidRateLimit = NewError(ErrValidation, "レート制限は\"件数/期間\"（例.180/1m）で指定ください")
+
+// "件数/期間"（例."180/1m"）の形式のレート制限を解析する
+func ParseRateLimit(s string) (RateLimit, error) {
+	limit, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || n <= 0 {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || d < time.Second {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+	return RateLimit{Limit: n, Window: d}, nil
+}
+
+// RateLimit-Policyヘッダーの値（例."180;w=60"）
+func (rl RateLimit) String() string {
+	return fmt.Sprintf("%d;w=%d", rl.Limit, int(rl.Window.Seconds()))
+}
+
+// nowを含む区間の開始時刻
+func (rl RateLimit) WindowStart(now time.Time) time.Time {
+	return now.Truncate(rl.Window)
+}
+
+// キー（方針の名前と対象）と区間ごとのリクエスト数。複数のAPIサーバーで共有するため、DBに保存する。
+type RateLimitCounter struct {
+	bun.BaseModel `bun:"table:rate_limit_counters,alias:rlc"`
+
+	Key         string    `bun:"key,pk"`
+	WindowStart time.Time `bun:"window_start,pk"`
+	Count       int       `bun:"count,notnull"`
+	ExpiresAt   time.Time `bun:"expires_at,notnull"` //区間の終了時刻
+}
+
+// リクエストを数えた結果
+type RateLimitResult struct {
+	RateLimit
+	Count int
+	Reset time.Duration //区間の終了までの期間
+}
+
+// 区間のcount件目のリクエストの結果
+func NewRateLimitResult(rl RateLimit, count int, now time.Time) *RateLimitResult {
+	return &RateLimitResult{RateLimit: rl, Count: count, Reset: rl.WindowStart(now).Add(rl.Window).Sub(now)}
+}
+
+// 上限以内か
+func (r *RateLimitResult) Allowed() bool {
+	return r.Count <= r.Limit
+}
+
+// 区間内の残りの件数
+func (r *RateLimitResult) Remaining() int {
+	return max(r.Limit-r.Count, 0)
+}
+
+// 区間の終了までの秒数（切り上げ）。RateLimit-Reset、Retry-Afterに使う
+func (r *RateLimitResult) ResetSeconds() int {
+	return int(math.Ceil(r.Reset.Seconds()))
+}
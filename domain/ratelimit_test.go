@@ -0,0 +1,55 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		s       string
+		want    domain.RateLimit
+		wantErr error
+	}{
+		"OK:1分":      {s: "180/1m", want: domain.RateLimit{Limit: 180, Window: time.Minute}},
+		"OK:空白あり":    {s: " 20 / 30s ", want: domain.RateLimit{Limit: 20, Window: 30 * time.Second}},
+		"NG:区切りなし":   {s: "180", wantErr: domain.ErrInvalidRateLimit},
+		"NG:件数が0":    {s: "0/1m", wantErr: domain.ErrInvalidRateLimit},
+		"NG:期間が不正":   {s: "180/1", wantErr: domain.ErrInvalidRateLimit},
+		"NG:期間が1秒未満": {s: "180/10ms", wantErr: domain.ErrInvalidRateLimit},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := domain.ParseRateLimit(test.s)
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestRateLimitResult(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	rl := domain.RateLimit{Limit: 2, Window: time.Minute}
+	now := time.Date(2024, 2, 5, 14, 43, 20, 500_000_000, utils.JST)
+
+	first := domain.NewRateLimitResult(rl, 1, now)
+	over := domain.NewRateLimitResult(rl, 3, now)
+
+	a.Equal(time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST), rl.WindowStart(now))
+	a.True(first.Allowed())
+	a.Equal(1, first.Remaining())
+	a.Equal(40, first.ResetSeconds()) //区間の終了（14:44）まで39.5秒
+	a.False(over.Allowed())
+	a.Equal(0, over.Remaining())
+	a.Equal("2;w=60", rl.String())
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 指定した版がない（他の本の版を含む）
+var ErrRevisionNotFound = NewError(ErrNotFound, "本の変更履歴の版がありません")
+
+// 本の変更履歴の版。本の更新（PUT、部分更新、一括操作の状態の変更、版に戻す操作）ごとに、更新前の本を1件記録する。
+// 誤って上書きした価格、ページ数、購入日などを版から元に戻せるようにする。
+type BookRevision struct {
+	bun.BaseModel `bun:"table:book_revisions,alias:br"`
+
+	ID            int64      `bun:"id,pk,autoincrement"`
+	BookId        int64      `bun:"book_id,notnull"`
+	AuthUserId    string     `bun:"auth_user_id,notnull"`
+	Version       int64      `bun:"version,notnull"` //この版の本のバージョン
+	ISBN10        string     `bun:"isbn_10"`
+	ImageURL      string     `bun:"image_url"`
+	Title         string     `bun:"title"`
+	Author        string     `bun:"author"`
+	Page          int        `bun:"page,type:integer"`
+	Price         int        `bun:"price,type:integer"`
+	Currency      Currency   `bun:"currency,notnull"`
+	BookStatus    BookStatus `bun:"book_status,notnull"`
+	BookCreatedAt time.Time  `bun:"book_created_at,nullzero"`                    //この版の本の購入日（記録前の版はゼロ値）
+	Changes       AuditDiff  `bun:"changes,type:jsonb,notnull"`                  //この版から次の版への変更（列名ごと）
+	CreatedAt     time.Time  `bun:",nullzero,notnull,default:current_timestamp"` //この版が更新された日時
+}
+
+// 更新前の本beforeを版として記録する。更新後の本afterとの間に変更がない場合はnil
+func NewBookRevision(before *Book, after *Book) *BookRevision {
+	changes := diffFields(before.auditFields(), after.auditFields())
+	if len(changes) == 0 {
+		return nil
+	}
+	return &BookRevision{
+		BookId:        before.ID,
+		AuthUserId:    before.AuthUserId,
+		Version:       before.Version,
+		ISBN10:        before.ISBN10,
+		ImageURL:      before.ImageURL,
+		Title:         before.Title,
+		Author:        before.Author,
+		Page:          before.Page,
+		Price:         before.Price,
+		Currency:      before.Currency,
+		BookStatus:    before.BookStatus,
+		BookCreatedAt: before.CreatedAt,
+		Changes:       changes,
+	}
+}
+
+// 版の内容（識別子、バージョン、更新日時以外）を本に戻す。購入日を記録していない版は購入日を変えない
+func (r *BookRevision) Apply(book *Book) {
+	book.ISBN10 = r.ISBN10
+	book.ImageURL = r.ImageURL
+	book.Title = r.Title
+	book.Author = r.Author
+	book.Page = r.Page
+	book.Price = r.Price
+	book.Currency = r.Currency
+	book.BookStatus = r.BookStatus
+	if !r.BookCreatedAt.IsZero() {
+		book.CreatedAt = r.BookCreatedAt
+	}
+}
+
+// この版から次の版への変更で値の変わった列名
+func (r *BookRevision) ChangedColumns() []string {
+	return slices.Sorted(maps.Keys(r.Changes))
+}
@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+)
+
+func TestNewBookRevision(t *testing.T) {
+	//Arrange
+	before := &domain.Book{ID: 1, Title: "容疑者Xの献身", Page: 330, Price: 1640, Currency: domain.JPY, BookStatus: domain.Bought, AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Version: 2, CreatedAt: time.Date(2024, 2, 5, 14, 43, 0, 0, time.UTC)}
+	after := *before
+	after.Price = 16400
+	after.Page = 33
+	after.CreatedAt = before.CreatedAt.AddDate(0, 1, 0)
+	a := assert.New(t)
+
+	//Act
+	got := domain.NewBookRevision(before, &after)
+	unchanged := domain.NewBookRevision(before, before)
+	reverted := after
+	got.Apply(&reverted)
+	//購入日を記録する前の版は購入日を変えない
+	legacy := *got
+	legacy.BookCreatedAt = time.Time{}
+	kept := after
+	legacy.Apply(&kept)
+
+	//Assert
+	a.Nil(unchanged) //変更がない場合は記録しない
+	a.Equal(int64(1), got.BookId)
+	a.Equal(int64(2), got.Version)
+	a.Equal(1640, got.Price)
+	a.Equal([]string{"created_at", "page", "price"}, got.ChangedColumns())
+	a.Equal(domain.AuditChange{Before: 1640, After: 16400}, got.Changes["price"])
+	a.Equal(*before, reverted)
+	a.Equal(after.CreatedAt, kept.CreatedAt)
+}
@@ -8,34 +8,29 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 
 	"github.com/tidwall/gjson"
-	"golang.org/x/text/language"
-	"golang.org/x/text/message"
 )
 
 type BookResult struct {
-	ISBN10   string `json:"isbn10"`
-	ImageURL string `json:"imageURL"`
-	Title    string `json:"title"`
-	Author   string `json:"author"`
-	Page     string `json:"page"`
-	Price    string `json:"price"`
+	ISBN10   string   `json:"isbn10"`
+	ImageURL string   `json:"imageURL"`
+	Title    string   `json:"title"`
+	Author   string   `json:"author"`
+	Page     int      `json:"page"`
+	Price    int      `json:"price"` //通貨の補助単位（例.USDはセント）
+	Currency Currency `json:"currency"`
 }
 
-const keyname = "GOOGLE_BOOKS_API_KEY"
-
-// GoogleBooksAPI（外部サービス）に本の検索を実施
-func SearchForGoogleBooks(query string, apiBaseURL string) ([]*BookResult, error) {
+// GoogleBooksAPI（外部サービス）に本の検索を実施。apikeyが空の場合はキーなしで呼び出す
+func SearchForGoogleBooks(query string, apiBaseURL string, apikey string) ([]*BookResult, error) {
 	//引数についてのvalidation
 	if apiBaseURL == "" {
 		return nil, errors.New("urlを入力ください")
 	}
 
 	//GoogleBooksAPIへのリクエスト処理
-	apikey := os.Getenv(keyname)
 	u, err := url.Parse(apiBaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("urlのパースに失敗:%v", err)
@@ -80,9 +75,6 @@ func ExtractBooksFromJSON(json string) ([]*BookResult, error) {
 	}
 	//jsonの各項目に直接アクセスするためgjsonを利用
 	gj := gjson.Get(json, "items")
-	//3桁カンマ区切りで出力するためのfmt拡張
-	fmtx := message.NewPrinter(language.Japanese)
-
 	books := []*BookResult{}
 	for _, r := range gj.Array() {
 		j := r.String()
@@ -95,15 +87,20 @@ func ExtractBooksFromJSON(json string) ([]*BookResult, error) {
 		isbn10 := gjson.Get(j, `volumeInfo.industryIdentifiers.#(type="ISBN_10").identifier`).String()
 		page := gjson.Get(j, "volumeInfo.pageCount").Int()
 		imageURL := gjson.Get(j, "volumeInfo.imageLinks.thumbnail").String()
-		price := gjson.Get(j, "saleInfo.listPrice.amount").Int()
+		currency, err := ParseCurrency(gjson.Get(j, "saleInfo.listPrice.currencyCode").String())
+		if err != nil {
+			return nil, err
+		}
+		price := PriceFromFloat(gjson.Get(j, "saleInfo.listPrice.amount").Float(), currency)
 
 		b := &BookResult{
 			ISBN10:   isbn10,
 			ImageURL: imageURL,
 			Title:    title,
 			Author:   strings.Join(authors, "、"), //配列から[]を削除する処理
-			Page:     fmtx.Sprint(page),          //数値をカンマ(,)区切りにする処理
-			Price:    fmtx.Sprint(price),         //数値をカンマ(,)区切りにする処理
+			Page:     int(page),
+			Price:    price,
+			Currency: currency,
 		}
 		books = append(books, b)
 	}
@@ -2,6 +2,7 @@ package domain_test
 
 import (
 	"net/url"
+	"os"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -32,7 +33,7 @@ func TestSearchForGoogleBooks(t *testing.T) {
 	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
 
 	//Act
-	results, err := domain.SearchForGoogleBooks(q, testURL)
+	results, err := domain.SearchForGoogleBooks(q, testURL, os.Getenv("GOOGLE_BOOKS_API_KEY"))
 
 	//Assert
 	j := testutils.ConvertToJSON(t, results)
@@ -54,40 +55,45 @@ func TestExtractBooksFromJSON(t *testing.T) {
 			ImageURL: "http://books.google.com/books/content?id=TL3APAAACAAJ&printsec=frontcover&img=1&zoom=1&source=gbs_api",
 			Title:    "容疑者Xの献身",
 			Author:   "東野圭吾",
-			Page:     "0",
-			Price:    "0",
+			Page:     0,
+			Price:    0,
+			Currency: domain.JPY,
 		},
 		{
 			ISBN10:   "",
 			ImageURL: "http://books.google.com/books/content?id=eNjdDwAAQBAJ&printsec=frontcover&img=1&zoom=1&edge=curl&source=gbs_api",
 			Title:    "容疑者Xの献身",
 			Author:   "東野圭吾",
-			Page:     "234",
-			Price:    "770",
+			Page:     234,
+			Price:    770,
+			Currency: domain.JPY,
 		},
 		{
 			ISBN10:   "",
 			ImageURL: "http://books.google.com/books/content?id=hQDeDwAAQBAJ&printsec=frontcover&img=1&zoom=1&edge=curl&source=gbs_api",
 			Title:    "容疑者Xの献身　無料試し読み版",
 			Author:   "東野圭吾",
-			Page:     "49",
-			Price:    "0",
+			Page:     49,
+			Price:    0,
+			Currency: domain.JPY,
 		},
 		{
 			ISBN10:   "416711013X",
 			ImageURL: "http://books.google.com/books/content?id=1LcsAwEACAAJ&printsec=frontcover&img=1&zoom=1&source=gbs_api",
 			Title:    "ガリレオの苦悩",
 			Author:   "東野圭吾",
-			Page:     "0",
-			Price:    "0",
+			Page:     0,
+			Price:    0,
+			Currency: domain.JPY,
 		},
 		{
 			ISBN10:   "4167110083",
 			ImageURL: "http://books.google.com/books/content?id=xdM9ywAACAAJ&printsec=frontcover&img=1&zoom=1&source=gbs_api",
 			Title:    "予知夢",
 			Author:   "東野圭吾",
-			Page:     "0",
-			Price:    "0",
+			Page:     0,
+			Price:    0,
+			Currency: domain.JPY,
 		},
 	}
 
@@ -121,7 +127,7 @@ func BenchmarkSearchForGoogleBooks(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err = domain.SearchForGoogleBooks(q, testURL)
+		_, err = domain.SearchForGoogleBooks(q, testURL, os.Getenv("GOOGLE_BOOKS_API_KEY"))
 		if err != nil {
 			b.Fatal(err)
 		}
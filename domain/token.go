@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// トークンの用途
+type TokenPurpose string
+
+const (
+	TokenPasswordReset     = TokenPurpose("password_reset")
+	TokenEmailVerification = TokenPurpose("email_verification")
+)
+
+const (
+	// パスワード再設定のトークンの有効期間
+	PasswordResetTTL = time.Hour
+	// メールアドレス確認のトークンの有効期間
+	EmailVerificationTTL = 24 * time.Hour
+	// メールアドレスごとにPasswordResetWindowの間に送るパスワード再設定のメールの上限
+	PasswordResetLimit  = 3
+	PasswordResetWindow = time.Hour
+)
+
+var ErrInvalidToken = NewError(ErrValidation, "トークンが不正、期限切れ、または使用済みです")
+var ErrEmailAlreadyVerified = NewError(ErrConflict, "メールアドレスは確認済みです")
+
+// パスワード再設定、メールアドレス確認のトークン。トークン自体は保存せず、ハッシュのみ保存する。
+type UserToken struct {
+	bun.BaseModel `bun:"table:user_tokens,alias:ut"`
+
+	ID         int64        `bun:",pk,autoincrement"`
+	AuthUserId string       `bun:"auth_user_id,notnull"`
+	Purpose    TokenPurpose `bun:"purpose,notnull"`
+	TokenHash  string       `bun:"token_hash,notnull,unique"`
+	Email      Email        `bun:"email,notnull"` //発行時のメールアドレス（変更後は無効）
+	ExpiresAt  time.Time    `bun:"expires_at,notnull"`
+	UsedAt     time.Time    `bun:"used_at,nullzero"`
+	CreatedAt  time.Time    `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// 用途ごとに署名したトークン（"乱数.署名"）を生成し、トークンと保存用のハッシュを返す。
+// 署名により、改ざんしたトークンや他の用途のトークンはDBを参照せずに拒否できる。
+func NewUserToken(secret string, purpose TokenPurpose) (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(b)
+	token = nonce + "." + signUserToken(secret, purpose, nonce)
+	return token, HashUserToken(token), nil
+}
+
+// トークンの署名を検証し、保存用のハッシュを返す。有効期限、使用済みかはDBで確認する
+func VerifyUserToken(secret string, purpose TokenPurpose, token string) (string, error) {
+	nonce, sig, ok := strings.Cut(token, ".")
+	if !ok || secret == "" || nonce == "" || !hmac.Equal([]byte(sig), []byte(signUserToken(secret, purpose, nonce))) {
+		return "", ErrInvalidToken
+	}
+	return HashUserToken(token), nil
+}
+
+func HashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func signUserToken(secret string, purpose TokenPurpose, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(string(purpose) + ":" + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+)
+
+func TestVerifyUserToken(t *testing.T) {
+	t.Parallel()
+	token, hash, err := domain.NewUserToken("secret", domain.TokenPasswordReset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, _, _ := strings.Cut(token, ".")
+
+	tests := map[string]struct {
+		secret  string
+		purpose domain.TokenPurpose
+		token   string
+		want    string
+		wantErr error
+	}{
+		"正常なトークン": {secret: "secret", purpose: domain.TokenPasswordReset, token: token, want: hash},
+		"用途が異なる":  {secret: "secret", purpose: domain.TokenEmailVerification, token: token, wantErr: domain.ErrInvalidToken},
+		"鍵が異なる":   {secret: "other", purpose: domain.TokenPasswordReset, token: token, wantErr: domain.ErrInvalidToken},
+		"鍵が未設定":   {secret: "", purpose: domain.TokenPasswordReset, token: token, wantErr: domain.ErrInvalidToken},
+		"署名を改ざん":  {secret: "secret", purpose: domain.TokenPasswordReset, token: nonce + ".x", wantErr: domain.ErrInvalidToken},
+		"署名なし":    {secret: "secret", purpose: domain.TokenPasswordReset, token: nonce, wantErr: domain.ErrInvalidToken},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := domain.VerifyUserToken(test.secret, test.purpose, test.token)
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestNewUserToken(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	first, firstHash, errFirst := domain.NewUserToken("secret", domain.TokenEmailVerification)
+	second, _, errSecond := domain.NewUserToken("secret", domain.TokenEmailVerification)
+
+	a.Nil(errFirst)
+	a.Nil(errSecond)
+	a.NotEqual(first, second)
+	a.Equal(domain.HashUserToken(first), firstHash)
+	a.NotContains(firstHash, first) //トークン自体は保存しない
+}
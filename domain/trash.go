@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ゴミ箱の保持期間の既定値。期間を過ぎた削除済みデータは完全に削除される。
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// 削除済み（ゴミ箱内）のユーザーと本
+type Trash struct {
+	User      *User //ユーザー自体が削除済みの場合のみ
+	Books     []*Book
+	Retention time.Duration
+}
+
+// 削除日時から完全に削除される予定日時を返す
+func (t *Trash) PurgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.Add(t.Retention)
+}
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/netip"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// Webhookの配信を再試行する回数の上限。超えた配信はデッドレターになる。
+	MaxWebhookAttempts = 8
+
+	// 再試行の間隔の初期値と上限（失敗するごとに2倍）
+	WebhookBackoffBase = 30 * time.Second
+	WebhookBackoffMax  = 6 * time.Hour
+
+	// 配信済みのアウトボックス、配信に成功した記録の保持期間
+	DefaultOutboxRetention = 7 * 24 * time.Hour
+)
+
+// 本の状態が読了になった（Webhookのみに送るイベント）
+const EventBookFinished = EventType("book.finished")
+
+// Webhookで購読できるイベントの種類
+var WebhookEventTypes = []EventType{EventBookCreated, EventBookUpdated, EventBookDeleted, EventBookFinished, EventGoalReached}
+
+var ErrInvalidWebhook = NewError(ErrValidation, "Webhookの登録内容が不正です")
+
+// 占有期間を過ぎて他の配信処理が取得し直した配信の結果は保存しない
+var ErrDeliveryLeaseLost = NewError(ErrConflict, "配信の占有期間が過ぎ、他の配信処理に取得されています")
+
+// 送信先が内部のアドレス（ループバック、プライベート、リンクローカルなど）
+var ErrWebhookAddrNotAllowed = NewError(ErrValidation, "Webhookの送信先に内部のアドレスは指定できません")
+
+// IsPrivate、IsLinkLocalUnicastなどに含まれない内部のアドレスの範囲
+var webhookDeniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     //このネットワーク
+	netip.MustParsePrefix("100.64.0.0/10"), //キャリアグレードNAT（クラウドの内部で使う場合がある）
+}
+
+// Webhookの送信先として許可するアドレスか。ループバック、プライベート、リンクローカル（169.254.169.254のメタデータを含む）、
+// 未指定、マルチキャストのアドレスは許可しない（SSRFを防ぐ）。IPv4射影アドレスはIPv4として判定する。
+func WebhookAddrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, p := range webhookDeniedPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// ユーザーが登録したWebhookの送信先。Eventsが空の場合はすべてのイベントを送る。
+type Webhook struct {
+	bun.BaseModel `bun:"table:webhooks,alias:wh"`
+
+	ID         int64       `bun:"id,pk,autoincrement"`
+	AuthUserId string      `bun:"auth_user_id,notnull"`
+	URL        string      `bun:"url,notnull"`
+	Secret     string      `bun:"secret,notnull"` //署名（HMAC-SHA256）の鍵
+	Events     []EventType `bun:"events,array"`
+	CreatedAt  time.Time   `bun:",nullzero,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time   `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// 購読するイベントの種類が正しいかを検証
+func (w *Webhook) Validate() error {
+	for _, t := range w.Events {
+		if !slices.Contains(WebhookEventTypes, t) {
+			return ErrInvalidWebhook
+		}
+	}
+	return nil
+}
+
+// イベントの種類を購読しているか
+func (w *Webhook) Subscribes(t EventType) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, t)
+}
+
+// 変更と同じトランザクションで登録する配信待ちのイベント（トランザクショナルアウトボックス）。
+// ディスパッチャが購読中のWebhookごとの配信（WebhookDelivery）に振り分け、DispatchedAtを設定する。
+type OutboxMessage struct {
+	bun.BaseModel `bun:"table:outbox_messages,alias:om"`
+
+	ID           int64           `bun:"id,pk,autoincrement"`
+	AuthUserId   string          `bun:"auth_user_id,notnull"`
+	Type         EventType       `bun:"type,notnull"`
+	Payload      json.RawMessage `bun:"payload,type:jsonb,notnull"`
+	CreatedAt    time.Time       `bun:",nullzero,notnull,default:current_timestamp"`
+	DispatchedAt time.Time       `bun:"dispatched_at,nullzero"`
+}
+
+// Webhookで送るボディ。本のイベントは変更後の本（削除は削除時点の本）を含む。
+type WebhookPayload struct {
+	Type       EventType    `json:"type"`
+	AuthUserId string       `json:"authUserId"`
+	Book       *WebhookBook `json:"book,omitempty"`
+	GoalId     int64        `json:"goalId,omitempty"`
+	OccurredAt time.Time    `json:"occurredAt"`
+}
+
+// Webhookのペイロードの本。削除済みの本のみdeletedAtを含む。
+type WebhookBook struct {
+	ID         int64      `json:"id"`
+	ISBN10     string     `json:"isbn10,omitempty"`
+	ImageURL   string     `json:"imageURL,omitempty"`
+	Title      string     `json:"title"`
+	Author     string     `json:"author,omitempty"`
+	Page       int        `json:"page"`
+	Price      int        `json:"price"`
+	Currency   Currency   `json:"currency"`
+	BookStatus BookStatus `json:"bookStatus"`
+	Version    int64      `json:"version"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty"`
+}
+
+func NewWebhookBook(b *Book) *WebhookBook {
+	wb := &WebhookBook{
+		ID:         b.ID,
+		ISBN10:     b.ISBN10,
+		ImageURL:   b.ImageURL,
+		Title:      b.Title,
+		Author:     b.Author,
+		Page:       b.Page,
+		Price:      b.Price,
+		Currency:   b.Currency.OrDefault(),
+		BookStatus: b.BookStatus,
+		Version:    b.Version,
+		CreatedAt:  b.CreatedAt,
+		UpdatedAt:  b.UpdatedAt,
+	}
+	if !b.DeletedAt.IsZero() {
+		wb.DeletedAt = &b.DeletedAt
+	}
+	return wb
+}
+
+// Webhookの配信の状態
+type DeliveryStatus string
+
+const (
+	DeliveryPending   = DeliveryStatus("pending")   //配信待ち（再試行待ちを含む）
+	DeliverySucceeded = DeliveryStatus("succeeded") //配信に成功
+	DeliveryDead      = DeliveryStatus("dead")      //再試行の上限に達した（デッドレター）
+)
+
+// Webhookごとの配信。失敗した場合はNextAttemptAtまで待って再試行する。
+type WebhookDelivery struct {
+	bun.BaseModel `bun:"table:webhook_deliveries,alias:wd"`
+
+	ID            int64           `bun:"id,pk,autoincrement"`
+	WebhookId     int64           `bun:"webhook_id,notnull"`
+	OutboxId      int64           `bun:"outbox_id,notnull"`
+	AuthUserId    string          `bun:"auth_user_id,notnull"`
+	Type          EventType       `bun:"type,notnull"`
+	Payload       json.RawMessage `bun:"payload,type:jsonb,notnull"`
+	Status        DeliveryStatus  `bun:"status,notnull,default:'pending'"`
+	Attempts      int             `bun:"attempts,notnull,default:0"`
+	NextAttemptAt time.Time       `bun:"next_attempt_at,notnull"`
+	LastStatus    int             `bun:"last_status,nullzero"` //最後の試行のHTTPステータス（接続できない場合は0）
+	LastError     string          `bun:"last_error,nullzero"`
+	CreatedAt     time.Time       `bun:",nullzero,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time       `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+
+	Webhook         *Webhook `bun:"-"` //配信先（配信時にリポジトリが設定する）
+	ClaimedAttempts int      `bun:"-"` //取得時の試行回数（結果の保存時に、取得し直されていないかの確認に使う）
+}
+
+// 配信の失敗を記録し、再試行の上限に達した場合はデッドレターにする
+func (d *WebhookDelivery) Fail(now time.Time, status int, msg string) {
+	d.Attempts++
+	d.LastStatus = status
+	d.LastError = msg
+	d.UpdatedAt = now
+	if d.Attempts >= MaxWebhookAttempts {
+		d.Status = DeliveryDead
+		return
+	}
+	d.NextAttemptAt = now.Add(WebhookBackoff(d.Attempts))
+}
+
+// attempts回失敗した後、次に再試行するまでの間隔（指数バックオフ）
+func WebhookBackoff(attempts int) time.Duration {
+	d := WebhookBackoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= WebhookBackoffMax {
+			return WebhookBackoffMax
+		}
+	}
+	return d
+}
+
+// Webhookの署名。"タイムスタンプ.ボディ"のHMAC-SHA256を"sha256=16進数"の形式で返す。
+// 受信側は同じ計算をして一致するか、タイムスタンプが古すぎないかを確認する。
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,113 @@
+package domain_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestSignWebhook(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	ts := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+	body := []byte(`{"type":"book.finished"}`)
+
+	got := domain.SignWebhook("whsec_test", ts, body)
+
+	a.Equal("sha256=59d3fe87880af1b8123a35734d15e29a978dbf63dcc04b786e0b1c08743e4ae2", got)
+	a.NotEqual(got, domain.SignWebhook("whsec_other", ts, body))
+	a.NotEqual(got, domain.SignWebhook("whsec_test", ts.Add(time.Second), body))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		attempts int
+		want     time.Duration
+	}{
+		"1回目の失敗": {attempts: 1, want: 30 * time.Second},
+		"2回目の失敗": {attempts: 2, want: time.Minute},
+		"5回目の失敗": {attempts: 5, want: 8 * time.Minute},
+		"上限":     {attempts: 20, want: domain.WebhookBackoffMax},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.want, domain.WebhookBackoff(test.attempts))
+		})
+	}
+}
+
+func TestWebhookDeliveryFail(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	now := time.Date(2024, 2, 5, 14, 43, 0, 0, utils.JST)
+	d := &domain.WebhookDelivery{Status: domain.DeliveryPending}
+
+	d.Fail(now, 500, "500 Internal Server Error")
+	a.Equal(domain.DeliveryPending, d.Status)
+	a.Equal(1, d.Attempts)
+	a.Equal(500, d.LastStatus)
+	a.Equal(now.Add(30*time.Second), d.NextAttemptAt)
+
+	for d.Attempts < domain.MaxWebhookAttempts-1 {
+		d.Fail(now, 0, "connection refused")
+	}
+	a.Equal(domain.DeliveryPending, d.Status)
+	d.Fail(now, 0, "connection refused")
+	a.Equal(domain.DeliveryDead, d.Status)
+	a.Equal(domain.MaxWebhookAttempts, d.Attempts)
+	a.Equal("connection refused", d.LastError)
+}
+
+func TestWebhookValidate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	all := &domain.Webhook{}
+	a.Nil(all.Validate())
+	a.True(all.Subscribes(domain.EventGoalReached))
+
+	finished := &domain.Webhook{Events: []domain.EventType{domain.EventBookFinished}}
+	a.Nil(finished.Validate())
+	a.True(finished.Subscribes(domain.EventBookFinished))
+	a.False(finished.Subscribes(domain.EventBookUpdated))
+
+	unknown := &domain.Webhook{Events: []domain.EventType{"book.read"}}
+	a.ErrorIs(unknown.Validate(), domain.ErrInvalidWebhook)
+}
+
+func TestWebhookAddrAllowed(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		addr string
+		want bool
+	}{
+		"OK:グローバル":          {addr: "93.184.216.34", want: true},
+		"OK:グローバル（IPv6）":    {addr: "2606:2800:220:1::1", want: true},
+		"NG:ループバック":         {addr: "127.0.0.1", want: false},
+		"NG:ループバック（IPv6）":   {addr: "::1", want: false},
+		"NG:プライベート":         {addr: "10.0.0.1", want: false},
+		"NG:ユニークローカル":       {addr: "fd00:ec2::254", want: false},
+		"NG:メタデータ（リンクローカル）": {addr: "169.254.169.254", want: false},
+		"NG:未指定":            {addr: "0.0.0.0", want: false},
+		"NG:キャリアグレードNAT":    {addr: "100.100.100.200", want: false},
+		"NG:IPv4射影のループバック":  {addr: "::ffff:127.0.0.1", want: false},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := domain.WebhookAddrAllowed(netip.MustParseAddr(test.addr))
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -4,22 +4,16 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"os"
 
+	"github.com/taimats/bhapi/config"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/uptrace/bun/extra/bundebug"
 )
 
-func NewPostgresDsn() (dsn string) {
-	u := os.Getenv("POSTGRES_USER")
-	pd := os.Getenv("POSTGRES_PASSWORD")
-	db := os.Getenv("POSTGRES_DB")
-	h := os.Getenv("POSTGRES_HOST")
-	pt := os.Getenv("POSTGRES_PORT")
-
-	dsn = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", u, pd, h, pt, db)
+func NewPostgresDsn(c config.DB) (dsn string) {
+	dsn = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", c.User, c.Password.Value(), c.Host, c.Port, c.Name)
 
 	return dsn
 }
@@ -34,16 +28,14 @@ func NewBunDB(dsn string) (*bun.DB, error) {
 
 	bundb := bun.NewDB(sqldb, pgdialect.New())
 
-	isVerbose := false
-	env := os.Getenv("Env")
-	if env != "dev" {
-		isVerbose = true
-	}
-	bundb.AddQueryHook(bundebug.NewQueryHook(
-		bundebug.WithVerbose(isVerbose),
-	))
-
 	log.Println("データベースの接続に成功")
 
 	return bundb, nil
 }
+
+// クエリをログに出力する。verboseがfalseの場合は失敗したクエリのみ出力する
+func LogQueries(bundb *bun.DB, verbose bool) {
+	bundb.AddQueryHook(bundebug.NewQueryHook(
+		bundebug.WithVerbose(verbose),
+	))
+}
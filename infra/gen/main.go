@@ -4,19 +4,19 @@ import (
 	"log"
 	"os"
 
+	"github.com/taimats/bhapi/config"
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/infra"
-	"github.com/taimats/bhapi/testutils"
 	"github.com/uptrace/bun"
 )
 
 func main() {
-	err := testutils.DotEnv()
+	cfg, err := config.Load(os.Args[1:])
 	if err != nil {
-		log.Fatalf(".envファイルの取得に失敗:%s", err)
+		log.Fatal(err)
 	}
 
-	dsn := infra.NewPostgresDsn()
+	dsn := infra.NewPostgresDsn(cfg.DB)
 	bundb, err := infra.NewBunDB(dsn)
 	if err != nil {
 		log.Fatal(err)
@@ -31,6 +31,21 @@ func main() {
 		(*domain.User)(nil),
 		(*domain.Book)(nil),
 		(*domain.Chart)(nil),
+		(*domain.ExchangeRate)(nil),
+		(*domain.Goal)(nil),
+		(*domain.IdempotencyRecord)(nil),
+		(*domain.Event)(nil),
+		(*domain.OutboxMessage)(nil),
+		(*domain.Webhook)(nil),
+		(*domain.WebhookDelivery)(nil),
+		(*domain.Job)(nil),
+		(*domain.MailSetting)(nil),
+		(*domain.UserToken)(nil),
+		(*domain.AuthFailure)(nil),
+		(*domain.RateLimitCounter)(nil),
+		(*domain.APIKey)(nil),
+		(*domain.AuditLog)(nil),
+		(*domain.BookRevision)(nil),
 	}
 
 	var data []byte
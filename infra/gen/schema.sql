@@ -1,3 +1,18 @@
-CREATE TABLE "users" ("id" BIGSERIAL NOT NULL, "auth_user_id" VARCHAR NOT NULL, "name" VARCHAR, "email" VARCHAR NOT NULL, "password" VARCHAR, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"), UNIQUE ("auth_user_id"), UNIQUE ("email"));
-CREATE TABLE "books" ("id" BIGSERIAL NOT NULL, "isbn_10" VARCHAR, "image_url" VARCHAR, "title" VARCHAR, "author" VARCHAR, "page" integer, "price" integer, "book_status" VARCHAR NOT NULL, "auth_user_id" VARCHAR NOT NULL, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"));
-CREATE TABLE "charts" ("id" BIGSERIAL NOT NULL, "label" VARCHAR, "year" integer, "month" integer, "data" integer, "auth_user_id" VARCHAR NOT NULL, "book_id" BIGINT NOT NULL, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"));
+CREATE TABLE "users" ("id" BIGSERIAL NOT NULL, "auth_user_id" VARCHAR NOT NULL, "name" VARCHAR, "email" VARCHAR NOT NULL, "password" VARCHAR, "home_currency" VARCHAR NOT NULL DEFAULT 'JPY', "version" BIGINT NOT NULL DEFAULT 1, "email_verified_at" TIMESTAMPTZ, "role" VARCHAR NOT NULL DEFAULT 'user', "disabled_at" TIMESTAMPTZ, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "deleted_at" TIMESTAMPTZ, PRIMARY KEY ("id"), UNIQUE ("auth_user_id"), UNIQUE ("email"));
+CREATE TABLE "books" ("id" BIGSERIAL NOT NULL, "isbn_10" VARCHAR, "image_url" VARCHAR, "title" VARCHAR, "author" VARCHAR, "page" integer, "price" integer, "currency" VARCHAR NOT NULL DEFAULT 'JPY', "book_status" VARCHAR NOT NULL, "auth_user_id" VARCHAR NOT NULL, "version" BIGINT NOT NULL DEFAULT 1, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "deleted_at" TIMESTAMPTZ, PRIMARY KEY ("id"));
+CREATE TABLE "charts" ("id" BIGSERIAL NOT NULL, "label" VARCHAR, "year" integer, "month" integer, "data" integer, "currency" VARCHAR, "auth_user_id" VARCHAR NOT NULL, "book_id" BIGINT NOT NULL, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "deleted_at" TIMESTAMPTZ, PRIMARY KEY ("id"));
+CREATE TABLE "exchange_rates" ("currency" VARCHAR NOT NULL, "rate" DOUBLE PRECISION NOT NULL, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("currency"));
+CREATE TABLE "goals" ("id" BIGSERIAL NOT NULL, "auth_user_id" VARCHAR NOT NULL, "kind" VARCHAR NOT NULL, "period" VARCHAR NOT NULL, "target" integer NOT NULL, "currency" VARCHAR, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"), CONSTRAINT "goals_auth_user_id_kind_period" UNIQUE ("auth_user_id", "kind", "period"));
+CREATE TABLE "idempotency_records" ("key" VARCHAR NOT NULL, "fingerprint" VARCHAR NOT NULL, "status" BIGINT NOT NULL DEFAULT 0, "content_type" VARCHAR, "body" bytea, "expires_at" TIMESTAMPTZ NOT NULL, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("key"));
+CREATE TABLE "events" ("id" BIGSERIAL NOT NULL, "auth_user_id" VARCHAR NOT NULL, "type" VARCHAR NOT NULL, "book_id" BIGINT, "goal_id" BIGINT, "version" BIGINT, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"));
+CREATE TABLE "outbox_messages" ("id" BIGSERIAL NOT NULL, "auth_user_id" VARCHAR NOT NULL, "type" VARCHAR NOT NULL, "payload" jsonb NOT NULL, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "dispatched_at" TIMESTAMPTZ, PRIMARY KEY ("id"));
+CREATE TABLE "webhooks" ("id" BIGSERIAL NOT NULL, "auth_user_id" VARCHAR NOT NULL, "url" VARCHAR NOT NULL, "secret" VARCHAR NOT NULL, "events" VARCHAR[], "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"));
+CREATE TABLE "webhook_deliveries" ("id" BIGSERIAL NOT NULL, "webhook_id" BIGINT NOT NULL, "outbox_id" BIGINT NOT NULL, "auth_user_id" VARCHAR NOT NULL, "type" VARCHAR NOT NULL, "payload" jsonb NOT NULL, "status" VARCHAR NOT NULL DEFAULT 'pending', "attempts" BIGINT NOT NULL DEFAULT 0, "next_attempt_at" TIMESTAMPTZ NOT NULL, "last_status" BIGINT, "last_error" VARCHAR, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"));
+CREATE TABLE "jobs" ("id" BIGSERIAL NOT NULL, "kind" VARCHAR NOT NULL, "payload" jsonb, "unique_key" VARCHAR, "status" VARCHAR NOT NULL DEFAULT 'queued', "attempts" BIGINT NOT NULL DEFAULT 0, "max_attempts" BIGINT NOT NULL, "run_at" TIMESTAMPTZ NOT NULL, "locked_until" TIMESTAMPTZ, "last_error" VARCHAR, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"));
+CREATE TABLE "mail_settings" ("auth_user_id" VARCHAR NOT NULL, "language" VARCHAR NOT NULL DEFAULT 'ja', "digest" BOOLEAN NOT NULL, "updated_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("auth_user_id"));
+CREATE TABLE "user_tokens" ("id" BIGSERIAL NOT NULL, "auth_user_id" VARCHAR NOT NULL, "purpose" VARCHAR NOT NULL, "token_hash" VARCHAR NOT NULL, "email" VARCHAR NOT NULL, "expires_at" TIMESTAMPTZ NOT NULL, "used_at" TIMESTAMPTZ, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"), UNIQUE ("token_hash"));
+CREATE TABLE "auth_failures" ("scope" VARCHAR NOT NULL, "key" VARCHAR NOT NULL, "failures" BIGINT NOT NULL, "locked_until" TIMESTAMPTZ, "last_failed_at" TIMESTAMPTZ NOT NULL, PRIMARY KEY ("scope", "key"));
+CREATE TABLE "rate_limit_counters" ("key" VARCHAR NOT NULL, "window_start" TIMESTAMPTZ NOT NULL, "count" BIGINT NOT NULL, "expires_at" TIMESTAMPTZ NOT NULL, PRIMARY KEY ("key", "window_start"));
+CREATE TABLE "api_keys" ("id" BIGSERIAL NOT NULL, "auth_user_id" VARCHAR NOT NULL, "name" VARCHAR NOT NULL, "prefix" VARCHAR NOT NULL, "key_hash" VARCHAR NOT NULL, "scopes" VARCHAR[] NOT NULL, "expires_at" TIMESTAMPTZ, "last_used_at" TIMESTAMPTZ, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"), UNIQUE ("key_hash"));
+CREATE TABLE "audit_logs" ("id" BIGSERIAL NOT NULL, "actor" VARCHAR, "via" VARCHAR NOT NULL, "api_key_id" BIGINT, "auth_user_id" VARCHAR NOT NULL, "target_type" VARCHAR NOT NULL, "target_id" VARCHAR NOT NULL, "action" VARCHAR NOT NULL, "diff" jsonb NOT NULL, "request_id" VARCHAR, "ip" VARCHAR, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"));
+CREATE TABLE "book_revisions" ("id" BIGSERIAL NOT NULL, "book_id" BIGINT NOT NULL, "auth_user_id" VARCHAR NOT NULL, "version" BIGINT NOT NULL, "isbn_10" VARCHAR, "image_url" VARCHAR, "title" VARCHAR, "author" VARCHAR, "page" integer, "price" integer, "currency" VARCHAR NOT NULL, "book_status" VARCHAR NOT NULL, "book_created_at" TIMESTAMPTZ, "changes" jsonb NOT NULL, "created_at" TIMESTAMPTZ NOT NULL DEFAULT current_timestamp, PRIMARY KEY ("id"));
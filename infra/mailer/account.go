@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+// パスワード再設定、メールアドレス確認のメールに埋め込む値
+type LinkMail struct {
+	Name string
+	URL  string        //トークン付きのリンク
+	TTL  time.Duration //リンクの有効期間
+}
+
+func (l *LinkMail) Hours() int {
+	return int(l.TTL / time.Hour)
+}
+
+// パスワード再設定のメールを言語に合わせて作成する。送信先は呼び出し側で設定する
+func RenderPasswordReset(lang domain.MailLanguage, data *LinkMail) (*Message, error) {
+	lang = language(lang)
+	msg, err := render("password_reset", lang, data)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Subject = "パスワードの再設定"
+	if lang == domain.MailEnglish {
+		msg.Subject = "Reset your password"
+	}
+	return msg, nil
+}
+
+// メールアドレス確認のメールを言語に合わせて作成する。送信先は呼び出し側で設定する
+func RenderEmailVerification(lang domain.MailLanguage, data *LinkMail) (*Message, error) {
+	lang = language(lang)
+	msg, err := render("email_verification", lang, data)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Subject = "メールアドレスの確認"
+	if lang == domain.MailEnglish {
+		msg.Subject = "Confirm your email address"
+	}
+	return msg, nil
+}
+
+// アカウントのロックを知らせるメールに埋め込む値
+type LockedMail struct {
+	Name     string
+	Until    time.Time //ロックの解除日時
+	ResetURL string    //パスワード再設定のページ
+}
+
+// ロックの解除日時（日本時間）
+func (l *LockedMail) UntilText() string {
+	return l.Until.In(utils.JST).Format("2006-01-02 15:04")
+}
+
+// アカウントのロックを知らせるメールを言語に合わせて作成する。送信先は呼び出し側で設定する
+func RenderAccountLocked(lang domain.MailLanguage, data *LockedMail) (*Message, error) {
+	lang = language(lang)
+	msg, err := render("account_locked", lang, data)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Subject = "ログインを一時的に制限しました"
+	if lang == domain.MailEnglish {
+		msg.Subject = "Sign-in temporarily locked"
+	}
+	return msg, nil
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+)
+
+// 月次のまとめのメールに埋め込む値
+type DigestMail struct {
+	Name           string
+	Digest         *domain.Digest
+	UnsubscribeURL string
+}
+
+func (d *DigestMail) MonthName() string {
+	return time.Month(d.Digest.Month).String()
+}
+
+// 月次のまとめのメールを言語に合わせて作成する。送信先と配信停止のヘッダーは呼び出し側で設定する
+func RenderDigest(lang domain.MailLanguage, data *DigestMail) (*Message, error) {
+	lang = language(lang)
+	msg, err := render("digest", lang, data)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Subject = fmt.Sprintf("%d年%d月の読書のまとめ", data.Digest.Year, data.Digest.Month)
+	if lang == domain.MailEnglish {
+		msg.Subject = fmt.Sprintf("Your reading digest for %s %d", data.MonthName(), data.Digest.Year)
+	}
+	return msg, nil
+}
@@ -0,0 +1,194 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/taimats/bhapi/config"
+)
+
+// 送信するメール。HTMLが空の場合はテキストのみで送る
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string //List-Unsubscribeなどの追加のヘッダー
+}
+
+type Mailer interface {
+	Send(ctx context.Context, m *Message) error
+}
+
+// 設定の送信方法（config.Mail.Mailer）から送信方法を選ぶ（smtp、file、それ以外はlog）。
+// file、logはネットワークを使わないため、ローカルの開発やテストで使う。
+func New(c config.Mail) Mailer {
+	switch c.Mailer {
+	case "smtp":
+		return &SMTP{
+			Host:     c.SMTP.Host,
+			Port:     c.SMTP.Port,
+			Username: c.SMTP.Username,
+			Password: c.SMTP.Password.Value(),
+			From:     c.From,
+		}
+	case "file":
+		dir := c.Dir
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "bhapi-mail")
+		}
+		return &File{Dir: dir, From: c.From}
+	default:
+		return &Log{From: c.From}
+	}
+}
+
+// SMTPサーバーで送信する
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTP) Send(ctx context.Context, m *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	from := fromOrDefault(m, s.From)
+	raw, err := Build(m, from, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	if err := smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, from, []string{m.To}, raw); err != nil {
+		return fmt.Errorf("メールの送信に失敗:%w", err)
+	}
+	return nil
+}
+
+// ディレクトリに1通ずつ.emlファイルとして書き出す
+type File struct {
+	Dir  string
+	From string
+}
+
+func (f *File) Send(ctx context.Context, m *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := time.Now()
+	raw, err := Build(m, fromOrDefault(m, f.From), now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return fmt.Errorf("メールの保存先の作成に失敗:%w", err)
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000000000"), randomHex(4))
+	if err := os.WriteFile(filepath.Join(f.Dir, name), raw, 0o644); err != nil {
+		return fmt.Errorf("メールの保存に失敗:%w", err)
+	}
+	return nil
+}
+
+// 送信せずにログへ出力する
+type Log struct {
+	From string
+}
+
+func (l *Log) Send(ctx context.Context, m *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	log.Printf("メール（未送信） from=%s to=%s subject=%q\n%s", fromOrDefault(m, l.From), m.To, m.Subject, m.Text)
+	return nil
+}
+
+// RFC 5322形式のメールを組み立てる。HTMLがある場合はテキストとのmultipart/alternativeにする
+func Build(m *Message, from string, now time.Time) ([]byte, error) {
+	if m.To == "" || from == "" {
+		return nil, fmt.Errorf("メールの送信元、送信先が未設定です")
+	}
+
+	var b bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&b, "%s: %s\r\n", k, v) }
+	header("From", from)
+	header("To", m.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", "<"+randomHex(16)+"@bhapi>")
+	header("MIME-Version", "1.0")
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		header(k, m.Headers[k])
+	}
+
+	if m.HTML == "" {
+		header("Content-Type", `text/plain; charset="utf-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		b.WriteString("\r\n")
+		if err := writeQuoted(&b, m.Text); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
+
+	boundary := "bhapi-" + randomHex(12)
+	header("Content-Type", "multipart/alternative; boundary="+strconv.Quote(boundary))
+	b.WriteString("\r\n")
+	for _, part := range []struct{ typ, body string }{{"text/plain", m.Text}, {"text/html", m.HTML}} {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s; charset=\"utf-8\"\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", part.typ)
+		if err := writeQuoted(&b, part.body); err != nil {
+			return nil, err
+		}
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes(), nil
+}
+
+func writeQuoted(b *bytes.Buffer, s string) error {
+	w := quotedprintable.NewWriter(b)
+	if _, err := w.Write([]byte(s)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func fromOrDefault(m *Message, from string) string {
+	if m.From != "" {
+		return m.From
+	}
+	return from
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
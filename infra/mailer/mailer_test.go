@@ -0,0 +1,180 @@
+package mailer_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/mailer"
+)
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	m := &mailer.Message{
+		To:      "user@example.com",
+		Subject: "2024年1月の読書のまとめ",
+		Text:    "テキスト",
+		HTML:    "<p>HTML</p>",
+		Headers: map[string]string{"List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+	}
+
+	raw, err := mailer.Build(m, "bhapi@example.com", time.Date(2024, 2, 5, 14, 43, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(p) //quoted-printableはNextPartでデコード済み
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[ct] = string(body)
+	}
+
+	a.Equal("bhapi@example.com", msg.Header.Get("From"))
+	a.Equal("user@example.com", msg.Header.Get("To"))
+	a.Equal("2024年1月の読書のまとめ", subject)
+	a.Equal("List-Unsubscribe=One-Click", msg.Header.Get("List-Unsubscribe-Post"))
+	a.Equal("multipart/alternative", mediaType)
+	a.Equal(map[string]string{"text/plain": "テキスト", "text/html": "<p>HTML</p>"}, parts)
+}
+
+func TestBuildWithoutAddress(t *testing.T) {
+	t.Parallel()
+
+	_, err := mailer.Build(&mailer.Message{To: "user@example.com"}, "", time.Now())
+
+	assert.Error(t, err)
+}
+
+func TestFileSend(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	dir := filepath.Join(t.TempDir(), "mail")
+	sut := &mailer.File{Dir: dir, From: "bhapi@example.com"}
+
+	err := sut.Send(context.Background(), &mailer.Message{To: "user@example.com", Subject: "subject", Text: "body"})
+
+	a.Nil(err)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Len(files, 1) {
+		a.True(strings.HasSuffix(files[0].Name(), ".eml"))
+		raw, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.Contains(string(raw), "To: user@example.com\r\n")
+		a.Contains(string(raw), "text/plain")
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	t.Parallel()
+	digest := &domain.Digest{
+		Year:          2024,
+		Month:         1,
+		Bought:        2,
+		Spent:         3500,
+		Read:          1,
+		UnreadVolumes: 3,
+		UnreadCosts:   4500,
+		Total:         &domain.Record{Costs: 9000, Volumes: 6, VolumesRead: 3, PagesRead: 900},
+		Currency:      "JPY",
+	}
+	tests := map[string]struct {
+		lang    domain.MailLanguage
+		subject string
+		text    []string
+	}{
+		"日本語":        {lang: domain.MailJapanese, subject: "2024年1月の読書のまとめ", text: []string{"田中 <太郎> さん", "購入した本: 2冊（3500 JPY）", "積読: 3冊（4500 JPY）"}},
+		"英語":         {lang: domain.MailEnglish, subject: "Your reading digest for January 2024", text: []string{"Hi 田中 <太郎>,", "Books bought: 2 (3500 JPY)", "Unread backlog: 3 (4500 JPY)"}},
+		"未対応の言語は日本語": {lang: "fr", subject: "2024年1月の読書のまとめ", text: []string{"読了した本: 1冊"}},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			a := assert.New(t)
+
+			got, err := mailer.RenderDigest(test.lang, &mailer.DigestMail{Name: "田中 <太郎>", Digest: digest, UnsubscribeURL: "https://example.com/unsubscribe?token=a.b"})
+
+			a.Nil(err)
+			a.Equal(test.subject, got.Subject)
+			for _, s := range test.text {
+				a.Contains(got.Text, s)
+			}
+			a.Contains(got.Text, "https://example.com/unsubscribe?token=a.b")
+			a.Contains(got.HTML, "田中 &lt;太郎&gt;") //HTMLはエスケープする
+			a.Contains(got.HTML, `href="https://example.com/unsubscribe?token=a.b"`)
+		})
+	}
+}
+
+func TestRenderPasswordReset(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	data := &mailer.LinkMail{Name: "田中", URL: "https://example.com/password-reset?token=a.b", TTL: time.Hour}
+
+	ja, errJa := mailer.RenderPasswordReset(domain.MailJapanese, data)
+	en, errEn := mailer.RenderEmailVerification(domain.MailEnglish, &mailer.LinkMail{Name: "Tanaka", URL: "https://example.com/verify-email?token=a.b", TTL: 24 * time.Hour})
+
+	a.Nil(errJa)
+	a.Equal("パスワードの再設定", ja.Subject)
+	a.Contains(ja.Text, "1時間以内")
+	a.Contains(ja.Text, "https://example.com/password-reset?token=a.b")
+	a.Contains(ja.HTML, `href="https://example.com/password-reset?token=a.b"`)
+	a.Nil(errEn)
+	a.Equal("Confirm your email address", en.Subject)
+	a.Contains(en.Text, "within 24 hour(s)")
+	a.Contains(en.HTML, `href="https://example.com/verify-email?token=a.b"`)
+}
+
+func TestRenderAccountLocked(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	data := &mailer.LockedMail{Name: "田中", Until: time.Date(2024, 2, 5, 5, 48, 0, 0, time.UTC), ResetURL: "https://example.com/password-reset"}
+
+	ja, errJa := mailer.RenderAccountLocked(domain.MailJapanese, data)
+	en, errEn := mailer.RenderAccountLocked(domain.MailEnglish, data)
+
+	a.Nil(errJa)
+	a.Equal("ログインを一時的に制限しました", ja.Subject)
+	a.Contains(ja.Text, "2024-02-05 14:48（日本時間）まで")
+	a.Contains(ja.HTML, `href="https://example.com/password-reset"`)
+	a.Nil(errEn)
+	a.Equal("Sign-in temporarily locked", en.Subject)
+	a.Contains(en.Text, "until 2024-02-05 14:48 (JST)")
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+
+	"github.com/taimats/bhapi/domain"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// テンプレートは"名前.言語.txt"、"名前.言語.html"の組で用意する
+var (
+	textTemplates = template.Must(template.ParseFS(templates, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/*.html"))
+)
+
+// 対応していない言語は日本語にする
+func language(lang domain.MailLanguage) domain.MailLanguage {
+	if lang != domain.MailEnglish {
+		return domain.MailJapanese
+	}
+	return lang
+}
+
+// nameのテンプレートからテキストとHTMLの本文を作成する
+func render(name string, lang domain.MailLanguage, data any) (*Message, error) {
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, fmt.Sprintf("%s.%s.txt", name, lang), data); err != nil {
+		return nil, fmt.Errorf("メールの作成に失敗:%w", err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, fmt.Sprintf("%s.%s.html", name, lang), data); err != nil {
+		return nil, fmt.Errorf("メールの作成に失敗:%w", err)
+	}
+	return &Message{Text: text.String(), HTML: html.String()}, nil
+}
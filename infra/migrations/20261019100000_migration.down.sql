@@ -0,0 +1,8 @@
+-- reverse: create "exchange_rates" table
+DROP TABLE "exchange_rates";
+-- reverse: modify "users" table
+ALTER TABLE "users" DROP COLUMN "home_currency";
+-- reverse: modify "charts" table
+ALTER TABLE "charts" DROP COLUMN "currency";
+-- reverse: modify "books" table
+ALTER TABLE "books" DROP COLUMN "currency";
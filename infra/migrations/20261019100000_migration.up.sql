@@ -0,0 +1,8 @@
+-- modify "books" table
+ALTER TABLE "books" ADD COLUMN "currency" character varying NOT NULL DEFAULT 'JPY';
+-- modify "charts" table
+ALTER TABLE "charts" ADD COLUMN "currency" character varying NULL;
+-- modify "users" table
+ALTER TABLE "users" ADD COLUMN "home_currency" character varying NOT NULL DEFAULT 'JPY';
+-- create "exchange_rates" table
+CREATE TABLE "exchange_rates" ("currency" character varying NOT NULL, "rate" double precision NOT NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("currency"));
@@ -0,0 +1,2 @@
+-- create "goals" table
+CREATE TABLE "goals" ("id" bigserial NOT NULL, "auth_user_id" character varying NOT NULL, "kind" character varying NOT NULL, "period" character varying NOT NULL, "target" integer NOT NULL, "currency" character varying NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"), CONSTRAINT "goals_auth_user_id_kind_period" UNIQUE ("auth_user_id", "kind", "period"));
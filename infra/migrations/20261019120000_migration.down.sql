@@ -0,0 +1,6 @@
+-- reverse: modify "users" table
+ALTER TABLE "users" DROP COLUMN "deleted_at";
+-- reverse: modify "charts" table
+ALTER TABLE "charts" DROP COLUMN "deleted_at";
+-- reverse: modify "books" table
+ALTER TABLE "books" DROP COLUMN "deleted_at";
@@ -0,0 +1,6 @@
+-- modify "books" table
+ALTER TABLE "books" ADD COLUMN "deleted_at" timestamptz NULL;
+-- modify "charts" table
+ALTER TABLE "charts" ADD COLUMN "deleted_at" timestamptz NULL;
+-- modify "users" table
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamptz NULL;
@@ -0,0 +1,4 @@
+-- reverse: create index "idempotency_records_expires_at_idx" to table: "idempotency_records"
+DROP INDEX "idempotency_records_expires_at_idx";
+-- reverse: create "idempotency_records" table
+DROP TABLE "idempotency_records";
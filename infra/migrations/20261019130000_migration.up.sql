@@ -0,0 +1,4 @@
+-- create "idempotency_records" table
+CREATE TABLE "idempotency_records" ("key" character varying NOT NULL, "fingerprint" character varying NOT NULL, "status" bigint NOT NULL DEFAULT 0, "content_type" character varying NULL, "body" bytea NULL, "expires_at" timestamptz NOT NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("key"));
+-- create index "idempotency_records_expires_at_idx" to table: "idempotency_records"
+CREATE INDEX "idempotency_records_expires_at_idx" ON "idempotency_records" ("expires_at");
@@ -0,0 +1,4 @@
+-- reverse: modify "users" table
+ALTER TABLE "users" DROP COLUMN "version";
+-- reverse: modify "books" table
+ALTER TABLE "books" DROP COLUMN "version";
@@ -0,0 +1,4 @@
+-- modify "books" table
+ALTER TABLE "books" ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
+-- modify "users" table
+ALTER TABLE "users" ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- reverse: create index "events_created_at_idx" to table: "events"
+DROP INDEX "events_created_at_idx";
+-- reverse: create index "events_auth_user_id_id_idx" to table: "events"
+DROP INDEX "events_auth_user_id_id_idx";
+-- reverse: create "events" table
+DROP TABLE "events";
@@ -0,0 +1,6 @@
+-- create "events" table
+CREATE TABLE "events" ("id" bigserial NOT NULL, "auth_user_id" character varying NOT NULL, "type" character varying NOT NULL, "book_id" bigint NULL, "goal_id" bigint NULL, "version" bigint NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"));
+-- create index "events_auth_user_id_id_idx" to table: "events"
+CREATE INDEX "events_auth_user_id_id_idx" ON "events" ("auth_user_id", "id");
+-- create index "events_created_at_idx" to table: "events"
+CREATE INDEX "events_created_at_idx" ON "events" ("created_at");
@@ -0,0 +1,16 @@
+-- reverse: create index "webhook_deliveries_webhook_id_outbox_id_idx" to table: "webhook_deliveries"
+DROP INDEX "webhook_deliveries_webhook_id_outbox_id_idx";
+-- reverse: create index "webhook_deliveries_auth_user_id_status_idx" to table: "webhook_deliveries"
+DROP INDEX "webhook_deliveries_auth_user_id_status_idx";
+-- reverse: create index "webhook_deliveries_status_next_attempt_at_idx" to table: "webhook_deliveries"
+DROP INDEX "webhook_deliveries_status_next_attempt_at_idx";
+-- reverse: create "webhook_deliveries" table
+DROP TABLE "webhook_deliveries";
+-- reverse: create index "webhooks_auth_user_id_idx" to table: "webhooks"
+DROP INDEX "webhooks_auth_user_id_idx";
+-- reverse: create "webhooks" table
+DROP TABLE "webhooks";
+-- reverse: create index "outbox_messages_undispatched_idx" to table: "outbox_messages"
+DROP INDEX "outbox_messages_undispatched_idx";
+-- reverse: create "outbox_messages" table
+DROP TABLE "outbox_messages";
@@ -0,0 +1,16 @@
+-- create "outbox_messages" table
+CREATE TABLE "outbox_messages" ("id" bigserial NOT NULL, "auth_user_id" character varying NOT NULL, "type" character varying NOT NULL, "payload" jsonb NOT NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, "dispatched_at" timestamptz NULL, PRIMARY KEY ("id"));
+-- create index "outbox_messages_undispatched_idx" to table: "outbox_messages"
+CREATE INDEX "outbox_messages_undispatched_idx" ON "outbox_messages" ("id") WHERE (dispatched_at IS NULL);
+-- create "webhooks" table
+CREATE TABLE "webhooks" ("id" bigserial NOT NULL, "auth_user_id" character varying NOT NULL, "url" character varying NOT NULL, "secret" character varying NOT NULL, "events" character varying[] NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"));
+-- create index "webhooks_auth_user_id_idx" to table: "webhooks"
+CREATE INDEX "webhooks_auth_user_id_idx" ON "webhooks" ("auth_user_id");
+-- create "webhook_deliveries" table
+CREATE TABLE "webhook_deliveries" ("id" bigserial NOT NULL, "webhook_id" bigint NOT NULL, "outbox_id" bigint NOT NULL, "auth_user_id" character varying NOT NULL, "type" character varying NOT NULL, "payload" jsonb NOT NULL, "status" character varying NOT NULL DEFAULT 'pending', "attempts" bigint NOT NULL DEFAULT 0, "next_attempt_at" timestamptz NOT NULL, "last_status" bigint NULL, "last_error" character varying NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"));
+-- create index "webhook_deliveries_status_next_attempt_at_idx" to table: "webhook_deliveries"
+CREATE INDEX "webhook_deliveries_status_next_attempt_at_idx" ON "webhook_deliveries" ("status", "next_attempt_at");
+-- create index "webhook_deliveries_auth_user_id_status_idx" to table: "webhook_deliveries"
+CREATE INDEX "webhook_deliveries_auth_user_id_status_idx" ON "webhook_deliveries" ("auth_user_id", "status");
+-- create index "webhook_deliveries_webhook_id_outbox_id_idx" to table: "webhook_deliveries"
+CREATE UNIQUE INDEX "webhook_deliveries_webhook_id_outbox_id_idx" ON "webhook_deliveries" ("webhook_id", "outbox_id");
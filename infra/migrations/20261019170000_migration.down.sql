@@ -0,0 +1,6 @@
+-- reverse: create index "jobs_status_run_at_idx" to table: "jobs"
+DROP INDEX "jobs_status_run_at_idx";
+-- reverse: create index "jobs_unique_key_idx" to table: "jobs"
+DROP INDEX "jobs_unique_key_idx";
+-- reverse: create "jobs" table
+DROP TABLE "jobs";
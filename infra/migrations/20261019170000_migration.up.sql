@@ -0,0 +1,6 @@
+-- create "jobs" table
+CREATE TABLE "jobs" ("id" bigserial NOT NULL, "kind" character varying NOT NULL, "payload" jsonb NULL, "unique_key" character varying NULL, "status" character varying NOT NULL DEFAULT 'queued', "attempts" bigint NOT NULL DEFAULT 0, "max_attempts" bigint NOT NULL, "run_at" timestamptz NOT NULL, "locked_until" timestamptz NULL, "last_error" character varying NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"));
+-- create index "jobs_unique_key_idx" to table: "jobs"
+CREATE UNIQUE INDEX "jobs_unique_key_idx" ON "jobs" ("unique_key");
+-- create index "jobs_status_run_at_idx" to table: "jobs"
+CREATE INDEX "jobs_status_run_at_idx" ON "jobs" ("status", "run_at");
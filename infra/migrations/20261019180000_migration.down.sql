@@ -0,0 +1,2 @@
+-- reverse: create "mail_settings" table
+DROP TABLE "mail_settings";
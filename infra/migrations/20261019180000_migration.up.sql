@@ -0,0 +1,2 @@
+-- create "mail_settings" table
+CREATE TABLE "mail_settings" ("auth_user_id" character varying NOT NULL, "language" character varying NOT NULL DEFAULT 'ja', "digest" boolean NOT NULL, "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("auth_user_id"));
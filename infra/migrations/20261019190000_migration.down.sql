@@ -0,0 +1,8 @@
+-- reverse: create index "user_tokens_auth_user_id_idx" to table: "user_tokens"
+DROP INDEX "user_tokens_auth_user_id_idx";
+-- reverse: create index "user_tokens_purpose_email_created_at_idx" to table: "user_tokens"
+DROP INDEX "user_tokens_purpose_email_created_at_idx";
+-- reverse: create "user_tokens" table
+DROP TABLE "user_tokens";
+-- reverse: modify "users" table
+ALTER TABLE "users" DROP COLUMN "email_verified_at";
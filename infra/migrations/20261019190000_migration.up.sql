@@ -0,0 +1,8 @@
+-- modify "users" table
+ALTER TABLE "users" ADD COLUMN "email_verified_at" timestamptz NULL;
+-- create "user_tokens" table
+CREATE TABLE "user_tokens" ("id" bigserial NOT NULL, "auth_user_id" character varying NOT NULL, "purpose" character varying NOT NULL, "token_hash" character varying NOT NULL, "email" character varying NOT NULL, "expires_at" timestamptz NOT NULL, "used_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"), CONSTRAINT "user_tokens_token_hash_key" UNIQUE ("token_hash"));
+-- create index "user_tokens_purpose_email_created_at_idx" to table: "user_tokens"
+CREATE INDEX "user_tokens_purpose_email_created_at_idx" ON "user_tokens" ("purpose", "email", "created_at");
+-- create index "user_tokens_auth_user_id_idx" to table: "user_tokens"
+CREATE INDEX "user_tokens_auth_user_id_idx" ON "user_tokens" ("auth_user_id");
@@ -0,0 +1,4 @@
+-- reverse: create index "auth_failures_last_failed_at_idx" to table: "auth_failures"
+DROP INDEX "auth_failures_last_failed_at_idx";
+-- reverse: create "auth_failures" table
+DROP TABLE "auth_failures";
@@ -0,0 +1,4 @@
+-- create "auth_failures" table
+CREATE TABLE "auth_failures" ("scope" character varying NOT NULL, "key" character varying NOT NULL, "failures" bigint NOT NULL, "locked_until" timestamptz NULL, "last_failed_at" timestamptz NOT NULL, PRIMARY KEY ("scope", "key"));
+-- create index "auth_failures_last_failed_at_idx" to table: "auth_failures"
+CREATE INDEX "auth_failures_last_failed_at_idx" ON "auth_failures" ("last_failed_at");
@@ -0,0 +1,4 @@
+-- reverse: create index "rate_limit_counters_expires_at_idx" to table: "rate_limit_counters"
+DROP INDEX "rate_limit_counters_expires_at_idx";
+-- reverse: create "rate_limit_counters" table
+DROP TABLE "rate_limit_counters";
@@ -0,0 +1,4 @@
+-- create "rate_limit_counters" table
+CREATE TABLE "rate_limit_counters" ("key" character varying NOT NULL, "window_start" timestamptz NOT NULL, "count" bigint NOT NULL, "expires_at" timestamptz NOT NULL, PRIMARY KEY ("key", "window_start"));
+-- create index "rate_limit_counters_expires_at_idx" to table: "rate_limit_counters"
+CREATE INDEX "rate_limit_counters_expires_at_idx" ON "rate_limit_counters" ("expires_at");
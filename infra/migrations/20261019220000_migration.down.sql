@@ -0,0 +1,4 @@
+-- reverse: create index "api_keys_auth_user_id_idx" to table: "api_keys"
+DROP INDEX "api_keys_auth_user_id_idx";
+-- reverse: create "api_keys" table
+DROP TABLE "api_keys";
@@ -0,0 +1,4 @@
+-- create "api_keys" table
+CREATE TABLE "api_keys" ("id" bigserial NOT NULL, "auth_user_id" character varying NOT NULL, "name" character varying NOT NULL, "prefix" character varying NOT NULL, "key_hash" character varying NOT NULL, "scopes" character varying[] NOT NULL, "expires_at" timestamptz NULL, "last_used_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"), CONSTRAINT "api_keys_key_hash_key" UNIQUE ("key_hash"));
+-- create index "api_keys_auth_user_id_idx" to table: "api_keys"
+CREATE INDEX "api_keys_auth_user_id_idx" ON "api_keys" ("auth_user_id");
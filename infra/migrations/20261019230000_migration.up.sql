@@ -0,0 +1,2 @@
+-- modify "users" table
+ALTER TABLE "users" ADD COLUMN "role" character varying NOT NULL DEFAULT 'user', ADD COLUMN "disabled_at" timestamptz NULL;
@@ -0,0 +1,9 @@
+-- reverse: reject update and delete on "audit_logs" (append-only)
+DROP TRIGGER "audit_logs_append_only" ON "audit_logs";
+DROP FUNCTION "audit_logs_append_only"();
+-- reverse: create index "audit_logs_target_type_target_id_idx" to table: "audit_logs"
+DROP INDEX "audit_logs_target_type_target_id_idx";
+-- reverse: create index "audit_logs_auth_user_id_id_idx" to table: "audit_logs"
+DROP INDEX "audit_logs_auth_user_id_id_idx";
+-- reverse: create "audit_logs" table
+DROP TABLE "audit_logs";
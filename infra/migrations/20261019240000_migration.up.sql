@@ -0,0 +1,13 @@
+-- create "audit_logs" table
+CREATE TABLE "audit_logs" ("id" bigserial NOT NULL, "actor" character varying NULL, "via" character varying NOT NULL, "api_key_id" bigint NULL, "auth_user_id" character varying NOT NULL, "target_type" character varying NOT NULL, "target_id" character varying NOT NULL, "action" character varying NOT NULL, "diff" jsonb NOT NULL, "request_id" character varying NULL, "ip" character varying NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"));
+-- create index "audit_logs_auth_user_id_id_idx" to table: "audit_logs"
+CREATE INDEX "audit_logs_auth_user_id_id_idx" ON "audit_logs" ("auth_user_id", "id");
+-- create index "audit_logs_target_type_target_id_idx" to table: "audit_logs"
+CREATE INDEX "audit_logs_target_type_target_id_idx" ON "audit_logs" ("target_type", "target_id");
+-- reject update and delete on "audit_logs" (append-only)
+CREATE FUNCTION "audit_logs_append_only"() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$;
+CREATE TRIGGER "audit_logs_append_only" BEFORE UPDATE OR DELETE ON "audit_logs" FOR EACH ROW EXECUTE FUNCTION "audit_logs_append_only"();
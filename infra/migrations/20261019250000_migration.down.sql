@@ -0,0 +1,4 @@
+-- reverse: create index "book_revisions_book_id_id_idx" to table: "book_revisions"
+DROP INDEX "book_revisions_book_id_id_idx";
+-- reverse: create "book_revisions" table
+DROP TABLE "book_revisions";
@@ -0,0 +1,4 @@
+-- create "book_revisions" table
+CREATE TABLE "book_revisions" ("id" bigserial NOT NULL, "book_id" bigint NOT NULL, "auth_user_id" character varying NOT NULL, "version" bigint NOT NULL, "isbn_10" character varying NULL, "image_url" character varying NULL, "title" character varying NULL, "author" character varying NULL, "page" integer NULL, "price" integer NULL, "currency" character varying NOT NULL, "book_status" character varying NOT NULL, "changes" jsonb NOT NULL, "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY ("id"));
+-- create index "book_revisions_book_id_id_idx" to table: "book_revisions"
+CREATE INDEX "book_revisions_book_id_id_idx" ON "book_revisions" ("book_id", "id");
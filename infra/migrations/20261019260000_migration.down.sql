@@ -0,0 +1,2 @@
+-- reverse: modify "book_revisions" table
+ALTER TABLE "book_revisions" DROP COLUMN "book_created_at";
@@ -0,0 +1,2 @@
+-- modify "book_revisions" table
+ALTER TABLE "book_revisions" ADD COLUMN "book_created_at" timestamptz NULL;
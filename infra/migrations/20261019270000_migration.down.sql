@@ -0,0 +1,6 @@
+-- reverse: allow anonymizing "audit_logs" of purged users (only "diff" and "ip", only while bhapi.audit_anonymize is set in the transaction)
+CREATE OR REPLACE FUNCTION "audit_logs_append_only"() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$;
@@ -0,0 +1,10 @@
+-- allow anonymizing "audit_logs" of purged users (only "diff" and "ip", only while bhapi.audit_anonymize is set in the transaction)
+CREATE OR REPLACE FUNCTION "audit_logs_append_only"() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP = 'UPDATE' AND current_setting('bhapi.audit_anonymize', true) = 'on'
+    AND (to_jsonb(NEW) - 'diff' - 'ip') = (to_jsonb(OLD) - 'diff' - 'ip') THEN
+    RETURN NEW;
+  END IF;
+  RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$;
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// 管理API（ユーザーの検索、集計、無効化、強制ログアウト）のための操作
+type Admin struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewAdmin(db *bun.DB, cl utils.Clock) *Admin {
+	return &Admin{db: db, cl: cl}
+}
+
+// LIKEのワイルドカードをエスケープする（検索文字列の%、_を文字として扱う）
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// 条件に一致するユーザー（削除済みを除く）を登録順に返し、条件に一致する全件数とともに返す
+func (ar *Admin) SearchUsers(ctx context.Context, s domain.UserSearch) ([]*domain.User, int, error) {
+	users := []*domain.User{}
+	q := ar.db.NewSelect().
+		Model(&users).
+		Order("id").
+		Limit(s.Limit).
+		Offset(s.Offset)
+	if s.Query != "" {
+		pattern := "%" + likeEscaper.Replace(s.Query) + "%"
+		q = q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("email ILIKE ?", pattern).
+				WhereOr("name ILIKE ?", pattern).
+				WhereOr("auth_user_id ILIKE ?", pattern)
+		})
+	}
+	total, err := q.ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, u := range users {
+		localizeUser(u)
+	}
+	return users, total, nil
+}
+
+// ユーザーの本棚（削除済みの本を除く）を集計する
+func (ar *Admin) FindShelfStats(ctx context.Context, authUserId string) (*domain.ShelfStats, error) {
+	var rows []struct {
+		BookStatus domain.BookStatus `bun:"book_status"`
+		Books      int               `bun:"books"`
+		Pages      int               `bun:"pages"`
+	}
+	err := ar.db.NewSelect().
+		Model((*domain.Book)(nil)).
+		Column("book_status").
+		ColumnExpr("count(*) AS books").
+		ColumnExpr("coalesce(sum(page), 0) AS pages").
+		Where("auth_user_id = ?", authUserId).
+		Group("book_status").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := new(domain.ShelfStats)
+	for _, r := range rows {
+		stats.Books += r.Books
+		stats.Pages += r.Pages
+		switch r.BookStatus {
+		case domain.Bought:
+			stats.Bought = r.Books
+		case domain.Reading:
+			stats.Reading = r.Books
+		case domain.Read:
+			stats.Read = r.Books
+		}
+	}
+	stats.Spend, err = ar.findSpend(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// システム全体（削除済みのユーザー、本を除く）を集計する
+func (ar *Admin) FindSystemStats(ctx context.Context) (*domain.SystemStats, error) {
+	stats := new(domain.SystemStats)
+	err := ar.db.NewSelect().
+		Model((*domain.User)(nil)).
+		ColumnExpr("count(*)").
+		ColumnExpr("count(*) FILTER (WHERE disabled_at IS NOT NULL)").
+		ColumnExpr("count(*) FILTER (WHERE role = ?)", domain.RoleAdmin).
+		Scan(ctx, &stats.Users, &stats.DisabledUsers, &stats.Admins)
+	if err != nil {
+		return nil, err
+	}
+	stats.Books, err = ar.db.NewSelect().Model((*domain.Book)(nil)).Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.Spend, err = ar.findSpend(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// 通貨ごとの購入額の合計。authUserIdが空の場合はすべてのユーザー
+func (ar *Admin) findSpend(ctx context.Context, authUserId string) ([]domain.Spend, error) {
+	spend := []domain.Spend{}
+	q := ar.db.NewSelect().
+		Model((*domain.Book)(nil)).
+		Column("currency").
+		ColumnExpr("coalesce(sum(price), 0) AS amount").
+		Group("currency").
+		Order("currency")
+	if authUserId != "" {
+		q = q.Where("auth_user_id = ?", authUserId)
+	}
+	if err := q.Scan(ctx, &spend); err != nil {
+		return nil, err
+	}
+	return spend, nil
+}
+
+// ユーザーを無効化し、個人用APIキーと未使用のトークンを無効にする（同じトランザクションで行う）。
+// 未登録、削除済みのユーザーはErrNotFound。無効化済みの場合は無効化した日時を変えない。
+func (ar *Admin) DisableUser(ctx context.Context, authUserId string) (*domain.User, *domain.Revocation, error) {
+	now := ar.cl.Now()
+	var user *domain.User
+	var rv *domain.Revocation
+	err := ar.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		user, err = setDisabledAt(ctx, tx, authUserId, now, "coalesce(?TableAlias.disabled_at, ?)", now)
+		if err != nil {
+			return err
+		}
+		rv, err = revokeTokens(ctx, tx, authUserId, now)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	localizeUser(user)
+	return user, rv, nil
+}
+
+// 無効化したユーザーを有効に戻す。未登録、削除済みのユーザーはErrNotFound。
+func (ar *Admin) EnableUser(ctx context.Context, authUserId string) (*domain.User, error) {
+	var user *domain.User
+	err := ar.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		user, err = setDisabledAt(ctx, tx, authUserId, ar.cl.Now(), "NULL")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	localizeUser(user)
+	return user, nil
+}
+
+// ユーザーの無効化した日時をexpr（argsはexprの引数）にし、変更を監査ログに記録して更新後のユーザーを返す。
+// 未登録、削除済みのユーザーはErrNotFound。
+func setDisabledAt(ctx context.Context, tx bun.Tx, authUserId string, now time.Time, expr string, args ...any) (*domain.User, error) {
+	before, err := findUserForAudit(ctx, tx, "auth_user_id", authUserId)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil {
+		return nil, utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+	user := new(domain.User)
+	err = tx.NewUpdate().
+		Model(user).
+		Set("disabled_at = "+expr, args...).
+		Where("id = ?", before.ID).
+		Returning("*").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = insertAuditLogs(ctx, tx, now, domain.NewAuditLog(ctx, domain.AuditUpdate, before, user))
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ユーザーを強制的にログアウトさせる（個人用APIキーを削除し、未使用のトークンを使用済みにする）。
+// 未登録、削除済みのユーザーはErrNotFound。
+func (ar *Admin) RevokeTokens(ctx context.Context, authUserId string) (*domain.Revocation, error) {
+	now := ar.cl.Now()
+	var rv *domain.Revocation
+	err := ar.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		exists, err := tx.NewSelect().
+			Model((*domain.User)(nil)).
+			Where("auth_user_id = ?", authUserId).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return utils.NewErrChains(domain.ErrNotFound, nil)
+		}
+		rv, err = revokeTokens(ctx, tx, authUserId, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+func revokeTokens(ctx context.Context, tx bun.Tx, authUserId string, now time.Time) (*domain.Revocation, error) {
+	rv := new(domain.Revocation)
+	res, err := tx.NewDelete().
+		Model((*domain.APIKey)(nil)).
+		Where("auth_user_id = ?", authUserId).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		rv.APIKeys = int(n)
+	}
+
+	res, err = tx.NewUpdate().
+		Model((*domain.UserToken)(nil)).
+		Set("used_at = ?", now).
+		Where("auth_user_id = ?", authUserId).
+		Where("used_at IS NULL").
+		Where("expires_at > ?", now).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		rv.Tokens = int(n)
+	}
+	return rv, nil
+}
+
+func localizeUser(u *domain.User) {
+	u.CreatedAt = u.CreatedAt.In(utils.JST)
+	u.UpdatedAt = u.UpdatedAt.In(utils.JST)
+	if !u.DisabledAt.IsZero() {
+		u.DisabledAt = u.DisabledAt.In(utils.JST)
+	}
+}
@@ -0,0 +1,123 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestAdminSearchUsersAndStats(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	tanaka := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Name: "tanaka", Email: "tanaka@example.com", Role: domain.RoleAdmin, CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	suzuki := &domain.User{AuthUserId: "9b0b1f4e-4e0c-4b8e-8f3a-1f7f0c2d3e4a", Name: "suzuki_100%", Email: "suzuki@example.com", DisabledAt: cl.Now(), CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	deleted := &domain.User{AuthUserId: "5d1e7a52-1a1c-4c1b-9d6e-2f8f3c4b5a6d", Email: "deleted@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now(), DeletedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, tanaka, suzuki, deleted)
+	books := []*domain.Book{
+		{Title: "本1", Page: 100, Price: 1000, Currency: domain.JPY, BookStatus: domain.Read, AuthUserId: tanaka.AuthUserId},
+		{Title: "本2", Page: 200, Price: 2000, Currency: domain.JPY, BookStatus: domain.Reading, AuthUserId: tanaka.AuthUserId},
+		{Title: "本3", Page: 300, Price: 15, Currency: domain.USD, BookStatus: domain.Bought, AuthUserId: tanaka.AuthUserId},
+		{Title: "本4", Page: 400, Price: 500, Currency: domain.JPY, BookStatus: domain.Read, AuthUserId: suzuki.AuthUserId},
+	}
+	testutils.InsertTestData(ctx, t, bundb, books...)
+	sut := repository.NewAdmin(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	all, total, errAll := sut.SearchUsers(ctx, domain.UserSearch{Limit: 1})
+	found, _, errFound := sut.SearchUsers(ctx, domain.UserSearch{Query: "_100%", Limit: 10})
+	notFound, _, errNotFound := sut.SearchUsers(ctx, domain.UserSearch{Query: "100_", Limit: 10}) //_は任意の1文字として扱わない
+	shelf, errShelf := sut.FindShelfStats(ctx, tanaka.AuthUserId)
+	system, errSystem := sut.FindSystemStats(ctx)
+
+	//Assert
+	a.Nil(errAll)
+	a.Equal(2, total) //削除済みを除く
+	if a.Len(all, 1) {
+		a.Equal(tanaka.AuthUserId, all[0].AuthUserId)
+	}
+	a.Nil(errFound)
+	if a.Len(found, 1) {
+		a.Equal(suzuki.AuthUserId, found[0].AuthUserId)
+	}
+	a.Nil(errNotFound)
+	a.Len(notFound, 0)
+
+	a.Nil(errShelf)
+	a.Equal(&domain.ShelfStats{Books: 3, Bought: 1, Reading: 1, Read: 1, Pages: 600, Spend: []domain.Spend{{Currency: domain.JPY, Amount: 3000}, {Currency: domain.USD, Amount: 15}}}, shelf)
+
+	a.Nil(errSystem)
+	a.Equal(&domain.SystemStats{Users: 2, DisabledUsers: 1, Admins: 1, Books: 4, Spend: []domain.Spend{{Currency: domain.JPY, Amount: 3500}, {Currency: domain.USD, Amount: 15}}}, system)
+}
+
+func TestAdminDisableUser(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+	key := &domain.APIKey{AuthUserId: user.AuthUserId, Name: "script", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}}
+	if _, err := domain.NewAPIKeyToken(key); err != nil {
+		t.Fatal(err)
+	}
+	kr := repository.NewAPIKey(bundb, cl)
+	if err := kr.CreateAPIKey(ctx, key, domain.MaxAPIKeysPerUser); err != nil {
+		t.Fatal(err)
+	}
+	tokens := []*domain.UserToken{
+		{AuthUserId: user.AuthUserId, Purpose: domain.TokenPasswordReset, TokenHash: "unused", Email: user.Email, ExpiresAt: cl.Now().Add(time.Hour)},
+		{AuthUserId: user.AuthUserId, Purpose: domain.TokenPasswordReset, TokenHash: "used", Email: user.Email, ExpiresAt: cl.Now().Add(time.Hour), UsedAt: cl.Now()},
+		{AuthUserId: user.AuthUserId, Purpose: domain.TokenEmailVerification, TokenHash: "expired", Email: user.Email, ExpiresAt: cl.Now().Add(-time.Hour)},
+	}
+	testutils.InsertTestData(ctx, t, bundb, tokens...)
+	sut := repository.NewAdmin(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	disabled, rv, errDisable := sut.DisableUser(ctx, user.AuthUserId)
+	_, errKey := kr.FindAPIKeyByHash(ctx, key.KeyHash)
+	_, again, errAgain := sut.DisableUser(ctx, user.AuthUserId)
+	_, _, errUnknown := sut.DisableUser(ctx, "unknown")
+	enabled, errEnable := sut.EnableUser(ctx, user.AuthUserId)
+	_, errRevokeUnknown := sut.RevokeTokens(ctx, "unknown")
+
+	//Assert
+	a.Nil(errDisable)
+	a.True(disabled.Disabled())
+	a.Equal(&domain.Revocation{APIKeys: 1, Tokens: 1}, rv) //使用済み、期限切れのトークンは数えない
+	a.ErrorIs(errKey, domain.ErrNotFound)
+	a.Nil(errAgain)
+	a.Equal(&domain.Revocation{}, again)
+	a.ErrorIs(errUnknown, domain.ErrNotFound)
+	a.Nil(errEnable)
+	a.False(enabled.Disabled())
+	a.ErrorIs(errRevokeUnknown, domain.ErrNotFound)
+}
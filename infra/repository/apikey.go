@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// 個人用APIキー（api_keysテーブル）を操作する
+type APIKey struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewAPIKey(db *bun.DB, cl utils.Clock) *APIKey {
+	return &APIKey{db: db, cl: cl}
+}
+
+// ユーザーのAPIキーがlimit件未満の場合のみ登録する。上限に達している場合はdomain.ErrAPIKeyLimit。
+// 同じユーザーの同時の発行は直列にする（pg_advisory_xact_lock）。
+func (kr *APIKey) CreateAPIKey(ctx context.Context, k *domain.APIKey, limit int) error {
+	return kr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", "api_keys:"+k.AuthUserId); err != nil {
+			return err
+		}
+		n, err := tx.NewSelect().
+			Model((*domain.APIKey)(nil)).
+			Where("auth_user_id = ?", k.AuthUserId).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+		if n >= limit {
+			return domain.ErrAPIKeyLimit
+		}
+		k.CreatedAt = kr.cl.Now()
+		return tx.NewInsert().Model(k).Returning("id").Scan(ctx, &k.ID)
+	})
+}
+
+// authUserIdのAPIキーを発行順に返す
+func (kr *APIKey) FindAPIKeysByAuthUserID(ctx context.Context, authUserId string) ([]*domain.APIKey, error) {
+	keys := []*domain.APIKey{}
+	err := kr.db.NewSelect().
+		Model(&keys).
+		Where("auth_user_id = ?", authUserId).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		k.CreatedAt = k.CreatedAt.Local().In(utils.JST)
+		if !k.ExpiresAt.IsZero() {
+			k.ExpiresAt = k.ExpiresAt.Local().In(utils.JST)
+		}
+		if !k.LastUsedAt.IsZero() {
+			k.LastUsedAt = k.LastUsedAt.Local().In(utils.JST)
+		}
+	}
+	return keys, nil
+}
+
+// キーのハッシュでAPIキーを返す。未登録、削除済み、無効化したユーザーのキーはErrNotFound。
+func (kr *APIKey) FindAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	k := new(domain.APIKey)
+	err := kr.db.NewSelect().
+		Model(k).
+		Where("key_hash = ?", hash).
+		Where("EXISTS (SELECT 1 FROM users AS u WHERE u.auth_user_id = ?TableAlias.auth_user_id AND u.deleted_at IS NULL AND u.disabled_at IS NULL)").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.NewErrChains(domain.ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// 最終使用日時をnowにする。前回の更新からdomain.APIKeyTouchInterval未満の場合は更新しない（同時のリクエストで書き込みが重ならないようにする）
+func (kr *APIKey) TouchAPIKey(ctx context.Context, id int64, now time.Time) error {
+	_, err := kr.db.NewUpdate().
+		Model((*domain.APIKey)(nil)).
+		Set("last_used_at = ?", now).
+		Where("id = ?", id).
+		Where("last_used_at IS NULL OR last_used_at <= ?", now.Add(-domain.APIKeyTouchInterval)).
+		Exec(ctx)
+	return err
+}
+
+// APIキーを削除する（以降のリクエストは認証に失敗する）。未登録、他のユーザーのキーはErrNotFound。
+func (kr *APIKey) DeleteAPIKey(ctx context.Context, authUserId string, id int64) error {
+	res, err := kr.db.NewDelete().
+		Model((*domain.APIKey)(nil)).
+		Where("id = ?", id).
+		Where("auth_user_id = ?", authUserId).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestAPIKeyCreateWithinLimit(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	sut := repository.NewAPIKey(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	//同時の発行でも上限を超えない
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var created, limited int
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			k := &domain.APIKey{AuthUserId: authUserId, Name: "script", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}}
+			if _, err := domain.NewAPIKeyToken(k); err != nil {
+				t.Error(err)
+				return
+			}
+			err := sut.CreateAPIKey(ctx, k, 3)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				created++
+			case assert.ErrorIs(t, err, domain.ErrAPIKeyLimit):
+				limited++
+			}
+		}()
+	}
+	wg.Wait()
+	got, errFind := sut.FindAPIKeysByAuthUserID(ctx, authUserId)
+
+	//Assert
+	a.Equal(3, created)
+	a.Equal(2, limited)
+	a.Nil(errFind)
+	a.Len(got, 3)
+}
+
+func TestAPIKeyFindByHashAndTouch(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	deleted := &domain.User{AuthUserId: "deleted-user", Email: "deleted@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now(), DeletedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user, deleted)
+	sut := repository.NewAPIKey(bundb, cl)
+	key := &domain.APIKey{AuthUserId: user.AuthUserId, Name: "script", Prefix: "bhk_aaaaaaaa", KeyHash: "hash", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}}
+	orphan := &domain.APIKey{AuthUserId: deleted.AuthUserId, Name: "script", Prefix: "bhk_bbbbbbbb", KeyHash: "deleted", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}}
+	for _, k := range []*domain.APIKey{key, orphan} {
+		if err := sut.CreateAPIKey(ctx, k, domain.MaxAPIKeysPerUser); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := cl.Now()
+	a := assert.New(t)
+
+	//Act
+	got, errFind := sut.FindAPIKeyByHash(ctx, "hash")
+	_, errUnknown := sut.FindAPIKeyByHash(ctx, "unknown")
+	_, errDeleted := sut.FindAPIKeyByHash(ctx, "deleted") //削除済みのユーザーのキーは使えない
+	errTouch := sut.TouchAPIKey(ctx, key.ID, now)
+	errSkip := sut.TouchAPIKey(ctx, key.ID, now.Add(time.Second)) //間隔が短いと更新しない
+	touched, err := sut.FindAPIKeyByHash(ctx, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert
+	a.Nil(errFind)
+	a.Equal(key.ID, got.ID)
+	a.Equal(key.Scopes, got.Scopes)
+	a.True(got.LastUsedAt.IsZero())
+	a.ErrorIs(errUnknown, domain.ErrNotFound)
+	a.ErrorIs(errDeleted, domain.ErrNotFound)
+	a.Nil(errTouch)
+	a.Nil(errSkip)
+	a.True(touched.LastUsedAt.Equal(now))
+}
+
+func TestAPIKeyDelete(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewAPIKey(bundb, cl)
+	key := &domain.APIKey{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Name: "script", Prefix: "bhk_aaaaaaaa", KeyHash: "hash", Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}}
+	if err := sut.CreateAPIKey(ctx, key, domain.MaxAPIKeysPerUser); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act
+	errOther := sut.DeleteAPIKey(ctx, "other", key.ID) //他のユーザーのキーは削除できない
+	errDelete := sut.DeleteAPIKey(ctx, key.AuthUserId, key.ID)
+	errAgain := sut.DeleteAPIKey(ctx, key.AuthUserId, key.ID)
+	got, errFind := sut.FindAPIKeysByAuthUserID(ctx, key.AuthUserId)
+
+	//Assert
+	a.ErrorIs(errOther, domain.ErrNotFound)
+	a.Nil(errDelete)
+	a.ErrorIs(errAgain, domain.ErrNotFound)
+	a.Nil(errFind)
+	a.Empty(got)
+}
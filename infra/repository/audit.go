@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// 監査ログ（audit_logsテーブル）を検索する。追記は変更と同じトランザクションでinsertAuditLogsで行う
+type Audit struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewAudit(db *bun.DB, cl utils.Clock) *Audit {
+	return &Audit{db: db, cl: cl}
+}
+
+// 条件に一致する監査ログを新しい順に返す
+func (ar *Audit) FindAuditLogs(ctx context.Context, q domain.AuditQuery) ([]*domain.AuditLog, error) {
+	logs := []*domain.AuditLog{}
+	sq := ar.db.NewSelect().
+		Model(&logs).
+		Order("id DESC").
+		Limit(q.Limit)
+	if q.AuthUserId != "" {
+		sq = sq.Where("auth_user_id = ?", q.AuthUserId)
+	}
+	if q.Actor != "" {
+		sq = sq.Where("actor = ?", q.Actor)
+	}
+	if q.TargetType != "" {
+		sq = sq.Where("target_type = ?", q.TargetType)
+	}
+	if q.TargetId != "" {
+		sq = sq.Where("target_id = ?", q.TargetId)
+	}
+	if q.Action != "" {
+		sq = sq.Where("action = ?", q.Action)
+	}
+	if q.BeforeId > 0 {
+		sq = sq.Where("id < ?", q.BeforeId)
+	}
+	if err := sq.Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, l := range logs {
+		l.CreatedAt = l.CreatedAt.In(utils.JST)
+	}
+	return logs, nil
+}
+
+// 監査ログを追記する。変更と同じトランザクション（db）で呼び出す
+func insertAuditLogs(ctx context.Context, db bun.IDB, now time.Time, logs ...*domain.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	for _, l := range logs {
+		l.CreatedAt = now
+	}
+	_, err := db.NewInsert().Model(&logs).Exec(ctx)
+	return err
+}
+
+// 変更前後の本（削除済みを除く）を返し、変更が終わるまで行をロックする。ない場合はnil
+func findBookForAudit(ctx context.Context, db bun.IDB, bookId int64, authUserId string) (*domain.Book, error) {
+	book := new(domain.Book)
+	err := db.NewSelect().
+		Model(book).
+		Where("id = ?", bookId).
+		Where("auth_user_id = ?", authUserId).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// 変更前後のユーザー（削除済みを除く）を返し、変更が終わるまで行をロックする。ない場合はnil
+func findUserForAudit(ctx context.Context, db bun.IDB, column string, value any) (*domain.User, error) {
+	user := new(domain.User)
+	err := db.NewSelect().
+		Model(user).
+		Where("?TableAlias.? = ?", bun.Ident(column), value).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,79 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestAuditLogs(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	ctx = domain.WithAuditMeta(ctx, domain.AuditMeta{Actor: authUserId, Via: domain.AuditViaApp, RequestId: "req-1", IP: "192.0.2.1"})
+	sr := repository.NewShelf(bundb, cl)
+	sut := repository.NewAudit(bundb, cl)
+	book := &domain.Book{Title: "容疑者Xの献身", Page: 247, Price: 980, Currency: domain.JPY, BookStatus: domain.Bought, AuthUserId: authUserId}
+	a := assert.New(t)
+
+	//Act
+	errCreate := sr.CreateBookWithCharts(ctx, book, nil)
+	book.BookStatus = domain.Reading
+	errUpdate := sr.UpdateBookWithCharts(ctx, book)
+	errDelete := sr.DleteBooksWithCharts(ctx, authUserId, []*domain.Book{{ID: book.ID}})
+	//変更を取り消した場合は監査ログも残らない
+	errRollback := sr.RunInTx(ctx, func(ctx context.Context, st *repository.ShelfTx) error {
+		if err := st.CreateBookWithCharts(ctx, &domain.Book{Title: "取り消す本", Currency: domain.JPY, BookStatus: domain.Bought, AuthUserId: authUserId}, nil); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	logs, errFind := sut.FindAuditLogs(ctx, domain.AuditQuery{AuthUserId: authUserId, Limit: 10})
+	updates, _ := sut.FindAuditLogs(ctx, domain.AuditQuery{Action: domain.AuditUpdate, Limit: 10})
+	older, _ := sut.FindAuditLogs(ctx, domain.AuditQuery{AuthUserId: authUserId, BeforeId: logs[len(logs)-1].ID + 1, Limit: 10})
+	//監査ログは更新、削除できない
+	_, errModify := bundb.NewUpdate().Model((*domain.AuditLog)(nil)).Set("action = ?", domain.AuditCreate).Where("id = ?", logs[0].ID).Exec(ctx)
+	_, errRemove := bundb.NewDelete().Model((*domain.AuditLog)(nil)).Where("id = ?", logs[0].ID).Exec(ctx)
+
+	//Assert
+	a.Nil(errCreate)
+	a.Nil(errUpdate)
+	a.Nil(errDelete)
+	a.EqualError(errRollback, "rollback")
+	a.Nil(errFind)
+	if a.Len(logs, 3) { //新しい順
+		a.Equal(domain.AuditDelete, logs[0].Action)
+		a.Equal(domain.AuditUpdate, logs[1].Action)
+		a.Equal(domain.AuditCreate, logs[2].Action)
+		a.Equal(domain.AuditDiff{"book_status": {Before: string(domain.Bought), After: string(domain.Reading)}}, logs[1].Diff)
+		a.Equal(strconv.FormatInt(book.ID, 10), logs[1].TargetId)
+		a.Equal(domain.AuditTargetBook, logs[1].TargetType)
+		a.Equal(authUserId, logs[1].Actor)
+		a.Equal("req-1", logs[1].RequestId)
+		a.Equal("192.0.2.1", logs[1].IP)
+	}
+	a.Len(updates, 1)
+	a.Len(older, 1)
+	a.NotNil(errModify)
+	a.NotNil(errRemove)
+}
@@ -17,16 +17,37 @@ func NewChart(db *bun.DB, cl utils.Clock) *Chart {
 	return &Chart{db: db, cl: cl}
 }
 
+// 購入額は通貨ごとに集計して返すため、換算はcontroller側で行う
 func (cr *Chart) FindChartsByAuthUserId(ctx context.Context, authUserId string) ([]*domain.Chart, error) {
 	var charts []*domain.Chart
 
+	err := cr.db.NewSelect().
+		Model(&charts).
+		Column("label", "year", "month", "currency").
+		ColumnExpr("SUM(data) AS data").
+		Where("auth_user_id = ?", authUserId).
+		Group("label", "year", "month", "currency").
+		Order("label DESC", "year DESC", "month ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return charts, nil
+}
+
+// 月ごとの購入冊数を古い順に返す。積読の推移の集計に使用。
+func (cr *Chart) FindMonthlyVolumesByAuthUserId(ctx context.Context, authUserId string) ([]*domain.Chart, error) {
+	var charts []*domain.Chart
+
 	err := cr.db.NewSelect().
 		Model(&charts).
 		Column("label", "year", "month").
 		ColumnExpr("SUM(data) AS data").
 		Where("auth_user_id = ?", authUserId).
+		Where("label = ?", domain.ChartVolumes).
 		Group("label", "year", "month").
-		Order("label DESC", "year DESC", "month ASC").
+		Order("year ASC", "month ASC").
 		Scan(ctx)
 	if err != nil {
 		return nil, err
@@ -128,3 +128,92 @@ func TestFindChartsByAuthUserId(t *testing.T) {
 	a.Nil(err)
 	a.Equal(want, got)
 }
+
+func TestFindMonthlyVolumesByAuthUserId(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	charts := []*domain.Chart{
+		{
+			ID:         int64(1),
+			Label:      domain.ChartVolumes,
+			Year:       2025,
+			Month:      2,
+			Data:       1,
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			BookId:     int64(1),
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			ID:         int64(2),
+			Label:      domain.ChartPages,
+			Year:       2025,
+			Month:      2,
+			Data:       247,
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			BookId:     int64(1),
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			ID:         int64(3),
+			Label:      domain.ChartVolumes,
+			Year:       2025,
+			Month:      2,
+			Data:       1,
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			BookId:     int64(2),
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			ID:         int64(4),
+			Label:      domain.ChartVolumes,
+			Year:       2024,
+			Month:      12,
+			Data:       1,
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			BookId:     int64(3),
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	want := []*domain.Chart{
+		{
+			Label: domain.ChartVolumes,
+			Year:  2024,
+			Month: 12,
+			Data:  1,
+		},
+		{
+			Label: domain.ChartVolumes,
+			Year:  2025,
+			Month: 2,
+			Data:  2,
+		},
+	}
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	sut := repository.NewChart(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	got, err := sut.FindMonthlyVolumesByAuthUserId(ctx, authUserId)
+
+	//Assert
+	a.Nil(err)
+	a.Equal(want, got)
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// 変更イベントを通知するPostgresのチャネル（LISTEN/NOTIFY）。ペイロードはauthUserId。
+const EventChannel = "bhapi_events"
+
+// 変更イベントのログ（eventsテーブル）とLISTEN/NOTIFYを操作する
+type Event struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewEvent(db *bun.DB, cl utils.Clock) *Event {
+	return &Event{db: db, cl: cl}
+}
+
+// イベントを登録する（目標の達成など、本の変更と別に記録するイベント用）
+func (er *Event) CreateEvents(ctx context.Context, events ...*domain.Event) error {
+	return er.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return insertEvents(ctx, tx, er.cl.Now(), events...)
+	})
+}
+
+// authUserIdのイベントのうち、afterIdより後のものをidの順に最大limit件返す
+func (er *Event) FindEventsAfter(ctx context.Context, authUserId string, afterId int64, limit int) ([]*domain.Event, error) {
+	events := []*domain.Event{}
+	err := er.db.NewSelect().
+		Model(&events).
+		Where("auth_user_id = ?", authUserId).
+		Where("id > ?", afterId).
+		Order("id").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ev := range events {
+		ev.CreatedAt = ev.CreatedAt.Local().In(utils.JST)
+	}
+	return events, nil
+}
+
+// authUserIdの最新のイベントのidを返す。イベントがない場合は0。
+func (er *Event) LatestEventID(ctx context.Context, authUserId string) (int64, error) {
+	var id int64
+	err := er.db.NewSelect().
+		Model((*domain.Event)(nil)).
+		ColumnExpr("COALESCE(MAX(id), 0)").
+		Where("auth_user_id = ?", authUserId).
+		Scan(ctx, &id)
+	return id, err
+}
+
+// since以降に目標（goalId）の達成イベントを記録済みか
+func (er *Event) ExistsGoalReached(ctx context.Context, authUserId string, goalId int64, since time.Time) (bool, error) {
+	return er.db.NewSelect().
+		Model((*domain.Event)(nil)).
+		Where("auth_user_id = ?", authUserId).
+		Where("type = ?", domain.EventGoalReached).
+		Where("goal_id = ?", goalId).
+		Where("created_at >= ?", since).
+		Exists(ctx)
+}
+
+// beforeより前のイベントを削除し、削除した件数を返す
+func (er *Event) PurgeEvents(ctx context.Context, before time.Time) (int64, error) {
+	res, err := er.db.NewDelete().
+		Model((*domain.Event)(nil)).
+		Where("created_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// EventChannelを購読し、通知ごとにfnをauthUserIdで呼び出す。ctxが終了するまで戻らない。
+// 接続が切れた場合は自動で再接続するが、その間の通知は失われるため、呼び出し元はログを定期的に確認すること。
+func (er *Event) Listen(ctx context.Context, fn func(authUserId string)) error {
+	ln := pgdriver.NewListener(er.db)
+	defer ln.Close()
+
+	if err := ln.Listen(ctx, EventChannel); err != nil {
+		return err
+	}
+	ch := ln.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			fn(n.Payload)
+		}
+	}
+}
+
+// イベントを登録し、ユーザーごとにEventChannelへ通知する。Webhookのアウトボックスにも同じイベントを登録する。
+// 変更と同じトランザクションで呼び出すことで、通知はコミット時に届き、ロールバックした変更は通知されない。
+func insertEvents(ctx context.Context, db bun.IDB, now time.Time, events ...*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	users := []string{}
+	for _, ev := range events {
+		ev.CreatedAt = now
+		if !slices.Contains(users, ev.AuthUserId) {
+			users = append(users, ev.AuthUserId)
+		}
+	}
+
+	//同じユーザーのイベントはコミットまでロックし、idの順にコミットされるようにする（Last-Event-IDより前のイベントが後から増えないように）。
+	//ロックの順序を揃えてデッドロックを防ぐ
+	slices.Sort(users)
+	for _, u := range users {
+		if _, err := db.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", EventChannel+":"+u); err != nil {
+			return err
+		}
+	}
+
+	_, err := db.NewInsert().Model(&events).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	err = insertOutbox(ctx, db, now, events...)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if _, err := db.ExecContext(ctx, "SELECT pg_notify(?, ?)", EventChannel, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
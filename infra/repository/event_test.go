@@ -0,0 +1,160 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+func TestEventsFromShelf(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	book := &domain.Book{
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       247,
+		Price:      980,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+	}
+	sr := repository.NewShelf(bundb, cl)
+	sut := repository.NewEvent(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	if err := sr.CreateBookWithCharts(ctx, book, domain.NewChartsFromBook(book)); err != nil {
+		t.Fatal(err)
+	}
+	book.BookStatus = domain.Read
+	if err := sr.UpdateBookWithCharts(ctx, book); err != nil {
+		t.Fatal(err)
+	}
+	if err := sr.DleteBooksWithCharts(ctx, authUserId, []*domain.Book{{ID: book.ID}}); err != nil {
+		t.Fatal(err)
+	}
+	//失敗した変更（ロールバック）のイベントは残らない
+	errStale := sr.UpdateBookWithCharts(ctx, &domain.Book{ID: book.ID, AuthUserId: authUserId, BookStatus: domain.Read, Version: 1})
+	all, err := sut.FindEventsAfter(ctx, authUserId, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := sut.FindEventsAfter(ctx, authUserId, all[0].ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err := sut.LatestEventID(ctx, authUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert
+	a.NotNil(errStale)
+	a.Len(all, 3)
+	want := []domain.EventType{domain.EventBookCreated, domain.EventBookUpdated, domain.EventBookDeleted}
+	for i, ev := range all {
+		a.Equal(want[i], ev.Type)
+		a.Equal(book.ID, ev.BookId)
+		a.Equal(authUserId, ev.AuthUserId)
+	}
+	a.Equal(int64(1), all[0].Version)
+	a.Equal(int64(2), all[1].Version)
+	a.Zero(all[2].Version)
+	a.Len(after, 2)
+	a.Equal(all[2].ID, latest)
+}
+
+func TestEventListen(t *testing.T) {
+	//Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	sut := repository.NewEvent(bundb, cl)
+	got := make(chan string, 1)
+	listening, stop := context.WithCancel(ctx)
+	defer stop()
+	go func() {
+		if err := sut.Listen(listening, func(u string) { got <- u }); err != nil {
+			log.Println(err)
+		}
+	}()
+	//LISTENの開始を待つ
+	time.Sleep(500 * time.Millisecond)
+
+	//Act
+	err = sut.CreateEvents(ctx, &domain.Event{AuthUserId: authUserId, Type: domain.EventGoalReached, GoalId: 1})
+
+	//Assert
+	assert.Nil(t, err)
+	select {
+	case u := <-got:
+		assert.Equal(t, authUserId, u)
+	case <-ctx.Done():
+		t.Fatal("通知が届きません")
+	}
+}
+
+func TestPurgeEvents(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	sut := repository.NewEvent(bundb, cl)
+	if err := sut.CreateEvents(ctx, &domain.Event{AuthUserId: authUserId, Type: domain.EventGoalReached, GoalId: 1}); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act
+	notYet, errNotYet := sut.PurgeEvents(ctx, cl.Now())
+	reached, err := sut.ExistsGoalReached(ctx, authUserId, 1, cl.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	purged, errPurged := sut.PurgeEvents(ctx, cl.Now().Add(time.Second))
+
+	//Assert
+	a.Nil(errNotYet)
+	a.Zero(notYet)
+	a.True(reached)
+	a.Nil(errPurged)
+	a.Equal(int64(1), purged)
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+type Goal struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewGoal(db *bun.DB, cl utils.Clock) *Goal {
+	return &Goal{db: db, cl: cl}
+}
+
+// authUserIdをもとに目標の一覧を返す
+func (gr *Goal) FindGoalsByAuthUserId(ctx context.Context, authUserId string) ([]*domain.Goal, error) {
+	goals := []*domain.Goal{}
+
+	err := gr.db.NewSelect().
+		Model(&goals).
+		Where("auth_user_id = ?", authUserId).
+		Order("period DESC", "kind ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range goals {
+		g.CreatedAt = g.CreatedAt.In(utils.JST)
+		g.UpdatedAt = g.UpdatedAt.In(utils.JST)
+	}
+
+	return goals, nil
+}
+
+// 目標を登録。同じ種類と期間の目標がすでにあれば目標値を上書きする。
+func (gr *Goal) UpsertGoal(ctx context.Context, goal *domain.Goal) error {
+	now := gr.cl.Now()
+	goal.CreatedAt = now
+	goal.UpdatedAt = now
+
+	tx, err := gr.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	err = tx.NewInsert().
+		Model(goal).
+		On("CONFLICT (auth_user_id, kind, period) DO UPDATE").
+		Set("target = EXCLUDED.target").
+		Set("currency = EXCLUDED.currency").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("id").
+		Scan(ctx, &goal.ID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return nil
+}
+
+// ユーザーの目標を削除
+func (gr *Goal) DeleteGoal(ctx context.Context, authUserId string, goalId int64) error {
+	tx, err := gr.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	res, err := tx.NewDelete().
+		Model((*domain.Goal)(nil)).
+		Where("id = ?", goalId).
+		Where("auth_user_id = ?", authUserId).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return nil
+}
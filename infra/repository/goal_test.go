@@ -0,0 +1,156 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestFindGoalsByAuthUserId(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	goals := []*domain.Goal{
+		{
+			ID:         1,
+			AuthUserId: authUserId,
+			Kind:       domain.GoalSpend,
+			Period:     domain.GoalMonthly,
+			Target:     5000,
+			Currency:   domain.JPY,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			ID:         2,
+			AuthUserId: authUserId,
+			Kind:       domain.GoalBooks,
+			Period:     domain.GoalYearly,
+			Target:     50,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			ID:         3,
+			AuthUserId: "7d7276bb-9a02-45ba-9de7-c0cc3f0c6058",
+			Kind:       domain.GoalBooks,
+			Period:     domain.GoalYearly,
+			Target:     10,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, goals...)
+	sut := repository.NewGoal(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	got, err := sut.FindGoalsByAuthUserId(ctx, authUserId)
+
+	//Assert
+	a.Nil(err)
+	a.Equal([]*domain.Goal{goals[0], goals[1]}, got)
+}
+
+func TestUpsertGoal(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	goal := &domain.Goal{
+		AuthUserId: authUserId,
+		Kind:       domain.GoalPages,
+		Period:     domain.GoalYearly,
+		Target:     10000,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, goal)
+
+	updated := &domain.Goal{
+		AuthUserId: authUserId,
+		Kind:       domain.GoalPages,
+		Period:     domain.GoalYearly,
+		Target:     12000,
+	}
+	sut := repository.NewGoal(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	err = sut.UpsertGoal(ctx, updated)
+
+	//Assert
+	a.Nil(err)
+	a.Equal(goal.ID, updated.ID)
+	got, err := sut.FindGoalsByAuthUserId(ctx, authUserId)
+	a.Nil(err)
+	a.Len(got, 1)
+	a.Equal(12000, got[0].Target)
+}
+
+func TestDeleteGoal(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	goal := &domain.Goal{
+		ID:         1,
+		AuthUserId: authUserId,
+		Kind:       domain.GoalBooks,
+		Period:     domain.GoalMonthly,
+		Target:     4,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, goal)
+	sut := repository.NewGoal(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	err = sut.DeleteGoal(ctx, authUserId, goal.ID)
+	errNotFound := sut.DeleteGoal(ctx, authUserId, goal.ID)
+
+	//Assert
+	a.Nil(err)
+	a.ErrorIs(errNotFound, domain.ErrNotFound)
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// Idempotency-Keyのキーとレスポンスを保存するidempotency_recordsテーブルを操作する
+type Idempotency struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewIdempotency(db *bun.DB, cl utils.Clock) *Idempotency {
+	return &Idempotency{db: db, cl: cl}
+}
+
+// キーを処理中として登録する。登録できた場合はnilを返す。
+// 同じキーがすでにある場合は登録せず、そのレコードを返す（同時に届いた重複リクエストはどちらか一方のみ登録できる）。
+// 有効期限を過ぎたキーは削除してから登録する。
+func (ir *Idempotency) Claim(ctx context.Context, rec *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	rec.CreatedAt = ir.cl.Now()
+
+	tx, err := ir.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, err = tx.NewDelete().
+		Model((*domain.IdempotencyRecord)(nil)).
+		Where("key = ?", rec.Key).
+		Where("expires_at <= ?", rec.CreatedAt).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.NewInsert().
+		Model(rec).
+		On("CONFLICT (key) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	var existing *domain.IdempotencyRecord
+	if n == 0 {
+		existing = new(domain.IdempotencyRecord)
+		err = tx.NewSelect().Model(existing).Where("key = ?", rec.Key).Scan(ctx)
+		if err != nil {
+			//確認までの間に削除された場合は、呼び出し元で登録し直す
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, utils.NewErrChains(domain.ErrConflict, err)
+			}
+			return nil, err
+		}
+		existing.ExpiresAt = existing.ExpiresAt.In(utils.JST)
+		existing.CreatedAt = existing.CreatedAt.In(utils.JST)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return existing, nil
+}
+
+// 処理中のキーにレスポンスを保存し、有効期限をexpiresAtまで延長する
+func (ir *Idempotency) Complete(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	res, err := ir.db.NewUpdate().
+		Model(rec).
+		Column("status", "content_type", "body", "expires_at").
+		Where("key = ?", rec.Key).
+		Where("status = 0").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+
+	return nil
+}
+
+// 処理中のキーを削除する（処理に失敗した場合に、同じキーで再試行できるようにする）
+func (ir *Idempotency) Release(ctx context.Context, key string) error {
+	_, err := ir.db.NewDelete().
+		Model((*domain.IdempotencyRecord)(nil)).
+		Where("key = ?", key).
+		Where("status = 0").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// 有効期限（before）を過ぎたキーを削除し、削除した件数を返す
+func (ir *Idempotency) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	res, err := ir.db.NewDelete().
+		Model((*domain.IdempotencyRecord)(nil)).
+		Where("expires_at <= ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,159 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+func TestIdempotencyClaim(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewIdempotency(bundb, cl)
+	first := &domain.IdempotencyRecord{Key: "key-1", Fingerprint: "fp", ExpiresAt: cl.Now().Add(time.Minute)}
+	second := &domain.IdempotencyRecord{Key: "key-1", Fingerprint: "fp", ExpiresAt: cl.Now().Add(time.Minute)}
+	a := assert.New(t)
+
+	//Act
+	claimed, err1 := sut.Claim(ctx, first)
+	existing, err2 := sut.Claim(ctx, second)
+
+	//Assert
+	a.Nil(err1)
+	a.Nil(claimed)
+	a.Nil(err2)
+	a.NotNil(existing)
+	a.Equal("fp", existing.Fingerprint)
+	a.True(existing.InProgress())
+}
+
+func TestIdempotencyClaimExpired(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewIdempotency(bundb, cl)
+	stale := &domain.IdempotencyRecord{Key: "key-1", Fingerprint: "old", ExpiresAt: cl.Now().Add(-time.Minute)}
+	if _, err := sut.Claim(ctx, stale); err != nil {
+		t.Fatal(err)
+	}
+	rec := &domain.IdempotencyRecord{Key: "key-1", Fingerprint: "new", ExpiresAt: cl.Now().Add(time.Minute)}
+
+	//Act
+	existing, err := sut.Claim(ctx, rec)
+
+	//Assert
+	assert.Nil(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestIdempotencyCompleteAndRelease(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewIdempotency(bundb, cl)
+	for _, key := range []string{"key-1", "key-2"} {
+		rec := &domain.IdempotencyRecord{Key: key, Fingerprint: "fp", ExpiresAt: cl.Now().Add(time.Minute)}
+		if _, err := sut.Claim(ctx, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	done := &domain.IdempotencyRecord{
+		Key:         "key-1",
+		Status:      201,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":1}`),
+		ExpiresAt:   cl.Now().Add(domain.DefaultIdempotencyTTL),
+	}
+	a := assert.New(t)
+
+	//Act
+	errComplete := sut.Complete(ctx, done)
+	errRelease1 := sut.Release(ctx, "key-1")
+	errRelease2 := sut.Release(ctx, "key-2")
+
+	//Assert
+	a.Nil(errComplete)
+	a.Nil(errRelease1)
+	a.Nil(errRelease2)
+	//完了済みのキーは解放されず、保存したレスポンスを返す
+	got, err := sut.Claim(ctx, &domain.IdempotencyRecord{Key: "key-1", Fingerprint: "fp", ExpiresAt: cl.Now().Add(time.Minute)})
+	a.Nil(err)
+	a.Equal(201, got.Status)
+	a.Equal([]byte(`{"id":1}`), got.Body)
+	//解放したキーは登録し直せる
+	got, err = sut.Claim(ctx, &domain.IdempotencyRecord{Key: "key-2", Fingerprint: "fp", ExpiresAt: cl.Now().Add(time.Minute)})
+	a.Nil(err)
+	a.Nil(got)
+	//処理中でないキーは完了できない
+	a.ErrorIs(sut.Complete(ctx, done), domain.ErrNotFound)
+}
+
+func TestIdempotencyPurgeExpired(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewIdempotency(bundb, cl)
+	recs := []*domain.IdempotencyRecord{
+		{Key: "key-1", Fingerprint: "fp", ExpiresAt: cl.Now().Add(-time.Hour)},
+		{Key: "key-2", Fingerprint: "fp", ExpiresAt: cl.Now().Add(time.Hour)},
+	}
+	for _, rec := range recs {
+		if _, err := sut.Claim(ctx, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	//Act
+	n, err := sut.PurgeExpired(ctx, cl.Now())
+
+	//Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// ジョブのキュー（jobsテーブル）を操作する
+type Job struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewJob(db *bun.DB, cl utils.Clock) *Job {
+	return &Job{db: db, cl: cl}
+}
+
+// ジョブを登録する。RunAtが未指定の場合はすぐに、MaxAttemptsが未指定の場合は既定の回数まで実行する。
+// UniqueKeyが登録済みのジョブと重なる場合は登録せずfalseを返す。
+func (jr *Job) EnqueueJob(ctx context.Context, j *domain.Job) (bool, error) {
+	now := jr.cl.Now()
+	j.Status = domain.JobQueued
+	if j.RunAt.IsZero() {
+		j.RunAt = now
+	}
+	if j.MaxAttempts <= 0 {
+		j.MaxAttempts = domain.DefaultJobMaxAttempts
+	}
+	j.CreatedAt = now
+	j.UpdatedAt = now
+
+	q := jr.db.NewInsert().Model(j).Returning("id")
+	if j.UniqueKey != "" {
+		q = q.On("CONFLICT (unique_key) DO NOTHING")
+	}
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// kindsのうち実行時刻を過ぎたジョブと、占有期間を過ぎた実行中のジョブを最大limit件取得し、実行中にする。
+// 取得したジョブはvisibilityの間占有し、試行回数を1増やす。複数のサーバーで同時に取得しても重ならない（FOR UPDATE SKIP LOCKED）。
+func (jr *Job) ClaimJobs(ctx context.Context, kinds []string, limit int, visibility time.Duration) ([]*domain.Job, error) {
+	if len(kinds) == 0 || limit <= 0 {
+		return nil, nil
+	}
+	now := jr.cl.Now()
+	due := jr.db.NewSelect().
+		Model((*domain.Job)(nil)).
+		Column("id").
+		Where("kind IN (?)", bun.In(kinds)).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				WhereGroup(" OR ", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.Where("status = ?", domain.JobQueued).Where("run_at <= ?", now)
+				}).
+				WhereGroup(" OR ", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.Where("status = ?", domain.JobRunning).Where("locked_until < ?", now)
+				})
+		}).
+		Order("run_at").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	jobs := []*domain.Job{}
+	_, err := jr.db.NewUpdate().
+		Model((*domain.Job)(nil)).
+		Set("status = ?", domain.JobRunning).
+		Set("attempts = attempts + 1").
+		Set("locked_until = ?", now.Add(visibility)).
+		Set("updated_at = ?", now).
+		Where("id IN (?)", due).
+		Returning("*").
+		Exec(ctx, &jobs)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// 実行の結果（状態、次の実行時刻、占有期間、エラー）を保存する。
+// 取得時の試行回数と一致しない（占有期間が過ぎて取得し直された）場合はdomain.ErrJobLeaseLostを返し、保存しない。
+func (jr *Job) SaveJobResult(ctx context.Context, j *domain.Job) error {
+	res, err := jr.db.NewUpdate().
+		Model(j).
+		Column("status", "run_at", "locked_until", "last_error", "updated_at").
+		WherePK().
+		Where("status = ?", domain.JobRunning).
+		Where("attempts = ?", j.Attempts).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrJobLeaseLost
+	}
+	return nil
+}
+
+// beforeより前に終了した（成功、dead）ジョブを削除し、削除した件数を返す
+func (jr *Job) PurgeJobs(ctx context.Context, before time.Time) (int64, error) {
+	res, err := jr.db.NewDelete().
+		Model((*domain.Job)(nil)).
+		Where("status IN (?)", bun.In([]domain.JobStatus{domain.JobSucceeded, domain.JobDead})).
+		Where("updated_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
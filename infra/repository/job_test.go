@@ -0,0 +1,125 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+func TestJobQueue(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewJob(bundb, cl)
+	key := domain.ScheduledJobKey("trash.purge", cl.Now())
+	later := &domain.Job{Kind: "digest.send", RunAt: cl.Now().Add(time.Hour)}
+	a := assert.New(t)
+
+	//Act
+	first, errFirst := sut.EnqueueJob(ctx, &domain.Job{Kind: "trash.purge", UniqueKey: key})
+	dup, errDup := sut.EnqueueJob(ctx, &domain.Job{Kind: "trash.purge", UniqueKey: key})
+	if _, err := sut.EnqueueJob(ctx, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sut.EnqueueJob(ctx, &domain.Job{Kind: "unknown"}); err != nil {
+		t.Fatal(err)
+	}
+	claimed, err := sut.ClaimJobs(ctx, []string{"trash.purge", "digest.send"}, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked, err := sut.ClaimJobs(ctx, []string{"trash.purge", "digest.send"}, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimed[0].Fail(cl.Now(), errors.New("失敗"))
+	errSave := sut.SaveJobResult(ctx, claimed[0])
+	retryLater, err := sut.ClaimJobs(ctx, []string{"trash.purge"}, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert
+	a.Nil(errFirst)
+	a.True(first)
+	a.Nil(errDup)
+	a.False(dup)
+	a.Len(claimed, 1)
+	a.Equal("trash.purge", claimed[0].Kind)
+	a.Equal(1, claimed[0].Attempts)
+	a.Equal(domain.DefaultJobMaxAttempts, claimed[0].MaxAttempts)
+	a.Empty(locked)
+	a.Nil(errSave)
+	a.Empty(retryLater)
+}
+
+func TestJobQueueVisibilityTimeout(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewJob(bundb, cl)
+	if _, err := sut.EnqueueJob(ctx, &domain.Job{Kind: "trash.purge"}); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act
+	//占有期間が過ぎた（負の期間）実行中のジョブは、他のサーバーが取得し直す
+	first, err := sut.ClaimJobs(ctx, []string{"trash.purge"}, 10, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := sut.ClaimJobs(ctx, []string{"trash.purge"}, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//取得し直される前の実行の結果は保存しない
+	first[0].Status = domain.JobSucceeded
+	first[0].LockedUntil = time.Time{}
+	errLost := sut.SaveJobResult(ctx, first[0])
+	second[0].Status = domain.JobSucceeded
+	second[0].LockedUntil = time.Time{}
+	if err := sut.SaveJobResult(ctx, second[0]); err != nil {
+		t.Fatal(err)
+	}
+	notYet, errNotYet := sut.PurgeJobs(ctx, cl.Now())
+	purged, errPurged := sut.PurgeJobs(ctx, cl.Now().Add(time.Second))
+
+	//Assert
+	a.Len(first, 1)
+	a.Len(second, 1)
+	a.Equal(first[0].ID, second[0].ID)
+	a.Equal(2, second[0].Attempts)
+	a.ErrorIs(errLost, domain.ErrJobLeaseLost)
+	a.Nil(errNotYet)
+	a.Zero(notYet)
+	a.Nil(errPurged)
+	a.Equal(int64(1), purged)
+}
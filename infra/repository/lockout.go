@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// 認証の失敗の回数とロック（auth_failuresテーブル）を操作する。
+// 複数のAPIサーバーで同じ回数を数えるため、メモリではなくDBに保存する。
+type Lockout struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewLockout(db *bun.DB, cl utils.Clock) *Lockout {
+	return &Lockout{db: db, cl: cl}
+}
+
+// 失敗の記録を返す。記録がない場合は回数0の記録を返す
+func (lr *Lockout) FindAuthFailure(ctx context.Context, scope domain.LockoutScope, key string) (*domain.AuthFailure, error) {
+	f := new(domain.AuthFailure)
+	err := lr.db.NewSelect().
+		Model(f).
+		Where("scope = ?", scope).
+		Where("key = ?", key).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &domain.AuthFailure{Scope: scope, Key: key}, nil
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// 失敗を1回記録し、更新後の記録とロックしたかを返す。
+// 同じキーへの同時の失敗は行ロック（FOR UPDATE）で直列にし、回数の数え漏れを防ぐ。
+func (lr *Lockout) RecordFailure(ctx context.Context, scope domain.LockoutScope, key string, p domain.LockoutPolicy) (*domain.AuthFailure, bool, error) {
+	now := lr.cl.Now()
+	f := &domain.AuthFailure{Scope: scope, Key: key, LastFailedAt: now}
+	locked := false
+	err := lr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(f).
+			On("CONFLICT (scope, key) DO NOTHING").
+			Returning("NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		err = tx.NewSelect().
+			Model(f).
+			Where("scope = ?", scope).
+			Where("key = ?", key).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		locked = f.Fail(now, p)
+		_, err = tx.NewUpdate().
+			Model(f).
+			Column("failures", "locked_until", "last_failed_at").
+			WherePK().
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return f, locked, nil
+}
+
+// 失敗の記録を削除する（認証に成功した場合）
+func (lr *Lockout) ResetFailures(ctx context.Context, scope domain.LockoutScope, key string) error {
+	_, err := lr.db.NewDelete().
+		Model((*domain.AuthFailure)(nil)).
+		Where("scope = ?", scope).
+		Where("key = ?", key).
+		Exec(ctx)
+	return err
+}
+
+// 最後の失敗がbefore以前で、ロック中でない記録を削除し、削除した件数を返す
+func (lr *Lockout) PurgeAuthFailures(ctx context.Context, before time.Time) (int64, error) {
+	res, err := lr.db.NewDelete().
+		Model((*domain.AuthFailure)(nil)).
+		Where("last_failed_at <= ?", before).
+		Where("locked_until IS NULL OR locked_until <= ?", lr.cl.Now()).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,103 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+func TestLockoutRecordFailure(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewLockout(bundb, cl)
+	p := domain.LockoutPolicy{Threshold: 10, Base: time.Minute, Max: time.Hour, Window: time.Hour}
+	a := assert.New(t)
+
+	//Act
+	//2台のAPIサーバーからの同時の失敗も数え漏れない
+	var wg sync.WaitGroup
+	lockedCount := 0
+	var mu sync.Mutex
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, locked, err := sut.RecordFailure(ctx, domain.LockoutIP, "192.0.2.1", p)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if locked {
+				mu.Lock()
+				lockedCount++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	got, errFind := sut.FindAuthFailure(ctx, domain.LockoutIP, "192.0.2.1")
+	other, errOther := sut.FindAuthFailure(ctx, domain.LockoutAccount, "192.0.2.1")
+
+	//Assert
+	a.Nil(errFind)
+	a.Nil(errOther)
+	a.Equal(10, got.Failures)
+	a.Equal(1, lockedCount)
+	a.Equal(time.Minute, got.RetryAfter(cl.Now()))
+	a.Equal(0, other.Failures) //単位ごとに数える
+}
+
+func TestLockoutResetAndPurge(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewLockout(bundb, cl)
+	p := domain.LockoutPolicy{Threshold: 1, Base: time.Minute, Max: time.Hour, Window: time.Hour}
+	for _, key := range []string{"reset@example.com", "locked@example.com"} {
+		if _, _, err := sut.RecordFailure(ctx, domain.LockoutAccount, key, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := assert.New(t)
+
+	//Act
+	errReset := sut.ResetFailures(ctx, domain.LockoutAccount, "reset@example.com")
+	reset, _ := sut.FindAuthFailure(ctx, domain.LockoutAccount, "reset@example.com")
+	n, errPurge := sut.PurgeAuthFailures(ctx, cl.Now())
+	locked, _ := sut.FindAuthFailure(ctx, domain.LockoutAccount, "locked@example.com")
+
+	//Assert
+	a.Nil(errReset)
+	a.Equal(0, reset.Failures)
+	a.Nil(errPurge)
+	a.Equal(int64(0), n) //ロック中の記録は削除しない
+	a.Equal(1, locked.Failures)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// メールの設定（mail_settingsテーブル）を操作する
+type Mail struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewMail(db *bun.DB, cl utils.Clock) *Mail {
+	return &Mail{db: db, cl: cl}
+}
+
+// メールの設定を取得する。未登録の場合は既定の設定を返す
+func (mr *Mail) FindMailSetting(ctx context.Context, authUserId string) (*domain.MailSetting, error) {
+	s := new(domain.MailSetting)
+	err := mr.db.NewSelect().Model(s).Where("auth_user_id = ?", authUserId).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DefaultMailSetting(authUserId), nil
+		}
+		return nil, err
+	}
+	s.UpdatedAt = s.UpdatedAt.In(utils.JST)
+	return s, nil
+}
+
+// メールの設定を登録、更新する
+func (mr *Mail) SaveMailSetting(ctx context.Context, s *domain.MailSetting) error {
+	s.UpdatedAt = mr.cl.Now()
+	_, err := mr.db.NewInsert().
+		Model(s).
+		On("CONFLICT (auth_user_id) DO UPDATE").
+		Set("language = EXCLUDED.language").
+		Set("digest = EXCLUDED.digest").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("NULL").
+		Exec(ctx)
+	return err
+}
+
+// 月次のまとめの配信を停止する。言語の設定は変えない
+func (mr *Mail) Unsubscribe(ctx context.Context, authUserId string) error {
+	s := domain.DefaultMailSetting(authUserId)
+	s.Digest = false
+	s.UpdatedAt = mr.cl.Now()
+	_, err := mr.db.NewInsert().
+		Model(s).
+		On("CONFLICT (auth_user_id) DO UPDATE").
+		Set("digest = EXCLUDED.digest").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("NULL").
+		Exec(ctx)
+	return err
+}
+
+// 月次のまとめを受け取るユーザーをusersのidがafterIdより大きい順に最大limit件取得する（削除済みのユーザーを除く）
+func (mr *Mail) FindDigestRecipients(ctx context.Context, afterId int64, limit int) ([]*domain.DigestRecipient, error) {
+	recipients := []*domain.DigestRecipient{}
+	err := mr.db.NewSelect().
+		TableExpr("users AS u").
+		Join("LEFT JOIN mail_settings AS ms ON ms.auth_user_id = u.auth_user_id").
+		ColumnExpr("u.id, u.auth_user_id, u.name, u.email").
+		ColumnExpr("COALESCE(ms.language, ?) AS language", domain.MailJapanese).
+		Where("u.deleted_at IS NULL").
+		Where("COALESCE(ms.digest, TRUE)").
+		Where("u.id > ?", afterId).
+		Order("u.id").
+		Limit(limit).
+		Scan(ctx, &recipients)
+	if err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
@@ -0,0 +1,96 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestMailSetting(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewMail(bundb, cl)
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	a := assert.New(t)
+
+	//Act
+	initial, errInitial := sut.FindMailSetting(ctx, authUserId)
+	errSave := sut.SaveMailSetting(ctx, &domain.MailSetting{AuthUserId: authUserId, Language: domain.MailEnglish, Digest: true})
+	saved, err := sut.FindMailSetting(ctx, authUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errUnsubscribe := sut.Unsubscribe(ctx, authUserId)
+	unsubscribed, err := sut.FindMailSetting(ctx, authUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert
+	a.Nil(errInitial)
+	a.Equal(domain.DefaultMailSetting(authUserId), initial)
+	a.Nil(errSave)
+	a.Equal(domain.MailEnglish, saved.Language)
+	a.True(saved.Digest)
+	a.Nil(errUnsubscribe)
+	a.Equal(domain.MailEnglish, unsubscribed.Language) //言語は変えない
+	a.False(unsubscribed.Digest)
+}
+
+func TestFindDigestRecipients(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	users := []*domain.User{
+		{AuthUserId: "user-default", Name: "既定", Email: "default@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()},
+		{AuthUserId: "user-english", Name: "英語", Email: "english@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()},
+		{AuthUserId: "user-unsubscribed", Name: "停止", Email: "unsubscribed@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()},
+		{AuthUserId: "user-deleted", Name: "削除", Email: "deleted@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now(), DeletedAt: cl.Now()},
+	}
+	testutils.InsertTestData(ctx, t, bundb, users...)
+	sut := repository.NewMail(bundb, cl)
+	if err := sut.SaveMailSetting(ctx, &domain.MailSetting{AuthUserId: "user-english", Language: domain.MailEnglish, Digest: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.Unsubscribe(ctx, "user-unsubscribed"); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act
+	first, errFirst := sut.FindDigestRecipients(ctx, 0, 1)
+	rest, errRest := sut.FindDigestRecipients(ctx, first[0].ID, 10)
+
+	//Assert
+	a.Nil(errFirst)
+	a.Nil(errRest)
+	a.Equal([]*domain.DigestRecipient{{ID: users[0].ID, AuthUserId: "user-default", Name: "既定", Email: "default@example.com", Language: domain.MailJapanese}}, first)
+	a.Equal([]*domain.DigestRecipient{{ID: users[1].ID, AuthUserId: "user-english", Name: "英語", Email: "english@example.com", Language: domain.MailEnglish}}, rest)
+}
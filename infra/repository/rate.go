@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+type Rate struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewRate(db *bun.DB, cl utils.Clock) *Rate {
+	return &Rate{db: db, cl: cl}
+}
+
+// 登録済みの為替レートをすべて返す
+func (rr *Rate) FindRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	rates := []*domain.ExchangeRate{}
+
+	err := rr.db.NewSelect().Model(&rates).Order("currency ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range rates {
+		r.CreatedAt = r.CreatedAt.In(utils.JST)
+		r.UpdatedAt = r.UpdatedAt.In(utils.JST)
+	}
+
+	return rates, nil
+}
+
+// 為替レートを通貨単位で登録。すでに存在する通貨はレートを上書きする。
+func (rr *Rate) UpsertRates(ctx context.Context, rates []*domain.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	now := rr.cl.Now()
+	for _, r := range rates {
+		r.CreatedAt = now
+		r.UpdatedAt = now
+	}
+
+	tx, err := rr.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, err = tx.NewInsert().
+		Model(&rates).
+		On("CONFLICT (currency) DO UPDATE").
+		Set("rate = EXCLUDED.rate").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return nil
+}
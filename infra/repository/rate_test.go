@@ -0,0 +1,96 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestFindRates(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	rates := []*domain.ExchangeRate{
+		{
+			Currency:  domain.EUR,
+			Rate:      162.3,
+			CreatedAt: cl.Now(),
+			UpdatedAt: cl.Now(),
+		},
+		{
+			Currency:  domain.USD,
+			Rate:      150.5,
+			CreatedAt: cl.Now(),
+			UpdatedAt: cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, rates...)
+	sut := repository.NewRate(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	got, err := sut.FindRates(ctx)
+
+	//Assert
+	a.Nil(err)
+	a.Equal(rates, got)
+}
+
+func TestUpsertRates(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	rate := &domain.ExchangeRate{
+		Currency:  domain.USD,
+		Rate:      150.5,
+		CreatedAt: cl.Now(),
+		UpdatedAt: cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, rate)
+
+	updated := []*domain.ExchangeRate{
+		{Currency: domain.USD, Rate: 148.2},
+		{Currency: domain.EUR, Rate: 162.3},
+	}
+	sut := repository.NewRate(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	err = sut.UpsertRates(ctx, updated)
+
+	//Assert
+	a.Nil(err)
+	got, err := sut.FindRates(ctx)
+	a.Nil(err)
+	a.Len(got, 2)
+	a.Equal(domain.EUR, got[0].Currency)
+	a.Equal(148.2, got[1].Rate)
+}
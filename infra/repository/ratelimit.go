@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// レート制限のリクエスト数（rate_limit_countersテーブル）を操作する。
+// 複数のAPIサーバーで同じ上限を共有するため、メモリではなくDBで数える。
+type RateLimit struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewRateLimit(db *bun.DB, cl utils.Clock) *RateLimit {
+	return &RateLimit{db: db, cl: cl}
+}
+
+// キーのnowを含む区間のリクエスト数を1増やし、増やした後の件数を返す。同時のリクエストも1回の更新（UPSERT）で数える
+func (rr *RateLimit) Increment(ctx context.Context, key string, rl domain.RateLimit, now time.Time) (int, error) {
+	start := rl.WindowStart(now)
+	c := &domain.RateLimitCounter{Key: key, WindowStart: start, Count: 1, ExpiresAt: start.Add(rl.Window)}
+	err := rr.db.NewInsert().
+		Model(c).
+		On("CONFLICT (key, window_start) DO UPDATE").
+		Set("count = ?TableAlias.count + 1").
+		Returning("count").
+		Scan(ctx, &c.Count)
+	if err != nil {
+		return 0, err
+	}
+	return c.Count, nil
+}
+
+// 区間の終了（before）を過ぎたリクエスト数を削除し、削除した件数を返す
+func (rr *RateLimit) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	res, err := rr.db.NewDelete().
+		Model((*domain.RateLimitCounter)(nil)).
+		Where("expires_at <= ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
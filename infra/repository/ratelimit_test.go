@@ -0,0 +1,62 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+func TestRateLimitIncrement(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewRateLimit(bundb, cl)
+	rl := domain.RateLimit{Limit: 10, Window: time.Minute}
+	now := cl.Now()
+	a := assert.New(t)
+
+	//Act
+	//2台のAPIサーバーからの同時のリクエストも数え漏れない
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := sut.Increment(ctx, "default:user:1", rl, now); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	got, errGot := sut.Increment(ctx, "default:user:1", rl, now)
+	other, errOther := sut.Increment(ctx, "default:user:2", rl, now)
+	next, errNext := sut.Increment(ctx, "default:user:1", rl, now.Add(time.Minute))
+	n, errPurge := sut.PurgeExpired(ctx, now.Add(time.Minute))
+
+	//Assert
+	a.Nil(errGot)
+	a.Equal(11, got)
+	a.Nil(errOther)
+	a.Equal(1, other) //キーごとに数える
+	a.Nil(errNext)
+	a.Equal(1, next) //次の区間は0から数える
+	a.Nil(errPurge)
+	a.Equal(int64(2), n) //終了した区間のみ削除する
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// 本の変更履歴（更新前の版）を新しい順に返す。本がない場合（他のユーザーの本、削除済みを含む）はErrNotFound
+func (sr *Shelf) FindBookRevisions(ctx context.Context, authUserId string, bookId int64) ([]*domain.BookRevision, error) {
+	if _, err := sr.FindBookByID(ctx, authUserId, bookId); err != nil {
+		return nil, err
+	}
+
+	revisions := []*domain.BookRevision{}
+	err := sr.db.NewSelect().
+		Model(&revisions).
+		Where("book_id = ?", bookId).
+		Where("auth_user_id = ?", authUserId).
+		Order("id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range revisions {
+		r.CreatedAt = r.CreatedAt.Local().In(utils.JST)
+		if !r.BookCreatedAt.IsZero() {
+			r.BookCreatedAt = r.BookCreatedAt.Local().In(utils.JST)
+		}
+	}
+	return revisions, nil
+}
+
+// 本を変更履歴の版の内容に戻し、チャートを再計算して更新後の本を返す。
+// 戻す操作も更新として変更履歴に記録する（戻す前の版に再び戻せる）。
+// 本がない場合はErrNotFound、版がない場合はErrRevisionNotFound。バージョンの扱いはUpdateBookWithChartsと同じ。
+func (sr *Shelf) RevertBookWithCharts(ctx context.Context, authUserId string, bookId int64, revisionId int64, version int64) (*domain.Book, error) {
+	tx, err := sr.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	book, err := findBookForAudit(ctx, tx, bookId, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	if book == nil {
+		return nil, utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+	if version > 0 && book.Version != version {
+		return nil, utils.NewErrChains(domain.ErrPreconditionFailed, nil)
+	}
+
+	revision := new(domain.BookRevision)
+	err = tx.NewSelect().
+		Model(revision).
+		Where("id = ?", revisionId).
+		Where("book_id = ?", bookId).
+		Where("auth_user_id = ?", authUserId).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.NewErrChains(domain.ErrRevisionNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	revision.Apply(book)
+	err = updateBookWithCharts(ctx, tx, sr.cl.Now(), book)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	book.CreatedAt = book.CreatedAt.Local().In(utils.JST)
+	book.UpdatedAt = book.UpdatedAt.Local().In(utils.JST)
+	return book, nil
+}
+
+// 変更履歴の版を記録する。revisionがnil（変更なし）の場合は何もしない
+func insertBookRevision(ctx context.Context, db bun.IDB, now time.Time, revision *domain.BookRevision) error {
+	if revision == nil {
+		return nil
+	}
+	revision.CreatedAt = now
+	_, err := db.NewInsert().Model(revision).Exec(ctx)
+	return err
+}
@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestRevertBookWithCharts(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	sut := repository.NewShelf(bundb, cl)
+	book := &domain.Book{Title: "容疑者Xの献身", Page: 330, Price: 1640, Currency: domain.JPY, BookStatus: domain.Bought, AuthUserId: authUserId, CreatedAt: cl.Now()}
+	if err := sut.CreateBookWithCharts(ctx, book, domain.NewChartsFromBook(book)); err != nil {
+		t.Fatal(err)
+	}
+	//価格を誤って上書きする
+	overwritten := *book
+	overwritten.Price = 16400
+	if err := sut.UpdateBookWithCharts(ctx, &overwritten); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act
+	history, errHistory := sut.FindBookRevisions(ctx, authUserId, book.ID)
+	_, errVersion := sut.RevertBookWithCharts(ctx, authUserId, book.ID, history[0].ID, 1)
+	reverted, errRevert := sut.RevertBookWithCharts(ctx, authUserId, book.ID, history[0].ID, 0)
+	_, errRevision := sut.RevertBookWithCharts(ctx, authUserId, book.ID, 100, 0)
+	_, errBook := sut.RevertBookWithCharts(ctx, "unknown", book.ID, history[0].ID, 0)
+	after, _ := sut.FindBookRevisions(ctx, authUserId, book.ID)
+	var price domain.Chart
+	errChart := bundb.NewSelect().Model(&price).Where("book_id = ?", book.ID).Where("label = ?", domain.ChartPrice).Scan(ctx)
+
+	//Assert
+	a.Nil(errHistory)
+	if a.Len(history, 1) {
+		a.Equal(int64(1), history[0].Version)
+		a.Equal(1640, history[0].Price)
+		a.Equal([]string{"price"}, history[0].ChangedColumns())
+	}
+	a.ErrorIs(errVersion, domain.ErrPreconditionFailed)
+	a.Nil(errRevert)
+	a.Equal(1640, reverted.Price)
+	a.Equal(int64(3), reverted.Version)
+	a.ErrorIs(errRevision, domain.ErrRevisionNotFound)
+	a.ErrorIs(errBook, domain.ErrNotFound)
+	a.Len(after, 2) //戻す操作も変更履歴に記録する
+	a.Nil(errChart)
+	a.Equal(1640, price.Data) //チャートも再計算する
+}
+
+func TestPatchBookWithChartsRevision(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	sut := repository.NewShelf(bundb, cl)
+	purchased := cl.Now().AddDate(0, -2, 0)
+	book := &domain.Book{Title: "容疑者Xの献身", Page: 330, Price: 1640, Currency: domain.JPY, BookStatus: domain.Bought, AuthUserId: authUserId, CreatedAt: purchased}
+	if err := sut.CreateBookWithCharts(ctx, book, domain.NewChartsFromBook(book)); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act
+	//購入日を誤って部分更新し、一括操作で状態を変更する
+	errPatch := sut.PatchBookWithCharts(ctx, &domain.Book{ID: book.ID, AuthUserId: authUserId, CreatedAt: cl.Now()}, []string{"created_at"})
+	errStatus := sut.RunInTx(ctx, func(ctx context.Context, st *repository.ShelfTx) error {
+		return st.UpdateBookStatus(ctx, &domain.Book{ID: book.ID, AuthUserId: authUserId, BookStatus: domain.Reading})
+	})
+	history, errHistory := sut.FindBookRevisions(ctx, authUserId, book.ID)
+	var reverted *domain.Book
+	var errRevert error
+	if len(history) == 2 {
+		reverted, errRevert = sut.RevertBookWithCharts(ctx, authUserId, book.ID, history[1].ID, 0)
+	}
+
+	//Assert
+	a.Nil(errPatch)
+	a.Nil(errStatus)
+	a.Nil(errHistory)
+	if a.Len(history, 2) { //新しい順
+		a.Equal([]string{"book_status"}, history[0].ChangedColumns())
+		a.Equal([]string{"created_at"}, history[1].ChangedColumns())
+		a.True(purchased.Equal(history[1].BookCreatedAt))
+		a.Nil(errRevert)
+		a.True(purchased.Equal(reverted.CreatedAt)) //購入日も版の内容に戻す
+		a.Equal(domain.Bought, reverted.BookStatus)
+	}
+}
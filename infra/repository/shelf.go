@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"time"
 
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/utils"
@@ -38,17 +41,26 @@ func (sr *Shelf) FindBooksByAuthUserID(ctx context.Context, authUserId string) (
 	return books, nil
 }
 
-// 本を新規で作成時に、book、chartsをまとめてデータベースに登録
-func (sr *Shelf) CreateBookWithCharts(ctx context.Context, book *domain.Book, charts []*domain.Chart) error {
-	now := sr.cl.Now()
+// authUserIdとidをもとに本を1冊返す。ない場合（他のユーザーの本、削除済みを含む）はErrNotFound
+func (sr *Shelf) FindBookByID(ctx context.Context, authUserId string, bookId int64) (*domain.Book, error) {
+	book := new(domain.Book)
 
-	book.CreatedAt = now
-	book.UpdatedAt = now
-	for _, c := range charts {
-		c.CreatedAt = now
-		c.UpdatedAt = now
+	err := sr.db.NewSelect().Model(book).Where("id = ?", bookId).Where("auth_user_id = ?", authUserId).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, utils.NewErrChains(domain.ErrNotFound, err)
+		}
+		return nil, err
 	}
 
+	book.CreatedAt = book.CreatedAt.Local().In(utils.JST)
+	book.UpdatedAt = book.UpdatedAt.Local().In(utils.JST)
+
+	return book, nil
+}
+
+// 本を新規で作成時に、book、chartsをまとめてデータベースに登録
+func (sr *Shelf) CreateBookWithCharts(ctx context.Context, book *domain.Book, charts []*domain.Chart) error {
 	//トランザクション
 	tx, err := sr.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
@@ -60,36 +72,66 @@ func (sr *Shelf) CreateBookWithCharts(ctx context.Context, book *domain.Book, ch
 		}
 	}()
 
+	err = createBookWithCharts(ctx, tx, sr.cl.Now(), book, charts)
+	if err != nil {
+		return err
+	}
+
+	//コミット処理
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return nil
+}
+
+func createBookWithCharts(ctx context.Context, db bun.IDB, now time.Time, book *domain.Book, charts []*domain.Chart) error {
+	book.Version = 1
+	book.CreatedAt = now
+	book.UpdatedAt = now
+	for _, c := range charts {
+		c.CreatedAt = now
+		c.UpdatedAt = now
+	}
+
 	//本の登録
 	var bookId int64
-	err = tx.NewInsert().Model(book).Returning("id").Scan(ctx, &bookId)
+	err := db.NewInsert().Model(book).Returning("id").Scan(ctx, &bookId)
 	if err != nil {
 		return err
 	}
 
+	//採番されたidを呼び出し元に返す（v2のレスポンスで利用）
+	book.ID = bookId
+
 	//チャートの登録
 	for _, c := range charts {
 		c.BookId = bookId
 	}
-	err = tx.NewInsert().Model(&charts).Scan(ctx)
+	err = db.NewInsert().Model(&charts).Scan(ctx)
 	if err != nil {
 		return err
 	}
 
-	//コミット処理
-	err = tx.Commit()
+	err = insertEvents(ctx, db, now, domain.NewBookEvent(domain.EventBookCreated, book))
 	if err != nil {
-		return fmt.Errorf("コミット失敗:%w", err)
+		return err
+	}
+	err = insertAuditLogs(ctx, db, now, domain.NewAuditLog(ctx, domain.AuditCreate, nil, book))
+	if err != nil {
+		return err
+	}
+	//読了で登録した本は、読み終えた通知も送る
+	if book.BookStatus == domain.Read {
+		return insertOutbox(ctx, db, now, domain.NewBookEvent(domain.EventBookFinished, book))
 	}
-
 	return nil
 }
 
-// 本の更新とチャートの更新を同時に行う
+// 本の更新とチャートの更新を同時に行う。
+// book.Versionが0以外の場合は、登録済みのバージョンと一致する場合のみ更新する（不一致はErrPreconditionFailed）。
 func (sr *Shelf) UpdateBookWithCharts(ctx context.Context, book *domain.Book) error {
-	now := sr.cl.Now()
-	book.UpdatedAt = now
-
 	//トランザクション
 	tx, err := sr.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
@@ -101,34 +143,106 @@ func (sr *Shelf) UpdateBookWithCharts(ctx context.Context, book *domain.Book) er
 		}
 	}()
 
-	//本の更新
-	_, err = tx.NewUpdate().Model(book).WherePK().Exec(ctx)
+	err = updateBookWithCharts(ctx, tx, sr.cl.Now(), book)
 	if err != nil {
 		return err
 	}
-	//チャートの更新
-	charts := []*domain.Chart{}
-	err = tx.NewSelect().Model(&charts).Where("book_id = ?", book.ID).Scan(ctx)
+
+	//コミット処理
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return nil
+}
+
+func updateBookWithCharts(ctx context.Context, db bun.IDB, now time.Time, book *domain.Book) error {
+	book.UpdatedAt = now
+	book.Currency = book.Currency.OrDefault()
+
+	//読了への変更（book.finished）、監査ログの変更は更新前の状態と比べて判定する
+	before, err := findBookForAudit(ctx, db, book.ID, book.AuthUserId)
 	if err != nil {
 		return err
 	}
-	for _, c := range charts {
-		switch c.Label {
-		case domain.ChartPrice:
-			c.Data = book.Price
-			c.UpdatedAt = now
-		case domain.ChartVolumes:
-			continue
-		case domain.ChartPages:
-			c.Data = book.Page
-			c.UpdatedAt = now
+	finished := finishesBook(before, book)
+
+	//本の更新（他のユーザーの本は対象外）。バージョンは更新ごとに1増やし、指定がある場合（If-Match）は一致する場合のみ更新する
+	version := book.Version
+	q := db.NewUpdate().
+		Model(book).
+		WherePK().
+		Where("auth_user_id = ?", book.AuthUserId).
+		Value("version", "?TableAlias.version + 1").
+		Returning("version")
+	if version > 0 {
+		q = q.Where("?TableAlias.version = ?", version)
+	}
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return err
+	}
+	//更新対象がない（未登録、削除済み）、またはバージョンが異なる場合は成功扱いにしない
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errNoRowsUpdated(ctx, db.NewSelect().
+			Model((*domain.Book)(nil)).
+			Where("id = ?", book.ID).
+			Where("auth_user_id = ?", book.AuthUserId), version)
+	}
+	err = insertEvents(ctx, db, now, domain.NewBookEvent(domain.EventBookUpdated, book))
+	if err != nil {
+		return err
+	}
+	after, err := auditBookUpdate(ctx, db, now, before)
+	if err != nil {
+		return err
+	}
+	//変更履歴として更新前の版を記録する（変更がない場合は記録しない）
+	err = insertBookRevision(ctx, db, now, domain.NewBookRevision(before, after))
+	if err != nil {
+		return err
+	}
+	if finished {
+		err = insertOutbox(ctx, db, now, domain.NewBookEvent(domain.EventBookFinished, book))
+		if err != nil {
+			return err
 		}
 	}
-	_, err = tx.NewUpdate().Model(&charts).
-		Column("data", "updated_at").
+
+	//チャートの更新（購入日の変更による年月を含む）
+	charts := []*domain.Chart{}
+	err = db.NewSelect().Model(&charts).Where("book_id = ?", book.ID).Scan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(charts) == 0 {
+		return nil
+	}
+	domain.ApplyBookToCharts(book, charts, now)
+	_, err = db.NewUpdate().Model(&charts).
+		Column("year", "month", "data", "currency", "updated_at").
 		WherePK().
 		Bulk().
 		Exec(ctx)
+	return err
+}
+
+// 本の部分更新（PATCH）。columns（bunの列名）のみ更新し、価格、ページ数、通貨、購入日を変更した場合はチャートも再計算する。
+// バージョンの扱いはUpdateBookWithChartsと同じ。更新後のバージョンはbook.Versionに反映される。
+func (sr *Shelf) PatchBookWithCharts(ctx context.Context, book *domain.Book, columns []string) error {
+	//トランザクション
+	tx, err := sr.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	err = patchBookWithCharts(ctx, tx, sr.cl.Now(), book, columns)
 	if err != nil {
 		return err
 	}
@@ -142,11 +256,90 @@ func (sr *Shelf) UpdateBookWithCharts(ctx context.Context, book *domain.Book) er
 	return nil
 }
 
-// 本の削除時、book_idで対応するチャートも削除
-func (sr *Shelf) DleteBooksWithCharts(ctx context.Context, books []*domain.Book) error {
+func patchBookWithCharts(ctx context.Context, db bun.IDB, now time.Time, book *domain.Book, columns []string) error {
+	book.UpdatedAt = now
+	book.Currency = book.Currency.OrDefault()
+
+	//読了への変更（book.finished）、監査ログの変更は更新前の状態と比べて判定する
+	before, err := findBookForAudit(ctx, db, book.ID, book.AuthUserId)
+	if err != nil {
+		return err
+	}
+	finished := slices.Contains(columns, "book_status") && finishesBook(before, book)
+
+	//指定した列のみ更新（他のユーザーの本は対象外）。versionは列に含め、Valueの式で1増やす
+	version := book.Version
+	q := db.NewUpdate().
+		Model(book).
+		Column(columns...).
+		Column("updated_at", "version").
+		WherePK().
+		Where("auth_user_id = ?", book.AuthUserId).
+		Value("version", "?TableAlias.version + 1").
+		Returning("version")
+	if version > 0 {
+		q = q.Where("?TableAlias.version = ?", version)
+	}
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errNoRowsUpdated(ctx, db.NewSelect().
+			Model((*domain.Book)(nil)).
+			Where("id = ?", book.ID).
+			Where("auth_user_id = ?", book.AuthUserId), version)
+	}
+	err = insertEvents(ctx, db, now, domain.NewBookEvent(domain.EventBookUpdated, book))
+	if err != nil {
+		return err
+	}
+	after, err := auditBookUpdate(ctx, db, now, before)
+	if err != nil {
+		return err
+	}
+	//変更履歴として更新前の版を記録する（変更がない場合は記録しない）
+	err = insertBookRevision(ctx, db, now, domain.NewBookRevision(before, after))
+	if err != nil {
+		return err
+	}
+	if finished {
+		err = insertOutbox(ctx, db, now, domain.NewBookEvent(domain.EventBookFinished, book))
+		if err != nil {
+			return err
+		}
+	}
+
+	//チャートの再計算
+	if !domain.ChartColumnsChanged(columns) {
+		return nil
+	}
+	charts := []*domain.Chart{}
+	err = db.NewSelect().Model(&charts).Where("book_id = ?", book.ID).Scan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(charts) == 0 {
+		return nil
+	}
+	domain.ApplyBookToCharts(book, charts, now)
+	_, err = db.NewUpdate().Model(&charts).
+		Column("year", "month", "data", "currency", "updated_at").
+		WherePK().
+		Bulk().
+		Exec(ctx)
+	return err
+}
+
+// 本の削除時、book_idで対応するチャートも削除。
+// 削除はdeleted_atによる論理削除で、保持期間内であればRestoreBooksWithChartsで復元できる。
+// authUserIdの本のみ削除する。未登録、削除済み、他のユーザーの本を含む場合は何も削除せずErrNotFound。
+func (sr *Shelf) DleteBooksWithCharts(ctx context.Context, authUserId string, books []*domain.Book) error {
 	bookIds := make([]int64, len(books))
+	unique := make(map[int64]struct{}, len(books))
 	for i, b := range books {
 		bookIds[i] = b.ID
+		unique[b.ID] = struct{}{}
 	}
 
 	//トランザクションの開始
@@ -160,8 +353,23 @@ func (sr *Shelf) DleteBooksWithCharts(ctx context.Context, books []*domain.Book)
 		}
 	}()
 
+	//削除イベント、監査ログのため、削除前の本を取得（削除済みの本は対象外）
+	var deleted []*domain.Book
+	err = tx.NewSelect().
+		Model(&deleted).
+		Where("id IN (?)", bun.In(bookIds)).
+		Where("auth_user_id = ?", authUserId).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(deleted) != len(unique) {
+		return utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+
 	//本の削除
-	err = tx.NewDelete().Model(&books).WherePK().Scan(ctx)
+	_, err = tx.NewDelete().Model(&deleted).WherePK().Where("auth_user_id = ?", authUserId).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -169,11 +377,33 @@ func (sr *Shelf) DleteBooksWithCharts(ctx context.Context, books []*domain.Book)
 	//bookIdをもとにチャートを削除
 	//NewDelete where inでは削除に失敗するので、仕方なく遠回りな実装となっている
 	var charts []*domain.Chart
-	err = tx.NewSelect().Model(&charts).Column("id").Where("book_id IN (?)", bun.In(bookIds)).Scan(ctx)
+	err = tx.NewSelect().
+		Model(&charts).
+		Column("id").
+		Where("book_id IN (?)", bun.In(bookIds)).
+		Where("auth_user_id = ?", authUserId).
+		Scan(ctx)
 	if err != nil {
 		return err
 	}
-	err = tx.NewDelete().Model(&charts).WherePK().Scan(ctx)
+	if len(charts) > 0 {
+		_, err = tx.NewDelete().Model(&charts).WherePK().Where("auth_user_id = ?", authUserId).Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	events := make([]*domain.Event, len(deleted))
+	logs := make([]*domain.AuditLog, len(deleted))
+	for i, b := range deleted {
+		events[i] = domain.NewBookEvent(domain.EventBookDeleted, b)
+		logs[i] = domain.NewAuditLog(ctx, domain.AuditDelete, b, nil)
+	}
+	err = insertEvents(ctx, tx, sr.cl.Now(), events...)
+	if err != nil {
+		return err
+	}
+	err = insertAuditLogs(ctx, tx, sr.cl.Now(), logs...)
 	if err != nil {
 		return err
 	}
@@ -185,3 +415,235 @@ func (sr *Shelf) DleteBooksWithCharts(ctx context.Context, books []*domain.Book)
 
 	return nil
 }
+
+// authUserIdをもとに削除済み（ゴミ箱内）の本を削除日時の新しい順に返す
+func (sr *Shelf) FindDeletedBooksByAuthUserID(ctx context.Context, authUserId string) ([]*domain.Book, error) {
+	books := []*domain.Book{}
+
+	err := sr.db.NewSelect().
+		Model(&books).
+		WhereDeleted().
+		Where("auth_user_id = ?", authUserId).
+		Order("deleted_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range books {
+		b.CreatedAt = b.CreatedAt.Local().In(utils.JST)
+		b.UpdatedAt = b.UpdatedAt.Local().In(utils.JST)
+		b.DeletedAt = b.DeletedAt.Local().In(utils.JST)
+	}
+
+	return books, nil
+}
+
+// 削除済みの本とbook_idで対応するチャートを復元
+func (sr *Shelf) RestoreBooksWithCharts(ctx context.Context, authUserId string, bookIds []int64) error {
+	tx, err := sr.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//本の復元（他のユーザーの本は対象外）
+	var restored []*domain.Book
+	_, err = tx.NewUpdate().
+		Model((*domain.Book)(nil)).
+		Set("deleted_at = NULL").
+		Set("updated_at = ?", sr.cl.Now()).
+		WhereDeleted().
+		Where("id IN (?)", bun.In(bookIds)).
+		Where("auth_user_id = ?", authUserId).
+		Returning("*").
+		Exec(ctx, &restored)
+	if err != nil {
+		return err
+	}
+	if len(restored) == 0 {
+		return utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+
+	//チャートの復元
+	_, err = tx.NewUpdate().
+		Model((*domain.Chart)(nil)).
+		Set("deleted_at = NULL").
+		WhereDeleted().
+		Where("book_id IN (?)", bun.In(bookIds)).
+		Where("auth_user_id = ?", authUserId).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	//復元した本は、他の端末では作成として扱う
+	events := make([]*domain.Event, len(restored))
+	logs := make([]*domain.AuditLog, len(restored))
+	for i, b := range restored {
+		events[i] = domain.NewBookEvent(domain.EventBookCreated, b)
+		logs[i] = domain.NewAuditLog(ctx, domain.AuditRestore, nil, b)
+	}
+	err = insertEvents(ctx, tx, sr.cl.Now(), events...)
+	if err != nil {
+		return err
+	}
+	err = insertAuditLogs(ctx, tx, sr.cl.Now(), logs...)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return nil
+}
+
+// beforeより前に削除された本とチャートを完全に削除し、削除した本の冊数を返す
+func (sr *Shelf) PurgeBooksWithCharts(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := sr.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	purged := tx.NewSelect().
+		Model((*domain.Book)(nil)).
+		Column("id").
+		WhereDeleted().
+		Where("deleted_at < ?", before)
+
+	//先に対応するチャート、変更履歴を削除
+	_, err = tx.NewDelete().
+		Model((*domain.Chart)(nil)).
+		WhereAllWithDeleted().
+		Where("book_id IN (?)", purged).
+		ForceDelete().
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.NewDelete().
+		Model((*domain.BookRevision)(nil)).
+		Where("book_id IN (?)", purged).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var books []*domain.Book
+	_, err = tx.NewDelete().
+		Model((*domain.Book)(nil)).
+		WhereDeleted().
+		Where("deleted_at < ?", before).
+		ForceDelete().
+		Returning("id, auth_user_id").
+		Exec(ctx, &books)
+	if err != nil {
+		return 0, err
+	}
+	logs := make([]*domain.AuditLog, len(books))
+	for i, b := range books {
+		logs[i] = domain.NewAuditLog(ctx, domain.AuditPurge, b, nil)
+	}
+	err = insertAuditLogs(ctx, tx, sr.cl.Now(), logs...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return int64(len(books)), nil
+}
+
+// 一括操作（バッチ）用のトランザクション。RunInTxで生成し、関数の終了時にコミットまたはロールバックする。
+type ShelfTx struct {
+	tx  bun.Tx
+	now time.Time
+}
+
+// fnをひとつのトランザクションで実行する。fnがエラーを返した場合はロールバックする。
+func (sr *Shelf) RunInTx(ctx context.Context, fn func(ctx context.Context, st *ShelfTx) error) error {
+	return sr.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		return fn(ctx, &ShelfTx{tx: tx, now: sr.cl.Now()})
+	})
+}
+
+// fnをセーブポイント内で実行する。fnがエラーを返した場合はfn内の変更のみ取り消す。
+func (st *ShelfTx) Savepoint(ctx context.Context, fn func(ctx context.Context, st *ShelfTx) error) error {
+	return st.tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(ctx, &ShelfTx{tx: tx, now: st.now})
+	})
+}
+
+// Shelf.CreateBookWithChartsと同じ（トランザクション内で実行）
+func (st *ShelfTx) CreateBookWithCharts(ctx context.Context, book *domain.Book, charts []*domain.Chart) error {
+	return createBookWithCharts(ctx, st.tx, st.now, book, charts)
+}
+
+// Shelf.UpdateBookWithChartsと同じ（トランザクション内で実行）
+func (st *ShelfTx) UpdateBookWithCharts(ctx context.Context, book *domain.Book) error {
+	return updateBookWithCharts(ctx, st.tx, st.now, book)
+}
+
+// 本のステータスのみ更新する。バージョンの扱いはUpdateBookWithChartsと同じ。
+func (st *ShelfTx) UpdateBookStatus(ctx context.Context, book *domain.Book) error {
+	return patchBookWithCharts(ctx, st.tx, st.now, book, []string{"book_status"})
+}
+
+// 本1冊とbook_idで対応するチャートを論理削除する。未登録、削除済み、他のユーザーの本はErrNotFound。
+func (st *ShelfTx) DeleteBookWithCharts(ctx context.Context, authUserId string, bookId int64) error {
+	book, err := findBookForAudit(ctx, st.tx, bookId, authUserId)
+	if err != nil {
+		return err
+	}
+	if book == nil {
+		return utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+	_, err = st.tx.NewDelete().Model(book).WherePK().Exec(ctx)
+	if err != nil {
+		return err
+	}
+	err = insertEvents(ctx, st.tx, st.now, domain.NewBookEvent(domain.EventBookDeleted, book))
+	if err != nil {
+		return err
+	}
+	err = insertAuditLogs(ctx, st.tx, st.now, domain.NewAuditLog(ctx, domain.AuditDelete, book, nil))
+	if err != nil {
+		return err
+	}
+
+	//DleteBooksWithChartsと同じく、idを取得してからチャートを削除する
+	var charts []*domain.Chart
+	err = st.tx.NewSelect().Model(&charts).Column("id").Where("book_id = ?", bookId).Scan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(charts) == 0 {
+		return nil
+	}
+	_, err = st.tx.NewDelete().Model(&charts).WherePK().Exec(ctx)
+	return err
+}
+
+// 更新後の本を取得し、beforeからの変更を監査ログに記録する。更新後の本を返す
+func auditBookUpdate(ctx context.Context, db bun.IDB, now time.Time, before *domain.Book) (*domain.Book, error) {
+	after, err := findBookForAudit(ctx, db, before.ID, before.AuthUserId)
+	if err != nil {
+		return nil, err
+	}
+	return after, insertAuditLogs(ctx, db, now, domain.NewAuditLog(ctx, domain.AuditUpdate, before, after))
+}
@@ -228,7 +228,7 @@ func TestUpdateBookWithCharts(t *testing.T) {
 		Price:      1000,
 		BookStatus: domain.Bought,
 		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
-		CreatedAt:  cl.Now(),
+		CreatedAt:  cl.Now().AddDate(0, -1, 0), //購入日の変更
 		UpdatedAt:  cl.Now(),
 	}
 	testutils.InsertTestData(ctx, t, bundb, book)
@@ -239,9 +239,188 @@ func TestUpdateBookWithCharts(t *testing.T) {
 
 	//Act
 	err = sut.UpdateBookWithCharts(ctx, updatedBook)
+	got := []*domain.Chart{}
+	errCharts := bundb.NewSelect().Model(&got).Where("book_id = ?", 1).Scan(ctx)
 
 	//Assert
 	a.Nil(err)
+	a.Nil(errCharts)
+	want := map[domain.ChartLabel]int{domain.ChartPrice: 1000, domain.ChartVolumes: 1, domain.ChartPages: 300}
+	if a.Len(got, 3) {
+		for _, c := range got {
+			a.Equal(want[c.Label], c.Data)
+			a.Equal(2024, c.Year) //購入日の年月に移す
+			a.Equal(1, c.Month)
+		}
+	}
+}
+
+func TestUpdateBookWithChartsNotFound(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       247,
+		Price:      980,
+		BookStatus: domain.Reading,
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+
+	tests := map[string]struct {
+		book *domain.Book
+	}{
+		"本がない": {
+			book: &domain.Book{ID: int64(100), Title: "予知夢", BookStatus: domain.Bought, AuthUserId: book.AuthUserId},
+		},
+		"他のユーザーの本": {
+			book: &domain.Book{ID: book.ID, Title: "予知夢", BookStatus: domain.Bought, AuthUserId: "2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e"},
+		},
+	}
+
+	sut := repository.NewShelf(bundb, cl)
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act
+			err := sut.UpdateBookWithCharts(ctx, tt.book)
+
+			//Assert
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+		})
+	}
+}
+
+func TestUpdateBookWithChartsWithVersion(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       247,
+		Price:      980,
+		BookStatus: domain.Reading,
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+
+	sut := repository.NewShelf(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	first := &domain.Book{ID: book.ID, Title: book.Title, Page: 300, BookStatus: domain.Bought, AuthUserId: book.AuthUserId, Version: 1}
+	errFirst := sut.UpdateBookWithCharts(ctx, first)
+	//同じバージョンでの2回目の更新は他の更新と競合する
+	second := &domain.Book{ID: book.ID, Title: book.Title, Page: 100, BookStatus: domain.Bought, AuthUserId: book.AuthUserId, Version: 1}
+	errSecond := sut.UpdateBookWithCharts(ctx, second)
+	//他のユーザーの本はバージョンに関わらずない扱い
+	other := &domain.Book{ID: book.ID, Title: book.Title, BookStatus: domain.Bought, AuthUserId: "2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e", Version: 2}
+	errOther := sut.UpdateBookWithCharts(ctx, other)
+
+	//Assert
+	a.Nil(errFirst)
+	a.Equal(int64(2), first.Version)
+	a.ErrorIs(errSecond, domain.ErrPreconditionFailed)
+	a.ErrorIs(errOther, domain.ErrNotFound)
+
+	got, err := sut.FindBooksByAuthUserID(ctx, book.AuthUserId)
+	a.Nil(err)
+	a.Equal(300, got[0].Page)
+	a.Equal(int64(2), got[0].Version)
+}
+
+func TestPatchBookWithCharts(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       247,
+		Price:      980,
+		BookStatus: domain.Reading,
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	sut := repository.NewShelf(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	//指定した列（title）以外は値が異なっていても更新しない
+	titleOnly := &domain.Book{ID: book.ID, Title: "予知夢", Price: 1, Page: 1, BookStatus: domain.Read, AuthUserId: book.AuthUserId, CreatedAt: book.CreatedAt, Version: 1}
+	errTitle := sut.PatchBookWithCharts(ctx, titleOnly, []string{"title"})
+	afterTitle, err := sut.FindBookByID(ctx, book.AuthUserId, book.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//価格の変更は図表に反映する
+	withPrice := *afterTitle
+	withPrice.Price = 1200
+	errPrice := sut.PatchBookWithCharts(ctx, &withPrice, []string{"price"})
+	gotCharts := []*domain.Chart{}
+	if err := bundb.NewSelect().Model(&gotCharts).Where("book_id = ?", book.ID).Where("label = ?", domain.ChartPrice).Scan(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert
+	a.Nil(errTitle)
+	a.Equal("予知夢", afterTitle.Title)
+	a.Equal(980, afterTitle.Price)
+	a.Equal(247, afterTitle.Page)
+	a.Equal(domain.Reading, afterTitle.BookStatus)
+	a.Equal(int64(2), afterTitle.Version)
+
+	a.Nil(errPrice)
+	a.Equal(int64(3), withPrice.Version)
+	a.Equal(1200, gotCharts[0].Data)
 }
 
 func TestDleteBooksWithCharts(t *testing.T) {
@@ -336,16 +515,256 @@ func TestDleteBooksWithCharts(t *testing.T) {
 			BookId:     int64(2),
 		},
 	}
+	//他のユーザーの本
+	other := &domain.Book{
+		ID:         int64(3),
+		Title:      "白夜行",
+		Page:       864,
+		Price:      1210,
+		BookStatus: domain.Bought,
+		AuthUserId: "0b6c1e5e-3a0b-4c0e-9d0e-6d1f2a3b4c5d",
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
 	testutils.InsertTestData(ctx, t, bundb, books...)
+	testutils.InsertTestData(ctx, t, bundb, other)
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	sut := repository.NewShelf(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	errOther := sut.DleteBooksWithCharts(ctx, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", []*domain.Book{{ID: 1}, {ID: 3}})
+	_, errFindOther := sut.FindBookByID(ctx, other.AuthUserId, other.ID)
+	_, errFindOwn := sut.FindBookByID(ctx, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", 1)
+	err = sut.DleteBooksWithCharts(ctx, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", books)
+
+	//Assert
+	a.ErrorIs(errOther, domain.ErrNotFound)
+	a.Nil(errFindOther) //他のユーザーの本を含む場合は何も削除しない
+	a.Nil(errFindOwn)
+	a.Nil(err)
+}
+
+func TestShelfRunInTx(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	book := &domain.Book{
+		ID:         int64(100),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       247,
+		Price:      980,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
 	testutils.InsertTestData(ctx, t, bundb, charts...)
 
+	sut := repository.NewShelf(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	//セーブポイント内の失敗（未登録の本の削除）は、その操作のみ取り消してコミットする
+	var errMissing error
+	errCommit := sut.RunInTx(ctx, func(ctx context.Context, st *repository.ShelfTx) error {
+		err := st.Savepoint(ctx, func(ctx context.Context, st *repository.ShelfTx) error {
+			return st.UpdateBookStatus(ctx, &domain.Book{ID: book.ID, AuthUserId: authUserId, BookStatus: domain.Read})
+		})
+		if err != nil {
+			return err
+		}
+		errMissing = st.Savepoint(ctx, func(ctx context.Context, st *repository.ShelfTx) error {
+			return st.DeleteBookWithCharts(ctx, authUserId, 999)
+		})
+		return nil
+	})
+	committed, err := sut.FindBookByID(ctx, authUserId, book.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//エラーを返した場合はすべて取り消す
+	errRollback := sut.RunInTx(ctx, func(ctx context.Context, st *repository.ShelfTx) error {
+		if err := st.DeleteBookWithCharts(ctx, authUserId, book.ID); err != nil {
+			return err
+		}
+		return st.DeleteBookWithCharts(ctx, "2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e", book.ID)
+	})
+	rolledBack, err := sut.FindBookByID(ctx, authUserId, book.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chartCount, err := bundb.NewSelect().Model((*domain.Chart)(nil)).Where("book_id = ?", book.ID).Count(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert
+	a.Nil(errCommit)
+	a.ErrorIs(errMissing, domain.ErrNotFound)
+	a.Equal(domain.Read, committed.BookStatus)
+	a.Equal(int64(2), committed.Version)
+
+	a.ErrorIs(errRollback, domain.ErrNotFound)
+	a.Equal(committed.Version, rolledBack.Version)
+	a.Equal(len(charts), chartCount)
+}
+
+func TestRestoreBooksWithCharts(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       247,
+		Price:      980,
+		BookStatus: domain.Reading,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	sut := repository.NewShelf(bundb, cl)
+	err = sut.DleteBooksWithCharts(ctx, authUserId, []*domain.Book{{ID: book.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cr := repository.NewChart(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	trashed, errTrash := sut.FindDeletedBooksByAuthUserID(ctx, authUserId)
+	chartsInTrash, errCharts := cr.FindChartsByAuthUserId(ctx, authUserId)
+	err = sut.RestoreBooksWithCharts(ctx, authUserId, []int64{book.ID})
+	errNotFound := sut.RestoreBooksWithCharts(ctx, authUserId, []int64{book.ID})
+
+	//Assert
+	a.Nil(errTrash)
+	a.Len(trashed, 1)
+	a.False(trashed[0].DeletedAt.IsZero())
+	a.Nil(errCharts)
+	a.Empty(chartsInTrash)
+	a.Nil(err)
+	a.ErrorIs(errNotFound, domain.ErrNotFound)
+	got, err := sut.FindBooksByAuthUserID(ctx, authUserId)
+	a.Nil(err)
+	a.Len(got, 1)
+	restored, err := cr.FindChartsByAuthUserId(ctx, authUserId)
+	a.Nil(err)
+	a.Len(restored, 3)
+}
+
+func TestPurgeBooksWithCharts(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	books := []*domain.Book{
+		{
+			ID:         int64(1),
+			Title:      "容疑者Xの献身",
+			BookStatus: domain.Reading,
+			AuthUserId: authUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+			DeletedAt:  cl.Now().AddDate(0, 0, -40),
+		},
+		{
+			ID:         int64(2),
+			Title:      "ガリレオの苦悩",
+			BookStatus: domain.Read,
+			AuthUserId: authUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+			DeletedAt:  cl.Now().AddDate(0, 0, -10),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, books...)
+	charts := []*domain.Chart{
+		{
+			Label:      domain.ChartVolumes,
+			Year:       2024,
+			Month:      2,
+			Data:       1,
+			AuthUserId: authUserId,
+			BookId:     int64(1),
+			DeletedAt:  cl.Now().AddDate(0, 0, -40),
+		},
+		{
+			Label:      domain.ChartVolumes,
+			Year:       2024,
+			Month:      2,
+			Data:       1,
+			AuthUserId: authUserId,
+			BookId:     int64(2),
+			DeletedAt:  cl.Now().AddDate(0, 0, -10),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
 	sut := repository.NewShelf(bundb, cl)
 
 	a := assert.New(t)
 
 	//Act
-	err = sut.DleteBooksWithCharts(ctx, books)
+	n, err := sut.PurgeBooksWithCharts(ctx, cl.Now().AddDate(0, 0, -30))
 
 	//Assert
 	a.Nil(err)
+	a.Equal(int64(1), n)
+	got, err := sut.FindDeletedBooksByAuthUserID(ctx, authUserId)
+	a.Nil(err)
+	a.Len(got, 1)
+	a.Equal(int64(2), got[0].ID)
+	count, err := bundb.NewSelect().Model((*domain.Chart)(nil)).WhereAllWithDeleted().Count(ctx)
+	a.Nil(err)
+	a.Equal(1, count)
 }
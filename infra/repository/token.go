@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// パスワード再設定、メールアドレス確認のトークン（user_tokensテーブル）を操作する
+type Token struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewToken(db *bun.DB, cl utils.Clock) *Token {
+	return &Token{db: db, cl: cl}
+}
+
+// トークンを登録する。有効期限は登録時刻にttlを加えた日時
+func (tr *Token) CreateToken(ctx context.Context, t *domain.UserToken, ttl time.Duration) error {
+	now := tr.cl.Now()
+	t.CreatedAt = now
+	t.ExpiresAt = now.Add(ttl)
+	_, err := tr.db.NewInsert().Model(t).Exec(ctx)
+	return err
+}
+
+// emailに対してwindowの間に発行したpurposeのトークンがlimit件未満の場合のみ登録し、登録した場合はtrueを返す。
+// 同じメールアドレスへの同時の発行は直列にする（pg_advisory_xact_lock）。
+func (tr *Token) CreateTokenWithinLimit(ctx context.Context, t *domain.UserToken, ttl time.Duration, limit int, window time.Duration) (bool, error) {
+	now := tr.cl.Now()
+	created := false
+	err := tr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", string(t.Purpose)+":"+string(t.Email)); err != nil {
+			return err
+		}
+		n, err := tx.NewSelect().
+			Model((*domain.UserToken)(nil)).
+			Where("purpose = ?", t.Purpose).
+			Where("email = ?", t.Email).
+			Where("created_at > ?", now.Add(-window)).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+		if n >= limit {
+			return nil
+		}
+		t.CreatedAt = now
+		t.ExpiresAt = now.Add(ttl)
+		if _, err := tx.NewInsert().Model(t).Exec(ctx); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// トークンでパスワードを再設定する。passwordはハッシュ化済みのもの。
+// トークンは1回のみ使用でき、使用時に同じユーザーの他の未使用の再設定のトークンも無効にする。
+func (tr *Token) ResetPassword(ctx context.Context, hash string, password domain.Password) error {
+	now := tr.cl.Now()
+	return tr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		t, err := useToken(ctx, tx, domain.TokenPasswordReset, hash, now)
+		if err != nil {
+			return err
+		}
+		before, err := findUserForAudit(ctx, tx, "auth_user_id", t.AuthUserId)
+		if err != nil {
+			return err
+		}
+		res, err := tx.NewUpdate().
+			Model((*domain.User)(nil)).
+			Set("password = ?", password).
+			Set("version = ?TableAlias.version + 1").
+			Set("updated_at = ?", now).
+			Where("auth_user_id = ?", t.AuthUserId).
+			Where("email = ?", t.Email).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return domain.ErrInvalidToken //発行後にユーザーの削除、メールアドレスの変更があった
+		}
+		if err := auditUserUpdate(ctx, tx, now, before); err != nil {
+			return err
+		}
+		_, err = tx.NewUpdate().
+			Model((*domain.UserToken)(nil)).
+			Set("used_at = ?", now).
+			Where("auth_user_id = ?", t.AuthUserId).
+			Where("purpose = ?", domain.TokenPasswordReset).
+			Where("used_at IS NULL").
+			Exec(ctx)
+		return err
+	})
+}
+
+// トークンでメールアドレスを確認済みにする。トークンは1回のみ使用できる
+func (tr *Token) VerifyEmail(ctx context.Context, hash string) error {
+	now := tr.cl.Now()
+	return tr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		t, err := useToken(ctx, tx, domain.TokenEmailVerification, hash, now)
+		if err != nil {
+			return err
+		}
+		before, err := findUserForAudit(ctx, tx, "auth_user_id", t.AuthUserId)
+		if err != nil {
+			return err
+		}
+		res, err := tx.NewUpdate().
+			Model((*domain.User)(nil)).
+			Set("email_verified_at = ?", now).
+			Where("auth_user_id = ?", t.AuthUserId).
+			Where("email = ?", t.Email).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return domain.ErrInvalidToken
+		}
+		return auditUserUpdate(ctx, tx, now, before)
+	})
+}
+
+// 有効期限内の未使用のトークンを使用済みにして返す。同時に使用しても1回のみ成功する
+func useToken(ctx context.Context, tx bun.Tx, purpose domain.TokenPurpose, hash string, now time.Time) (*domain.UserToken, error) {
+	t := new(domain.UserToken)
+	err := tx.NewUpdate().
+		Model(t).
+		Set("used_at = ?", now).
+		Where("token_hash = ?", hash).
+		Where("purpose = ?", purpose).
+		Where("used_at IS NULL").
+		Where("expires_at > ?", now).
+		Returning("*").
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,150 @@
+package repository_test
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestTokenResetPassword(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Email: "tanaka@example.com", Password: "old", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+	sut := repository.NewToken(bundb, cl)
+	ur := repository.NewUser(bundb, cl)
+	newToken := func(hash string, ttl time.Duration) *domain.UserToken {
+		tk := &domain.UserToken{AuthUserId: user.AuthUserId, Purpose: domain.TokenPasswordReset, TokenHash: hash, Email: user.Email}
+		if err := sut.CreateToken(ctx, tk, ttl); err != nil {
+			t.Fatal(err)
+		}
+		return tk
+	}
+	newToken("first", time.Hour)
+	newToken("second", time.Hour)
+	newToken("expired", -time.Minute)
+	a := assert.New(t)
+
+	//Act
+	errExpired := sut.ResetPassword(ctx, "expired", "new")
+	errReset := sut.ResetPassword(ctx, "first", "new")
+	errReused := sut.ResetPassword(ctx, "first", "other")
+	errOther := sut.ResetPassword(ctx, "second", "other") //再設定後は他のトークンも無効
+	got, err := ur.FindUserByAuthUserId(ctx, user.AuthUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert
+	a.ErrorIs(errExpired, domain.ErrInvalidToken)
+	a.Nil(errReset)
+	a.ErrorIs(errReused, domain.ErrInvalidToken)
+	a.ErrorIs(errOther, domain.ErrInvalidToken)
+	a.Equal(domain.Password("new"), got.Password)
+	a.Equal(int64(2), got.Version)
+}
+
+func TestTokenCreateTokenWithinLimit(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewToken(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	got := []bool{}
+	for _, hash := range []string{"1", "2", "3"} {
+		tk := &domain.UserToken{AuthUserId: "user", Purpose: domain.TokenPasswordReset, TokenHash: hash, Email: "tanaka@example.com"}
+		created, err := sut.CreateTokenWithinLimit(ctx, tk, time.Hour, 2, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, created)
+	}
+	other, errOther := sut.CreateTokenWithinLimit(ctx, &domain.UserToken{AuthUserId: "other", Purpose: domain.TokenPasswordReset, TokenHash: "4", Email: "other@example.com"}, time.Hour, 2, time.Hour)
+
+	//Assert
+	a.Equal([]bool{true, true, false}, got)
+	a.Nil(errOther)
+	a.True(other) //上限はメールアドレスごと
+}
+
+func TestTokenVerifyEmail(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+	sut := repository.NewToken(bundb, cl)
+	ur := repository.NewUser(bundb, cl)
+	for _, hash := range []string{"verify", "stale"} {
+		tk := &domain.UserToken{AuthUserId: user.AuthUserId, Purpose: domain.TokenEmailVerification, TokenHash: hash, Email: user.Email}
+		if err := sut.CreateToken(ctx, tk, domain.EmailVerificationTTL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := assert.New(t)
+
+	//Act
+	errWrongPurpose := sut.ResetPassword(ctx, "verify", "new")
+	errVerify := sut.VerifyEmail(ctx, "verify")
+	verified, err := ur.FindUserByAuthUserId(ctx, user.AuthUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//メールアドレスを変更すると確認済みでなくなり、変更前に発行したトークンも使えない
+	verified.Email = "tanaka@example.org"
+	if err := ur.UpdateUser(ctx, verified); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := ur.FindUserByAuthUserId(ctx, user.AuthUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errStale := sut.VerifyEmail(ctx, "stale")
+
+	//Assert
+	a.ErrorIs(errWrongPurpose, domain.ErrInvalidToken)
+	a.Nil(errVerify)
+	a.True(verified.EmailVerifiedAt.Equal(cl.Now()))
+	a.True(changed.EmailVerifiedAt.IsZero())
+	a.ErrorIs(errStale, domain.ErrInvalidToken)
+}
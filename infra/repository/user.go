@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"time"
 
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/utils"
@@ -29,8 +31,9 @@ func (ur *User) FindUserByAuthUserId(ctx context.Context, authUserId string) (*d
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, utils.NewErrChains(utils.ErrNotFound, err)
+			return nil, utils.NewErrChains(domain.ErrNotFound, err)
 		}
+		return nil, err
 	}
 
 	user.CreatedAt = user.CreatedAt.In(utils.JST)
@@ -39,7 +42,23 @@ func (ur *User) FindUserByAuthUserId(ctx context.Context, authUserId string) (*d
 	return user, nil
 }
 
+// メールアドレスでユーザー情報を取得（削除済みのユーザーを除く）
+func (ur *User) FindUserByEmail(ctx context.Context, email domain.Email) (*domain.User, error) {
+	user := new(domain.User)
+
+	err := ur.db.NewSelect().Model(user).Where("email = ?", email).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, utils.NewErrChains(domain.ErrNotFound, err)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func (ur *User) CreateUser(ctx context.Context, user *domain.User) (userId int64, err error) {
+	user.Version = 1
 	user.CreatedAt = ur.cl.Now()
 	user.UpdatedAt = ur.cl.Now()
 
@@ -57,6 +76,10 @@ func (ur *User) CreateUser(ctx context.Context, user *domain.User) (userId int64
 	if err != nil {
 		return 0, fmt.Errorf("userの登録に失敗:%w", err)
 	}
+	err = insertAuditLogs(ctx, tx, user.CreatedAt, domain.NewAuditLog(ctx, domain.AuditCreate, nil, user))
+	if err != nil {
+		return 0, err
+	}
 
 	err = tx.Commit()
 	if err != nil {
@@ -66,8 +89,14 @@ func (ur *User) CreateUser(ctx context.Context, user *domain.User) (userId int64
 	return userId, nil
 }
 
+// ユーザー情報の更新。user.Versionが0以外の場合は、登録済みのバージョンと一致する場合のみ更新する（不一致はErrPreconditionFailed）。
+// 更新後のバージョンはuser.Versionに反映される。
+// メールアドレスの確認日時は、メールアドレスが変わる場合のみクリアする
+const emailVerifiedAtExpr = "CASE WHEN ?TableAlias.email = ? THEN ?TableAlias.email_verified_at END"
+
 func (ur *User) UpdateUser(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = ur.cl.Now()
+	user.HomeCurrency = user.HomeCurrency.OrDefault()
 
 	tx, err := ur.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
@@ -79,7 +108,41 @@ func (ur *User) UpdateUser(ctx context.Context, user *domain.User) error {
 		}
 	}()
 
-	_, err = tx.NewUpdate().Model(user).WherePK().Exec(ctx)
+	before, err := findUserForAudit(ctx, tx, "id", user.ID)
+	if err != nil {
+		return err
+	}
+	//無効化されたユーザーは変更できない（PUT /usersはパスにauthUserIdがなく、ミドルウェアで拒否できないため）
+	if before != nil && before.Disabled() {
+		return utils.NewErrChains(domain.ErrAccountDisabled, nil)
+	}
+
+	//バージョンは更新ごとに1増やし、指定がある場合（If-Match）は一致する場合のみ更新する。
+	//役割、無効化は管理APIのみで変更する。パスワードが空の場合は変更しない
+	excluded := []string{"role", "disabled_at"}
+	if user.Password == "" {
+		excluded = append(excluded, "password")
+	}
+	version := user.Version
+	q := tx.NewUpdate().
+		Model(user).
+		WherePK().
+		ExcludeColumn(excluded...).
+		Value("version", "?TableAlias.version + 1").
+		Value("email_verified_at", emailVerifiedAtExpr, user.Email).
+		Returning("version")
+	if version > 0 {
+		q = q.Where("?TableAlias.version = ?", version)
+	}
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return err
+	}
+	//更新対象がない（未登録、削除済み）、またはバージョンが異なる場合は成功扱いにしない
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errNoRowsUpdated(ctx, tx.NewSelect().Model((*domain.User)(nil)).Where("id = ?", user.ID), version)
+	}
+	err = auditUserUpdate(ctx, tx, user.UpdatedAt, before)
 	if err != nil {
 		return err
 	}
@@ -92,6 +155,62 @@ func (ur *User) UpdateUser(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// ユーザー情報の部分更新（PATCH）。columns（bunの列名）のみ更新する。
+// バージョンの扱いはUpdateUserと同じ。更新後のバージョンはuser.Versionに反映される。
+func (ur *User) PatchUser(ctx context.Context, user *domain.User, columns []string) error {
+	user.UpdatedAt = ur.cl.Now()
+	user.HomeCurrency = user.HomeCurrency.OrDefault()
+
+	tx, err := ur.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	before, err := findUserForAudit(ctx, tx, "id", user.ID)
+	if err != nil {
+		return err
+	}
+
+	version := user.Version
+	q := tx.NewUpdate().
+		Model(user).
+		Column(columns...).
+		Column("updated_at", "version").
+		WherePK().
+		Value("version", "?TableAlias.version + 1").
+		Returning("version")
+	if slices.Contains(columns, "email") {
+		q = q.Column("email_verified_at").Value("email_verified_at", emailVerifiedAtExpr, user.Email)
+	}
+	if version > 0 {
+		q = q.Where("?TableAlias.version = ?", version)
+	}
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return errNoRowsUpdated(ctx, tx.NewSelect().Model((*domain.User)(nil)).Where("id = ?", user.ID), version)
+	}
+	err = auditUserUpdate(ctx, tx, user.UpdatedAt, before)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return nil
+}
+
+// ユーザーの論理削除。保持期間内であればRestoreUserで復元できる。
 func (ur *User) DleteUser(ctx context.Context, user *domain.User) error {
 	tx, err := ur.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
@@ -103,10 +222,20 @@ func (ur *User) DleteUser(ctx context.Context, user *domain.User) error {
 		}
 	}()
 
+	before, err := findUserForAudit(ctx, tx, "id", user.ID)
+	if err != nil {
+		return err
+	}
 	_, err = tx.NewDelete().Model(user).WherePK().Exec(ctx)
 	if err != nil {
 		return err
 	}
+	if before != nil {
+		err = insertAuditLogs(ctx, tx, ur.cl.Now(), domain.NewAuditLog(ctx, domain.AuditDelete, before, nil))
+		if err != nil {
+			return err
+		}
+	}
 
 	err = tx.Commit()
 	if err != nil {
@@ -115,3 +244,302 @@ func (ur *User) DleteUser(ctx context.Context, user *domain.User) error {
 
 	return nil
 }
+
+// 削除済み（ゴミ箱内）のユーザー情報の取得
+func (ur *User) FindDeletedUserByAuthUserId(ctx context.Context, authUserId string) (*domain.User, error) {
+	user := new(domain.User)
+
+	err := ur.db.NewSelect().Model(user).WhereDeleted().Where("auth_user_id = ?", authUserId).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, utils.NewErrChains(domain.ErrNotFound, err)
+		}
+		return nil, err
+	}
+
+	user.CreatedAt = user.CreatedAt.In(utils.JST)
+	user.UpdatedAt = user.UpdatedAt.In(utils.JST)
+	user.DeletedAt = user.DeletedAt.In(utils.JST)
+
+	return user, nil
+}
+
+// 削除済みのユーザーと、ユーザーと同時に削除された本、チャートを復元
+func (ur *User) RestoreUser(ctx context.Context, authUserId string) error {
+	tx, err := ur.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := new(domain.User)
+	err = tx.NewSelect().Model(user).WhereDeleted().Where("auth_user_id = ?", authUserId).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return utils.NewErrChains(domain.ErrNotFound, err)
+		}
+		return err
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*domain.User)(nil)).
+		Set("deleted_at = NULL").
+		Set("updated_at = ?", ur.cl.Now()).
+		WhereDeleted().
+		Where("id = ?", user.ID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = insertAuditLogs(ctx, tx, ur.cl.Now(), domain.NewAuditLog(ctx, domain.AuditRestore, nil, user))
+	if err != nil {
+		return err
+	}
+
+	//ユーザーより前に個別で削除された本はゴミ箱に残す
+	for _, model := range []any{(*domain.Book)(nil), (*domain.Chart)(nil)} {
+		_, err = tx.NewUpdate().
+			Model(model).
+			Set("deleted_at = NULL").
+			WhereDeleted().
+			Where("auth_user_id = ?", authUserId).
+			Where("deleted_at = ?", user.DeletedAt).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return nil
+}
+
+// ユーザーと本、チャート、目標をまとめて削除し、削除したデータ一式を返す。
+// immediateがfalseの場合は論理削除（目標は完全削除時まで残す）、trueの場合は完全に削除する。
+// archiveはコミット前に削除したデータ一式を渡して呼び出し、エラーを返した場合は削除を取り消す（アーカイブを返せないまま削除しない）。
+func (ur *User) DeleteUserWithData(ctx context.Context, authUserId string, immediate bool, archive func(export *domain.UserExport) error) (*domain.UserExport, error) {
+	now := ur.cl.Now()
+
+	tx, err := ur.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//削除対象のデータの取得（ゴミ箱内の本も含む）
+	export := &domain.UserExport{User: new(domain.User), DeletedAt: now}
+	err = tx.NewSelect().Model(export.User).Where("auth_user_id = ?", authUserId).For("UPDATE").Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, utils.NewErrChains(domain.ErrNotFound, err)
+		}
+		return nil, err
+	}
+	export.Books = []*domain.Book{}
+	err = tx.NewSelect().Model(&export.Books).WhereAllWithDeleted().Where("auth_user_id = ?", authUserId).Order("id ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	export.Charts = []*domain.Chart{}
+	err = tx.NewSelect().Model(&export.Charts).WhereAllWithDeleted().Where("auth_user_id = ?", authUserId).Order("id ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	export.Goals = []*domain.Goal{}
+	err = tx.NewSelect().Model(&export.Goals).Where("auth_user_id = ?", authUserId).Order("id ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	action := domain.AuditDelete
+	if immediate {
+		action = domain.AuditPurge
+		err = forceDeleteUserData(ctx, tx, export.User)
+	} else {
+		err = softDeleteUserData(ctx, tx, authUserId, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	//本、チャートの削除はユーザーの削除に含める
+	err = insertAuditLogs(ctx, tx, now, domain.NewAuditLog(ctx, action, export.User, nil))
+	if err != nil {
+		return nil, err
+	}
+	if err := archive(export); err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return export, nil
+}
+
+// 論理削除せず、ユーザーの完全削除時に削除するデータ（目標、メールの設定、トークン、APIキー、本の変更履歴、
+// Webhookとその配信、アウトボックス、イベント）。
+// 監査ログは削除せず、anonymizeUserRecordsで匿名化する。完全削除の記録（識別子のみ）も監査ログに残すため
+var userSettingModels = []any{
+	(*domain.Goal)(nil), (*domain.MailSetting)(nil), (*domain.UserToken)(nil), (*domain.APIKey)(nil), (*domain.BookRevision)(nil),
+	(*domain.WebhookDelivery)(nil), (*domain.Webhook)(nil), (*domain.OutboxMessage)(nil), (*domain.Event)(nil),
+}
+
+// ユーザーと本、チャートに同じ削除日時を記録する（復元時に同時に削除されたものを判別するため）
+func softDeleteUserData(ctx context.Context, tx bun.Tx, authUserId string, now time.Time) error {
+	for _, model := range []any{(*domain.Chart)(nil), (*domain.Book)(nil), (*domain.User)(nil)} {
+		_, err := tx.NewUpdate().
+			Model(model).
+			Set("deleted_at = ?", now).
+			Where("auth_user_id = ?", authUserId).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func forceDeleteUserData(ctx context.Context, tx bun.Tx, user *domain.User) error {
+	authUserId := user.AuthUserId
+	for _, model := range userSettingModels {
+		_, err := tx.NewDelete().Model(model).Where("auth_user_id = ?", authUserId).Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if err := anonymizeUserRecords(ctx, tx, user); err != nil {
+		return err
+	}
+	for _, model := range []any{(*domain.Chart)(nil), (*domain.Book)(nil), (*domain.User)(nil)} {
+		_, err := tx.NewDelete().
+			Model(model).
+			WhereAllWithDeleted().
+			Where("auth_user_id = ?", authUserId).
+			ForceDelete().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// beforeより前に削除されたユーザーとuserSettingModelsのデータを完全に削除し、削除したユーザーの件数を返す。
+// ユーザーの本、チャートは同じ削除日時が記録されているためShelf.PurgeBooksWithChartsで削除される。
+func (ur *User) PurgeUsers(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := ur.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("トランザクションの生成に失敗:%w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	purged := tx.NewSelect().
+		Model((*domain.User)(nil)).
+		Column("auth_user_id").
+		WhereDeleted().
+		Where("deleted_at < ?", before)
+
+	for _, model := range userSettingModels {
+		_, err = tx.NewDelete().
+			Model(model).
+			Where("auth_user_id IN (?)", purged).
+			Exec(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var users []*domain.User
+	_, err = tx.NewDelete().
+		Model((*domain.User)(nil)).
+		WhereDeleted().
+		Where("deleted_at < ?", before).
+		ForceDelete().
+		Returning("auth_user_id, email").
+		Exec(ctx, &users)
+	if err != nil {
+		return 0, err
+	}
+	logs := make([]*domain.AuditLog, len(users))
+	for i, u := range users {
+		if err := anonymizeUserRecords(ctx, tx, u); err != nil {
+			return 0, err
+		}
+		logs[i] = domain.NewAuditLog(ctx, domain.AuditPurge, u, nil)
+	}
+	err = insertAuditLogs(ctx, tx, ur.cl.Now(), logs...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("コミット失敗:%w", err)
+	}
+
+	return int64(len(users)), nil
+}
+
+// 完全削除するユーザーの個人情報を、削除しない記録から除く。
+// メールアドレスをキーにしたログインの失敗の記録は削除し、監査ログは値とIPアドレスを消して変更した項目のみ残す。
+// 監査ログの更新はDBのトリガーで拒否するため、トランザクション内でのみbhapi.audit_anonymizeを設定して許可する。
+func anonymizeUserRecords(ctx context.Context, tx bun.Tx, user *domain.User) error {
+	_, err := tx.NewDelete().
+		Model((*domain.AuthFailure)(nil)).
+		Where("scope = ?", domain.LockoutAccount).
+		Where("key = ?", domain.LockoutEmailKey(user.Email)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	logs := []*domain.AuditLog{}
+	err = tx.NewSelect().Model(&logs).Where("auth_user_id = ?", user.AuthUserId).For("UPDATE").Scan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "SET LOCAL bhapi.audit_anonymize = 'on'"); err != nil {
+		return err
+	}
+	for _, l := range logs {
+		l.Diff = l.Diff.Anonymize()
+		l.IP = ""
+		_, err = tx.NewUpdate().Model(l).Column("diff", "ip").WherePK().Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, "SET LOCAL bhapi.audit_anonymize = 'off'")
+	return err
+}
+
+// 更新後のユーザーを取得し、beforeからの変更を監査ログに記録する
+func auditUserUpdate(ctx context.Context, db bun.IDB, now time.Time, before *domain.User) error {
+	after, err := findUserForAudit(ctx, db, "id", before.ID)
+	if err != nil {
+		return err
+	}
+	return insertAuditLogs(ctx, db, now, domain.NewAuditLog(ctx, domain.AuditUpdate, before, after))
+}
@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/taimats/bhapi/infra"
 	"github.com/taimats/bhapi/infra/repository"
 	"github.com/taimats/bhapi/testutils"
+	"github.com/uptrace/bun"
 )
 
 func TestCreateUser(t *testing.T) {
@@ -99,7 +101,7 @@ func TestUpdateUser(t *testing.T) {
 		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
 		Name:       "",
 		Email:      domain.Email("example@example.com"),
-		Password:   domain.Password(""),
+		Password:   domain.Password("$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"),
 		CreatedAt:  cl.Now(),
 		UpdatedAt:  cl.Now(),
 	}
@@ -121,9 +123,138 @@ func TestUpdateUser(t *testing.T) {
 
 	//Act
 	err = sut.UpdateUser(ctx, updatedUser)
+	got, errFind := sut.FindUserByAuthUserId(ctx, user.AuthUserId)
 
 	//Assert
 	a.Nil(err)
+	a.Nil(errFind)
+	a.Equal("update", got.Name)
+	a.Equal(user.Password, got.Password) //パスワードが空の場合は変更しない
+}
+
+func TestUpdateUserWithVersion(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{
+		ID:         int64(1),
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		Email:      domain.Email("example@example.com"),
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, user)
+
+	sut := repository.NewUser(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	first := &domain.User{ID: user.ID, AuthUserId: user.AuthUserId, Name: "update", Email: user.Email, Version: 1}
+	errFirst := sut.UpdateUser(ctx, first)
+	//同じバージョンでの2回目の更新は他の更新と競合する
+	second := &domain.User{ID: user.ID, AuthUserId: user.AuthUserId, Name: "stale", Email: user.Email, Version: 1}
+	errSecond := sut.UpdateUser(ctx, second)
+
+	//Assert
+	a.Nil(errFirst)
+	a.Equal(int64(2), first.Version)
+	a.ErrorIs(errSecond, domain.ErrPreconditionFailed)
+
+	got, err := sut.FindUserByAuthUserId(ctx, user.AuthUserId)
+	a.Nil(err)
+	a.Equal("update", got.Name)
+	a.Equal(int64(2), got.Version)
+}
+
+func TestFindUserByAuthUserIdNotFound(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut := repository.NewUser(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	got, err := sut.FindUserByAuthUserId(ctx, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
+
+	//Assert
+	a.ErrorIs(err, domain.ErrNotFound)
+	a.Nil(got)
+}
+
+func TestFindUserByAuthUserIdWithDBError(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//接続を閉じてDBのエラーを発生させる
+	if err := bundb.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sut := repository.NewUser(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	got, err := sut.FindUserByAuthUserId(ctx, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
+
+	//Assert
+	a.Error(err)
+	a.NotErrorIs(err, domain.ErrNotFound)
+	a.Nil(got)
+}
+
+func TestUpdateUserNotFound(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{
+		ID:         int64(100),
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		Email:      domain.Email("example@example.com"),
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	sut := repository.NewUser(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	err = sut.UpdateUser(ctx, user)
+
+	//Assert
+	a.ErrorIs(err, domain.ErrNotFound)
 }
 
 func TestDeleteUser(t *testing.T) {
@@ -161,3 +292,290 @@ func TestDeleteUser(t *testing.T) {
 	//Assert
 	a.Nil(err)
 }
+
+func TestRestoreUser(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{
+		ID:         int64(1),
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		Email:      domain.Email("example@example.com"),
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, user)
+
+	sut := repository.NewUser(bundb, cl)
+	err = sut.DleteUser(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := assert.New(t)
+
+	//Act
+	deleted, errDeleted := sut.FindDeletedUserByAuthUserId(ctx, user.AuthUserId)
+	err = sut.RestoreUser(ctx, user.AuthUserId)
+	errNotFound := sut.RestoreUser(ctx, user.AuthUserId)
+
+	//Assert
+	a.Nil(errDeleted)
+	a.Equal(user.Email, deleted.Email)
+	a.Nil(err)
+	a.ErrorIs(errNotFound, domain.ErrNotFound)
+	got, err := sut.FindUserByAuthUserId(ctx, user.AuthUserId)
+	a.Nil(err)
+	a.True(got.DeletedAt.IsZero())
+}
+
+func TestPurgeUsers(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{
+		ID:         int64(1),
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		Email:      domain.Email("example@example.com"),
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+		DeletedAt:  cl.Now().AddDate(0, 0, -40),
+	}
+	testutils.InsertTestData(ctx, t, bundb, user)
+	webhook := &domain.Webhook{ID: 1, AuthUserId: user.AuthUserId, URL: "https://example.com/hook", Secret: "secret", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, webhook)
+	outbox := &domain.OutboxMessage{ID: 1, AuthUserId: user.AuthUserId, Type: domain.EventBookCreated, Payload: json.RawMessage(`{}`), CreatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, outbox)
+	delivery := &domain.WebhookDelivery{WebhookId: webhook.ID, OutboxId: outbox.ID, AuthUserId: user.AuthUserId, Type: domain.EventBookCreated, Payload: json.RawMessage(`{}`), NextAttemptAt: cl.Now(), CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, delivery)
+	event := &domain.Event{AuthUserId: user.AuthUserId, Type: domain.EventBookCreated, BookId: 1, Version: 1, CreatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, event)
+	testutils.InsertTestData(ctx, t, bundb, &domain.AuthFailure{Scope: domain.LockoutAccount, Key: string(user.Email), Failures: 1, LastFailedAt: cl.Now()})
+	sut := repository.NewUser(bundb, cl)
+
+	a := assert.New(t)
+
+	//Act
+	n, err := sut.PurgeUsers(ctx, cl.Now().AddDate(0, 0, -30))
+	found := rowsContaining(ctx, t, bundb, string(user.Email))
+
+	//Assert
+	a.Nil(err)
+	a.Equal(int64(1), n)
+	_, err = sut.FindDeletedUserByAuthUserId(ctx, user.AuthUserId)
+	a.ErrorIs(err, domain.ErrNotFound)
+	for _, model := range []any{(*domain.Webhook)(nil), (*domain.WebhookDelivery)(nil), (*domain.OutboxMessage)(nil), (*domain.Event)(nil)} {
+		remaining, err := bundb.NewSelect().Model(model).Where("auth_user_id = ?", user.AuthUserId).Count(ctx)
+		a.Nil(err)
+		a.Equal(0, remaining)
+	}
+	//監査ログは完全削除の記録を含めて残す
+	logs, err := bundb.NewSelect().Model((*domain.AuditLog)(nil)).Where("auth_user_id = ?", user.AuthUserId).Where("action = ?", domain.AuditPurge).Count(ctx)
+	a.Nil(err)
+	a.Equal(1, logs)
+	a.Empty(found) //メールアドレスはどのテーブルにも残らない
+}
+
+func TestDeleteUserWithData(t *testing.T) {
+	tests := map[string]struct {
+		immediate bool
+	}{
+		"論理削除": {immediate: false},
+		"即時削除": {immediate: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Arrange
+			ctx := context.Background()
+			dbctr.Restore(ctx, t)
+			bundb, err := infra.NewBunDB(dbctr.Dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				if err := bundb.Close(); err != nil {
+					log.Println(err)
+				}
+			}()
+
+			authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+			user := &domain.User{
+				AuthUserId: authUserId,
+				Email:      domain.Email("example@example.com"),
+				CreatedAt:  cl.Now(),
+				UpdatedAt:  cl.Now(),
+			}
+			testutils.InsertTestData(ctx, t, bundb, user)
+			book := &domain.Book{
+				ID:         int64(1),
+				Title:      "容疑者Xの献身",
+				Price:      980,
+				BookStatus: domain.Reading,
+				AuthUserId: authUserId,
+				CreatedAt:  cl.Now(),
+				UpdatedAt:  cl.Now(),
+			}
+			testutils.InsertTestData(ctx, t, bundb, book)
+			charts := domain.NewChartsFromBook(book)
+			for _, c := range charts {
+				c.BookId = book.ID
+			}
+			testutils.InsertTestData(ctx, t, bundb, charts...)
+			goal := &domain.Goal{
+				AuthUserId: authUserId,
+				Kind:       domain.GoalBooks,
+				Period:     domain.GoalYearly,
+				Target:     10,
+				CreatedAt:  cl.Now(),
+				UpdatedAt:  cl.Now(),
+			}
+			testutils.InsertTestData(ctx, t, bundb, goal)
+
+			sut := repository.NewUser(bundb, cl)
+			sr := repository.NewShelf(bundb, cl)
+
+			a := assert.New(t)
+
+			//Act
+			archived := 0
+			got, err := sut.DeleteUserWithData(ctx, authUserId, test.immediate, func(export *domain.UserExport) error {
+				archived++
+				return nil
+			})
+
+			//Assert
+			a.Nil(err)
+			a.Equal(1, archived)
+			a.Equal(authUserId, got.User.AuthUserId)
+			a.Len(got.Books, 1)
+			a.Len(got.Charts, 3)
+			a.Len(got.Goals, 1)
+
+			_, err = sut.FindUserByAuthUserId(ctx, authUserId)
+			a.ErrorIs(err, domain.ErrNotFound)
+			books, err := sr.FindBooksByAuthUserID(ctx, authUserId)
+			a.Nil(err)
+			a.Empty(books)
+
+			remaining, err := bundb.NewSelect().Model((*domain.Book)(nil)).WhereAllWithDeleted().Count(ctx)
+			a.Nil(err)
+			if test.immediate {
+				a.Equal(0, remaining)
+				return
+			}
+			a.Equal(1, remaining)
+
+			//ユーザーの復元で同時に削除された本も復元される
+			err = sut.RestoreUser(ctx, authUserId)
+			a.Nil(err)
+			books, err = sr.FindBooksByAuthUserID(ctx, authUserId)
+			a.Nil(err)
+			a.Len(books, 1)
+		})
+	}
+}
+
+func TestDeleteUserWithDataAnonymize(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	user := &domain.User{AuthUserId: authUserId, Name: "田中", Email: domain.Email("Tanaka@example.com"), CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+	testutils.InsertTestData(ctx, t, bundb,
+		&domain.AuthFailure{Scope: domain.LockoutAccount, Key: domain.LockoutEmailKey(user.Email), Failures: 3, LastFailedAt: cl.Now()},
+		&domain.AuthFailure{Scope: domain.LockoutIP, Key: "192.0.2.1", Failures: 3, LastFailedAt: cl.Now()},
+	)
+	//氏名、メールアドレスの値を記録していた以前の監査ログ
+	legacy := &domain.AuditLog{
+		Actor: authUserId, Via: domain.AuditViaApp, AuthUserId: authUserId, TargetType: domain.AuditTargetUser, TargetId: authUserId, Action: domain.AuditCreate,
+		Diff: domain.AuditDiff{"name": {After: "田中"}, "email": {After: "tanaka@example.com"}}, RequestId: "req-1", IP: "192.0.2.1", CreatedAt: cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, legacy)
+	sut := repository.NewUser(bundb, cl)
+	a := assert.New(t)
+
+	//Act
+	_, err = sut.DeleteUserWithData(ctx, authUserId, true, func(export *domain.UserExport) error { return nil })
+	found := rowsContaining(ctx, t, bundb, "tanaka@example.com")
+	got := new(domain.AuditLog)
+	errFind := bundb.NewSelect().Model(got).Where("id = ?", legacy.ID).Scan(ctx)
+	ipFailures, errIP := bundb.NewSelect().Model((*domain.AuthFailure)(nil)).Where("scope = ?", domain.LockoutIP).Count(ctx)
+	//匿名化の設定があっても、差分、IPアドレス以外は更新できない
+	errModify := bundb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SET LOCAL bhapi.audit_anonymize = 'on'"); err != nil {
+			return err
+		}
+		_, err := tx.NewUpdate().Model((*domain.AuditLog)(nil)).Set("action = ?", domain.AuditUpdate).Where("id = ?", legacy.ID).Exec(ctx)
+		return err
+	})
+
+	//Assert
+	a.Nil(err)
+	a.Empty(found) //メールアドレスはどのテーブルにも残らない
+	a.Nil(errFind)
+	a.Equal(domain.AuditDiff{"name": {After: domain.AuditRedacted}, "email": {After: domain.AuditRedacted}}, got.Diff)
+	a.Equal("", got.IP)
+	a.Equal(domain.AuditCreate, got.Action) //誰がいつ何をしたかは残す
+	a.Equal("req-1", got.RequestId)
+	a.Nil(errIP)
+	a.Equal(1, ipFailures) //IPアドレスごとの記録は他のユーザーと共有のため残す
+	a.NotNil(errModify)
+}
+
+// public schemaのテーブルごとに、行の文字列表現にsを含む（大文字と小文字を区別しない）件数を返す。含まないテーブルは返さない
+func rowsContaining(ctx context.Context, t *testing.T, db *bun.DB, s string) map[string]int {
+	t.Helper()
+	var tables []string
+	err := db.NewSelect().
+		TableExpr("information_schema.tables").
+		Column("table_name").
+		Where("table_schema = 'public'").
+		Where("table_type = 'BASE TABLE'").
+		Scan(ctx, &tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]int{}
+	for _, table := range tables {
+		n, err := db.NewSelect().TableExpr("? AS t", bun.Ident(table)).Where("t::text ILIKE ?", "%"+s+"%").Count(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n > 0 {
+			found[table] = n
+		}
+	}
+	return found
+}
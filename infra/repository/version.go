@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// 更新件数が0件だった場合のエラーを返す。
+// バージョンを指定した（version > 0）更新で対象（q）が存在する場合は、他の更新と競合したとしてErrPreconditionFailed、
+// それ以外は対象がない（未登録、削除済み）としてErrNotFound。
+func errNoRowsUpdated(ctx context.Context, q *bun.SelectQuery, version int64) error {
+	if version == 0 {
+		return utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+
+	exists, err := q.Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return utils.NewErrChains(domain.ErrPreconditionFailed, nil)
+	}
+	return utils.NewErrChains(domain.ErrNotFound, nil)
+}
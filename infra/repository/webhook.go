@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+	"github.com/uptrace/bun"
+)
+
+// Webhookの登録、アウトボックスからの振り分け、配信の記録を操作する
+type Webhook struct {
+	db *bun.DB
+	cl utils.Clock
+}
+
+func NewWebhook(db *bun.DB, cl utils.Clock) *Webhook {
+	return &Webhook{db: db, cl: cl}
+}
+
+// Webhookを登録し、採番されたidをw.IDに設定する
+func (wr *Webhook) CreateWebhook(ctx context.Context, w *domain.Webhook) error {
+	now := wr.cl.Now()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+	return wr.db.NewInsert().Model(w).Returning("id").Scan(ctx, &w.ID)
+}
+
+// authUserIdのWebhookを登録順に返す
+func (wr *Webhook) FindWebhooksByAuthUserID(ctx context.Context, authUserId string) ([]*domain.Webhook, error) {
+	webhooks := []*domain.Webhook{}
+	err := wr.db.NewSelect().
+		Model(&webhooks).
+		Where("auth_user_id = ?", authUserId).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range webhooks {
+		w.CreatedAt = w.CreatedAt.In(utils.JST)
+		w.UpdatedAt = w.UpdatedAt.In(utils.JST)
+	}
+	return webhooks, nil
+}
+
+// Webhookと、その配信（デッドレターを含む）を削除する。未登録、他のユーザーのWebhookはErrNotFound。
+func (wr *Webhook) DeleteWebhook(ctx context.Context, authUserId string, webhookId int64) error {
+	return wr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewDelete().
+			Model((*domain.Webhook)(nil)).
+			Where("id = ?", webhookId).
+			Where("auth_user_id = ?", authUserId).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return utils.NewErrChains(domain.ErrNotFound, nil)
+		}
+
+		_, err = tx.NewDelete().
+			Model((*domain.WebhookDelivery)(nil)).
+			Where("webhook_id = ?", webhookId).
+			Exec(ctx)
+		return err
+	})
+}
+
+// 配信していないアウトボックスを古い順に最大limit件、購読中のWebhookごとの配信に振り分け、振り分けた件数を返す。
+// 複数のAPIサーバーで同時に実行しても、同じメッセージを重ねて振り分けない（FOR UPDATE SKIP LOCKED）。
+func (wr *Webhook) DispatchOutbox(ctx context.Context, limit int) (int, error) {
+	var n int
+	err := wr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		msgs := []*domain.OutboxMessage{}
+		err := tx.NewSelect().
+			Model(&msgs).
+			Where("dispatched_at IS NULL").
+			Order("id").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return nil
+		}
+
+		users := []string{}
+		ids := make([]int64, len(msgs))
+		for i, m := range msgs {
+			users = append(users, m.AuthUserId)
+			ids[i] = m.ID
+		}
+		webhooks := []*domain.Webhook{}
+		err = tx.NewSelect().Model(&webhooks).Where("auth_user_id IN (?)", bun.In(users)).Scan(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := wr.cl.Now()
+		deliveries := []*domain.WebhookDelivery{}
+		for _, m := range msgs {
+			for _, w := range webhooks {
+				if w.AuthUserId != m.AuthUserId || !w.Subscribes(m.Type) {
+					continue
+				}
+				deliveries = append(deliveries, &domain.WebhookDelivery{
+					WebhookId:     w.ID,
+					OutboxId:      m.ID,
+					AuthUserId:    m.AuthUserId,
+					Type:          m.Type,
+					Payload:       m.Payload,
+					Status:        domain.DeliveryPending,
+					NextAttemptAt: now,
+					CreatedAt:     now,
+					UpdatedAt:     now,
+				})
+			}
+		}
+		if len(deliveries) > 0 {
+			_, err = tx.NewInsert().Model(&deliveries).On("CONFLICT (webhook_id, outbox_id) DO NOTHING").Returning("NULL").Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*domain.OutboxMessage)(nil)).
+			Set("dispatched_at = ?", now).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		n = len(msgs)
+		return nil
+	})
+	return n, err
+}
+
+// 配信時刻を過ぎた配信を最大limit件取得し、配信先のWebhookを設定して返す。
+// 取得した配信は次の試行をleaseだけ先に延ばし、配信中にサーバーが停止しても期限後に再試行される。
+func (wr *Webhook) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	now := wr.cl.Now()
+	deliveries := []*domain.WebhookDelivery{}
+	err := wr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		due := tx.NewSelect().
+			Model((*domain.WebhookDelivery)(nil)).
+			Column("id").
+			Where("status = ?", domain.DeliveryPending).
+			Where("next_attempt_at <= ?", now).
+			Order("next_attempt_at").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED")
+		_, err := tx.NewUpdate().
+			Model((*domain.WebhookDelivery)(nil)).
+			Set("next_attempt_at = ?", now.Add(lease)).
+			Where("id IN (?)", due).
+			Returning("*").
+			Exec(ctx, &deliveries)
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, len(deliveries))
+		for i, d := range deliveries {
+			ids[i] = d.WebhookId
+		}
+		webhooks := []*domain.Webhook{}
+		err = tx.NewSelect().Model(&webhooks).Where("id IN (?)", bun.In(ids)).Scan(ctx)
+		if err != nil {
+			return err
+		}
+		for _, d := range deliveries {
+			d.ClaimedAttempts = d.Attempts
+			for _, w := range webhooks {
+				if w.ID == d.WebhookId {
+					d.Webhook = w
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// 配信の結果（状態、試行回数、次の試行、最後のステータスとエラー）を保存する。
+// 取得時の試行回数と一致しない（占有期間が過ぎて取得し直された）場合はdomain.ErrDeliveryLeaseLostを返し、保存しない。
+func (wr *Webhook) SaveDeliveryResult(ctx context.Context, d *domain.WebhookDelivery) error {
+	res, err := wr.db.NewUpdate().
+		Model(d).
+		Column("status", "attempts", "next_attempt_at", "last_status", "last_error", "updated_at").
+		WherePK().
+		Where("status = ?", domain.DeliveryPending).
+		Where("attempts = ?", d.ClaimedAttempts).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrDeliveryLeaseLost
+	}
+	return nil
+}
+
+// authUserIdのデッドレター（再試行の上限に達した配信）を新しい順に返す
+func (wr *Webhook) FindDeadDeliveries(ctx context.Context, authUserId string) ([]*domain.WebhookDelivery, error) {
+	deliveries := []*domain.WebhookDelivery{}
+	err := wr.db.NewSelect().
+		Model(&deliveries).
+		Where("auth_user_id = ?", authUserId).
+		Where("status = ?", domain.DeliveryDead).
+		Order("updated_at DESC", "id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range deliveries {
+		d.NextAttemptAt = d.NextAttemptAt.In(utils.JST)
+		d.CreatedAt = d.CreatedAt.In(utils.JST)
+		d.UpdatedAt = d.UpdatedAt.In(utils.JST)
+	}
+	return deliveries, nil
+}
+
+// デッドレターを配信待ちに戻し、試行回数を0からやり直す。デッドレターでない、他のユーザーの配信はErrNotFound。
+func (wr *Webhook) RetryDeadDelivery(ctx context.Context, authUserId string, deliveryId int64) error {
+	now := wr.cl.Now()
+	res, err := wr.db.NewUpdate().
+		Model((*domain.WebhookDelivery)(nil)).
+		Set("status = ?", domain.DeliveryPending).
+		Set("attempts = 0").
+		Set("next_attempt_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", deliveryId).
+		Where("auth_user_id = ?", authUserId).
+		Where("status = ?", domain.DeliveryDead).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return utils.NewErrChains(domain.ErrNotFound, nil)
+	}
+	return nil
+}
+
+// beforeより前に振り分けたアウトボックスと、配信に成功した配信を削除し、削除した件数を返す。
+// デッドレターは利用者が確認できるよう削除しない（Webhookの削除時に削除する）。
+func (wr *Webhook) PurgeDispatched(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	err := wr.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewDelete().
+			Model((*domain.OutboxMessage)(nil)).
+			Where("dispatched_at < ?", before).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		total += n
+
+		res, err = tx.NewDelete().
+			Model((*domain.WebhookDelivery)(nil)).
+			Where("status = ?", domain.DeliverySucceeded).
+			Where("updated_at < ?", before).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		n, err = res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		total += n
+		return nil
+	})
+	return total, err
+}
+
+// イベントをWebhookのアウトボックスに登録する。本のイベントは登録時点の本（削除済みを含む）をペイロードに含める。
+// 変更と同じトランザクションで呼び出すことで、コミットした変更のみが配信される。
+func insertOutbox(ctx context.Context, db bun.IDB, now time.Time, events ...*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	bookIds := []int64{}
+	for _, ev := range events {
+		if ev.BookId != 0 {
+			bookIds = append(bookIds, ev.BookId)
+		}
+	}
+	books := []*domain.Book{}
+	if len(bookIds) > 0 {
+		err := db.NewSelect().
+			Model(&books).
+			WhereAllWithDeleted().
+			Where("id IN (?)", bun.In(bookIds)).
+			Scan(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	msgs := make([]*domain.OutboxMessage, len(events))
+	for i, ev := range events {
+		payload := &domain.WebhookPayload{
+			Type:       ev.Type,
+			AuthUserId: ev.AuthUserId,
+			GoalId:     ev.GoalId,
+			OccurredAt: now.In(utils.JST),
+		}
+		for _, b := range books {
+			if b.ID == ev.BookId {
+				b.CreatedAt = b.CreatedAt.In(utils.JST)
+				b.UpdatedAt = b.UpdatedAt.In(utils.JST)
+				if !b.DeletedAt.IsZero() {
+					b.DeletedAt = b.DeletedAt.In(utils.JST)
+				}
+				payload.Book = domain.NewWebhookBook(b)
+			}
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		msgs[i] = &domain.OutboxMessage{AuthUserId: ev.AuthUserId, Type: ev.Type, Payload: data, CreatedAt: now}
+	}
+
+	_, err := db.NewInsert().Model(&msgs).Exec(ctx)
+	return err
+}
+
+// bookを読了にする更新で、更新前（before）が読了でない場合はtrue（book.finishedの判定）。
+// beforeは更新前の行をロックして取得したもの（findBookForAudit）で、同時に読了にした場合に重ねて判定しない。
+// 本がない場合（nil）はfalse（更新側でエラーにする）。
+func finishesBook(before *domain.Book, book *domain.Book) bool {
+	return before != nil && before.BookStatus != domain.Read && book.BookStatus == domain.Read
+}
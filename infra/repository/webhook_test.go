@@ -0,0 +1,230 @@
+package repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+)
+
+func TestWebhookDispatchOutbox(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	sr := repository.NewShelf(bundb, cl)
+	sut := repository.NewWebhook(bundb, cl)
+	all := &domain.Webhook{AuthUserId: authUserId, URL: "https://example.com/all", Secret: "whsec_all"}
+	finished := &domain.Webhook{AuthUserId: authUserId, URL: "https://example.com/finished", Secret: "whsec_finished", Events: []domain.EventType{domain.EventBookFinished}}
+	other := &domain.Webhook{AuthUserId: "other", URL: "https://example.com/other", Secret: "whsec_other"}
+	for _, w := range []*domain.Webhook{all, finished, other} {
+		if err := sut.CreateWebhook(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	book := &domain.Book{
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       247,
+		Price:      980,
+		BookStatus: domain.Reading,
+		AuthUserId: authUserId,
+	}
+	a := assert.New(t)
+
+	//Act
+	if err := sr.CreateBookWithCharts(ctx, book, domain.NewChartsFromBook(book)); err != nil {
+		t.Fatal(err)
+	}
+	book.BookStatus = domain.Read
+	if err := sr.UpdateBookWithCharts(ctx, book); err != nil {
+		t.Fatal(err)
+	}
+	//読了のままの更新はbook.finishedにしない
+	book.Page = 248
+	if err := sr.PatchBookWithCharts(ctx, book, []string{"page", "book_status"}); err != nil {
+		t.Fatal(err)
+	}
+	n, err := sut.DispatchOutbox(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := sut.DispatchOutbox(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deliveries, err := sut.ClaimDueDeliveries(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimedAgain, err := sut.ClaimDueDeliveries(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert
+	a.Equal(4, n) //created, updated, finished, updated
+	a.Zero(again)
+	a.Len(deliveries, 4)
+	a.Empty(claimedAgain)
+	byWebhook := map[int64][]domain.EventType{}
+	for _, d := range deliveries {
+		a.NotNil(d.Webhook)
+		byWebhook[d.WebhookId] = append(byWebhook[d.WebhookId], d.Type)
+		if d.Type != domain.EventBookFinished {
+			continue
+		}
+		var payload domain.WebhookPayload
+		if err := json.Unmarshal(d.Payload, &payload); err != nil {
+			t.Fatal(err)
+		}
+		a.Equal(authUserId, payload.AuthUserId)
+		a.Equal("容疑者Xの献身", payload.Book.Title)
+		a.Equal(domain.Read, payload.Book.BookStatus)
+	}
+	a.ElementsMatch([]domain.EventType{domain.EventBookCreated, domain.EventBookUpdated, domain.EventBookUpdated}, byWebhook[all.ID])
+	a.Equal([]domain.EventType{domain.EventBookFinished}, byWebhook[finished.ID])
+	a.Empty(byWebhook[other.ID])
+}
+
+func TestWebhookDeadLetters(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	er := repository.NewEvent(bundb, cl)
+	sut := repository.NewWebhook(bundb, cl)
+	w := &domain.Webhook{AuthUserId: authUserId, URL: "https://example.com/hook", Secret: "whsec_test"}
+	if err := sut.CreateWebhook(ctx, w); err != nil {
+		t.Fatal(err)
+	}
+	if err := er.CreateEvents(ctx, &domain.Event{AuthUserId: authUserId, Type: domain.EventGoalReached, GoalId: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sut.DispatchOutbox(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	claimed, err := sut.ClaimDueDeliveries(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := claimed[0]
+	for d.Status != domain.DeliveryDead {
+		d.Fail(cl.Now(), 500, "500 Internal Server Error")
+	}
+	a := assert.New(t)
+
+	//Act
+	errSave := sut.SaveDeliveryResult(ctx, d)
+	dead, err := sut.FindDeadDeliveries(ctx, authUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errOther := sut.RetryDeadDelivery(ctx, "other", d.ID)
+	errRetry := sut.RetryDeadDelivery(ctx, authUserId, d.ID)
+	errRetryAgain := sut.RetryDeadDelivery(ctx, authUserId, d.ID)
+	retried, err := sut.ClaimDueDeliveries(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errDelete := sut.DeleteWebhook(ctx, authUserId, w.ID)
+	errDeleteAgain := sut.DeleteWebhook(ctx, authUserId, w.ID)
+
+	//Assert
+	a.Nil(errSave)
+	a.Len(dead, 1)
+	a.Equal(domain.MaxWebhookAttempts, dead[0].Attempts)
+	a.Equal(500, dead[0].LastStatus)
+	a.ErrorIs(errOther, domain.ErrNotFound)
+	a.Nil(errRetry)
+	a.ErrorIs(errRetryAgain, domain.ErrNotFound)
+	a.Len(retried, 1)
+	a.Zero(retried[0].Attempts)
+	a.Nil(errDelete)
+	a.ErrorIs(errDeleteAgain, domain.ErrNotFound)
+}
+
+func TestWebhookDeliveryLease(t *testing.T) {
+	//Arrange
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	er := repository.NewEvent(bundb, cl)
+	sut := repository.NewWebhook(bundb, cl)
+	if err := sut.CreateWebhook(ctx, &domain.Webhook{AuthUserId: authUserId, URL: "https://example.com/hook", Secret: "whsec_test"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := er.CreateEvents(ctx, &domain.Event{AuthUserId: authUserId, Type: domain.EventGoalReached, GoalId: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sut.DispatchOutbox(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	a := assert.New(t)
+
+	//Act
+	//占有期間が過ぎた（負の期間）配信は、他の配信処理が取得し直す
+	first, err := sut.ClaimDueDeliveries(ctx, 10, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := sut.ClaimDueDeliveries(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second[0].Status = domain.DeliverySucceeded
+	second[0].Attempts++
+	errSucceeded := sut.SaveDeliveryResult(ctx, second[0])
+	//取得し直される前の配信の遅れた失敗は、成功した結果を上書きしない
+	first[0].Fail(cl.Now(), 500, "500 Internal Server Error")
+	errLost := sut.SaveDeliveryResult(ctx, first[0])
+	got := new(domain.WebhookDelivery)
+	errFind := bundb.NewSelect().Model(got).Where("id = ?", first[0].ID).Scan(ctx)
+
+	//Assert
+	a.Len(first, 1)
+	a.Len(second, 1)
+	a.Equal(first[0].ID, second[0].ID)
+	a.Nil(errSucceeded)
+	a.ErrorIs(errLost, domain.ErrDeliveryLeaseLost)
+	a.Nil(errFind)
+	a.Equal(domain.DeliverySucceeded, got.Status)
+	a.Equal(1, got.Attempts)
+	a.Zero(got.LastStatus)
+}
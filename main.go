@@ -10,11 +10,15 @@ import (
 	"os/signal"
 	"time"
 
-	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/apigenv2"
+	"github.com/taimats/bhapi/config"
 	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/mailer"
 	"github.com/taimats/bhapi/infra/repository"
 	"github.com/taimats/bhapi/presenter/handler"
 	"github.com/taimats/bhapi/presenter/middleware"
@@ -22,18 +26,20 @@ import (
 )
 
 func main() {
-	//環境変数の設定
-	err := godotenv.Load(".env")
+	//設定の読み込み（設定ファイル、環境変数、フラグの順に優先）。秘密の値は伏せて出力する
+	cfg, err := config.Load(os.Args[1:])
 	if err != nil {
-		log.Fatalf(".envファイルの読み込みに失敗:%s", err)
+		log.Fatal(err)
 	}
+	log.Printf("設定:%+v", *cfg)
 
 	//データベースの接続設定
-	dsn := infra.NewPostgresDsn()
+	dsn := infra.NewPostgresDsn(cfg.DB)
 	db, err := infra.NewBunDB(dsn)
 	if err != nil {
 		log.Fatalf("データベースの接続に失敗:%s", err)
 	}
+	infra.LogQueries(db, !cfg.IsDev())
 	defer func() {
 		if err := db.Close(); err != nil {
 			log.Println(err)
@@ -45,29 +51,75 @@ func main() {
 	cr := repository.NewChart(db, cl)
 	sr := repository.NewShelf(db, cl)
 	ur := repository.NewUser(db, cl)
+	rr := repository.NewRate(db, cl)
+	gr := repository.NewGoal(db, cl)
+	ir := repository.NewIdempotency(db, cl)
+	er := repository.NewEvent(db, cl)
+	wr := repository.NewWebhook(db, cl)
+	jr := repository.NewJob(db, cl)
+	mr := repository.NewMail(db, cl)
+	tkr := repository.NewToken(db, cl)
+	lr := repository.NewLockout(db, cl)
+	kr := repository.NewAPIKey(db, cl)
+	adr := repository.NewAdmin(db, cl)
+	aur := repository.NewAudit(db, cl)
+	rlr := repository.NewRateLimit(db, cl)
 
 	//controllerインスタンスの生成
-	cc := controller.NewChart(cr)
+	cc := controller.NewChart(cr, ur, rr)
 	sc := controller.NewShelf(sr)
 	uc := controller.NewUser(ur)
-	rc := controller.NewRecord(sr)
-	sbc := controller.NewSearchBooks()
+	rc := controller.NewRecord(sr, ur, rr)
+	sbc := controller.NewSearchBooks(cfg.GoogleBooks.APIURL, cfg.GoogleBooks.APIKey.Value())
 	hc := controller.NewHealthDB(db)
+	rtc := controller.NewRate(rr)
+	gc := controller.NewGoal(gr, sr, ur, rr, er, cl)
+	bc := controller.NewBacklog(cr, sr, ur, rr, cl)
+	tc := controller.NewTrash(sr, ur, cl, cfg.TrashRetention)
+	ic := controller.NewIdempotency(ir, cl, domain.DefaultIdempotencyTTL)
+	ec := controller.NewEvent(er, cl, domain.DefaultEventRetention)
+	wc := controller.NewWebhook(wr, cl, nil, domain.DefaultOutboxRetention)
+	jc := controller.NewJobs(jr, cl, 4, domain.DefaultJobVisibilityTimeout, domain.DefaultJobRetention)
+	lc := controller.NewLockout(lr, cl, domain.DefaultAuthFailureRetention)
+	kc := controller.NewAPIKey(kr, ur, cl)
+	adc := controller.NewAdmin(adr, ur)
+	auc := controller.NewAudit(aur)
+	rlc := controller.NewRateLimiter(rlr, cl)
+	m := mailer.New(cfg.Mail)
+	mc := controller.NewMail(mr, ur, rc, cc, bc, jc, m, cl,
+		cfg.Mail.TokenSecret.Value(),
+		cfg.FrontURL+"/unsubscribe",
+		cfg.Server.PublicURL+handler.BaseURL+"/mail/unsubscribe",
+	)
+	ac := controller.NewAccount(ur, tkr, mr, lc, m, cl,
+		cfg.Mail.TokenSecret.Value(),
+		cfg.FrontURL+"/password-reset",
+		cfg.FrontURL+"/verify-email",
+	)
+
+	//為替レートファイルの読み込み（指定があれば）
+	if path := cfg.ExchangeRatesFile; path != "" {
+		if err := rtc.LoadRatesFromFile(context.Background(), path); err != nil {
+			log.Fatalf("為替レートの読み込みに失敗:%s", err)
+		}
+	}
 
 	//hanlderの生成
-	h := handler.NewHandler(uc, cc, rc, sc, sbc, hc)
+	h := handler.NewHandler(uc, cc, rc, sc, sbc, hc, rtc, gc, bc, tc, ec, wc, mc, ac, kc, adc, auc)
 
 	//echoの生成
-	e, w := middleware.SetAll(echo.New())
+	policies := middleware.RateLimitPolicies(cfg.RateLimit.Default, cfg.RateLimit.Search, cfg.RateLimit.APIKey)
+	e, w := middleware.SetAll(echo.New(), cfg, ic, lc, kc, adc, adc, rlc, policies)
 	defer func() {
 		if err := w.Close(); err != nil {
 			log.Println(err)
 		}
 	}()
-	handler.RegisterHandlersWithBaseURL(e, h)
+	apigen.RegisterHandlersWithBaseURL(e, h, handler.BaseURL)
+	apigenv2.RegisterHandlersWithBaseURL(e, h.V2(), handler.BaseURLV2)
 
 	//サーバーの起動
-	address := fmt.Sprintf("%v:%v", os.Getenv("BACK_API_HOST"), os.Getenv("BACK_API_PORT"))
+	address := fmt.Sprintf("%v:%v", cfg.Server.Host, cfg.Server.Port)
 	go func() {
 		if err := e.Start(address); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			e.Logger.Fatalf("サーバーの起動に失敗:%w", err)
@@ -76,6 +128,45 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
+	//変更イベントの購読（他のAPIサーバーの変更も受け取る）
+	go ec.Run(ctx, 5*time.Second)
+
+	//アウトボックスのWebhookへの配信（失敗は指数バックオフで再試行）
+	go wc.Run(ctx, 5*time.Second)
+
+	//ジョブの実行と定期実行（保持期間を過ぎたデータの削除など）
+	jc.Register(controller.JobTrashPurge, func(ctx context.Context, _ *domain.Job) error { return tc.Purge(ctx) })
+	jc.Register(controller.JobIdempotencyPurge, func(ctx context.Context, _ *domain.Job) error { return ic.Purge(ctx) })
+	jc.Register(controller.JobEventPurge, func(ctx context.Context, _ *domain.Job) error { return ec.Purge(ctx) })
+	jc.Register(controller.JobWebhookPurge, func(ctx context.Context, _ *domain.Job) error { return wc.Purge(ctx) })
+	jc.Register(controller.JobJobPurge, func(ctx context.Context, _ *domain.Job) error { return jc.Purge(ctx) })
+	jc.Register(controller.JobLockoutPurge, func(ctx context.Context, _ *domain.Job) error { return lc.Purge(ctx) })
+	jc.Register(controller.JobRateLimitPurge, func(ctx context.Context, _ *domain.Job) error { return rlc.Purge(ctx) })
+	jc.Register(controller.JobDigestMonthly, func(ctx context.Context, _ *domain.Job) error { return mc.EnqueueMonthlyDigests(ctx) })
+	jc.Register(controller.JobDigestSend, mc.SendDigest)
+	for kind, spec := range map[string]string{
+		controller.JobTrashPurge:       "@hourly",
+		controller.JobIdempotencyPurge: "@hourly",
+		controller.JobEventPurge:       "@hourly",
+		controller.JobWebhookPurge:     "@hourly",
+		controller.JobJobPurge:         "@daily",
+		controller.JobLockoutPurge:     "@daily",
+		controller.JobRateLimitPurge:   "@hourly",
+	} {
+		if err := jc.Schedule(kind, spec); err != nil {
+			log.Fatalf("定期実行の登録に失敗:%s", err)
+		}
+	}
+	//月次のまとめは毎月1日の9時に先月分を送る（配信停止のトークンの鍵が必要）
+	if cfg.Mail.TokenSecret != "" {
+		if err := jc.Schedule(controller.JobDigestMonthly, "0 9 1 * *"); err != nil {
+			log.Fatalf("定期実行の登録に失敗:%s", err)
+		}
+	} else {
+		log.Println("MAIL_TOKEN_SECRETが未設定のため、月次のまとめを送信しません")
+	}
+	go jc.Run(ctx, time.Second)
+
 	//サーバーのシャットダウンの処理
 	<-ctx.Done()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -83,5 +174,12 @@ func main() {
 	if err = e.Shutdown(ctx); err != nil {
 		e.Logger.Fatalf("サーバーのシャットダウンに失敗:%w", err)
 	}
+
+	//新しいジョブの取得は止まっているため、実行中のジョブの完了を待つ。期限を過ぎたジョブは中断し、再起動後に再実行する
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelDrain()
+	if err = jc.Drain(drainCtx); err != nil {
+		log.Printf("実行中のジョブを中断しました:%s", err)
+	}
 	log.Println("サーバーが正常にシャットダウンしました")
 }
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// メールアドレスとパスワードでログイン。失敗が続いた場合は429（Retry-Afterはエラーハンドラで付ける）
+// (POST /auth/login)
+func (h *Handler) PostAuthLogin(c echo.Context) error {
+	r := new(Login)
+	if err := c.Bind(r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(r); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	user, err := h.ac.Login(ctx, domain.Email(r.Email), domain.Password(r.Password), c.RealIP())
+	if err != nil {
+		return problem.Wrap(err, problem.CodeLoginFailed, problem.Codes{
+			domain.ErrUnauthorized:    problem.CodeInvalidCredentials,
+			domain.ErrTooManyRequests: problem.CodeAuthLocked,
+			domain.ErrForbidden:       problem.CodeAccountDisabled,
+		})
+	}
+
+	return c.JSON(http.StatusOK, &LoginResult{AuthUserId: user.AuthUserId})
+}
+
+// パスワード再設定のメールを送る（登録のないメールアドレスでも202）
+// (POST /auth/password/reset)
+func (h *Handler) PostAuthPasswordReset(c echo.Context) error {
+	r := new(PasswordResetRequest)
+	if err := c.Bind(r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(r); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.ac.RequestPasswordReset(ctx, domain.Email(r.Email)); err != nil {
+		return problem.Wrap(err, problem.CodePasswordResetFailed, nil)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// メールのトークンでパスワードを再設定
+// (POST /auth/password/reset/confirm)
+func (h *Handler) PostAuthPasswordResetConfirm(c echo.Context) error {
+	r := new(PasswordReset)
+	if err := c.Bind(r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(r); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.ac.ResetPassword(ctx, r.Token, domain.Password(r.Password)); err != nil {
+		return problem.Wrap(err, problem.CodePasswordResetFailed, problem.Codes{domain.ErrInvalidToken: problem.CodeInvalidToken})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// メールアドレス確認のメールを送る
+// (POST /users/{authUserId}/email/verification)
+func (h *Handler) PostUsersAuthUserIdEmailVerification(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	err := h.ac.SendVerification(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeEmailVerificationFailed, problem.Codes{
+			domain.ErrNotFound:             problem.CodeUserNotFound,
+			domain.ErrEmailAlreadyVerified: problem.CodeEmailAlreadyVerified,
+		})
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// メールのトークンでメールアドレスを確認済みにする
+// (POST /auth/email/verify)
+func (h *Handler) PostAuthEmailVerify(c echo.Context) error {
+	r := new(EmailVerification)
+	if err := c.Bind(r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(r); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.ac.VerifyEmail(ctx, r.Token); err != nil {
+		return problem.Wrap(err, problem.CodeEmailVerificationFailed, problem.Codes{domain.ErrInvalidToken: problem.CodeInvalidToken})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
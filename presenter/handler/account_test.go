@@ -0,0 +1,207 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/mailer"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+var tokenInURL = regexp.MustCompile(`\?token=(\S+)`)
+
+// メールのリンクのトークンを取り出す
+func tokenFromMail(t *testing.T, text string) string {
+	t.Helper()
+	m := tokenInURL.FindStringSubmatch(text)
+	if m == nil {
+		t.Fatalf("メールにトークンがありません:%s", text)
+	}
+	token, err := url.QueryUnescape(m[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthLogin(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	dir := t.TempDir()
+	_, e := testutils.SetupHandlerWithMailer(bundb, &mailer.File{Dir: dir, From: "bhapi@example.com"})
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	registered := serve(e, http.MethodPost, "/v1/auth/register", `{"authUserId":"`+authUserId+`","name":"田中","email":"tanaka@example.com","password":"password123"}`, nil)
+	if registered.Code != http.StatusCreated {
+		t.Fatalf("ユーザー登録に失敗:%s", registered.Body.String())
+	}
+	a := assert.New(t)
+
+	//Act ***************
+	ok := serve(e, http.MethodPost, "/v1/auth/login", `{"email":"tanaka@example.com","password":"password123"}`, nil)
+	var failed []int
+	for range domain.LockoutPolicies[domain.LockoutAccount].Threshold {
+		failed = append(failed, serve(e, http.MethodPost, "/v1/auth/login", `{"email":"tanaka@example.com","password":"wrong-password"}`, nil).Code)
+	}
+	locked := serve(e, http.MethodPost, "/v1/auth/login", `{"email":"tanaka@example.com","password":"password123"}`, nil)
+	mails := testutils.MailTexts(t, dir)
+
+	//Assert ***************
+	a.Equal(http.StatusOK, ok.Code) //登録時にハッシュ化したパスワードで照合できる
+	a.JSONEq(`{"authUserId":"`+authUserId+`"}`, ok.Body.String())
+	for _, code := range failed {
+		a.Equal(http.StatusUnauthorized, code)
+	}
+	a.Equal(http.StatusTooManyRequests, locked.Code)
+	a.Equal("60", locked.Header().Get(echo.HeaderRetryAfter))
+	a.Contains(locked.Body.String(), string(problem.CodeAuthLocked))
+	if a.Len(mails, 1) { //ロックした時点で本人に知らせる
+		a.Contains(mails[0], "https://example.com/password-reset")
+	}
+}
+
+func TestAuthPasswordReset(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	dir := t.TempDir()
+	_, e := testutils.SetupHandlerWithMailer(bundb, &mailer.File{Dir: dir, From: "bhapi@example.com"})
+	a := assert.New(t)
+
+	//Act ***************
+	unknown := serve(e, http.MethodPost, "/v1/auth/password/reset", `{"email":"unknown@example.com"}`, nil)
+	requested := serve(e, http.MethodPost, "/v1/auth/password/reset", `{"email":"tanaka@example.com"}`, nil)
+	mails := testutils.MailTexts(t, dir)
+	if len(mails) != 1 {
+		t.Fatalf("メールの件数が一致しません:%d", len(mails))
+	}
+	body := `{"token":"` + tokenFromMail(t, mails[0]) + `","password":"newpassword"}`
+	confirmed := serve(e, http.MethodPost, "/v1/auth/password/reset/confirm", body, nil)
+	reused := serve(e, http.MethodPost, "/v1/auth/password/reset/confirm", body, nil)
+
+	//Assert ***************
+	a.Equal(http.StatusAccepted, unknown.Code) //登録の有無によらず同じレスポンス
+	a.Equal(http.StatusAccepted, requested.Code)
+	a.Contains(mails[0], "https://example.com/password-reset?token=")
+	a.Equal(http.StatusNoContent, confirmed.Code)
+	a.Equal(http.StatusBadRequest, reused.Code)
+	a.Contains(reused.Body.String(), string(problem.CodeInvalidToken))
+}
+
+func TestUsersAuthUserIdEmailVerification(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	dir := t.TempDir()
+	_, e := testutils.SetupHandlerWithMailer(bundb, &mailer.File{Dir: dir, From: "bhapi@example.com"})
+	target := "/v1/users/" + authUserId + "/email/verification"
+	a := assert.New(t)
+
+	//Act ***************
+	sent := serve(e, http.MethodPost, target, "", nil)
+	mails := testutils.MailTexts(t, dir)
+	if len(mails) != 1 {
+		t.Fatalf("メールの件数が一致しません:%d", len(mails))
+	}
+	verified := serve(e, http.MethodPost, "/v1/auth/email/verify", `{"token":"`+tokenFromMail(t, mails[0])+`"}`, nil)
+	again := serve(e, http.MethodPost, target, "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusAccepted, sent.Code)
+	a.Contains(mails[0], "https://example.com/verify-email?token=")
+	a.Equal(http.StatusNoContent, verified.Code)
+	a.Equal(http.StatusConflict, again.Code)
+	a.Contains(again.Body.String(), string(problem.CodeEmailAlreadyVerified))
+}
+
+func TestAuthTokenWithError(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, e := testutils.SetupHandler(bundb)
+	forged, _, err := domain.NewUserToken("other-secret", domain.TokenPasswordReset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reset, _, err := domain.NewUserToken(testutils.MailTokenSecret, domain.TokenPasswordReset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		target string
+		body   string
+		status int
+		code   problem.Code
+	}{
+		"NG:ログインのパスワードなし":     {target: "/v1/auth/login", body: `{"email":"tanaka@example.com"}`, status: http.StatusBadRequest, code: problem.CodeValidationFailed},
+		"NG:未登録のメールアドレスでログイン": {target: "/v1/auth/login", body: `{"email":"unknown@example.com","password":"password123"}`, status: http.StatusUnauthorized, code: problem.CodeInvalidCredentials},
+		"NG:不正なメールアドレス":       {target: "/v1/auth/password/reset", body: `{"email":"tanaka"}`, status: http.StatusBadRequest, code: problem.CodeValidationFailed},
+		"NG:短いパスワード":          {target: "/v1/auth/password/reset/confirm", body: `{"token":"` + forged + `","password":"short"}`, status: http.StatusBadRequest, code: problem.CodeValidationFailed},
+		"NG:鍵の異なるトークン":        {target: "/v1/auth/password/reset/confirm", body: `{"token":"` + forged + `","password":"newpassword"}`, status: http.StatusBadRequest, code: problem.CodeInvalidToken},
+		"NG:用途の異なるトークン":       {target: "/v1/auth/email/verify", body: `{"token":"` + reset + `"}`, status: http.StatusBadRequest, code: problem.CodeInvalidToken},
+		"NG:未登録のユーザーへの確認":     {target: "/v1/users/unknown/email/verification", status: http.StatusNotFound, code: problem.CodeUserNotFound},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act ***************
+			w := serve(e, http.MethodPost, test.target, test.body, nil)
+
+			//Assert ***************
+			assert.Equal(t, test.status, w.Code)
+			assert.Contains(t, w.Body.String(), string(test.code))
+		})
+	}
+}
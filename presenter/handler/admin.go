@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/middleware/auth"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// ユーザーを検索する（削除済みを除く、登録順）
+// (GET /admin/users)
+func (h *Handler) GetAdminUsers(c echo.Context, params apigen.GetAdminUsersParams) error {
+	s := domain.UserSearch{}
+	if params.Q != nil {
+		s.Query = *params.Q
+	}
+	if params.Limit != nil {
+		s.Limit = *params.Limit
+	}
+	if params.Offset != nil {
+		s.Offset = *params.Offset
+	}
+
+	ctx := c.Request().Context()
+	users, total, err := h.adc.SearchUsers(ctx, s)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserSearchFailed, nil)
+	}
+
+	res := AdminUserList{Users: make([]AdminUser, len(users)), Total: total}
+	for i, u := range users {
+		res.Users[i] = tweakAdminUserForJSON(u)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ユーザーと本棚の集計を返す
+// (GET /admin/users/{authUserId}/stats)
+func (h *Handler) GetAdminUsersAuthUserIdStats(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+	user, stats, err := h.adc.GetUserStats(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeStatsGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeUserNotFound})
+	}
+
+	return c.JSON(http.StatusOK, AdminUserStats{
+		User:    tweakAdminUserForJSON(user),
+		Books:   stats.Books,
+		Bought:  stats.Bought,
+		Reading: stats.Reading,
+		Read:    stats.Read,
+		Pages:   stats.Pages,
+		Spend:   tweakSpendForJSON(stats.Spend),
+	})
+}
+
+// ユーザーを無効化し、強制的にログアウトさせる
+// (POST /admin/users/{authUserId}/disable)
+func (h *Handler) PostAdminUsersAuthUserIdDisable(c echo.Context, authUserId string) error {
+	//管理APIはadminスコープの個人用APIキーのみ（auth.RequireRole）
+	k, ok := auth.APIKeyFrom(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusForbidden, problem.CodeAdminRequired)
+	}
+
+	ctx := c.Request().Context()
+	user, rv, err := h.adc.DisableUser(ctx, k.AuthUserId, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserDisableFailed, problem.Codes{
+			controller.ErrDisableSelf: problem.CodeCannotDisableSelf,
+			domain.ErrNotFound:        problem.CodeUserNotFound,
+		})
+	}
+
+	return c.JSON(http.StatusOK, AdminUserDisabled{
+		User:       tweakAdminUserForJSON(user),
+		Revocation: tweakRevocationForJSON(rv),
+	})
+}
+
+// 無効化したユーザーを有効に戻す
+// (POST /admin/users/{authUserId}/enable)
+func (h *Handler) PostAdminUsersAuthUserIdEnable(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+	user, err := h.adc.EnableUser(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserEnableFailed, problem.Codes{domain.ErrNotFound: problem.CodeUserNotFound})
+	}
+
+	return c.JSON(http.StatusOK, tweakAdminUserForJSON(user))
+}
+
+// ユーザーを強制的にログアウトさせる
+// (POST /admin/users/{authUserId}/revoke)
+func (h *Handler) PostAdminUsersAuthUserIdRevoke(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+	rv, err := h.adc.RevokeTokens(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeRevokeFailed, problem.Codes{domain.ErrNotFound: problem.CodeUserNotFound})
+	}
+
+	return c.JSON(http.StatusOK, tweakRevocationForJSON(rv))
+}
+
+// システム全体の集計を返す
+// (GET /admin/stats)
+func (h *Handler) GetAdminStats(c echo.Context) error {
+	ctx := c.Request().Context()
+	stats, err := h.adc.GetSystemStats(ctx)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeStatsGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, AdminStats{
+		Users:         stats.Users,
+		DisabledUsers: stats.DisabledUsers,
+		Admins:        stats.Admins,
+		Books:         stats.Books,
+		Spend:         tweakSpendForJSON(stats.Spend),
+	})
+}
+
+// 為替レートを登録、更新する（全ユーザーの換算に使うため、管理APIのみ）
+// (PUT /admin/rates)
+func (h *Handler) PutAdminRates(c echo.Context) error {
+	var rs []ExchangeRate
+	if err := c.Bind(&rs); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	for _, r := range rs {
+		if err := c.Validate(&r); err != nil {
+			return err
+		}
+	}
+
+	rates, err := convertRates(rs)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeInvalidRate, nil)
+	}
+
+	ctx := c.Request().Context()
+	err = h.rtc.UpdateRates(ctx, rates)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeRateUpdateFailed, nil)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
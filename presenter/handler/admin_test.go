@@ -0,0 +1,158 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/middleware/auth"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestAdmin(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	admin := &domain.User{AuthUserId: "9b0b1f4e-4e0c-4b8e-8f3a-1f7f0c2d3e4a", Name: "管理者", Email: "admin@example.com", Role: domain.RoleAdmin, CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, admin, user)
+	testutils.InsertTestData(ctx, t, bundb, &domain.Book{Title: "本", Page: 100, Price: 1000, Currency: domain.JPY, BookStatus: domain.Read, AuthUserId: user.AuthUserId})
+	_, e := testutils.SetupHandler(bundb)
+	//管理者のadminスコープの個人用APIキーで認証した状態（認証、役割の確認はミドルウェアで行う）
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(auth.ContextKeyAPIKey, &domain.APIKey{AuthUserId: admin.AuthUserId, Scopes: []domain.APIKeyScope{domain.ScopeAdmin}})
+			return next(c)
+		}
+	})
+	target := "/v1/admin/users/" + user.AuthUserId
+	a := assert.New(t)
+
+	//Act ***************
+	search := serve(e, http.MethodGet, "/v1/admin/users?q=tanaka&limit=10", "", nil)
+	stats := serve(e, http.MethodGet, target+"/stats", "", nil)
+	disabled := serve(e, http.MethodPost, target+"/disable", "", nil)
+	system := serve(e, http.MethodGet, "/v1/admin/stats", "", nil)
+	enabled := serve(e, http.MethodPost, target+"/enable", "", nil)
+	revoked := serve(e, http.MethodPost, target+"/revoke", "", nil)
+	self := serve(e, http.MethodPost, "/v1/admin/users/"+admin.AuthUserId+"/disable", "", nil)
+	unknown := serve(e, http.MethodGet, "/v1/admin/users/unknown/stats", "", nil)
+	rated := serve(e, http.MethodPut, "/v1/admin/rates", `[{"currency":"USD","rate":"150.5"}]`, nil)
+	rates := serve(e, http.MethodGet, "/v1/rates", "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusOK, search.Code)
+	a.JSONEq(`{"users":[{"authUserId":"c0cc3f0c-9a02-45ba-9de7-7d7276bb6058","name":"田中","email":"tanaka@example.com","role":"user","createdAt":"`+user.CreatedAt.In(utils.JST).Format(time.RFC3339)+`"}],"total":1}`, search.Body.String())
+	a.Equal(http.StatusOK, stats.Code)
+	a.Contains(stats.Body.String(), `"books":1,"bought":0,"pages":100,"read":1,"reading":0,"spend":[{"amount":1000,"currency":"JPY"}]`)
+	a.Equal(http.StatusOK, disabled.Code)
+	a.Contains(disabled.Body.String(), `"disabledAt":"`)
+	a.Contains(disabled.Body.String(), `"revocation":{"apiKeys":0,"tokens":0}`)
+	a.Equal(http.StatusOK, system.Code)
+	a.JSONEq(`{"users":2,"disabledUsers":1,"admins":1,"books":1,"spend":[{"currency":"JPY","amount":1000}]}`, system.Body.String())
+	a.Equal(http.StatusOK, enabled.Code)
+	a.NotContains(enabled.Body.String(), `"disabledAt"`)
+	a.Equal(http.StatusOK, revoked.Code)
+	a.Equal(http.StatusBadRequest, self.Code)
+	a.Contains(self.Body.String(), string(problem.CodeCannotDisableSelf))
+	a.Equal(http.StatusNotFound, unknown.Code)
+	a.Contains(unknown.Body.String(), string(problem.CodeUserNotFound))
+	a.Equal(http.StatusOK, rated.Code)
+	a.Contains(rates.Body.String(), `"currency":"USD"`)
+}
+
+func TestAdminRatesWithAppKey(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, e := testutils.SetupHandler(bundb)
+	//アプリのキーで認証した状態（個人用APIキーを設定しない）
+	adc := controller.NewAdmin(repository.NewAdmin(bundb, cl), repository.NewUser(bundb, cl))
+	e.Use(auth.RequireRole(auth.RoleConfig{Skipper: auth.PrefixSkipper(handler.AdminBaseURL), Roles: adc, Role: domain.RoleAdmin}))
+	body := `[{"currency":"USD","rate":"1"}]`
+	a := assert.New(t)
+
+	//Act ***************
+	admin := serve(e, http.MethodPut, "/v1/admin/rates", body, nil)
+	removed := serve(e, http.MethodPut, "/v1/rates", body, nil) //以前の全ユーザーに開いていたルート
+	rates := serve(e, http.MethodGet, "/v1/rates", "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusForbidden, admin.Code)
+	a.Contains(admin.Body.String(), string(problem.CodeAdminRequired))
+	a.Equal(http.StatusMethodNotAllowed, removed.Code)
+	a.Equal(http.StatusOK, rates.Code) //取得はアプリのキーでもできる
+	a.NotContains(rates.Body.String(), `"currency":"USD"`)
+}
+
+func TestDisabledUserWithAppKey(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	user := &domain.User{AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Name: "田中", Email: "tanaka@example.com", DisabledAt: cl.Now(), CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+	_, e := testutils.SetupHandler(bundb)
+	//アプリのキーで認証した状態（個人用APIキーを設定しない）
+	adc := controller.NewAdmin(repository.NewAdmin(bundb, cl), repository.NewUser(bundb, cl))
+	e.Use(auth.RejectDisabled(auth.DisabledConfig{Users: adc}))
+	a := assert.New(t)
+
+	//Act ***************
+	shelf := serve(e, http.MethodGet, "/v1/shelf/"+user.AuthUserId, "", nil)
+	goals := serve(e, http.MethodGet, "/v2/goals/"+user.AuthUserId, "", nil)
+	//パスにauthUserIdがないPUT /usersも、ボディのユーザーで拒否する
+	updated := serve(e, http.MethodPut, "/v1/users", `{"id":"`+strconv.FormatInt(user.ID, 10)+`","authUserId":"`+user.AuthUserId+`","name":"佐藤","email":"sato@example.com","password":"password123"}`, nil)
+	got, errFind := repository.NewUser(bundb, cl).FindUserByAuthUserId(ctx, user.AuthUserId)
+
+	//Assert ***************
+	a.Equal(http.StatusForbidden, shelf.Code)
+	a.Contains(shelf.Body.String(), string(problem.CodeAccountDisabled))
+	a.Equal(http.StatusForbidden, goals.Code)
+	a.Equal(http.StatusForbidden, updated.Code)
+	a.Contains(updated.Body.String(), string(problem.CodeAccountDisabled))
+	a.Nil(errFind)
+	a.Equal("田中", got.Name)
+	a.Equal(domain.Email("tanaka@example.com"), got.Email)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// ユーザーごとに発行した個人用APIキーを返す
+// (GET /apikeys/{authUserId})
+func (h *Handler) GetApikeysAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	keys, err := h.kc.GetAPIKeys(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeAPIKeyGetFailed, nil)
+	}
+
+	res := make([]*APIKey, len(keys))
+	for i, k := range keys {
+		res[i] = tweakAPIKeyForJSON(k)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// 個人用APIキーを発行し、キーを返す
+// (POST /apikeys/{authUserId})
+func (h *Handler) PostApikeysAuthUserId(c echo.Context, authUserId string) error {
+	k := new(APIKey)
+	if err := c.Bind(k); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(k); err != nil {
+		return err
+	}
+
+	key := &domain.APIKey{AuthUserId: authUserId, Name: k.Name}
+	for _, s := range k.Scopes {
+		key.Scopes = append(key.Scopes, domain.APIKeyScope(s))
+	}
+	if k.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, k.ExpiresAt)
+		if err != nil {
+			return problem.Wrap(domain.ErrInvalidAPIKey, problem.CodeInvalidAPIKey, nil)
+		}
+		key.ExpiresAt = expiresAt
+	}
+
+	ctx := c.Request().Context()
+	token, err := h.kc.CreateAPIKey(ctx, key)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeAPIKeyCreateFailed, problem.Codes{
+			domain.ErrInvalidAPIKey: problem.CodeInvalidAPIKey,
+			domain.ErrAPIKeyLimit:   problem.CodeAPIKeyLimit,
+			domain.ErrAdminScope:    problem.CodeAdminScope,
+		})
+	}
+
+	res := tweakAPIKeyForJSON(key)
+	res.Key = token
+	return c.JSON(http.StatusCreated, res)
+}
+
+// 個人用APIキーを削除
+// (DELETE /apikeys/{authUserId}/{keyId})
+func (h *Handler) DeleteApikeysAuthUserIdKeyId(c echo.Context, authUserId string, keyId string) error {
+	id, err := strconv.ParseInt(keyId, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeBadRequest)
+	}
+
+	ctx := c.Request().Context()
+	err = h.kc.DeleteAPIKey(ctx, authUserId, id)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeAPIKeyDeleteFailed, problem.Codes{domain.ErrNotFound: problem.CodeAPIKeyNotFound})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,123 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/apigenv2"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestApikeysAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/apikeys/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	a := assert.New(t)
+
+	//Act ***************
+	created := serve(e, http.MethodPost, target, `{"name":"script","scopes":["shelf:read","charts:read"],"expiresAt":"2030-01-01T00:00:00+09:00"}`, nil)
+	list := serve(e, http.MethodGet, target, "", nil)
+	var key handler.APIKey
+	if err := json.Unmarshal(created.Body.Bytes(), &key); err != nil {
+		t.Fatal(err)
+	}
+	deleted := serve(e, http.MethodDelete, target+"/"+key.Id, "", nil)
+	after := serve(e, http.MethodGet, target, "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusCreated, created.Code)
+	a.True(strings.HasPrefix(key.Key, key.Prefix))
+	a.Equal([]string{"shelf:read", "charts:read"}, key.Scopes)
+	a.Equal("2030-01-01T00:00:00+09:00", key.ExpiresAt)
+	a.Equal(http.StatusOK, list.Code)
+	a.Contains(list.Body.String(), `"prefix":"`+key.Prefix+`"`)
+	a.NotContains(list.Body.String(), key.Key) //キーは発行時のみ返す
+	a.Equal(http.StatusNoContent, deleted.Code)
+	a.JSONEq(`[]`, after.Body.String())
+}
+
+func TestApikeysAuthUserIdWithError(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/apikeys/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	tests := map[string]struct {
+		method     string
+		target     string
+		body       string
+		statusWant int
+		codeWant   problem.Code
+	}{
+		"未対応のスコープ":        {method: http.MethodPost, target: target, body: `{"name":"script","scopes":["shelf:delete"]}`, statusWant: http.StatusBadRequest, codeWant: problem.CodeInvalidAPIKey},
+		"管理者でないadminスコープ": {method: http.MethodPost, target: target, body: `{"name":"script","scopes":["admin"]}`, statusWant: http.StatusForbidden, codeWant: problem.CodeAdminScope},
+		"有効期限が過去":         {method: http.MethodPost, target: target, body: `{"name":"script","scopes":["shelf:read"],"expiresAt":"2020-01-01T00:00:00Z"}`, statusWant: http.StatusBadRequest, codeWant: problem.CodeInvalidAPIKey},
+		"有効期限の形式":         {method: http.MethodPost, target: target, body: `{"name":"script","scopes":["shelf:read"],"expiresAt":"2030-01-01"}`, statusWant: http.StatusBadRequest, codeWant: problem.CodeInvalidAPIKey},
+		"スコープなし":          {method: http.MethodPost, target: target, body: `{"name":"script","scopes":[]}`, statusWant: http.StatusBadRequest, codeWant: problem.CodeValidationFailed},
+		"未登録のキーの削除":       {method: http.MethodDelete, target: target + "/100", statusWant: http.StatusNotFound, codeWant: problem.CodeAPIKeyNotFound},
+		"idが数値でない":        {method: http.MethodDelete, target: target + "/x", statusWant: http.StatusBadRequest, codeWant: problem.CodeBadRequest},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act ***************
+			w := serve(e, tt.method, tt.target, tt.body, nil)
+
+			//Assert ***************
+			assert.Equal(t, tt.statusWant, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), string(tt.codeWant))
+		})
+	}
+}
+
+// 個人用APIキーのスコープを設定したルートが、すべて登録したルートであることを確認する（パスの誤りで許可漏れにしない）
+func TestRouteScopes(t *testing.T) {
+	//Arrange ***************
+	e := echo.New()
+	h := &handler.Handler{}
+	apigen.RegisterHandlersWithBaseURL(e, h, handler.BaseURL)
+	apigenv2.RegisterHandlersWithBaseURL(e, h.V2(), handler.BaseURLV2)
+
+	//Act ***************
+	routes := map[string]struct{}{}
+	for _, r := range e.Routes() {
+		routes[r.Method+" "+r.Path] = struct{}{}
+	}
+
+	//Assert ***************
+	for route := range handler.RouteScopes {
+		assert.Contains(t, routes, route)
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// ユーザーのデータへの変更の履歴を新しい順に返す
+// (GET /activity/{authUserId})
+func (h *Handler) GetActivityAuthUserId(c echo.Context, authUserId string, params apigen.GetActivityAuthUserIdParams) error {
+	q := domain.AuditQuery{}
+	if err := setAuditPage(&q, params.BeforeId, params.Limit); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	logs, err := h.auc.GetActivity(ctx, authUserId, q.BeforeId, q.Limit)
+	return auditLogsJSON(c, logs, q.Limit, err)
+}
+
+// 監査ログを検索する（新しい順）
+// (GET /admin/audit)
+func (h *Handler) GetAdminAudit(c echo.Context, params apigen.GetAdminAuditParams) error {
+	q := domain.AuditQuery{}
+	if params.AuthUserId != nil {
+		q.AuthUserId = *params.AuthUserId
+	}
+	if params.Actor != nil {
+		q.Actor = *params.Actor
+	}
+	if params.TargetType != nil {
+		q.TargetType = domain.AuditTarget(*params.TargetType)
+	}
+	if params.TargetId != nil {
+		q.TargetId = *params.TargetId
+	}
+	if params.Action != nil {
+		q.Action = domain.AuditAction(*params.Action)
+	}
+	if err := setAuditPage(&q, params.BeforeId, params.Limit); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	logs, err := h.auc.GetAuditLogs(ctx, q)
+	return auditLogsJSON(c, logs, q.Limit, err)
+}
+
+// ページの指定（beforeId、limit）を検索条件に設定する
+func setAuditPage(q *domain.AuditQuery, beforeId *string, limit *int) error {
+	if beforeId != nil && *beforeId != "" {
+		id, err := strconv.ParseInt(*beforeId, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidAuditQuery)
+		}
+		q.BeforeId = id
+	}
+	if limit != nil {
+		q.Limit = *limit
+	}
+	return nil
+}
+
+// 監査ログをJson形式で返す。件数が上限に達した場合は次のページのbeforeIdを返す
+func auditLogsJSON(c echo.Context, logs []*domain.AuditLog, limit int, err error) error {
+	if err != nil {
+		return problem.Wrap(err, problem.CodeAuditGetFailed, problem.Codes{domain.ErrInvalidAuditQuery: problem.CodeInvalidAuditQuery})
+	}
+
+	res := AuditLogList{Entries: make([]AuditLog, len(logs))}
+	for i, l := range logs {
+		res.Entries[i] = tweakAuditLogForJSON(l)
+	}
+	if limit <= 0 {
+		limit = domain.DefaultAuditLimit
+	}
+	if len(logs) > 0 && len(logs) == min(limit, domain.MaxAuditLimit) {
+		res.NextBeforeId = strconv.FormatInt(logs[len(logs)-1].ID, 10)
+	}
+	return c.JSON(http.StatusOK, res)
+}
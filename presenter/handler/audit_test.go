@@ -0,0 +1,58 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestAudit(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/shelf/" + authUserId
+	a := assert.New(t)
+
+	//Act ***************
+	created := serve(e, http.MethodPost, target, `{"title":"容疑者Xの献身","page":"247","price":"980","bookStatus":"bought","authUserId":"`+authUserId+`"}`, nil)
+	first := serve(e, http.MethodGet, "/v1/activity/"+authUserId+"?limit=1", "", nil)
+	activity := serve(e, http.MethodGet, "/v1/activity/"+authUserId, "", nil)
+	search := serve(e, http.MethodGet, "/v1/admin/audit?targetType=book&action=create", "", nil)
+	invalid := serve(e, http.MethodGet, "/v1/admin/audit?action=read", "", nil)
+	badCursor := serve(e, http.MethodGet, "/v1/activity/"+authUserId+"?beforeId=abc", "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusCreated, created.Code)
+	a.Equal(http.StatusOK, first.Code)
+	a.Contains(first.Body.String(), `"nextBeforeId":"`) //件数が上限に達した場合は次のページがある
+	a.Equal(http.StatusOK, activity.Code)
+	a.Contains(activity.Body.String(), `"action":"create"`)
+	a.Contains(activity.Body.String(), `"title":{"after":"容疑者Xの献身","before":null}`)
+	a.NotContains(activity.Body.String(), `"nextBeforeId"`)
+	a.Equal(http.StatusOK, search.Code)
+	a.Contains(search.Body.String(), `"targetType":"book"`)
+	a.Equal(http.StatusBadRequest, invalid.Code)
+	a.Contains(invalid.Body.String(), string(problem.CodeInvalidAuditQuery))
+	a.Equal(http.StatusBadRequest, badCursor.Code)
+	a.Contains(badCursor.Body.String(), string(problem.CodeInvalidAuditQuery))
+}
@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestGetBacklogWithAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	books := []*domain.Book{
+		{
+			ID:         int64(1),
+			Title:      "容疑者Xの献身",
+			Author:     "東野圭吾",
+			Page:       330,
+			Price:      1640,
+			BookStatus: domain.Read,
+			AuthUserId: authUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			ID:         int64(2),
+			Title:      "予知夢",
+			Author:     "東野圭吾",
+			Page:       220,
+			Price:      220,
+			BookStatus: domain.Bought,
+			AuthUserId: authUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, books...)
+	charts := []*domain.Chart{
+		{
+			Label:      domain.ChartVolumes,
+			Year:       2024,
+			Month:      2,
+			Data:       1,
+			AuthUserId: authUserId,
+			BookId:     int64(1),
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			Label:      domain.ChartVolumes,
+			Year:       2024,
+			Month:      2,
+			Data:       1,
+			AuthUserId: authUserId,
+			BookId:     int64(2),
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodGet, "/backlog/:authUserId", nil)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
+
+	//Act ***************
+	err = sut.GetBacklogAuthUserId(c, authUserId)
+
+	//Assert ***************
+	resBody := testutils.IndentForJSON(t, w.Body.String())
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	g.Assert(t, t.Name(), resBody)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+	"golang.org/x/text/language"
+)
+
+// 一括操作のリクエストをドメインの操作に変換する。操作の形式に誤りがある場合は何も実行せずに400、
+// パスと異なるユーザーの本を含む場合は403を返すため、エラーはproblem.Wrap済みのものを返す。
+func convertShelfOperations(authUserId string, ops []ShelfOperation) ([]*domain.ShelfOperation, error) {
+	dops := make([]*domain.ShelfOperation, len(ops))
+	for i, op := range ops {
+		kind := domain.ShelfOpKind(op.Op)
+
+		var book *domain.Book
+		switch kind {
+		case domain.ShelfOpCreate, domain.ShelfOpUpdate:
+			if op.Book == nil {
+				err := fmt.Errorf("operations[%d]:bookが必要です:%w", i, ErrFailParse)
+				return nil, problem.Wrap(err, problem.CodeInvalidBook, nil)
+			}
+			b, err := convertBook(op.Book)
+			if err != nil {
+				return nil, problem.Wrap(fmt.Errorf("operations[%d]:%w", i, err), problem.CodeInvalidBook, nil)
+			}
+			//updateはbook.versionが指定された場合のみバージョンを確認する
+			if kind == domain.ShelfOpUpdate {
+				b.Version, err = parseBatchVersion(op.Book.Version)
+				if err != nil {
+					return nil, problem.Wrap(fmt.Errorf("operations[%d]:%w", i, err), problem.CodeInvalidBook, nil)
+				}
+			}
+			book = b
+		case domain.ShelfOpStatus, domain.ShelfOpDelete:
+			id, err := strconv.ParseInt(op.BookId, 10, 64)
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingBookId).SetInternal(fmt.Errorf("operations[%d]:%w", i, err))
+			}
+			book = &domain.Book{ID: id, AuthUserId: authUserId}
+			if kind == domain.ShelfOpStatus {
+				status := domain.BookStatus(op.BookStatus)
+				if status != domain.Bought && status != domain.Reading && status != domain.Read {
+					err := fmt.Errorf("operations[%d]:bookStatusの値が不正:%w:%s", i, ErrFailParse, op.BookStatus)
+					return nil, problem.Wrap(err, problem.CodeInvalidBook, nil)
+				}
+				book.BookStatus = status
+				book.Version, err = parseBatchVersion(op.Version)
+				if err != nil {
+					return nil, problem.Wrap(fmt.Errorf("operations[%d]:%w", i, err), problem.CodeInvalidBook, nil)
+				}
+			}
+		}
+		//パスと異なるユーザーの本は操作できない
+		if book.AuthUserId != authUserId {
+			return nil, problem.Wrap(domain.ErrForbidden, problem.CodeForbidden, nil)
+		}
+		dops[i] = &domain.ShelfOperation{Kind: kind, Book: book}
+	}
+	return dops, nil
+}
+
+// 一括操作で指定するバージョン（例."3"）。空の場合は0（バージョンを確認しない）。
+func parseBatchVersion(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("versionの数値変換に失敗:%w:%s", ErrFailParse, s)
+	}
+	return version, nil
+}
+
+// 一括操作の結果をJson形式に調整。操作ごとのステータスは単独のエンドポイントと同じ（create:201、update、status:200、delete:204）。
+// 失敗した操作はエラーのステータスとコード、他の操作の失敗で取り消された操作は424を返す。
+func tweakShelfBatchResultForJSON(res *domain.ShelfBatchResult, lang language.Tag) *ShelfBatchResult {
+	results := make([]ShelfOperationResult, len(res.Results))
+	for i, r := range res.Results {
+		result := ShelfOperationResult{Op: string(r.Kind)}
+		//取り消された作成は採番されたidも無効になる
+		if r.Book.ID != 0 && !(r.Aborted && r.Kind == domain.ShelfOpCreate) {
+			result.BookId = strconv.FormatInt(r.Book.ID, 10)
+		}
+
+		switch {
+		case r.Err != nil:
+			p := problem.FromError(wrapShelfOperationErr(r.Kind, r.Err), lang)
+			result.Status = p.Status
+			result.Code = string(p.Code)
+			result.Detail = p.Detail
+		case r.Aborted:
+			p := problem.New(http.StatusFailedDependency, problem.CodeBatchAborted, lang)
+			result.Status = p.Status
+			result.Code = string(p.Code)
+			result.Detail = p.Detail
+		case r.Kind == domain.ShelfOpCreate:
+			result.Status = http.StatusCreated
+			result.Version = strconv.FormatInt(r.Book.Version, 10)
+		case r.Kind == domain.ShelfOpDelete:
+			result.Status = http.StatusNoContent
+		default:
+			result.Status = http.StatusOK
+			result.Version = strconv.FormatInt(r.Book.Version, 10)
+		}
+		results[i] = result
+	}
+	return &ShelfBatchResult{Committed: res.Committed, Results: results}
+}
+
+// 操作ごとのエラーを単独のエンドポイントと同じコードにする
+func wrapShelfOperationErr(kind domain.ShelfOpKind, err error) error {
+	switch kind {
+	case domain.ShelfOpCreate:
+		return problem.Wrap(err, problem.CodeBookCreateFailed, problem.Codes{domain.ErrValidation: problem.CodeInvalidBook})
+	case domain.ShelfOpDelete:
+		return problem.Wrap(err, problem.CodeBookDeleteFailed, problem.Codes{domain.ErrNotFound: problem.CodeBookNotFound})
+	}
+	return problem.Wrap(err, problem.CodeBookUpdateFailed, problem.Codes{
+		domain.ErrNotFound:           problem.CodeBookNotFound,
+		domain.ErrPreconditionFailed: problem.CodePreconditionFailed,
+	})
+}
@@ -0,0 +1,207 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+	"github.com/uptrace/bun"
+)
+
+const batchAuthUserId = "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+
+// 状態の変更、削除、作成が成功し、未登録の本の状態の変更が失敗する一括操作
+const batchBody = `{"mode":"%s","operations":[
+	{"op":"status","bookId":"100","bookStatus":"read","version":"1"},
+	{"op":"delete","bookId":"101"},
+	{"op":"create","book":{"title":"予知夢","author":"東野圭吾","page":"300","price":"660","bookStatus":"bought","authUserId":"` + batchAuthUserId + `"}},
+	{"op":"status","bookId":"999","bookStatus":"read"}
+]}`
+
+func insertBatchBooks(ctx context.Context, t *testing.T, bundb *bun.DB) {
+	t.Helper()
+	for _, id := range []int64{100, 101} {
+		book := &domain.Book{
+			ID:         id,
+			Title:      "容疑者Xの献身",
+			Author:     "東野圭吾",
+			Page:       247,
+			Price:      980,
+			BookStatus: domain.Bought,
+			AuthUserId: batchAuthUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		}
+		testutils.InsertTestData(ctx, t, bundb, book)
+		charts := domain.NewChartsFromBook(book)
+		for _, c := range charts {
+			c.BookId = book.ID
+		}
+		testutils.InsertTestData(ctx, t, bundb, charts...)
+	}
+}
+
+func TestPostShelfAuthUserIdBatch(t *testing.T) {
+	tests := map[string]struct {
+		mode          string
+		committedWant bool
+		statusesWant  []int
+		bookCountWant int
+		statusWant    domain.BookStatus
+	}{
+		"atomic:ひとつでも失敗するとすべて取り消す": {
+			mode:          "atomic",
+			committedWant: false,
+			statusesWant:  []int{http.StatusFailedDependency, http.StatusFailedDependency, http.StatusFailedDependency, http.StatusNotFound},
+			bookCountWant: 2,
+			statusWant:    domain.Bought,
+		},
+		"bestEffort:成功した操作のみ反映する": {
+			mode:          "bestEffort",
+			committedWant: true,
+			statusesWant:  []int{http.StatusOK, http.StatusNoContent, http.StatusCreated, http.StatusNotFound},
+			bookCountWant: 2,
+			statusWant:    domain.Read,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Arrange ***************
+			ctx := context.Background()
+			dbctr.Restore(ctx, t)
+			bundb, err := infra.NewBunDB(dbctr.Dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				if err := bundb.Close(); err != nil {
+					log.Println(err)
+				}
+			}()
+			insertBatchBooks(ctx, t, bundb)
+
+			_, e := testutils.SetupHandler(bundb)
+			a := assert.New(t)
+
+			//Act ***************
+			w := serve(e, http.MethodPost, "/v1/shelf/"+batchAuthUserId+"/batch", strings.Replace(batchBody, "%s", tt.mode, 1), nil)
+			books := []*domain.Book{}
+			if err := bundb.NewSelect().Model(&books).Where("auth_user_id = ?", batchAuthUserId).Scan(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			//Assert ***************
+			a.Equal(http.StatusOK, w.Code, w.Body.String())
+			var got handler.ShelfBatchResult
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			a.Equal(tt.committedWant, got.Committed)
+			a.Len(got.Results, len(tt.statusesWant))
+			for i, r := range got.Results {
+				a.Equal(tt.statusesWant[i], r.Status, r.Op)
+			}
+			a.Equal(string(problem.CodeBookNotFound), got.Results[3].Code)
+
+			a.Len(books, tt.bookCountWant)
+			titles := map[int64]string{}
+			for _, b := range books {
+				titles[b.ID] = b.Title
+				if b.ID == 100 {
+					a.Equal(tt.statusWant, b.BookStatus)
+				}
+			}
+			if tt.committedWant {
+				a.Equal("2", got.Results[0].Version)
+				a.NotEmpty(got.Results[2].BookId)
+				a.NotContains(titles, int64(101))
+				a.Contains(titles, int64(100))
+			} else {
+				a.Equal(string(problem.CodeBatchAborted), got.Results[0].Code)
+				a.Empty(got.Results[2].BookId)
+				a.Equal(map[int64]string{100: "容疑者Xの献身", 101: "容疑者Xの献身"}, titles)
+			}
+		})
+	}
+}
+
+func TestPostShelfAuthUserIdBatchWithError(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+	insertBatchBooks(ctx, t, bundb)
+
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/shelf/" + batchAuthUserId + "/batch"
+	tooMany := `{"operations":[` + strings.Repeat(`{"op":"delete","bookId":"100"},`, domain.MaxShelfBatchSize) + `{"op":"delete","bookId":"101"}]}`
+
+	tests := map[string]struct {
+		body       string
+		statusWant int
+		codeWant   problem.Code
+	}{
+		"他のユーザーの本": {
+			body:       `{"operations":[{"op":"create","book":{"title":"予知夢","page":"300","price":"660","bookStatus":"bought","authUserId":"2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e"}}]}`,
+			statusWant: http.StatusForbidden,
+			codeWant:   problem.CodeForbidden,
+		},
+		"本の状態が不正": {
+			body:       `{"operations":[{"op":"status","bookId":"100","bookStatus":"lost"}]}`,
+			statusWant: http.StatusBadRequest,
+			codeWant:   problem.CodeInvalidBook,
+		},
+		"bookIdがない": {
+			body:       `{"operations":[{"op":"delete"}]}`,
+			statusWant: http.StatusBadRequest,
+			codeWant:   problem.CodeMissingBookId,
+		},
+		"操作数の上限超過": {
+			body:       tooMany,
+			statusWant: http.StatusBadRequest,
+			codeWant:   problem.CodeSpecMismatch,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := assert.New(t)
+
+			//Act ***************
+			w := serve(e, http.MethodPost, target, tt.body, nil)
+
+			//Assert ***************
+			a.Equal(tt.statusWant, w.Code, w.Body.String())
+			var got problem.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			a.Equal(tt.codeWant, got.Code)
+		})
+	}
+
+	//一括操作の前に検証するため、本は変わらない
+	count, err := bundb.NewSelect().Model((*domain.Book)(nil)).Where("auth_user_id = ?", batchAuthUserId).Count(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, count)
+}
@@ -1,20 +1,22 @@
 package handler
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/taimats/bhapi/apigenv2"
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/utils"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
 
+// 入力値の誤りのため、domain.ErrValidationの種類（400）として扱う
 var (
-	ErrFailParse = errors.New("要素のパースに失敗")
+	ErrFailParse = domain.NewError(domain.ErrValidation, "要素のパースに失敗")
 )
 
 // Json形式のUserをドメインのUser型に変換
@@ -43,14 +45,20 @@ func convertUser(u *User) (*domain.User, error) {
 		}
 	}
 
+	hc, err := domain.ParseCurrency(u.HomeCurrency)
+	if err != nil {
+		return nil, utils.NewErrChains(ErrFailParse, err)
+	}
+
 	return &domain.User{
-		ID:         id,
-		AuthUserId: u.AuthUserId,
-		Name:       u.Name,
-		Email:      domain.Email(u.Email),
-		Password:   domain.Password(u.Password),
-		CreatedAt:  ca,
-		UpdatedAt:  ua,
+		ID:           id,
+		AuthUserId:   u.AuthUserId,
+		Name:         u.Name,
+		Email:        domain.Email(u.Email),
+		Password:     domain.Password(u.Password),
+		HomeCurrency: hc,
+		CreatedAt:    ca,
+		UpdatedAt:    ua,
 	}, nil
 }
 
@@ -62,16 +70,20 @@ func convertBook(b *Book) (*domain.Book, error) {
 	if b.Id != "" {
 		id, err = strconv.ParseInt(b.Id, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("idの数値変換に失敗:%w", err)
+			return nil, fmt.Errorf("idの数値変換に失敗:%w:%w", ErrFailParse, err)
 		}
 	}
-	price, err := strconv.Atoi(strings.ReplaceAll(b.Price, ",", ""))
+	currency, err := domain.ParseCurrency(b.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("currencyの変換に失敗:%w:%w", ErrFailParse, err)
+	}
+	price, err := domain.ParsePrice(b.Price, currency)
 	if err != nil {
-		return nil, fmt.Errorf("priceの数値変換に失敗:%w", err)
+		return nil, fmt.Errorf("priceの数値変換に失敗:%w:%w", ErrFailParse, err)
 	}
 	page, err := strconv.Atoi(strings.ReplaceAll(b.Page, ",", ""))
 	if err != nil {
-		return nil, fmt.Errorf("pageの数値変換に失敗:%w", err)
+		return nil, fmt.Errorf("pageの数値変換に失敗:%w:%w", ErrFailParse, err)
 	}
 	ca := time.Now()
 	if b.CreatedAt != "" {
@@ -96,6 +108,7 @@ func convertBook(b *Book) (*domain.Book, error) {
 		Author:     b.Author,
 		Page:       page,
 		Price:      price,
+		Currency:   currency,
 		BookStatus: domain.BookStatus(b.BookStatus),
 		AuthUserId: b.AuthUserId,
 		CreatedAt:  ca,
@@ -105,47 +118,16 @@ func convertBook(b *Book) (*domain.Book, error) {
 	return book, nil
 }
 
-// ドメインUser型の配列をJson形式用に調整
+// ドメインUser型をJson形式用に調整
 func tweakUserForJSON(u *domain.User) *User {
-	return &User{
-		Id:         fmt.Sprint(u.ID),
-		Name:       fmt.Sprint(u.Name),
-		AuthUserId: fmt.Sprint(u.AuthUserId),
-		Email:      fmt.Sprint(u.Email),
-		Password:   u.Password.String(),
-		CreatedAt:  u.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  u.UpdatedAt.Format(time.RFC3339),
-	}
+	user := formatUserV1(newUserV2(u))
+	user.Password = u.Password.String()
+	return user
 }
 
 // ドメインBook型の配列をJson形式用に調整
-func tweakBooksForJSON(books []*domain.Book) []*Book {
-	if len(books) == 0 {
-		return []*Book{}
-	}
-
-	//3桁カンマ区切りで出力するためのfmt拡張
-	fmtx := message.NewPrinter(language.Japanese)
-
-	updateBooks := make([]*Book, len(books))
-	for i, book := range books {
-		b := &Book{
-			Id:         strconv.FormatInt(book.ID, 10),
-			Isbn10:     book.ISBN10,
-			ImageURL:   book.ImageURL,
-			Title:      book.Title,
-			Author:     book.Author,
-			Page:       fmtx.Sprint(book.Page),
-			Price:      fmtx.Sprint(book.Price),
-			BookStatus: string(book.BookStatus),
-			AuthUserId: book.AuthUserId,
-			CreatedAt:  book.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:  book.UpdatedAt.Format(time.RFC3339),
-		}
-		updateBooks[i] = b
-	}
-
-	return updateBooks
+func tweakBooksForJSON(books []*domain.Book) []Book {
+	return formatBooksV1(newBooksV2(books))
 }
 
 // ドメインRecord型の配列をJson形式に調整
@@ -153,25 +135,29 @@ func tweakRecordForJSON(dr *domain.Record) *Record {
 	//3桁カンマ区切りで出力するためのfmt拡張
 	fmtx := message.NewPrinter(language.Japanese)
 
+	r := newRecordV2(dr)
+	currency := domain.Currency(r.Currency)
 	record := &Record{
-		Costs:       fmtx.Sprint(dr.Costs),
-		CostsRead:   fmtx.Sprint(dr.CostsRead),
-		Volumes:     fmtx.Sprint(dr.Volumes),
-		VolumesRead: fmtx.Sprint(dr.VolumesRead),
-		Pages:       fmtx.Sprint(dr.Pages),
-		PagesRead:   fmtx.Sprint(dr.PagesRead),
+		Costs:       domain.FormatPrice(r.Costs, currency),
+		CostsRead:   domain.FormatPrice(r.CostsRead, currency),
+		Volumes:     fmtx.Sprint(r.Volumes),
+		VolumesRead: fmtx.Sprint(r.VolumesRead),
+		Pages:       fmtx.Sprint(r.Pages),
+		PagesRead:   fmtx.Sprint(r.PagesRead),
+		Currency:    r.Currency,
 	}
 
 	return record
 }
 
 // ドメインCharts型の配列をJson形式に調整
-func tweakChartsForJSON(chs []*domain.Chart) []*Chart {
-	charts := make([]*Chart, len(chs))
+func tweakChartsForJSON(chs []*domain.Chart) []Chart {
+	chsV2 := newChartsV2(chs)
+	charts := make([]Chart, len(chsV2))
 
-	for i, c := range chs {
-		chart := &Chart{
-			Label: fmt.Sprint(c.Label),
+	for i, c := range chsV2 {
+		chart := Chart{
+			Label: fmt.Sprint(chartLabelFromV2(c.Label)),
 			Year:  fmt.Sprint(c.Year),
 			Month: fmt.Sprintf("%v月", c.Month),
 			Data:  fmt.Sprint(c.Data),
@@ -182,6 +168,230 @@ func tweakChartsForJSON(chs []*domain.Chart) []*Chart {
 	return charts
 }
 
+// ドメインBookResult型の配列をJson形式に調整
+func tweakSearchResultsForJSON(results []*domain.BookResult) []SearchResult {
+	//3桁カンマ区切りで出力するためのfmt拡張
+	fmtx := message.NewPrinter(language.Japanese)
+
+	rsV2 := newSearchResultsV2(results)
+	rs := make([]SearchResult, len(rsV2))
+	for i, r := range rsV2 {
+		rs[i] = SearchResult{
+			ISBN10:   r.Isbn10,
+			ImageURL: r.ImageURL,
+			Title:    r.Title,
+			Author:   r.Author,
+			Page:     fmtx.Sprint(r.Page),
+			Price:    domain.FormatPrice(r.Price, domain.Currency(r.Currency)),
+			Currency: r.Currency,
+		}
+	}
+	return rs
+}
+
+// Json形式のExchangeRateの配列をドメインのExchangeRate型に変換
+func convertRates(rs []ExchangeRate) ([]*domain.ExchangeRate, error) {
+	rates := make([]*domain.ExchangeRate, len(rs))
+	for i, r := range rs {
+		currency, err := domain.ParseCurrency(r.Currency)
+		if err != nil {
+			return nil, utils.NewErrChains(ErrFailParse, err)
+		}
+		rate, err := strconv.ParseFloat(strings.ReplaceAll(r.Rate, ",", ""), 64)
+		if err != nil || rate <= 0 {
+			return nil, utils.NewErrChains(ErrFailParse, err)
+		}
+		rates[i] = &domain.ExchangeRate{Currency: currency, Rate: rate}
+	}
+	return rates, nil
+}
+
+// ドメインExchangeRate型の配列をJson形式に調整
+func tweakRatesForJSON(rates []*domain.ExchangeRate) []ExchangeRate {
+	rsV2 := newRatesV2(rates)
+	rs := make([]ExchangeRate, len(rsV2))
+	for i, r := range rsV2 {
+		rs[i] = ExchangeRate{
+			Currency:  r.Currency,
+			Rate:      strconv.FormatFloat(r.Rate, 'f', -1, 64),
+			UpdatedAt: r.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return rs
+}
+
+// Json形式のGoalをドメインのGoal型に変換。購入額の目標で通貨の指定がない場合はhome（ユーザーの基準通貨）の金額として変換する
+func convertGoal(g *Goal, authUserId string, home domain.Currency) (*domain.Goal, error) {
+	var err error
+
+	var id int64 = 0
+	if g.Id != "" {
+		id, err = strconv.ParseInt(g.Id, 10, 64)
+		if err != nil {
+			return nil, utils.NewErrChains(ErrFailParse, err)
+		}
+	}
+
+	var currency domain.Currency
+	if g.Currency != "" {
+		currency, err = domain.ParseCurrency(g.Currency)
+		if err != nil {
+			return nil, utils.NewErrChains(ErrFailParse, err)
+		}
+	}
+
+	var target int
+	if domain.GoalKind(g.Kind) == domain.GoalSpend {
+		if currency == "" {
+			currency = home
+		}
+		target, err = domain.ParsePrice(g.Target, currency)
+	} else {
+		target, err = strconv.Atoi(strings.ReplaceAll(g.Target, ",", ""))
+	}
+	if err != nil {
+		return nil, utils.NewErrChains(ErrFailParse, err)
+	}
+
+	return &domain.Goal{
+		ID:         id,
+		AuthUserId: authUserId,
+		Kind:       domain.GoalKind(g.Kind),
+		Period:     domain.GoalPeriod(g.Period),
+		Target:     target,
+		Currency:   currency,
+	}, nil
+}
+
+// ドメインGoalProgress型の配列をJson形式に調整
+func tweakGoalProgressForJSON(progress []*domain.GoalProgress) []GoalProgress {
+	//3桁カンマ区切りで出力するためのfmt拡張
+	fmtx := message.NewPrinter(language.Japanese)
+
+	gpsV2 := newGoalProgressV2(progress)
+	gps := make([]GoalProgress, len(gpsV2))
+	for i, p := range gpsV2 {
+		//購入額の上限は通貨の桁数に合わせて出力
+		format := func(n int) string { return fmtx.Sprint(n) }
+		if p.Kind == apigenv2.Spend {
+			format = func(n int) string { return domain.FormatPrice(n, domain.Currency(p.Currency)) }
+		}
+
+		gps[i] = GoalProgress{
+			Id:          strconv.FormatInt(p.Id, 10),
+			Kind:        string(p.Kind),
+			Period:      string(p.Period),
+			Target:      format(p.Target),
+			Currency:    p.Currency,
+			Current:     format(p.Current),
+			Expected:    format(p.Expected),
+			Remaining:   format(p.Remaining),
+			DaysLeft:    fmt.Sprint(p.DaysLeft),
+			PacePerDay:  strconv.FormatFloat(p.PacePerDay, 'f', -1, 64),
+			Status:      string(p.Status),
+			PeriodStart: p.PeriodStart.Format(time.RFC3339),
+			PeriodEnd:   p.PeriodEnd.Format(time.RFC3339),
+		}
+	}
+	return gps
+}
+
+// ドメインBacklog型をJson形式に調整
+func tweakBacklogForJSON(db *domain.Backlog) *Backlog {
+	//3桁カンマ区切りで出力するためのfmt拡張
+	fmtx := message.NewPrinter(language.Japanese)
+
+	b := newBacklogV2(db)
+	months := make([]BacklogMonth, len(b.Months))
+	for i, m := range b.Months {
+		months[i] = BacklogMonth{
+			Year:    fmt.Sprint(m.Year),
+			Month:   fmt.Sprintf("%v月", m.Month),
+			Bought:  fmtx.Sprint(m.Bought),
+			Read:    fmtx.Sprint(m.Read),
+			Backlog: fmtx.Sprint(m.Backlog),
+		}
+	}
+
+	backlog := &Backlog{
+		Months:        months,
+		UnreadVolumes: fmtx.Sprint(b.UnreadVolumes),
+		UnreadCosts:   domain.FormatPrice(b.UnreadCosts, domain.Currency(b.Currency)),
+		Currency:      b.Currency,
+		OldestUnread:  formatBooksV1(b.OldestUnread),
+		ReadPerDay:    strconv.FormatFloat(b.ReadPerDay, 'f', -1, 64),
+	}
+	if b.DaysToClear != nil {
+		backlog.DaysToClear = fmtx.Sprint(*b.DaysToClear)
+	}
+
+	return backlog
+}
+
+// ドメインTrash型をJson形式に調整
+func tweakTrashForJSON(dt *domain.Trash) *Trash {
+	t := newTrashV2(dt)
+	trash := &Trash{
+		Books:         formatBooksV1(t.Books),
+		RetentionDays: fmt.Sprint(t.RetentionDays),
+	}
+	if t.User != nil {
+		trash.User = formatUserV1(t.User)
+		trash.User.Password = dt.User.Password.String()
+	}
+	return trash
+}
+
+// v2のUserをv1のJson形式（文字列）に整形
+func formatUserV1(u *UserV2) *User {
+	return &User{
+		Id:           strconv.FormatInt(u.Id, 10),
+		Name:         u.Name,
+		AuthUserId:   u.AuthUserId,
+		Email:        string(u.Email),
+		HomeCurrency: u.HomeCurrency,
+		Version:      strconv.FormatInt(u.Version, 10),
+		CreatedAt:    u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    u.UpdatedAt.Format(time.RFC3339),
+		DeletedAt:    formatDeletedAt(u.DeletedAt),
+	}
+}
+
+// v2のBookの配列をv1のJson形式（文字列）に整形
+func formatBooksV1(books []BookV2) []Book {
+	//3桁カンマ区切りで出力するためのfmt拡張
+	fmtx := message.NewPrinter(language.Japanese)
+
+	bs := make([]Book, len(books))
+	for i, b := range books {
+		bs[i] = Book{
+			Id:         strconv.FormatInt(b.Id, 10),
+			Isbn10:     b.Isbn10,
+			ImageURL:   b.ImageURL,
+			Title:      b.Title,
+			Author:     b.Author,
+			Page:       fmtx.Sprint(b.Page),
+			Price:      domain.FormatPrice(b.Price, domain.Currency(b.Currency)),
+			Currency:   b.Currency,
+			BookStatus: string(b.BookStatus),
+			AuthUserId: b.AuthUserId,
+			Version:    strconv.FormatInt(b.Version, 10),
+			CreatedAt:  b.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:  b.UpdatedAt.Format(time.RFC3339),
+			DeletedAt:  formatDeletedAt(b.DeletedAt),
+		}
+	}
+	return bs
+}
+
+// 削除済みでない（nilの）場合は空文字を返す
+func formatDeletedAt(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 // RFC3339形式のtime文字列をtime.Time型に変換するヘルパー関数。
 // 引数sにはRFC3339形式(例."2006-01-02T15:04:05Z07:00")の文字列を入れる。
 func parseStrTime(s string) (time.Time, error) {
@@ -191,3 +401,155 @@ func parseStrTime(s string) (time.Time, error) {
 	}
 	return t, nil
 }
+
+// ドメインEvent型をJson形式用に調整
+func tweakEventForJSON(ev *domain.Event) *Event {
+	e := &Event{
+		Id:         strconv.FormatInt(ev.ID, 10),
+		Type:       string(ev.Type),
+		AuthUserId: ev.AuthUserId,
+		CreatedAt:  ev.CreatedAt.Format(time.RFC3339),
+	}
+	if ev.BookId != 0 {
+		e.BookId = strconv.FormatInt(ev.BookId, 10)
+	}
+	if ev.GoalId != 0 {
+		e.GoalId = strconv.FormatInt(ev.GoalId, 10)
+	}
+	if ev.Version != 0 {
+		e.Version = strconv.FormatInt(ev.Version, 10)
+	}
+	return e
+}
+
+// ドメインWebhook型をJson形式用に調整（署名の鍵は含めない）
+func tweakWebhookForJSON(w *domain.Webhook) *Webhook {
+	res := &Webhook{
+		Id:        strconv.FormatInt(w.ID, 10),
+		Url:       w.URL,
+		CreatedAt: w.CreatedAt.Format(time.RFC3339),
+	}
+	for _, ev := range w.Events {
+		res.Events = append(res.Events, string(ev))
+	}
+	return res
+}
+
+// ドメインAPIKey型をJson形式用に調整（キー自体は含まない）
+func tweakAPIKeyForJSON(k *domain.APIKey) *APIKey {
+	res := &APIKey{
+		Id:        strconv.FormatInt(k.ID, 10),
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    make([]string, len(k.Scopes)),
+		CreatedAt: k.CreatedAt.Format(time.RFC3339),
+	}
+	for i, s := range k.Scopes {
+		res.Scopes[i] = string(s)
+	}
+	if !k.ExpiresAt.IsZero() {
+		res.ExpiresAt = k.ExpiresAt.Format(time.RFC3339)
+	}
+	if !k.LastUsedAt.IsZero() {
+		res.LastUsedAt = k.LastUsedAt.Format(time.RFC3339)
+	}
+	return res
+}
+
+// ドメインUser型を管理API用のJson形式に調整（パスワードは含まない）
+func tweakAdminUserForJSON(u *domain.User) AdminUser {
+	res := AdminUser{
+		AuthUserId:    u.AuthUserId,
+		Name:          u.Name,
+		Email:         string(u.Email),
+		Role:          string(u.Role),
+		EmailVerified: !u.EmailVerifiedAt.IsZero(),
+		CreatedAt:     u.CreatedAt.Format(time.RFC3339),
+	}
+	if !u.DisabledAt.IsZero() {
+		res.DisabledAt = u.DisabledAt.Format(time.RFC3339)
+	}
+	return res
+}
+
+// 通貨ごとの購入額をJson形式用に調整
+func tweakSpendForJSON(spend []domain.Spend) []Spend {
+	res := make([]Spend, len(spend))
+	for i, s := range spend {
+		res[i] = Spend{Currency: string(s.Currency), Amount: s.Amount}
+	}
+	return res
+}
+
+func tweakRevocationForJSON(rv *domain.Revocation) Revocation {
+	return Revocation{ApiKeys: rv.APIKeys, Tokens: rv.Tokens}
+}
+
+// ドメインWebhookDelivery型をJson形式用に調整
+func tweakWebhookDeliveryForJSON(d *domain.WebhookDelivery) *WebhookDelivery {
+	res := &WebhookDelivery{
+		Id:         strconv.FormatInt(d.ID, 10),
+		WebhookId:  strconv.FormatInt(d.WebhookId, 10),
+		EventId:    strconv.FormatInt(d.OutboxId, 10),
+		Type:       string(d.Type),
+		Attempts:   d.Attempts,
+		LastStatus: d.LastStatus,
+		LastError:  d.LastError,
+		CreatedAt:  d.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  d.UpdatedAt.Format(time.RFC3339),
+	}
+	if err := json.Unmarshal(d.Payload, &res.Payload); err != nil {
+		res.Payload = nil
+	}
+	return res
+}
+
+// 監査ログをJson形式用に調整
+func tweakAuditLogForJSON(l *domain.AuditLog) AuditLog {
+	res := AuditLog{
+		Id:         strconv.FormatInt(l.ID, 10),
+		Actor:      l.Actor,
+		Via:        string(l.Via),
+		AuthUserId: l.AuthUserId,
+		TargetType: string(l.TargetType),
+		TargetId:   l.TargetId,
+		Action:     string(l.Action),
+		Diff:       make(map[string]AuditChange, len(l.Diff)),
+		RequestId:  l.RequestId,
+		Ip:         l.IP,
+		CreatedAt:  l.CreatedAt.Format(time.RFC3339),
+	}
+	if l.APIKeyId != 0 {
+		res.ApiKeyId = strconv.FormatInt(l.APIKeyId, 10)
+	}
+	for k, c := range l.Diff {
+		res.Diff[k] = AuditChange{Before: c.Before, After: c.After}
+	}
+	return res
+}
+
+// 本の変更履歴の版をJson形式用に調整。値の変わった項目は列名からJSONの項目名に変換する
+func tweakBookRevisionForJSON(r *domain.BookRevision) BookRevision {
+	book := &domain.Book{ID: r.BookId, AuthUserId: r.AuthUserId, Version: r.Version}
+	r.Apply(book)
+	res := BookRevision{
+		Id:        strconv.FormatInt(r.ID, 10),
+		Version:   strconv.FormatInt(r.Version, 10),
+		Book:      tweakBooksForJSON([]*domain.Book{book})[0],
+		Changed:   []string{},
+		CreatedAt: r.CreatedAt.Format(time.RFC3339),
+	}
+	//版には更新日時を記録しない。購入日は記録した版のみ返す
+	res.Book.UpdatedAt = ""
+	if r.BookCreatedAt.IsZero() {
+		res.Book.CreatedAt = ""
+	}
+	for _, column := range r.ChangedColumns() {
+		for _, pc := range bookPatchColumns {
+			if pc.column == column {
+				res.Changed = append(res.Changed, pc.field)
+			}
+		}
+	}
+	return res
+}
@@ -27,7 +27,7 @@ func TestTweakBooksForJSON(t *testing.T) {
 			UpdatedAt:  cl.Now(),
 		},
 	}
-	want := []*Book{
+	want := []Book{
 		{
 			Id:         "4167",
 			Isbn10:     "4167110121",
@@ -36,6 +36,7 @@ func TestTweakBooksForJSON(t *testing.T) {
 			Author:     "東野圭吾",
 			Page:       "2,110",
 			Price:      "8,800",
+			Currency:   "JPY",
 			BookStatus: "bought",
 			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
 			CreatedAt:  cl.NowString(),
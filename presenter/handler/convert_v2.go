@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/taimats/bhapi/apigenv2"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+// v2では数値、日時、列挙型をそのまま返し、表示用の整形（カンマ区切り、"2月"など）はクライアントに任せる。
+// v1のJson形式は、この変換結果をもとに文字列へ整形して作成する（convert.goを参照）。
+
+// チャートのラベル（ドメインは表示用の日本語）とv2の列挙型の対応
+var chartLabelsV2 = map[domain.ChartLabel]apigenv2.ChartLabel{
+	domain.ChartPrice:   apigenv2.ChartLabelPrice,
+	domain.ChartVolumes: apigenv2.ChartLabelVolumes,
+	domain.ChartPages:   apigenv2.ChartLabelPages,
+}
+
+// v2のチャートのラベルをドメインのラベルに戻す
+func chartLabelFromV2(l apigenv2.ChartLabel) domain.ChartLabel {
+	for dl, vl := range chartLabelsV2 {
+		if vl == l {
+			return dl
+		}
+	}
+	return domain.ChartLabel(l)
+}
+
+// ドメインBook型をv2のJson形式に変換
+func newBookV2(b *domain.Book) BookV2 {
+	return BookV2{
+		Id:         b.ID,
+		Isbn10:     b.ISBN10,
+		ImageURL:   b.ImageURL,
+		Title:      b.Title,
+		Author:     b.Author,
+		Page:       b.Page,
+		Price:      b.Price,
+		Currency:   string(b.Currency.OrDefault()),
+		BookStatus: apigenv2.BookBookStatus(b.BookStatus),
+		AuthUserId: b.AuthUserId,
+		Version:    b.Version,
+		CreatedAt:  b.CreatedAt,
+		UpdatedAt:  b.UpdatedAt,
+		DeletedAt:  deletedAtV2(b.DeletedAt),
+	}
+}
+
+// ドメインBook型の配列をv2のJson形式に変換
+func newBooksV2(books []*domain.Book) []BookV2 {
+	bs := make([]BookV2, len(books))
+	for i, b := range books {
+		bs[i] = newBookV2(b)
+	}
+	return bs
+}
+
+// ドメインUser型をv2のJson形式に変換（パスワードは返さない）
+func newUserV2(u *domain.User) *UserV2 {
+	return &UserV2{
+		Id:           u.ID,
+		AuthUserId:   u.AuthUserId,
+		Name:         u.Name,
+		Email:        openapi_types.Email(u.Email),
+		HomeCurrency: string(u.HomeCurrency.OrDefault()),
+		Version:      u.Version,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+		DeletedAt:    deletedAtV2(u.DeletedAt),
+	}
+}
+
+// ドメインRecord型をv2のJson形式に変換
+func newRecordV2(dr *domain.Record) *RecordV2 {
+	return &RecordV2{
+		Costs:       dr.Costs,
+		CostsRead:   dr.CostsRead,
+		Volumes:     dr.Volumes,
+		VolumesRead: dr.VolumesRead,
+		Pages:       dr.Pages,
+		PagesRead:   dr.PagesRead,
+		Currency:    string(dr.Currency.OrDefault()),
+	}
+}
+
+// ドメインChart型の配列をv2のJson形式に変換
+func newChartsV2(chs []*domain.Chart) []ChartV2 {
+	charts := make([]ChartV2, len(chs))
+	for i, c := range chs {
+		charts[i] = ChartV2{
+			Label:    chartLabelsV2[c.Label],
+			Year:     c.Year,
+			Month:    c.Month,
+			Data:     c.Data,
+			Currency: string(c.Currency),
+		}
+	}
+	return charts
+}
+
+// ドメインExchangeRate型の配列をv2のJson形式に変換
+func newRatesV2(rates []*domain.ExchangeRate) []ExchangeRateV2 {
+	rs := make([]ExchangeRateV2, len(rates))
+	for i, r := range rates {
+		rs[i] = ExchangeRateV2{
+			Currency:  string(r.Currency),
+			Rate:      r.Rate,
+			UpdatedAt: r.UpdatedAt,
+		}
+	}
+	return rs
+}
+
+// ドメインGoalProgress型の配列をv2のJson形式に変換
+func newGoalProgressV2(progress []*domain.GoalProgress) []GoalProgressV2 {
+	gps := make([]GoalProgressV2, len(progress))
+	for i, p := range progress {
+		gps[i] = GoalProgressV2{
+			Id:          p.Goal.ID,
+			Kind:        apigenv2.GoalProgressKind(p.Goal.Kind),
+			Period:      apigenv2.GoalProgressPeriod(p.Goal.Period),
+			Target:      p.Goal.Target,
+			Currency:    string(p.Goal.Currency),
+			Current:     p.Current,
+			Expected:    p.Expected,
+			Remaining:   p.Remaining,
+			DaysLeft:    p.DaysLeft,
+			PacePerDay:  p.PacePerDay,
+			Status:      apigenv2.GoalProgressStatus(p.Status),
+			PeriodStart: p.PeriodStart,
+			PeriodEnd:   p.PeriodEnd,
+		}
+	}
+	return gps
+}
+
+// ドメインBacklog型をv2のJson形式に変換
+func newBacklogV2(db *domain.Backlog) *BacklogV2 {
+	months := make([]BacklogMonthV2, len(db.Months))
+	for i, m := range db.Months {
+		months[i] = BacklogMonthV2{
+			Year:    m.Year,
+			Month:   m.Month,
+			Bought:  m.Bought,
+			Read:    m.Read,
+			Backlog: m.Backlog,
+		}
+	}
+
+	backlog := &BacklogV2{
+		Months:        months,
+		UnreadVolumes: db.UnreadVolumes,
+		UnreadCosts:   db.UnreadCosts,
+		Currency:      string(db.Currency.OrDefault()),
+		OldestUnread:  newBooksV2(db.OldestUnread),
+		ReadPerDay:    db.ReadPerDay,
+	}
+	//読了ペースが0の場合（-1）は返さない
+	if db.DaysToClear >= 0 {
+		days := db.DaysToClear
+		backlog.DaysToClear = &days
+	}
+	return backlog
+}
+
+// ドメインTrash型をv2のJson形式に変換
+func newTrashV2(dt *domain.Trash) *TrashV2 {
+	trash := &TrashV2{
+		Books:         newBooksV2(dt.Books),
+		RetentionDays: int(dt.Retention.Hours() / 24),
+	}
+	if dt.User != nil {
+		trash.User = newUserV2(dt.User)
+	}
+	return trash
+}
+
+// ドメインBookResult型の配列をv2のJson形式に変換
+func newSearchResultsV2(results []*domain.BookResult) []SearchResultV2 {
+	rs := make([]SearchResultV2, len(results))
+	for i, r := range results {
+		rs[i] = SearchResultV2{
+			Isbn10:   r.ISBN10,
+			ImageURL: r.ImageURL,
+			Title:    r.Title,
+			Author:   r.Author,
+			Page:     r.Page,
+			Price:    r.Price,
+			Currency: string(r.Currency.OrDefault()),
+		}
+	}
+	return rs
+}
+
+// v2のJson形式BookをドメインのBook型に変換。authUserIdはパスの値を使う。
+func convertBookV2(b *BookV2, authUserId string) (*domain.Book, error) {
+	currency, err := domain.ParseCurrency(b.Currency)
+	if err != nil {
+		return nil, utils.NewErrChains(ErrFailParse, err)
+	}
+
+	now := time.Now()
+	return &domain.Book{
+		ISBN10:     b.Isbn10,
+		ImageURL:   b.ImageURL,
+		Title:      b.Title,
+		Author:     b.Author,
+		Page:       b.Page,
+		Price:      b.Price,
+		Currency:   currency,
+		BookStatus: domain.BookStatus(b.BookStatus),
+		AuthUserId: authUserId,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// v2のJson形式UserをドメインのUser型に変換
+func convertUserV2(u *UserV2) (*domain.User, error) {
+	hc, err := domain.ParseCurrency(u.HomeCurrency)
+	if err != nil {
+		return nil, utils.NewErrChains(ErrFailParse, err)
+	}
+
+	return &domain.User{
+		AuthUserId:   u.AuthUserId,
+		Name:         u.Name,
+		Email:        domain.Email(u.Email),
+		Password:     domain.Password(u.Password),
+		HomeCurrency: hc,
+	}, nil
+}
+
+// v2のJson形式のGoalをドメインのGoal型に変換
+func convertGoalV2(g *GoalV2, authUserId string) (*domain.Goal, error) {
+	var currency domain.Currency
+	if g.Currency != "" {
+		c, err := domain.ParseCurrency(g.Currency)
+		if err != nil {
+			return nil, utils.NewErrChains(ErrFailParse, err)
+		}
+		currency = c
+	}
+
+	return &domain.Goal{
+		AuthUserId: authUserId,
+		Kind:       domain.GoalKind(g.Kind),
+		Period:     domain.GoalPeriod(g.Period),
+		Target:     g.Target,
+		Currency:   currency,
+	}, nil
+}
+
+// 削除済みでない（ゼロ値の）場合はnilを返す
+func deletedAtV2(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigenv2"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+func TestNewBooksV2(t *testing.T) {
+	//Arrange
+	cl := utils.NewTestClocker()
+
+	books := []*domain.Book{
+		{
+			ID:         4167,
+			ISBN10:     "4167110121",
+			Title:      "容疑者Xの献身",
+			Author:     "東野圭吾",
+			Page:       2110,
+			Price:      1999,
+			Currency:   domain.Currency("USD"),
+			BookStatus: domain.Bought,
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	want := []BookV2{
+		{
+			Id:         4167,
+			Isbn10:     "4167110121",
+			Title:      "容疑者Xの献身",
+			Author:     "東野圭吾",
+			Page:       2110,
+			Price:      1999,
+			Currency:   "USD",
+			BookStatus: apigenv2.Bought,
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+
+	//Act
+	got := newBooksV2(books)
+
+	//Assert
+	assert.Equal(t, want, got)
+}
+
+func TestTweakChartsForJSON(t *testing.T) {
+	//Arrange
+	chs := []*domain.Chart{
+		{Label: domain.ChartPrice, Year: 2024, Month: 2, Data: 1640, Currency: domain.JPY},
+		{Label: domain.ChartVolumes, Year: 2024, Month: 2, Data: 1},
+		{Label: domain.ChartPages, Year: 2024, Month: 2, Data: 1330},
+	}
+	wantV2 := []ChartV2{
+		{Label: apigenv2.ChartLabelPrice, Year: 2024, Month: 2, Data: 1640, Currency: "JPY"},
+		{Label: apigenv2.ChartLabelVolumes, Year: 2024, Month: 2, Data: 1},
+		{Label: apigenv2.ChartLabelPages, Year: 2024, Month: 2, Data: 1330},
+	}
+	//v1はv2の変換結果を表示用の文字列に整形する
+	want := []Chart{
+		{Label: "購入額", Year: "2024", Month: "2月", Data: "1640"},
+		{Label: "購入冊数", Year: "2024", Month: "2月", Data: "1"},
+		{Label: "購入ページ数", Year: "2024", Month: "2月", Data: "1330"},
+	}
+
+	//Act
+	gotV2 := newChartsV2(chs)
+	got := tweakChartsForJSON(chs)
+
+	//Assert
+	assert.Equal(t, wantV2, gotV2)
+	assert.Equal(t, want, got)
+}
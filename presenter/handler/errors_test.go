@@ -0,0 +1,189 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+// リポジトリ、コントローラのエラーが、エンドポイントごとに正しいステータスとコードになることを確認する
+func TestErrorStatusMapping(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入（本のないユーザーも用意する）
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	emptyUserId := "2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e"
+	users := []*domain.User{
+		{ID: int64(1), AuthUserId: authUserId, Email: "example@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()},
+		{ID: int64(2), AuthUserId: emptyUserId, Email: "empty@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()},
+	}
+	testutils.InsertTestData(ctx, t, bundb, users...)
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	_, e := testutils.SetupHandler(bundb)
+
+	tests := map[string]struct {
+		method     string
+		target     string
+		body       string
+		statusWant int
+		codeWant   problem.Code
+	}{
+		//404
+		"GET /shelf（本なし）": {
+			method: http.MethodGet, target: "/v1/shelf/" + emptyUserId,
+			statusWant: http.StatusNotFound, codeWant: problem.CodeShelfNotFound,
+		},
+		"GET /v2/shelf（本なし）": {
+			method: http.MethodGet, target: "/v2/shelf/" + emptyUserId,
+			statusWant: http.StatusNotFound, codeWant: problem.CodeShelfNotFound,
+		},
+		"PUT /shelf（本なし）": {
+			method: http.MethodPut, target: "/v1/shelf/" + authUserId,
+			body:       `{"id":"100","title":"予知夢","page":"220","price":"220","bookStatus":"read","authUserId":"` + authUserId + `"}`,
+			statusWant: http.StatusNotFound, codeWant: problem.CodeBookNotFound,
+		},
+		"PUT /users（ユーザーなし）": {
+			method: http.MethodPut, target: "/v1/users",
+			body:       `{"id":"100","authUserId":"unknown","email":"unknown@example.com","password":"password123"}`,
+			statusWant: http.StatusNotFound, codeWant: problem.CodeUserNotFound,
+		},
+		"PUT /v2/users（ユーザーなし）": {
+			method: http.MethodPut, target: "/v2/users/unknown",
+			body:       `{"authUserId":"unknown","email":"unknown@example.com"}`,
+			statusWant: http.StatusNotFound, codeWant: problem.CodeUserNotFound,
+		},
+		//409
+		"POST /auth/register（登録済み）": {
+			method: http.MethodPost, target: "/v1/auth/register",
+			body:       `{"authUserId":"` + authUserId + `","email":"example@example.com","password":"password123"}`,
+			statusWant: http.StatusConflict, codeWant: problem.CodeUserAlreadyExists,
+		},
+		"POST /v2/auth/register（登録済み）": {
+			method: http.MethodPost, target: "/v2/auth/register",
+			body:       `{"authUserId":"` + authUserId + `","email":"example@example.com","password":"password123"}`,
+			statusWant: http.StatusConflict, codeWant: problem.CodeUserAlreadyExists,
+		},
+		//403
+		"PUT /shelf（他のユーザーの本）": {
+			method: http.MethodPut, target: "/v1/shelf/" + emptyUserId,
+			body:       `{"id":"1","title":"容疑者Xの献身","page":"330","price":"1800","bookStatus":"read","authUserId":"` + authUserId + `"}`,
+			statusWant: http.StatusForbidden, codeWant: problem.CodeForbidden,
+		},
+		"POST /shelf（他のユーザーの本）": {
+			method: http.MethodPost, target: "/v1/shelf/" + emptyUserId,
+			body:       `{"title":"予知夢","page":"220","price":"220","bookStatus":"read","authUserId":"` + authUserId + `"}`,
+			statusWant: http.StatusForbidden, codeWant: problem.CodeForbidden,
+		},
+		//400
+		"PUT /shelf（価格が数値でない）": {
+			method: http.MethodPut, target: "/v1/shelf/" + authUserId,
+			body:       `{"id":"1","title":"容疑者Xの献身","page":"330","price":"abc","bookStatus":"read","authUserId":"` + authUserId + `"}`,
+			statusWant: http.StatusBadRequest, codeWant: problem.CodeInvalidBook,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			w := httptest.NewRecorder()
+
+			//Act ***************
+			e.ServeHTTP(w, r)
+
+			//Assert ***************
+			var got problem.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.statusWant, w.Code, w.Body.String())
+			assert.Equal(t, tt.codeWant, got.Code)
+		})
+	}
+}
+
+// DBの障害などの内部エラーは、対象なし（404）ではなく500になることを確認する
+func TestErrorStatusMappingInternal(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, e := testutils.SetupHandler(bundb)
+
+	//接続を閉じてDBのエラーを発生させる
+	if err := bundb.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	tests := map[string]struct {
+		target   string
+		codeWant problem.Code
+	}{
+		"GET /records":    {target: "/v1/records/" + authUserId, codeWant: problem.CodeRecordGetFailed},
+		"GET /users":      {target: "/v1/users/" + authUserId, codeWant: problem.CodeUserGetFailed},
+		"GET /shelf":      {target: "/v1/shelf/" + authUserId, codeWant: problem.CodeShelfGetFailed},
+		"GET /v2/records": {target: "/v2/records/" + authUserId, codeWant: problem.CodeRecordGetFailed},
+		"GET /v2/users":   {target: "/v2/users/" + authUserId, codeWant: problem.CodeUserGetFailed},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			w := httptest.NewRecorder()
+
+			//Act ***************
+			e.ServeHTTP(w, r)
+
+			//Assert ***************
+			var got problem.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+			assert.Equal(t, tt.codeWant, got.Code)
+		})
+	}
+}
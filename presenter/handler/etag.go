@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/utils"
+)
+
+// 条件付きリクエストのヘッダー
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// 本、ユーザーのバージョンをETagの形式（例."3"）にする
+func versionETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// 更新後のバージョンをETagヘッダーに設定
+func setVersionETag(c echo.Context, version int64) {
+	c.Response().Header().Set(HeaderETag, versionETag(version))
+}
+
+// If-Matchヘッダーから更新の前提とするバージョンを返す。
+// ヘッダーがない、または"*"の場合は0（バージョンを確認しない）。
+// バージョンのETagでない場合（弱いETag、複数指定を含む）は一致しないため、domain.ErrPreconditionFailedを返す。
+func ifMatchVersion(ifMatch *string) (int64, error) {
+	if ifMatch == nil {
+		return 0, nil
+	}
+	tag := strings.TrimSpace(*ifMatch)
+	if tag == "" || tag == "*" {
+		return 0, nil
+	}
+
+	s, err := strconv.Unquote(tag)
+	if err != nil || !strings.HasPrefix(tag, `"`) {
+		return 0, utils.NewErrChains(domain.ErrPreconditionFailed, err)
+	}
+	version, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, utils.NewErrChains(domain.ErrPreconditionFailed, err)
+	}
+	return version, nil
+}
+
+// vをJsonで返す。ボディのハッシュをETagとして付け、If-None-Matchが一致する場合はボディを返さずに304を返す。
+// ボディはc.JSONと同じ形式（末尾に改行）。
+func jsonWithETag(c echo.Context, ifNoneMatch *string, v any) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+
+	//圧縮などでボディのバイト列が変わっても同じ内容として扱うため、弱いETagにする
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Response().Header().Set(HeaderETag, etag)
+
+	if ifNoneMatch != nil && etagMatch(*ifNoneMatch, etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSONBlob(http.StatusOK, buf.Bytes())
+}
+
+// If-None-Matchのいずれか（カンマ区切り、"*"）がetagに一致するか。比較は弱い比較（W/を無視）。
+func etagMatch(header string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
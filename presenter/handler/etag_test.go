@@ -0,0 +1,183 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func serve(e *echo.Echo, method string, target string, body string, header map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+	return w
+}
+
+// If-None-Matchが一覧のETagに一致する場合は304、一致しない場合は200を返すことを確認する
+func TestIfNoneMatch(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	_, e := testutils.SetupHandler(bundb)
+
+	tests := map[string]struct {
+		target string
+	}{
+		"GET /shelf":      {target: "/v1/shelf/" + authUserId},
+		"GET /charts":     {target: "/v1/charts/" + authUserId},
+		"GET /records":    {target: "/v1/records/" + authUserId},
+		"GET /v2/shelf":   {target: "/v2/shelf/" + authUserId},
+		"GET /v2/records": {target: "/v2/records/" + authUserId},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			first := serve(e, http.MethodGet, tt.target, "", nil)
+			etag := first.Header().Get(handler.HeaderETag)
+
+			//Act ***************
+			matched := serve(e, http.MethodGet, tt.target, "", map[string]string{handler.HeaderIfNoneMatch: etag})
+			unmatched := serve(e, http.MethodGet, tt.target, "", map[string]string{handler.HeaderIfNoneMatch: `W/"stale"`})
+
+			//Assert ***************
+			assert.Equal(t, http.StatusOK, first.Code, first.Body.String())
+			assert.NotEmpty(t, etag)
+			assert.Equal(t, http.StatusNotModified, matched.Code)
+			assert.Empty(t, matched.Body.String())
+			assert.Equal(t, etag, matched.Header().Get(handler.HeaderETag))
+			assert.Equal(t, http.StatusOK, unmatched.Code)
+			assert.Equal(t, first.Body.String(), unmatched.Body.String())
+		})
+	}
+}
+
+// If-Matchのバージョンが古い更新は412になり、上書きされないことを確認する
+func TestIfMatch(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	user := &domain.User{ID: int64(1), AuthUserId: authUserId, Email: "example@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	_, e := testutils.SetupHandler(bundb)
+	bookBody := `{"id":"1","title":"容疑者Xの献身","page":"330","price":"1800","bookStatus":"read","authUserId":"` + authUserId + `"}`
+	userBody := `{"authUserId":"` + authUserId + `","name":"update","email":"example@example.com"}`
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		ifMatch    string
+		statusWant int
+		etagWant   string
+	}{
+		{name: "PUT /shelf（最新のバージョン）", method: http.MethodPut, target: "/v1/shelf/" + authUserId, body: bookBody, ifMatch: `"1"`, statusWant: http.StatusOK, etagWant: `"2"`},
+		{name: "PUT /shelf（古いバージョン）", method: http.MethodPut, target: "/v1/shelf/" + authUserId, body: bookBody, ifMatch: `"1"`, statusWant: http.StatusPreconditionFailed},
+		{name: "PUT /shelf（バージョンの形式でない）", method: http.MethodPut, target: "/v1/shelf/" + authUserId, body: bookBody, ifMatch: `W/"2"`, statusWant: http.StatusPreconditionFailed},
+		{name: "PUT /shelf（If-Matchなし）", method: http.MethodPut, target: "/v1/shelf/" + authUserId, body: bookBody, statusWant: http.StatusOK, etagWant: `"3"`},
+		{name: "PUT /v2/users（最新のバージョン）", method: http.MethodPut, target: "/v2/users/" + authUserId, body: userBody, ifMatch: `"1"`, statusWant: http.StatusOK, etagWant: `"2"`},
+		{name: "PUT /v2/users（古いバージョン）", method: http.MethodPut, target: "/v2/users/" + authUserId, body: userBody, ifMatch: `"1"`, statusWant: http.StatusPreconditionFailed},
+		//If-Matchは任意。ない場合、"*"の場合はバージョンを確認せずに上書きする
+		{name: "PUT /v2/users（If-Matchなし）", method: http.MethodPut, target: "/v2/users/" + authUserId, body: userBody, statusWant: http.StatusOK, etagWant: `"3"`},
+		{name: "PUT /shelf（If-Matchが*）", method: http.MethodPut, target: "/v1/shelf/" + authUserId, body: bookBody, ifMatch: "*", statusWant: http.StatusOK, etagWant: `"4"`},
+	}
+
+	//順に更新するため、サブテストは並べた順に実行する
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := map[string]string{}
+			if tt.ifMatch != "" {
+				header[handler.HeaderIfMatch] = tt.ifMatch
+			}
+
+			//Act ***************
+			w := serve(e, tt.method, tt.target, tt.body, header)
+
+			//Assert ***************
+			assert.Equal(t, tt.statusWant, w.Code, w.Body.String())
+			if tt.statusWant != http.StatusPreconditionFailed {
+				assert.Equal(t, tt.etagWant, w.Header().Get(handler.HeaderETag))
+				return
+			}
+			var got problem.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, problem.CodePreconditionFailed, got.Code)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// Server-Sent EventsのContent-Typeとヘッダー
+const (
+	MIMETextEventStream = "text/event-stream"
+	HeaderLastEventID   = "Last-Event-ID"
+)
+
+var (
+	// 接続が切れた場合にクライアントが再接続するまでの時間
+	eventRetry = 3 * time.Second
+
+	// 接続を保つためのコメントを送る間隔。あわせて通知の取りこぼしがないかイベントのログを確認する。
+	eventHeartbeat = 15 * time.Second
+)
+
+// 本棚の変更をServer-Sent Eventsで配信する。Last-Event-IDがある場合はその後のイベントから配信する。
+// (GET /events/{authUserId})
+func (h *Handler) GetEventsAuthUserId(c echo.Context, authUserId string, params apigen.GetEventsAuthUserIdParams) error {
+	ctx := c.Request().Context()
+
+	//ログを確認する前に購読を始め、確認中に登録されたイベントも取りこぼさない
+	notified, unsubscribe := h.ec.Subscribe(authUserId)
+	defer unsubscribe()
+
+	var lastId int64
+	if params.LastEventID != nil && strings.TrimSpace(*params.LastEventID) != "" {
+		id, err := strconv.ParseInt(strings.TrimSpace(*params.LastEventID), 10, 64)
+		if err != nil || id < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidLastEventId)
+		}
+		lastId = id
+	} else {
+		id, err := h.ec.LatestEventID(ctx, authUserId)
+		if err != nil {
+			return problem.Wrap(err, problem.CodeEventGetFailed, nil)
+		}
+		lastId = id
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, MIMETextEventStream)
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no") //プロキシでバッファさせない
+	res.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", eventRetry.Milliseconds()); err != nil {
+		return nil
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		//ヘッダーは送信済みのため、以降のエラーはログに出力して接続を閉じる（クライアントはLast-Event-IDで再接続する）
+		var err error
+		lastId, err = h.writeEvents(c, authUserId, lastId)
+		if err != nil {
+			c.Logger().Error(err)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.ec.Done():
+			return nil
+		case <-notified:
+		case <-heartbeat.C:
+			if _, err := io.WriteString(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// lastIdより後のイベントをすべて書き出し、最後に書き出したイベントのidを返す
+func (h *Handler) writeEvents(c echo.Context, authUserId string, lastId int64) (int64, error) {
+	for {
+		events, err := h.ec.EventsAfter(c.Request().Context(), authUserId, lastId)
+		if err != nil {
+			return lastId, err
+		}
+		if len(events) == 0 {
+			return lastId, nil
+		}
+
+		for _, ev := range events {
+			data, err := json.Marshal(tweakEventForJSON(ev))
+			if err != nil {
+				return lastId, err
+			}
+			if _, err := fmt.Fprintf(c.Response(), "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data); err != nil {
+				return lastId, err
+			}
+			lastId = ev.ID
+		}
+		c.Response().Flush()
+	}
+}
+
+// 本の変更後に、達成した読書目標を達成イベントとして記録する。記録に失敗しても本の変更自体は成功扱い。
+func (h *Handler) recordReachedGoals(c echo.Context, authUserId string) {
+	if err := h.gc.RecordReachedGoals(c.Request().Context(), authUserId); err != nil {
+		c.Logger().Error(err)
+	}
+}
@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/infra/repository"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+// Last-Event-IDより後のイベントを、id、event、dataの形式で配信することを確認する
+func TestGetEventsAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	er := repository.NewEvent(bundb, cl)
+	events := []*domain.Event{
+		{AuthUserId: authUserId, Type: domain.EventBookCreated, BookId: 1, Version: 1},
+		{AuthUserId: authUserId, Type: domain.EventBookUpdated, BookId: 1, Version: 2},
+		{AuthUserId: authUserId, Type: domain.EventGoalReached, GoalId: 3},
+		{AuthUserId: "2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e", Type: domain.EventBookDeleted, BookId: 9},
+	}
+	if err := er.CreateEvents(ctx, events...); err != nil {
+		t.Fatal(err)
+	}
+	first, err := er.FindEventsAfter(ctx, authUserId, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, e := testutils.SetupHandler(bundb)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/events/"+authUserId, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set(handler.HeaderLastEventID, strconv.FormatInt(first[0].ID, 10))
+	a := assert.New(t)
+
+	//Act ***************
+	res, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var lines []string
+	sc := bufio.NewScanner(res.Body)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+		//2件目のイベントの空行まで読む
+		if strings.Count(strings.Join(lines, "\n"), "data: ") == 2 && sc.Text() == "" {
+			break
+		}
+	}
+	cancel()
+
+	//Assert ***************
+	a.Equal(http.StatusOK, res.StatusCode)
+	a.Equal(handler.MIMETextEventStream, res.Header.Get("Content-Type"))
+	got := strings.Join(lines, "\n")
+	a.Contains(got, "retry: 3000")
+	a.Contains(got, "event: book.updated\ndata: {")
+	a.Contains(got, `"bookId":"1"`)
+	a.Contains(got, `"version":"2"`)
+	a.Contains(got, "event: goal.reached\ndata: {")
+	a.Contains(got, `"goalId":"3"`)
+	a.NotContains(got, "book.created")
+	a.NotContains(got, "book.deleted")
+}
+
+func TestGetEventsAuthUserIdWithError(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, e := testutils.SetupHandler(bundb)
+	a := assert.New(t)
+
+	//Act ***************
+	w := serve(e, http.MethodGet, "/v1/events/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", "", map[string]string{handler.HeaderLastEventID: "abc"})
+
+	//Assert ***************
+	a.Equal(http.StatusBadRequest, w.Code)
+	a.Contains(w.Body.String(), string(problem.CodeInvalidLastEventId))
+}
@@ -0,0 +1,210 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestGetGoalsWithAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	goal := &domain.Goal{
+		ID:         1,
+		AuthUserId: authUserId,
+		Kind:       domain.GoalSpend,
+		Period:     domain.GoalMonthly,
+		Target:     3000,
+		Currency:   domain.JPY,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, goal)
+	book := &domain.Book{
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodGet, "/goals/:authUserId", nil)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
+
+	//Act ***************
+	err = sut.GetGoalsAuthUserId(c, authUserId)
+
+	//Assert ***************
+	resBody := testutils.IndentForJSON(t, w.Body.String())
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	g.Assert(t, t.Name(), resBody)
+}
+
+func TestPutGoalsWithAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//リクエストボディの準備
+	goal := &handler.Goal{
+		Kind:     "spend",
+		Period:   "monthly",
+		Target:   "49.99",
+		Currency: "USD",
+	}
+	jb := testutils.ConvertToJSON(t, goal)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodPut, "/goals/:authUserId", &jb)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.PutGoalsAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
+
+	//Assert ***************
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	a.Empty(w.Body.Bytes())
+}
+
+func TestPutGoalsWithHomeCurrency(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	user := &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", HomeCurrency: domain.USD, CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+
+	//リクエストボディの準備（通貨の指定なし）
+	goal := &handler.Goal{
+		Kind:   "spend",
+		Period: "monthly",
+		Target: "12.50",
+	}
+	jb := testutils.ConvertToJSON(t, goal)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodPut, "/goals/:authUserId", &jb)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.PutGoalsAuthUserId(c, authUserId)
+
+	//Assert ***************
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	got := new(domain.Goal)
+	if err := bundb.NewSelect().Model(got).Where("auth_user_id = ?", authUserId).Scan(ctx); err != nil {
+		t.Fatal(err)
+	}
+	a.Equal(domain.USD, got.Currency) //ユーザーの基準通貨を保存
+	a.Equal(1250, got.Target)         //USDの補助単位（セント）
+}
+
+func TestPostShelfAuthUserIdWithSpendCapWarning(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	goal := &domain.Goal{
+		AuthUserId: authUserId,
+		Kind:       domain.GoalSpend,
+		Period:     domain.GoalMonthly,
+		Target:     1000,
+		Currency:   domain.JPY,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, goal)
+
+	//リクエストボディの準備
+	book := &handler.Book{
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       "330",
+		Price:      "1,640",
+		BookStatus: "bought",
+		AuthUserId: authUserId,
+	}
+	jb := testutils.ConvertToJSON(t, book)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodPost, "/shelf/:authUserId", &jb)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.PostShelfAuthUserId(c, authUserId)
+
+	//Assert ***************
+	a.Nil(err)
+	a.Equal(http.StatusCreated, w.Code)
+	a.Contains(w.Body.String(), `"spendCapExceeded":true`)
+}
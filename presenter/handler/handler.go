@@ -1,15 +1,71 @@
 package handler
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
-	"os"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/apigen"
 	"github.com/taimats/bhapi/controller"
-	"github.com/taimats/bhapi/utils"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
 )
 
+// APIのルーティングの接頭辞（openapi.yamlのserversのパス）
+const BaseURL = "/v1"
+
+// 管理APIのルーティングの接頭辞。管理者のadminスコープの個人用APIキーのみ呼び出せる
+const AdminBaseURL = BaseURL + "/admin"
+
+// Idempotency-Keyヘッダーに対応するルート（"メソッド echoのパス"）。再送で重複して作成されるのを防ぐ。
+var IdempotentRoutes = []string{
+	http.MethodPost + " " + BaseURL + "/auth/register",
+	http.MethodPost + " " + BaseURL + "/shelf/:authUserId",
+	http.MethodPost + " " + BaseURL + "/shelf/:authUserId/batch",
+	http.MethodPost + " " + BaseURLV2 + "/auth/register",
+	http.MethodPost + " " + BaseURLV2 + "/shelf/:authUserId",
+}
+
+// 外部のAPIを呼び出す書籍の検索のルート。レート制限の上限を他より低くする。
+var SearchRoutes = []string{
+	http.MethodGet + " " + BaseURL + "/search",
+	http.MethodGet + " " + BaseURLV2 + "/search",
+}
+
+// 個人用APIキーで呼び出せるルート（"メソッド echoのパス"）と、必要なスコープ。
+// ここにないルート（APIキーの管理、アカウントなど）は個人用APIキーでは呼び出せない。
+var RouteScopes = map[string]domain.APIKeyScope{
+	http.MethodGet + " " + BaseURL + "/shelf/:authUserId":                                     domain.ScopeShelfRead,
+	http.MethodGet + " " + BaseURL + "/records/:authUserId":                                   domain.ScopeShelfRead,
+	http.MethodGet + " " + BaseURL + "/backlog/:authUserId":                                   domain.ScopeShelfRead,
+	http.MethodPost + " " + BaseURL + "/shelf/:authUserId":                                    domain.ScopeShelfWrite,
+	http.MethodPut + " " + BaseURL + "/shelf/:authUserId":                                     domain.ScopeShelfWrite,
+	http.MethodDelete + " " + BaseURL + "/shelf/:authUserId":                                  domain.ScopeShelfWrite,
+	http.MethodPost + " " + BaseURL + "/shelf/:authUserId/batch":                              domain.ScopeShelfWrite,
+	http.MethodPatch + " " + BaseURL + "/shelf/:authUserId/:bookId":                           domain.ScopeShelfWrite,
+	http.MethodGet + " " + BaseURL + "/shelf/:authUserId/:bookId/history":                     domain.ScopeShelfRead,
+	http.MethodPost + " " + BaseURL + "/shelf/:authUserId/:bookId/history/:revisionId/revert": domain.ScopeShelfWrite,
+	http.MethodGet + " " + BaseURL + "/charts/:authUserId":                                    domain.ScopeChartsRead,
+	http.MethodGet + " " + BaseURLV2 + "/shelf/:authUserId":                                   domain.ScopeShelfRead,
+	http.MethodGet + " " + BaseURLV2 + "/records/:authUserId":                                 domain.ScopeShelfRead,
+	http.MethodGet + " " + BaseURLV2 + "/backlog/:authUserId":                                 domain.ScopeShelfRead,
+	http.MethodPost + " " + BaseURLV2 + "/shelf/:authUserId":                                  domain.ScopeShelfWrite,
+	http.MethodDelete + " " + BaseURLV2 + "/shelf/:authUserId":                                domain.ScopeShelfWrite,
+	http.MethodPut + " " + BaseURLV2 + "/shelf/:authUserId/:bookId":                           domain.ScopeShelfWrite,
+	http.MethodGet + " " + BaseURLV2 + "/charts/:authUserId":                                  domain.ScopeChartsRead,
+	http.MethodGet + " " + AdminBaseURL + "/users":                                            domain.ScopeAdmin,
+	http.MethodGet + " " + AdminBaseURL + "/users/:authUserId/stats":                          domain.ScopeAdmin,
+	http.MethodPost + " " + AdminBaseURL + "/users/:authUserId/disable":                       domain.ScopeAdmin,
+	http.MethodPost + " " + AdminBaseURL + "/users/:authUserId/enable":                        domain.ScopeAdmin,
+	http.MethodPost + " " + AdminBaseURL + "/users/:authUserId/revoke":                        domain.ScopeAdmin,
+	http.MethodGet + " " + AdminBaseURL + "/stats":                                            domain.ScopeAdmin,
+	http.MethodPut + " " + AdminBaseURL + "/rates":                                            domain.ScopeAdmin,
+	http.MethodGet + " " + AdminBaseURL + "/audit":                                            domain.ScopeAdmin,
+}
+
 type Handler struct {
 	uc  *controller.User
 	cc  *controller.Chart
@@ -17,6 +73,17 @@ type Handler struct {
 	sc  *controller.Shelf
 	sbc *controller.SearchBooks
 	hc  *controller.HealthDB
+	rtc *controller.Rate
+	gc  *controller.Goal
+	bc  *controller.Backlog
+	tc  *controller.Trash
+	ec  *controller.Event
+	wc  *controller.Webhook
+	mc  *controller.Mail
+	ac  *controller.Account
+	kc  *controller.APIKey
+	adc *controller.Admin
+	auc *controller.Audit
 }
 
 func NewHandler(
@@ -26,6 +93,17 @@ func NewHandler(
 	sc *controller.Shelf,
 	sbc *controller.SearchBooks,
 	hc *controller.HealthDB,
+	rtc *controller.Rate,
+	gc *controller.Goal,
+	bc *controller.Backlog,
+	tc *controller.Trash,
+	ec *controller.Event,
+	wc *controller.Webhook,
+	mc *controller.Mail,
+	ac *controller.Account,
+	kc *controller.APIKey,
+	adc *controller.Admin,
+	auc *controller.Audit,
 ) *Handler {
 	return &Handler{
 		uc:  uc,
@@ -34,17 +112,28 @@ func NewHandler(
 		sc:  sc,
 		sbc: sbc,
 		hc:  hc,
+		rtc: rtc,
+		gc:  gc,
+		bc:  bc,
+		tc:  tc,
+		ec:  ec,
+		wc:  wc,
+		mc:  mc,
+		ac:  ac,
+		kc:  kc,
+		adc: adc,
+		auc: auc,
 	}
 }
 
-var _ HandlerInterface = (*Handler)(nil)
+var _ apigen.ServerInterface = (*Handler)(nil)
 
 // user情報の登録
 // (POST /auth/register)
 func (h *Handler) PostAuthRegister(c echo.Context) error {
 	var u User
 	if err := c.Bind(&u); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "リクエストボディの読み込みに失敗")
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
 	}
 
 	if err := c.Validate(&u); err != nil {
@@ -54,36 +143,32 @@ func (h *Handler) PostAuthRegister(c echo.Context) error {
 	ctx := c.Request().Context()
 	user, err := convertUser(&u)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "ユーザー登録に失敗")
+		return problem.Wrap(err, problem.CodeInvalidUser, nil)
 	}
 
 	err = h.uc.RegisterUser(ctx, user)
 	if err != nil {
-		if errors.Is(err, utils.ErrAlrExists) {
-			return echo.NewHTTPError(http.StatusBadRequest, "すでにユーザーが存在します")
-		}
-		return echo.NewHTTPError(http.StatusInternalServerError, "ユーザー登録に失敗")
+		return problem.Wrap(err, problem.CodeUserRegisterFailed, problem.Codes{
+			domain.ErrConflict:       problem.CodeUserAlreadyExists,
+			domain.ErrPasswordHashed: problem.CodeInvalidUser,
+		})
 	}
 
 	return c.NoContent(http.StatusCreated)
 }
 
-// ユーザーごとにチャートデータを返す
-// (GET /charts/{AuthUserId})
-func (h *Handler) GetChartsWithAuthUserId(c echo.Context) error {
-	authUserId := c.Param("authUserId")
+// ユーザーごとにチャートデータを返す。If-None-Matchが一致する場合は304。
+// (GET /charts/{authUserId})
+func (h *Handler) GetChartsAuthUserId(c echo.Context, authUserId string, params apigen.GetChartsAuthUserIdParams) error {
 	ctx := c.Request().Context()
 
 	chs, err := h.cc.GetCharts(ctx, authUserId)
 	if err != nil {
-		if errors.Is(err, utils.ErrNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "図表がありません")
-		}
-		return echo.NewHTTPError(http.StatusInternalServerError, "図表の取得に失敗")
+		return problem.Wrap(err, problem.CodeChartGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeChartNotFound})
 	}
 
 	charts := tweakChartsForJSON(chs)
-	return c.JSON(http.StatusOK, charts)
+	return jsonWithETag(c, params.IfNoneMatch, charts)
 }
 
 // サーバーの監視
@@ -102,188 +187,504 @@ func (h *Handler) GetHealthDb(c echo.Context) error {
 			"message": "ok",
 		})
 	} else {
-		return echo.NewHTTPError(http.StatusInternalServerError, "DBに異常があります")
+		return echo.NewHTTPError(http.StatusInternalServerError, problem.CodeDBUnavailable)
 	}
 }
 
-// ユーザーごとに記録を返す
-// (GET /records/{AuthUserId})
-func (h *Handler) GetRecordsWithAuthUserId(c echo.Context) error {
-	authUserId := c.Param("authUserId")
+// ユーザーごとに記録を返す。If-None-Matchが一致する場合は304。
+// (GET /records/{authUserId})
+func (h *Handler) GetRecordsAuthUserId(c echo.Context, authUserId string, params apigen.GetRecordsAuthUserIdParams) error {
 	ctx := c.Request().Context()
 
 	record, err := h.rc.GetRecord(ctx, authUserId)
 	if err != nil {
-		if errors.Is(err, utils.ErrNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "記録がありません")
-		}
-		return echo.NewHTTPError(http.StatusNotFound, "記録の取得に失敗")
+		return problem.Wrap(err, problem.CodeRecordGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeRecordNotFound})
 	}
 
-	return c.JSON(http.StatusOK, tweakRecordForJSON(record))
+	return jsonWithETag(c, params.IfNoneMatch, tweakRecordForJSON(record))
 }
 
 // 書籍の検索結果を取得
 // (GET /search)
-func (h *Handler) GetSearch(c echo.Context) error {
-	q := c.QueryParam("q")
+func (h *Handler) GetSearch(c echo.Context, params apigen.GetSearchParams) error {
+	q := params.Q
 	if q == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "検索文字を入力ください")
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingQuery)
 	}
 
 	ctx := c.Request().Context()
-	baseURL := os.Getenv("GOOGL_BOOKS_API_URL")
-
-	results, err := h.sbc.SearchBooks(ctx, q, baseURL)
+	results, err := h.sbc.SearchBooks(ctx, q)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "書籍の検索に失敗")
+		return problem.Wrap(err, problem.CodeSearchFailed, nil)
 	}
 
-	return c.JSON(http.StatusOK, results)
+	return c.JSON(http.StatusOK, tweakSearchResultsForJSON(results))
 }
 
 // ユーザーごとに本棚を複数削除
-// (DELETE /shelf/{AuthUserId})
-func (h *Handler) DeleteShelfWithAuthUserId(c echo.Context) error {
-	bookIds := c.QueryParams()["bookId"]
+// (DELETE /shelf/{authUserId})
+func (h *Handler) DeleteShelfAuthUserId(c echo.Context, authUserId string, params apigen.DeleteShelfAuthUserIdParams) error {
+	bookIds := params.BookId
 	if len(bookIds) == 0 {
-		return echo.NewHTTPError(http.StatusBadRequest, "bookIdが必要です")
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingBookId)
 	}
 
 	ctx := c.Request().Context()
 
-	err := h.sc.DeleteShelf(ctx, bookIds)
+	err := h.sc.DeleteShelf(ctx, authUserId, bookIds)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "本の削除に失敗")
+		return problem.Wrap(err, problem.CodeBookDeleteFailed, problem.Codes{domain.ErrNotFound: problem.CodeBookNotFound})
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
 
-// ユーザーごとに本棚を取得
-// (GET /shelf/{AuthUserId})
-func (h *Handler) GetShelfWithAuthUserId(c echo.Context) error {
-	authUserId := c.Param("authUserId")
+// ユーザーごとに本棚を取得。If-None-Matchが一致する場合は304。
+// (GET /shelf/{authUserId})
+func (h *Handler) GetShelfAuthUserId(c echo.Context, authUserId string, params apigen.GetShelfAuthUserIdParams) error {
 	ctx := c.Request().Context()
 
 	books, err := h.sc.GetShelf(ctx, authUserId)
 	if err != nil {
-		if errors.Is(err, utils.ErrNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "本棚がありません")
-		}
-		return echo.NewHTTPError(http.StatusInternalServerError, "本棚の取得に失敗")
+		return problem.Wrap(err, problem.CodeShelfGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeShelfNotFound})
 	}
 
 	shelf := tweakBooksForJSON(books)
 
-	return c.JSON(http.StatusOK, shelf)
+	return jsonWithETag(c, params.IfNoneMatch, shelf)
 }
 
 // ユーザーごとに本を本棚に1冊ずつ作成
 // (POST /shelf/{authUserId})
-func (h *Handler) PostShelfAuthUserId(c echo.Context) error {
+func (h *Handler) PostShelfAuthUserId(c echo.Context, authUserId string) error {
 	var b Book
 	if err := c.Bind(&b); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "リクエストボディの取得に失敗")
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
 	}
 
 	if err := c.Validate(b); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "不正なリクエストです")
+		return err
 	}
 
 	ctx := c.Request().Context()
 	book, err := convertBook(&b)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "本の変換に失敗")
+		return problem.Wrap(err, problem.CodeInvalidBook, nil)
+	}
+	//パスと異なるユーザーの本は操作できない
+	if book.AuthUserId != authUserId {
+		return problem.Wrap(domain.ErrForbidden, problem.CodeForbidden, nil)
 	}
 
 	err = h.sc.PostBookWithCharts(ctx, book)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "本の作成に失敗")
+		return problem.Wrap(err, problem.CodeBookCreateFailed, nil)
+	}
+	h.recordReachedGoals(c, book.AuthUserId)
+
+	//購入額の上限を超過した場合は警告を返す（本の作成自体は成功扱い）
+	exceeded, err := h.gc.ExceededSpendCaps(ctx, book.AuthUserId)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.NoContent(http.StatusCreated)
+	}
+	if len(exceeded) > 0 {
+		return c.JSON(http.StatusCreated, &ShelfWarning{
+			SpendCapExceeded: true,
+			Goals:            tweakGoalProgressForJSON(exceeded),
+		})
 	}
 
 	return c.NoContent(http.StatusCreated)
 }
 
-// ユーザーごとに本棚を1冊ずつ更新
-// (PUT /shelf/{AuthUserId})
-func (h *Handler) PutShelfWithAuthUserId(c echo.Context) error {
+// ユーザーごとに本棚を1冊ずつ更新。If-Matchがある場合はバージョンが一致する場合のみ更新する。
+// (PUT /shelf/{authUserId})
+func (h *Handler) PutShelfAuthUserId(c echo.Context, authUserId string, params apigen.PutShelfAuthUserIdParams) error {
 	b := new(Book)
 	if err := c.Bind(b); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "リクエストボディの取得に失敗")
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
 	}
 
 	if err := c.Validate(b); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "不正なリクエストです")
+		return err
 	}
 
 	book, err := convertBook(b)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "本の変換に失敗")
+		return problem.Wrap(err, problem.CodeInvalidBook, nil)
+	}
+	//パスと異なるユーザーの本は操作できない
+	if book.AuthUserId != authUserId {
+		return problem.Wrap(domain.ErrForbidden, problem.CodeForbidden, nil)
+	}
+	book.Version, err = ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return problem.Wrap(err, problem.CodePreconditionFailed, nil)
 	}
 
 	ctx := c.Request().Context()
 	err = h.sc.UpdateShelf(ctx, book)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "本の更新に失敗")
+		return problem.Wrap(err, problem.CodeBookUpdateFailed, problem.Codes{
+			domain.ErrNotFound:           problem.CodeBookNotFound,
+			domain.ErrPreconditionFailed: problem.CodePreconditionFailed,
+		})
 	}
+	h.recordReachedGoals(c, book.AuthUserId)
 
+	setVersionETag(c, book.Version)
 	return c.NoContent(http.StatusOK)
 }
 
+// 本棚の本を部分更新（JSON Merge Patch）し、更新後の本を返す。If-Matchがある場合はバージョンが一致する場合のみ更新する。
+// (PATCH /shelf/{authUserId}/{bookId})
+func (h *Handler) PatchShelfAuthUserIdBookId(c echo.Context, authUserId string, bookId string, params apigen.PatchShelfAuthUserIdBookIdParams) error {
+	id, err := strconv.ParseInt(bookId, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingBookId)
+	}
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return problem.Wrap(err, problem.CodePreconditionFailed, nil)
+	}
+	patch, err := bindMergePatch(c, bookPatchColumns)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	book, err := h.sc.PatchShelf(ctx, authUserId, id, version, func(b *domain.Book) ([]string, error) {
+		return applyBookPatch(b, patch)
+	})
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBookUpdateFailed, problem.Codes{
+			domain.ErrValidation:         problem.CodeInvalidBook,
+			domain.ErrNotFound:           problem.CodeBookNotFound,
+			domain.ErrPreconditionFailed: problem.CodePreconditionFailed,
+		})
+	}
+	h.recordReachedGoals(c, authUserId)
+
+	setVersionETag(c, book.Version)
+	return c.JSON(http.StatusOK, tweakBooksForJSON([]*domain.Book{book})[0])
+}
+
+// 本の変更履歴（更新前の版）を新しい順に返す
+// (GET /shelf/{authUserId}/{bookId}/history)
+func (h *Handler) GetShelfAuthUserIdBookIdHistory(c echo.Context, authUserId string, bookId string) error {
+	id, err := strconv.ParseInt(bookId, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingBookId)
+	}
+
+	ctx := c.Request().Context()
+	revisions, err := h.sc.GetBookHistory(ctx, authUserId, id)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeHistoryGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeBookNotFound})
+	}
+
+	res := BookHistory{Revisions: make([]BookRevision, len(revisions))}
+	for i, r := range revisions {
+		res.Revisions[i] = tweakBookRevisionForJSON(r)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// 本を変更履歴の版の内容に戻す。図表も再計算する
+// (POST /shelf/{authUserId}/{bookId}/history/{revisionId}/revert)
+func (h *Handler) PostShelfAuthUserIdBookIdHistoryRevisionIdRevert(c echo.Context, authUserId string, bookId string, revisionId string, params apigen.PostShelfAuthUserIdBookIdHistoryRevisionIdRevertParams) error {
+	id, err := strconv.ParseInt(bookId, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingBookId)
+	}
+	rid, err := strconv.ParseInt(revisionId, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeBadRequest)
+	}
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return problem.Wrap(err, problem.CodePreconditionFailed, nil)
+	}
+
+	ctx := c.Request().Context()
+	book, err := h.sc.RevertBook(ctx, authUserId, id, rid, version)
+	//版がない場合と本がない場合を区別する（どちらもErrNotFound）
+	if errors.Is(err, domain.ErrRevisionNotFound) {
+		return problem.Wrap(err, problem.CodeRevisionNotFound, nil)
+	}
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBookUpdateFailed, problem.Codes{
+			domain.ErrNotFound:           problem.CodeBookNotFound,
+			domain.ErrPreconditionFailed: problem.CodePreconditionFailed,
+		})
+	}
+	h.recordReachedGoals(c, authUserId)
+
+	setVersionETag(c, book.Version)
+	return c.JSON(http.StatusOK, tweakBooksForJSON([]*domain.Book{book})[0])
+}
+
+// 本棚の本を一括で作成、更新、ステータス変更、削除。操作ごとの結果を返す（リクエスト自体は200）。
+// (POST /shelf/{authUserId}/batch)
+func (h *Handler) PostShelfAuthUserIdBatch(c echo.Context, authUserId string) error {
+	var b ShelfBatch
+	if err := c.Bind(&b); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+
+	if err := c.Validate(b); err != nil {
+		return err
+	}
+
+	ops, err := convertShelfOperations(authUserId, b.Operations)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	res, err := h.sc.BatchShelf(ctx, ops, b.Mode == apigen.BestEffort)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBatchFailed, nil)
+	}
+	if res.Committed {
+		h.recordReachedGoals(c, authUserId)
+	}
+
+	return c.JSON(http.StatusOK, tweakShelfBatchResultForJSON(res, problem.Language(c.Request())))
+}
+
 // ユーザーを削除
-// (DELETE /users/{AuthUserId})
-func (h *Handler) DeleteUsersWithAuthUserId(c echo.Context) error {
-	authUserId := c.Param("authUserId")
+// (DELETE /users/{authUserId})
+func (h *Handler) DeleteUsersAuthUserId(c echo.Context, authUserId string, params apigen.DeleteUsersAuthUserIdParams) error {
 	ctx := c.Request().Context()
 
-	err := h.uc.DeleteUser(ctx, authUserId)
+	//immediate=trueの場合はゴミ箱を経由せずに完全に削除
+	immediate := params.Immediate != nil && *params.Immediate
+
+	//削除したデータ一式をzipアーカイブで返す（作成できない場合は削除しない）
+	var buf bytes.Buffer
+	export, err := h.uc.DeleteUser(ctx, authUserId, immediate, h.tc.Retention(), &buf)
 	if err != nil {
-		if errors.Is(err, utils.ErrNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "ユーザーがありません")
-		}
-		return echo.NewHTTPError(http.StatusInternalServerError, "ユーザーの削除に失敗")
+		return problem.Wrap(err, problem.CodeUserDeleteFailed, problem.Codes{
+			domain.ErrNotFound:     problem.CodeUserNotFound,
+			domain.ErrExportFailed: problem.CodeExportFailed,
+		})
 	}
+	filename := fmt.Sprintf("bhapi-export-%s-%s.zip", authUserId, export.DeletedAt.Format("20060102150405"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
 
-	return c.NoContent(http.StatusNoContent)
+	return c.Blob(http.StatusOK, "application/zip", buf.Bytes())
 }
 
 // ユーザー情報を返す
-// (GET /users/{AuthUserId})
-func (h *Handler) GetUsersWithAuthUserId(c echo.Context) error {
-	authUserId := c.Param("authUserId")
+// (GET /users/{authUserId})
+func (h *Handler) GetUsersAuthUserId(c echo.Context, authUserId string) error {
 	ctx := c.Request().Context()
 
 	user, err := h.uc.GetUser(ctx, authUserId)
 	if err != nil {
-		if errors.Is(err, utils.ErrNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "ユーザーがありません")
-		}
-
-		return echo.NewHTTPError(http.StatusInternalServerError, "ユーザーの取得に失敗")
+		return problem.Wrap(err, problem.CodeUserGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeUserNotFound})
 	}
 
+	setVersionETag(c, user.Version)
 	return c.JSON(http.StatusOK, tweakUserForJSON(user))
 }
 
-func (h *Handler) PutUsers(c echo.Context) error {
+// ユーザー情報を更新。If-Matchがある場合はバージョンが一致する場合のみ更新する。
+// (PUT /users)
+func (h *Handler) PutUsers(c echo.Context, params apigen.PutUsersParams) error {
 	u := new(User)
 	if err := c.Bind(u); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "リクエストボディの読み込みに失敗")
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
 	}
 	if err := c.Validate(u); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "不正なリクエストです")
+		return err
 	}
 
 	ctx := c.Request().Context()
 	user, err := convertUser(u)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "ユーザーの変換に失敗")
+		return problem.Wrap(err, problem.CodeInvalidUser, nil)
+	}
+	user.Version, err = ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return problem.Wrap(err, problem.CodePreconditionFailed, nil)
 	}
 
 	err = h.uc.UpdateUser(ctx, user)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "ユーザーの更新に失敗")
+		return problem.Wrap(err, problem.CodeUserUpdateFailed, problem.Codes{
+			domain.ErrNotFound:           problem.CodeUserNotFound,
+			domain.ErrPreconditionFailed: problem.CodePreconditionFailed,
+			domain.ErrPasswordHashed:     problem.CodeInvalidUser,
+			domain.ErrAccountDisabled:    problem.CodeAccountDisabled,
+		})
+	}
+
+	setVersionETag(c, user.Version)
+	return c.NoContent(http.StatusOK)
+}
+
+// ユーザー情報を部分更新（JSON Merge Patch）し、更新後のユーザーを返す。If-Matchがある場合はバージョンが一致する場合のみ更新する。
+// (PATCH /users/{authUserId})
+func (h *Handler) PatchUsersAuthUserId(c echo.Context, authUserId string, params apigen.PatchUsersAuthUserIdParams) error {
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return problem.Wrap(err, problem.CodePreconditionFailed, nil)
+	}
+	patch, err := bindMergePatch(c, userPatchColumns)
+	if err != nil {
+		return err
+	}
+	if err := validatePatchEmail(c, patch); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	user, err := h.uc.PatchUser(ctx, authUserId, version, func(u *domain.User) ([]string, error) {
+		return applyUserPatch(u, patch)
+	})
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserUpdateFailed, problem.Codes{
+			domain.ErrValidation:         problem.CodeInvalidUser,
+			domain.ErrNotFound:           problem.CodeUserNotFound,
+			domain.ErrPreconditionFailed: problem.CodePreconditionFailed,
+		})
+	}
+
+	setVersionETag(c, user.Version)
+	return c.JSON(http.StatusOK, tweakUserForJSON(user))
+}
+
+// 為替レートの一覧を返す
+// (GET /rates)
+func (h *Handler) GetRates(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	rates, err := h.rtc.GetRates(ctx)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeRateGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, tweakRatesForJSON(rates))
+}
+
+// ユーザーごとに目標の進捗を返す
+// (GET /goals/{authUserId})
+func (h *Handler) GetGoalsAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	progress, err := h.gc.GetGoalProgress(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeGoalGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, tweakGoalProgressForJSON(progress))
+}
+
+// ユーザーごとに目標を登録、更新
+// (PUT /goals/{authUserId})
+func (h *Handler) PutGoalsAuthUserId(c echo.Context, authUserId string) error {
+	g := new(Goal)
+	if err := c.Bind(g); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(g); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	//購入額の目標の金額は通貨によって補助単位が異なるため、通貨を決めてから変換する
+	var home domain.Currency
+	if domain.GoalKind(g.Kind) == domain.GoalSpend && g.Currency == "" {
+		var err error
+		home, err = h.gc.HomeCurrency(ctx, authUserId)
+		if err != nil {
+			return problem.Wrap(err, problem.CodeGoalSetFailed, nil)
+		}
+	}
+	goal, err := convertGoal(g, authUserId, home)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeInvalidGoal, nil)
+	}
+
+	err = h.gc.SetGoal(ctx, goal)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeGoalSetFailed, problem.Codes{domain.ErrInvalidGoal: problem.CodeInvalidGoal})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ユーザーごとに目標を削除
+// (DELETE /goals/{authUserId})
+func (h *Handler) DeleteGoalsAuthUserId(c echo.Context, authUserId string, params apigen.DeleteGoalsAuthUserIdParams) error {
+	goalId, err := strconv.ParseInt(params.GoalId, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingGoalId)
+	}
+
+	ctx := c.Request().Context()
+	err = h.gc.DeleteGoal(ctx, authUserId, goalId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeGoalDeleteFailed, problem.Codes{domain.ErrNotFound: problem.CodeGoalNotFound})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ユーザーごとに積読の状況を返す
+// (GET /backlog/{authUserId})
+func (h *Handler) GetBacklogAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	backlog, err := h.bc.GetBacklog(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBacklogGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, tweakBacklogForJSON(backlog))
+}
+
+// ユーザーごとにゴミ箱の中身を返す
+// (GET /trash/{authUserId})
+func (h *Handler) GetTrashAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	trash, err := h.tc.GetTrash(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeTrashGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, tweakTrashForJSON(trash))
+}
+
+// ゴミ箱の本を複数復元
+// (POST /trash/{authUserId}/books/restore)
+func (h *Handler) PostTrashAuthUserIdBooksRestore(c echo.Context, authUserId string, params apigen.PostTrashAuthUserIdBooksRestoreParams) error {
+	bookIds := params.BookId
+	if len(bookIds) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingBookId)
+	}
+
+	ctx := c.Request().Context()
+	err := h.tc.RestoreBooks(ctx, authUserId, bookIds)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBookRestoreFailed, problem.Codes{domain.ErrNotFound: problem.CodeTrashBookNotFound})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ゴミ箱のユーザーを復元
+// (POST /trash/{authUserId}/user/restore)
+func (h *Handler) PostTrashAuthUserIdUserRestore(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	err := h.tc.RestoreUser(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserRestoreFailed, problem.Codes{domain.ErrNotFound: problem.CodeTrashUserNotFound})
 	}
 
 	return c.NoContent(http.StatusOK)
@@ -0,0 +1,60 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestBookHistory(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	book := &domain.Book{ID: 1, Title: "容疑者Xの献身", Page: 330, Price: 1640, BookStatus: domain.Bought, AuthUserId: authUserId, CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/shelf/" + authUserId + "/1/history"
+	a := assert.New(t)
+
+	//Act ***************
+	//PUTは本全体を置き換えるため、ページ数、価格を誤って上書きする（購入日は変えない）
+	updated := serve(e, http.MethodPut, "/v1/shelf/"+authUserId, `{"id":"1","title":"容疑者Xの献身","page":"33","price":"16400","bookStatus":"bought","authUserId":"`+authUserId+`","createdAt":"`+cl.NowString()+`"}`, nil)
+	history := serve(e, http.MethodGet, target, "", nil)
+	conflict := serve(e, http.MethodPost, target+"/1/revert", "", map[string]string{"If-Match": `"1"`})
+	reverted := serve(e, http.MethodPost, target+"/1/revert", "", nil)
+	unknown := serve(e, http.MethodPost, target+"/100/revert", "", nil)
+	other := serve(e, http.MethodGet, "/v1/shelf/unknown/1/history", "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusOK, updated.Code)
+	a.Equal(http.StatusOK, history.Code)
+	a.Contains(history.Body.String(), `"changed":["page","price"]`)
+	a.Contains(history.Body.String(), `"page":"330","price":"1,640"`)
+	a.Equal(http.StatusPreconditionFailed, conflict.Code)
+	a.Equal(http.StatusOK, reverted.Code)
+	a.Contains(reverted.Body.String(), `"page":"330","price":"1,640"`)
+	a.Equal(`"3"`, reverted.Header().Get("ETag"))
+	a.Equal(http.StatusNotFound, unknown.Code)
+	a.Contains(unknown.Body.String(), string(problem.CodeRevisionNotFound))
+	a.Equal(http.StatusNotFound, other.Code)
+	a.Contains(other.Body.String(), string(problem.CodeBookNotFound))
+}
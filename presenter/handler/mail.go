@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// メールの設定を返す
+// (GET /mail/{authUserId})
+func (h *Handler) GetMailAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	s, err := h.mc.GetMailSetting(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeMailSettingGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeUserNotFound})
+	}
+
+	return c.JSON(http.StatusOK, &MailSetting{Language: string(s.Language), Digest: s.Digest})
+}
+
+// メールの設定を更新
+// (PUT /mail/{authUserId})
+func (h *Handler) PutMailAuthUserId(c echo.Context, authUserId string) error {
+	m := new(MailSetting)
+	if err := c.Bind(m); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(m); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	err := h.mc.UpdateMailSetting(ctx, &domain.MailSetting{AuthUserId: authUserId, Language: domain.MailLanguage(m.Language), Digest: m.Digest})
+	if err != nil {
+		return problem.Wrap(err, problem.CodeMailSettingUpdateFailed, problem.Codes{
+			domain.ErrInvalidMailSetting: problem.CodeInvalidMailSetting,
+			domain.ErrNotFound:           problem.CodeUserNotFound,
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// メールのリンクのトークンで月次のまとめの配信を停止（認証なし）
+// (POST /mail/unsubscribe)
+func (h *Handler) PostMailUnsubscribe(c echo.Context, params apigen.PostMailUnsubscribeParams) error {
+	ctx := c.Request().Context()
+
+	err := h.mc.Unsubscribe(ctx, params.Token)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUnsubscribeFailed, problem.Codes{domain.ErrInvalidUnsubscribeToken: problem.CodeInvalidUnsubscribeToken})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestMailAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/mail/" + authUserId
+	token := url.QueryEscape(domain.NewUnsubscribeToken(testutils.MailTokenSecret, authUserId))
+	a := assert.New(t)
+
+	//Act ***************
+	initial := serve(e, http.MethodGet, target, "", nil)
+	updated := serve(e, http.MethodPut, target, `{"language":"en","digest":true}`, nil)
+	afterUpdate := serve(e, http.MethodGet, target, "", nil)
+	unsubscribed := serve(e, http.MethodPost, "/v1/mail/unsubscribe?token="+token, "", nil)
+	afterUnsubscribe := serve(e, http.MethodGet, target, "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusOK, initial.Code)
+	a.JSONEq(`{"language":"ja","digest":true}`, initial.Body.String())
+	a.Equal(http.StatusNoContent, updated.Code)
+	a.JSONEq(`{"language":"en","digest":true}`, afterUpdate.Body.String())
+	a.Equal(http.StatusNoContent, unsubscribed.Code)
+	a.JSONEq(`{"language":"en","digest":false}`, afterUnsubscribe.Body.String())
+}
+
+func TestMailAuthUserIdWithError(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	testutils.InsertTestData(ctx, t, bundb, &domain.User{AuthUserId: authUserId, Name: "田中", Email: "tanaka@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()})
+	_, e := testutils.SetupHandler(bundb)
+	forged := url.QueryEscape(domain.NewUnsubscribeToken("other-secret", authUserId))
+	tests := map[string]struct {
+		method string
+		target string
+		body   string
+		status int
+		code   problem.Code
+	}{
+		"NG:未対応の言語":    {method: http.MethodPut, target: "/v1/mail/" + authUserId, body: `{"language":"fr","digest":true}`, status: http.StatusBadRequest, code: problem.CodeValidationFailed},
+		"NG:未登録のユーザー":  {method: http.MethodGet, target: "/v1/mail/unknown", status: http.StatusNotFound, code: problem.CodeUserNotFound},
+		"NG:鍵の異なるトークン": {method: http.MethodPost, target: "/v1/mail/unsubscribe?token=" + forged, status: http.StatusBadRequest, code: problem.CodeInvalidUnsubscribeToken},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act ***************
+			w := serve(e, test.method, test.target, test.body, nil)
+
+			//Assert ***************
+			assert.Equal(t, test.status, w.Code)
+			assert.Contains(t, w.Body.String(), string(test.code))
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/utils"
+)
+
+// JSON Merge Patch（RFC 7396）のContent-Type
+const MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+
+// 部分更新の内容の誤り（オブジェクトでない、変更できない項目）
+var ErrInvalidPatch = domain.NewError(domain.ErrValidation, "部分更新の内容に誤りがあります")
+
+// JSON Merge Patchの項目名とbunの列名。ここにない項目（id、authUserId、versionなど）は変更できない。
+var (
+	bookPatchColumns = []patchColumn{
+		{"isbn10", "isbn_10"},
+		{"imageURL", "image_url"},
+		{"title", "title"},
+		{"author", "author"},
+		{"currency", "currency"}, //priceの変換に使うため、priceより先に適用する
+		{"page", "page"},
+		{"price", "price"},
+		{"bookStatus", "book_status"},
+		{"createdAt", "created_at"},
+	}
+	userPatchColumns = []patchColumn{
+		{"name", "name"},
+		{"email", "email"},
+		{"homeCurrency", "home_currency"},
+	}
+)
+
+type patchColumn struct {
+	field  string
+	column string
+}
+
+// リクエストボディをJSON Merge Patchとして読み込む。
+// Content-Typeがapplication/merge-patch+jsonでない場合は415、オブジェクトでない場合は400を返す。
+func bindMergePatch(c echo.Context, columns []patchColumn) (map[string]json.RawMessage, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != MIMEApplicationMergePatchJSON {
+		return nil, echo.NewHTTPError(http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType)
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(c.Request().Body).Decode(&patch); err != nil {
+		return nil, problem.Wrap(utils.NewErrChains(ErrInvalidPatch, err), problem.CodeInvalidPatch, nil)
+	}
+	//ボディがnullの場合（オブジェクト全体の置き換え）は対応しない
+	if patch == nil {
+		return nil, problem.Wrap(utils.NewErrChains(ErrInvalidPatch, nil), problem.CodeInvalidPatch, nil)
+	}
+
+	for field := range patch {
+		if !hasPatchField(columns, field) {
+			err := fmt.Errorf("%sは変更できません:%w", field, ErrInvalidPatch)
+			return nil, problem.Wrap(err, problem.CodeInvalidPatch, nil)
+		}
+	}
+	return patch, nil
+}
+
+func hasPatchField(columns []patchColumn, field string) bool {
+	for _, pc := range columns {
+		if pc.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge Patchの値を文字列として読み込む。nullの場合はnullがtrue。
+func patchString(raw json.RawMessage) (s string, null bool, err error) {
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return "", true, nil
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", false, utils.NewErrChains(ErrFailParse, err)
+	}
+	return s, false, nil
+}
+
+// 本にMerge Patchを適用し、値の変わった列を返す。nullは空（page、priceは0、currencyは既定の通貨）にする。
+// bookStatus、createdAtにnullは指定できない。priceは通貨の補助単位で保存するため、currencyを変更する場合はpriceも指定する。
+func applyBookPatch(book *domain.Book, patch map[string]json.RawMessage) ([]string, error) {
+	var columns []string
+	for _, pc := range bookPatchColumns {
+		raw, ok := patch[pc.field]
+		if !ok {
+			continue
+		}
+		s, null, err := patchString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%sの変換に失敗:%w", pc.field, err)
+		}
+
+		changed := false
+		switch pc.field {
+		case "isbn10":
+			changed = setIfChanged(&book.ISBN10, s)
+		case "imageURL":
+			changed = setIfChanged(&book.ImageURL, s)
+		case "title":
+			changed = setIfChanged(&book.Title, s)
+		case "author":
+			changed = setIfChanged(&book.Author, s)
+		case "currency":
+			currency, err := domain.ParseCurrency(s)
+			if err != nil {
+				return nil, fmt.Errorf("currencyの変換に失敗:%w:%w", ErrFailParse, err)
+			}
+			changed = setIfChanged(&book.Currency, currency)
+		case "page":
+			page := 0
+			if !null {
+				page, err = strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+				if err != nil {
+					return nil, fmt.Errorf("pageの数値変換に失敗:%w:%w", ErrFailParse, err)
+				}
+			}
+			changed = setIfChanged(&book.Page, page)
+		case "price":
+			price := 0
+			if !null {
+				price, err = domain.ParsePrice(s, book.Currency)
+				if err != nil {
+					return nil, fmt.Errorf("priceの数値変換に失敗:%w:%w", ErrFailParse, err)
+				}
+			}
+			changed = setIfChanged(&book.Price, price)
+		case "bookStatus":
+			status := domain.BookStatus(s)
+			if null || (status != domain.Bought && status != domain.Reading && status != domain.Read) {
+				return nil, fmt.Errorf("bookStatusの値が不正:%w:%s", ErrFailParse, s)
+			}
+			changed = setIfChanged(&book.BookStatus, status)
+		case "createdAt":
+			if null {
+				return nil, fmt.Errorf("createdAtにnullは指定できません:%w", ErrFailParse)
+			}
+			ca, err := parseStrTime(s)
+			if err != nil {
+				return nil, err
+			}
+			if !ca.Equal(book.CreatedAt) {
+				book.CreatedAt = ca.In(utils.JST)
+				changed = true
+			}
+		}
+		if changed {
+			columns = append(columns, pc.column)
+		}
+	}
+	//priceなしで通貨のみ変更すると、同じ補助単位の数値を別の通貨の金額として扱ってしまう
+	if _, ok := patch["price"]; !ok && slices.Contains(columns, "currency") {
+		return nil, fmt.Errorf("currencyを変更する場合はpriceも指定してください:%w", ErrFailParse)
+	}
+	return columns, nil
+}
+
+// ユーザーにMerge Patchを適用し、値の変わった列を返す。nullは空（homeCurrencyは既定の通貨）にする。
+// emailの形式はvalidatePatchEmailで確認済みとする。
+func applyUserPatch(user *domain.User, patch map[string]json.RawMessage) ([]string, error) {
+	var columns []string
+	for _, pc := range userPatchColumns {
+		raw, ok := patch[pc.field]
+		if !ok {
+			continue
+		}
+		s, _, err := patchString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%sの変換に失敗:%w", pc.field, err)
+		}
+
+		changed := false
+		switch pc.field {
+		case "name":
+			changed = setIfChanged(&user.Name, s)
+		case "email":
+			changed = setIfChanged(&user.Email, domain.Email(s))
+		case "homeCurrency":
+			hc, err := domain.ParseCurrency(s)
+			if err != nil {
+				return nil, utils.NewErrChains(ErrFailParse, err)
+			}
+			changed = setIfChanged(&user.HomeCurrency, hc)
+		}
+		if changed {
+			columns = append(columns, pc.column)
+		}
+	}
+	return columns, nil
+}
+
+// Merge Patchにemailがある場合は、登録時と同じ形式（必須、メールアドレス）か確認する。
+// null、文字列以外は空として扱う。
+func validatePatchEmail(c echo.Context, patch map[string]json.RawMessage) error {
+	raw, ok := patch["email"]
+	if !ok {
+		return nil
+	}
+	var u struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	_ = json.Unmarshal(raw, &u.Email)
+	return c.Validate(&u)
+}
+
+// 値が異なる場合のみdstにvを設定し、変更したかを返す
+func setIfChanged[T comparable](dst *T, v T) bool {
+	if *dst == v {
+		return false
+	}
+	*dst = v
+	return true
+}
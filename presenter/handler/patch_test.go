@@ -0,0 +1,222 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+var mergePatch = map[string]string{echo.HeaderContentType: handler.MIMEApplicationMergePatchJSON}
+
+func TestPatchShelfAuthUserIdBookId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	_, e := testutils.SetupHandler(bundb)
+	a := assert.New(t)
+
+	//Act ***************
+	status := serve(e, http.MethodPatch, "/v1/shelf/"+authUserId+"/1", `{"bookStatus":"read"}`, mergePatch)
+	afterStatus := []*domain.Chart{}
+	if err := bundb.NewSelect().Model(&afterStatus).Where("book_id = ?", book.ID).Order("id").Scan(ctx); err != nil {
+		t.Fatal(err)
+	}
+	price := serve(e, http.MethodPatch, "/v1/shelf/"+authUserId+"/1", `{"price":"1,800","page":null,"createdAt":"2024-01-10T10:00:00+09:00"}`, mergePatch)
+	afterPrice := []*domain.Chart{}
+	if err := bundb.NewSelect().Model(&afterPrice).Where("book_id = ?", book.ID).Order("id").Scan(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	//Assert ***************
+	//状態のみの変更では図表は変わらない
+	a.Equal(http.StatusOK, status.Code, status.Body.String())
+	a.Equal(`"2"`, status.Header().Get(handler.HeaderETag))
+	var got handler.Book
+	if err := json.Unmarshal(status.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	a.Equal("read", got.BookStatus)
+	a.Equal("1,640", got.Price)
+	a.Equal("2", got.Version)
+	for i, c := range afterStatus {
+		a.Equal(charts[i].Data, c.Data)
+		a.Equal(charts[i].Month, c.Month)
+	}
+
+	//価格、ページ数、購入日の変更では図表を再計算する
+	a.Equal(http.StatusOK, price.Code, price.Body.String())
+	a.Equal(`"3"`, price.Header().Get(handler.HeaderETag))
+	want := map[domain.ChartLabel]int{domain.ChartPrice: 1800, domain.ChartVolumes: 1, domain.ChartPages: 0}
+	for _, c := range afterPrice {
+		a.Equal(want[c.Label], c.Data, c.Label)
+		a.Equal(2024, c.Year)
+		a.Equal(1, c.Month)
+	}
+}
+
+func TestPatchShelfAuthUserIdBookIdWithError(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+
+	_, e := testutils.SetupHandler(bundb)
+
+	tests := map[string]struct {
+		target     string
+		body       string
+		header     map[string]string
+		statusWant int
+		codeWant   problem.Code
+	}{
+		"変更できない項目": {
+			target: "/v1/shelf/" + authUserId + "/1", body: `{"authUserId":"2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e"}`, header: mergePatch,
+			statusWant: http.StatusBadRequest, codeWant: problem.CodeSpecMismatch,
+		},
+		"価格が数値でない": {
+			target: "/v1/shelf/" + authUserId + "/1", body: `{"price":"abc"}`, header: mergePatch,
+			statusWant: http.StatusBadRequest, codeWant: problem.CodeInvalidBook,
+		},
+		"価格なしで通貨のみ変更": {
+			target: "/v1/shelf/" + authUserId + "/1", body: `{"currency":"USD"}`, header: mergePatch,
+			statusWant: http.StatusBadRequest, codeWant: problem.CodeInvalidBook,
+		},
+		"状態にnull": {
+			target: "/v1/shelf/" + authUserId + "/1", body: `{"bookStatus":null}`, header: mergePatch,
+			statusWant: http.StatusBadRequest, codeWant: problem.CodeInvalidBook,
+		},
+		"Content-Typeがjson": {
+			target: "/v1/shelf/" + authUserId + "/1", body: `{"bookStatus":"read"}`,
+			statusWant: http.StatusBadRequest, codeWant: problem.CodeSpecMismatch,
+		},
+		"他のユーザーの本": {
+			target: "/v1/shelf/2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e/1", body: `{"bookStatus":"read"}`, header: mergePatch,
+			statusWant: http.StatusNotFound, codeWant: problem.CodeBookNotFound,
+		},
+		"バージョンが古い": {
+			target: "/v1/shelf/" + authUserId + "/1", body: `{"bookStatus":"read"}`,
+			header:     map[string]string{echo.HeaderContentType: handler.MIMEApplicationMergePatchJSON, handler.HeaderIfMatch: `"5"`},
+			statusWant: http.StatusPreconditionFailed, codeWant: problem.CodePreconditionFailed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act ***************
+			w := serve(e, http.MethodPatch, tt.target, tt.body, tt.header)
+
+			//Assert ***************
+			var got problem.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.statusWant, w.Code, w.Body.String())
+			assert.Equal(t, tt.codeWant, got.Code)
+		})
+	}
+}
+
+func TestPatchUsersAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	user := &domain.User{ID: int64(1), AuthUserId: authUserId, Name: "before", Email: "example@example.com", CreatedAt: cl.Now(), UpdatedAt: cl.Now()}
+	testutils.InsertTestData(ctx, t, bundb, user)
+
+	_, e := testutils.SetupHandler(bundb)
+	a := assert.New(t)
+
+	//Act ***************
+	w := serve(e, http.MethodPatch, "/v1/users/"+authUserId, `{"name":null,"homeCurrency":"usd"}`, mergePatch)
+	invalid := serve(e, http.MethodPatch, "/v1/users/"+authUserId, `{"email":"example"}`, mergePatch)
+
+	//Assert ***************
+	a.Equal(http.StatusOK, w.Code, w.Body.String())
+	a.Equal(`"2"`, w.Header().Get(handler.HeaderETag))
+	var got handler.User
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	a.Empty(got.Name)
+	a.Equal("USD", got.HomeCurrency)
+	//省略した項目は変わらない
+	saved := new(domain.User)
+	if err := bundb.NewSelect().Model(saved).Where("auth_user_id = ?", authUserId).Scan(ctx); err != nil {
+		t.Fatal(err)
+	}
+	a.Equal(domain.Email("example@example.com"), saved.Email)
+
+	a.Equal(http.StatusBadRequest, invalid.Code)
+	a.Contains(invalid.Body.String(), string(problem.CodeValidationFailed))
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestGetRates(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	rates := []*domain.ExchangeRate{
+		{
+			Currency:  domain.USD,
+			Rate:      150.5,
+			CreatedAt: cl.Now(),
+			UpdatedAt: cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, rates...)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodGet, "/rates", nil)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
+
+	//Act ***************
+	err = sut.GetRates(c)
+
+	//Assert ***************
+	resBody := testutils.IndentForJSON(t, w.Body.String())
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	g.Assert(t, t.Name(), resBody)
+}
+
+func TestPutAdminRates(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//リクエストボディの準備
+	rates := []*handler.ExchangeRate{
+		{Currency: "USD", Rate: "150.5"},
+		{Currency: "eur", Rate: "162.3"},
+	}
+	jb := testutils.ConvertToJSON(t, rates)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodPut, "/admin/rates", &jb)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.PutAdminRates(c)
+
+	//Assert ***************
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	a.Empty(w.Body.Bytes())
+}
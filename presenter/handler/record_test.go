@@ -9,6 +9,7 @@ import (
 
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigen"
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/infra"
 	"github.com/taimats/bhapi/testutils"
@@ -101,14 +102,12 @@ func TestGetRecordsWithAuthUserId(t *testing.T) {
 	sut, e := testutils.SetupHandler(bundb)
 	r := httptest.NewRequest(http.MethodGet, "/records/:authUserId", nil)
 	c, w := testutils.EchoContextWithRecorder(r, e)
-	c.SetParamNames("authUserId")
-	c.SetParamValues("c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
 
 	a := assert.New(t)
 	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
 
 	//Act ***************
-	err = sut.GetRecordsWithAuthUserId(c)
+	err = sut.GetRecordsAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", apigen.GetRecordsAuthUserIdParams{})
 
 	//Assert ***************
 	resBody := testutils.IndentForJSON(t, w.Body.String())
@@ -9,6 +9,7 @@ import (
 
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigen"
 	"github.com/taimats/bhapi/infra"
 	"github.com/taimats/bhapi/testutils"
 )
@@ -34,7 +35,7 @@ func TestGetSearch(t *testing.T) {
 		t.Fatalf("GoogleBooksAPIテストサーバーのurlパースに失敗:%v", err)
 	}
 	testURL := u.JoinPath("books", "v1", "volumes").String()
-	t.Setenv("GOOGL_BOOKS_API_URL", testURL)
+	t.Setenv("GOOGLE_BOOKS_API_URL", testURL)
 
 	sut, e := testutils.SetupHandler(bundb)
 	q := make(url.Values)
@@ -46,7 +47,7 @@ func TestGetSearch(t *testing.T) {
 	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
 
 	//Act ***************
-	err = sut.GetSearch(c)
+	err = sut.GetSearch(c, apigen.GetSearchParams{Q: "容疑者の献身"})
 
 	//Assert ***************
 	resBody := testutils.IndentForJSON(t, w.Body.String())
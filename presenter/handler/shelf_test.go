@@ -2,17 +2,22 @@ package handler_test
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 
+	"github.com/labstack/echo/v4"
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigen"
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/infra"
 	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/middleware/auth"
+	"github.com/taimats/bhapi/presenter/problem"
 	"github.com/taimats/bhapi/testutils"
 )
 
@@ -51,7 +56,7 @@ func TestPostShelfAuthUserId(t *testing.T) {
 	a := assert.New(t)
 
 	//Act ***************
-	err = sut.PostShelfAuthUserId(c)
+	err = sut.PostShelfAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
 
 	//Assert ***************
 	a.Nil(err)
@@ -93,14 +98,12 @@ func TestGetShelfWithAuthUserId(t *testing.T) {
 	sut, e := testutils.SetupHandler(bundb)
 	r := httptest.NewRequest(http.MethodGet, "/shelf/:authUserId", nil)
 	c, w := testutils.EchoContextWithRecorder(r, e)
-	c.SetParamNames("authUserId")
-	c.SetParamValues("c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
 
 	a := assert.New(t)
 	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
 
 	//Act ***************
-	err = sut.GetShelfWithAuthUserId(c)
+	err = sut.GetShelfAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", apigen.GetShelfAuthUserIdParams{})
 
 	//Assert ***************
 	resBody := testutils.IndentForJSON(t, w.Body.String())
@@ -198,7 +201,7 @@ func TestPutShelfWithAuthUserId(t *testing.T) {
 	a := assert.New(t)
 
 	//Act ***************
-	err = sut.PutShelfWithAuthUserId(c)
+	err = sut.PutShelfAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", apigen.PutShelfAuthUserIdParams{})
 
 	//Assert ***************
 	a.Nil(err)
@@ -278,16 +281,54 @@ func TestDeleteShelfWithAuthUserId(t *testing.T) {
 	target := "/shelf/:authUserId?"
 	r := httptest.NewRequest(http.MethodDelete, target+q.Encode(), nil)
 	c, w := testutils.EchoContextWithRecorder(r, e)
-	c.SetParamNames("authUserId")
-	c.SetParamValues("c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
 
 	a := assert.New(t)
 
 	//Act ***************
-	err = sut.DeleteShelfWithAuthUserId(c)
+	err = sut.DeleteShelfAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", apigen.DeleteShelfAuthUserIdParams{BookId: []string{"1"}})
 
 	//Assert ***************
 	a.Nil(err)
 	a.Equal(http.StatusNoContent, w.Code)
 	a.Empty(w.Body.Bytes())
 }
+
+// 個人用APIキーで他のユーザーの本のidを指定した場合は404で、本を削除しないことを確認する
+func TestDeleteShelfWithOtherUsersBook(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	owner := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	other := &domain.Book{Title: "白夜行", Page: 864, Price: 1210, Currency: domain.JPY, BookStatus: domain.Bought, AuthUserId: "0b6c1e5e-3a0b-4c0e-9d0e-6d1f2a3b4c5d"}
+	testutils.InsertTestData(ctx, t, bundb, other)
+	_, e := testutils.SetupHandler(bundb)
+	//ownerのshelf:writeスコープの個人用APIキーで認証した状態（パスのauthUserIdの確認はミドルウェアで行う）
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(auth.ContextKeyAPIKey, &domain.APIKey{AuthUserId: owner, Scopes: []domain.APIKeyScope{domain.ScopeShelfWrite}})
+			return next(c)
+		}
+	})
+	a := assert.New(t)
+
+	//Act ***************
+	w := serve(e, http.MethodDelete, fmt.Sprintf("/v1/shelf/%s?bookId=%d", owner, other.ID), "", nil)
+	var remaining int
+	remaining, err = bundb.NewSelect().Model((*domain.Book)(nil)).Where("id = ?", other.ID).Count(ctx)
+
+	//Assert ***************
+	a.Equal(http.StatusNotFound, w.Code)
+	a.Contains(w.Body.String(), string(problem.CodeBookNotFound))
+	a.Nil(err)
+	a.Equal(1, remaining)
+}
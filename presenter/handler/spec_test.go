@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/testutils"
+)
+
+// ルーティングとAPI仕様の検証（strict）を通して、レスポンスがopenapi.yaml、openapi.v2.yamlに一致することを確認する。
+// 仕様に一致しないリクエスト、レスポンスは400、500になる。
+func TestAPISpecConformance(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	u := &domain.User{
+		AuthUserId: authUserId,
+		Email:      "example@example.com",
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, u)
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+	goal := &domain.Goal{
+		AuthUserId: authUserId,
+		Kind:       domain.GoalBooks,
+		Period:     domain.GoalYearly,
+		Target:     12,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, goal)
+
+	_, e := testutils.SetupHandler(bundb)
+
+	tests := map[string]struct {
+		method     string
+		target     string
+		body       string
+		statusWant int
+	}{
+		"GET /health":            {method: http.MethodGet, target: "/v1/health", statusWant: http.StatusOK},
+		"GET /users":             {method: http.MethodGet, target: "/v1/users/" + authUserId, statusWant: http.StatusOK},
+		"GET /users（なし）":         {method: http.MethodGet, target: "/v1/users/unknown", statusWant: http.StatusNotFound},
+		"GET /records":           {method: http.MethodGet, target: "/v1/records/" + authUserId, statusWant: http.StatusOK},
+		"GET /charts":            {method: http.MethodGet, target: "/v1/charts/" + authUserId, statusWant: http.StatusOK},
+		"GET /shelf":             {method: http.MethodGet, target: "/v1/shelf/" + authUserId, statusWant: http.StatusOK},
+		"GET /shelf/history":     {method: http.MethodGet, target: "/v1/shelf/" + authUserId + "/1/history", statusWant: http.StatusOK},
+		"POST /shelf/revert（なし）": {method: http.MethodPost, target: "/v1/shelf/" + authUserId + "/1/history/100/revert", statusWant: http.StatusNotFound},
+		"GET /rates":             {method: http.MethodGet, target: "/v1/rates", statusWant: http.StatusOK},
+		"GET /goals":             {method: http.MethodGet, target: "/v1/goals/" + authUserId, statusWant: http.StatusOK},
+		"GET /backlog":           {method: http.MethodGet, target: "/v1/backlog/" + authUserId, statusWant: http.StatusOK},
+		"GET /trash":             {method: http.MethodGet, target: "/v1/trash/" + authUserId, statusWant: http.StatusOK},
+		"GET /search（qなし）":       {method: http.MethodGet, target: "/v1/search", statusWant: http.StatusBadRequest},
+		"DELETE /goals（なし）":      {method: http.MethodDelete, target: "/v1/goals/" + authUserId + "?goalId=100", statusWant: http.StatusNotFound},
+		"PUT /goals":             {method: http.MethodPut, target: "/v1/goals/" + authUserId, body: `{"kind":"pages","period":"monthly","target":"1,000"}`, statusWant: http.StatusOK},
+		"PUT /admin/rates（型）":    {method: http.MethodPut, target: "/v1/admin/rates", body: `{"currency":"USD","rate":"150"}`, statusWant: http.StatusBadRequest},
+		"GET /apikeys":           {method: http.MethodGet, target: "/v1/apikeys/" + authUserId, statusWant: http.StatusOK},
+		"POST /apikeys":          {method: http.MethodPost, target: "/v1/apikeys/" + authUserId, body: `{"name":"script","scopes":["shelf:read"]}`, statusWant: http.StatusCreated},
+		"GET /admin/users":       {method: http.MethodGet, target: "/v1/admin/users?q=example&limit=10", statusWant: http.StatusOK},
+		"GET /admin/users/stats": {method: http.MethodGet, target: "/v1/admin/users/" + authUserId + "/stats", statusWant: http.StatusOK},
+		"GET /admin/stats":       {method: http.MethodGet, target: "/v1/admin/stats", statusWant: http.StatusOK},
+		"GET /admin/audit":       {method: http.MethodGet, target: "/v1/admin/audit?targetType=book&limit=10", statusWant: http.StatusOK},
+		"GET /activity":          {method: http.MethodGet, target: "/v1/activity/" + authUserId, statusWant: http.StatusOK},
+
+		//v2
+		"GET /v2/users":          {method: http.MethodGet, target: "/v2/users/" + authUserId, statusWant: http.StatusOK},
+		"GET /v2/records":        {method: http.MethodGet, target: "/v2/records/" + authUserId, statusWant: http.StatusOK},
+		"GET /v2/charts":         {method: http.MethodGet, target: "/v2/charts/" + authUserId, statusWant: http.StatusOK},
+		"GET /v2/shelf":          {method: http.MethodGet, target: "/v2/shelf/" + authUserId, statusWant: http.StatusOK},
+		"GET /v2/rates":          {method: http.MethodGet, target: "/v2/rates", statusWant: http.StatusOK},
+		"GET /v2/goals":          {method: http.MethodGet, target: "/v2/goals/" + authUserId, statusWant: http.StatusOK},
+		"GET /v2/backlog":        {method: http.MethodGet, target: "/v2/backlog/" + authUserId, statusWant: http.StatusOK},
+		"GET /v2/trash":          {method: http.MethodGet, target: "/v2/trash/" + authUserId, statusWant: http.StatusOK},
+		"PUT /v2/shelf/{bookId}": {method: http.MethodPut, target: "/v2/shelf/" + authUserId + "/1", body: `{"title":"容疑者Xの献身","page":330,"price":1800,"bookStatus":"read"}`, statusWant: http.StatusOK},
+		"PUT /v2/shelf（idが数値でない）":   {method: http.MethodPut, target: "/v2/shelf/" + authUserId + "/x", body: `{"title":"容疑者Xの献身","page":330,"price":1800,"bookStatus":"read"}`, statusWant: http.StatusBadRequest},
+		"PUT /v2/goals（数値が文字列）":     {method: http.MethodPut, target: "/v2/goals/" + authUserId, body: `{"kind":"pages","period":"monthly","target":"1,000"}`, statusWant: http.StatusBadRequest},
+		"DELETE /v2/goals（なし）":      {method: http.MethodDelete, target: "/v2/goals/" + authUserId + "/100", statusWant: http.StatusNotFound},
+		"POST /v2/shelf（statusが不正）": {method: http.MethodPost, target: "/v2/shelf/" + authUserId, body: `{"title":"予知夢","page":220,"price":220,"bookStatus":"unknown"}`, statusWant: http.StatusBadRequest},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			w := httptest.NewRecorder()
+
+			//Act ***************
+			e.ServeHTTP(w, r)
+
+			//Assert ***************
+			assert.Equal(t, tt.statusWant, w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package handler_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestGetTrashWithAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	books := []*domain.Book{
+		{
+			ID:         int64(1),
+			Title:      "容疑者Xの献身",
+			Author:     "東野圭吾",
+			Page:       330,
+			Price:      1640,
+			BookStatus: domain.Read,
+			AuthUserId: authUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			ID:         int64(2),
+			Title:      "予知夢",
+			Author:     "東野圭吾",
+			Page:       220,
+			Price:      220,
+			BookStatus: domain.Bought,
+			AuthUserId: authUserId,
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+			DeletedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, books...)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodGet, "/trash/:authUserId", nil)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
+
+	//Act ***************
+	err = sut.GetTrashAuthUserId(c, authUserId)
+
+	//Assert ***************
+	resBody := testutils.IndentForJSON(t, w.Body.String())
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	g.Assert(t, t.Name(), resBody)
+}
+
+func TestPostTrashBooksRestoreWithAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "予知夢",
+		BookStatus: domain.Bought,
+		AuthUserId: authUserId,
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+		DeletedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodPost, "/trash/:authUserId/books/restore?bookId=1", nil)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.PostTrashAuthUserIdBooksRestore(c, authUserId, apigen.PostTrashAuthUserIdBooksRestoreParams{BookId: []string{"1"}})
+
+	//Assert ***************
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+}
+
+func TestPostTrashUserRestoreWithAuthUserIdNotFound(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	sut, e := testutils.SetupHandler(bundb)
+	r := httptest.NewRequest(http.MethodPost, "/trash/:authUserId/user/restore", nil)
+	c, _ := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.PostTrashAuthUserIdUserRestore(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
+
+	//Assert ***************
+	var he *echo.HTTPError
+	a.ErrorAs(err, &he)
+	a.Equal(http.StatusNotFound, he.Code)
+}
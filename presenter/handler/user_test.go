@@ -1,14 +1,18 @@
 package handler_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/labstack/echo/v4"
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigen"
 	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/infra"
 	"github.com/taimats/bhapi/presenter/handler"
@@ -81,14 +85,12 @@ func TestGetUsersWithAuthUserId(t *testing.T) {
 	sut, e := testutils.SetupHandler(bundb)
 	r := httptest.NewRequest(http.MethodGet, "/users/:authUserId", nil)
 	c, w := testutils.EchoContextWithRecorder(r, e)
-	c.SetParamNames("authUserId")
-	c.SetParamValues("c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
 
 	a := assert.New(t)
 	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
 
 	//Act ***************
-	err = sut.GetUsersWithAuthUserId(c)
+	err = sut.GetUsersAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
 
 	//Assert ***************
 	resBody := testutils.IndentForJSON(t, w.Body.String())
@@ -121,18 +123,20 @@ func TestDeleteUsersWithAuthUserId(t *testing.T) {
 	sut, e := testutils.SetupHandler(bundb)
 	r := httptest.NewRequest(http.MethodDelete, "/users/:authUserId", nil)
 	c, w := testutils.EchoContextWithRecorder(r, e)
-	c.SetParamNames("authUserId")
-	c.SetParamValues("c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
 
 	a := assert.New(t)
 
 	//Act ***************
-	err = sut.DeleteUsersWithAuthUserId(c)
+	err = sut.DeleteUsersAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", apigen.DeleteUsersAuthUserIdParams{})
 
 	//Assert ***************
 	a.Nil(err)
-	a.Equal(http.StatusNoContent, w.Code)
-	a.Empty(w.Body.Bytes())
+	a.Equal(http.StatusOK, w.Code)
+	a.Equal("application/zip", w.Header().Get(echo.HeaderContentType))
+	a.Equal(`attachment; filename="bhapi-export-c0cc3f0c-9a02-45ba-9de7-7d7276bb6058-20240205144300.zip"`, w.Header().Get(echo.HeaderContentDisposition))
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	a.Nil(err)
+	a.Len(zr.File, 5)
 }
 
 func TestPutUsers(t *testing.T) {
@@ -177,7 +181,7 @@ func TestPutUsers(t *testing.T) {
 	a := assert.New(t)
 
 	//Act ***************
-	err = sut.PutUsers(c)
+	err = sut.PutUsers(c, apigen.PutUsersParams{})
 
 	//Assert ***************
 	a.Nil(err)
@@ -0,0 +1,428 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/apigenv2"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/utils"
+)
+
+// v2のルーティングの接頭辞（openapi.v2.yamlのserversのパス）
+const BaseURLV2 = "/v2"
+
+// v2のAPI。数値、日時、列挙型を型のまま返す（openapi.v2.yaml）。
+// コントローラはv1と共有し、Json形式への変換のみが異なる。
+type HandlerV2 Handler
+
+// 同じコントローラを使うv2のハンドラを返す
+func (h *Handler) V2() *HandlerV2 {
+	return (*HandlerV2)(h)
+}
+
+var _ apigenv2.ServerInterface = (*HandlerV2)(nil)
+
+// user情報の登録
+// (POST /auth/register)
+func (h *HandlerV2) PostAuthRegister(c echo.Context) error {
+	var u UserV2
+	if err := c.Bind(&u); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(&u); err != nil {
+		return err
+	}
+
+	user, err := convertUserV2(&u)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeInvalidUser, nil)
+	}
+
+	ctx := c.Request().Context()
+	err = h.uc.RegisterUser(ctx, user)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserRegisterFailed, problem.Codes{
+			domain.ErrConflict:       problem.CodeUserAlreadyExists,
+			domain.ErrPasswordHashed: problem.CodeInvalidUser,
+		})
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// ユーザーごとに積読の状況を返す
+// (GET /backlog/{authUserId})
+func (h *HandlerV2) GetBacklogAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	backlog, err := h.bc.GetBacklog(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBacklogGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, newBacklogV2(backlog))
+}
+
+// ユーザーごとにチャートデータを返す。If-None-Matchが一致する場合は304。
+// (GET /charts/{authUserId})
+func (h *HandlerV2) GetChartsAuthUserId(c echo.Context, authUserId string, params apigenv2.GetChartsAuthUserIdParams) error {
+	ctx := c.Request().Context()
+
+	chs, err := h.cc.GetCharts(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeChartGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeChartNotFound})
+	}
+
+	return jsonWithETag(c, params.IfNoneMatch, newChartsV2(chs))
+}
+
+// ユーザーごとに目標の進捗を返す
+// (GET /goals/{authUserId})
+func (h *HandlerV2) GetGoalsAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	progress, err := h.gc.GetGoalProgress(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeGoalGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, newGoalProgressV2(progress))
+}
+
+// ユーザーごとに目標を登録、更新
+// (PUT /goals/{authUserId})
+func (h *HandlerV2) PutGoalsAuthUserId(c echo.Context, authUserId string) error {
+	g := new(GoalV2)
+	if err := c.Bind(g); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(g); err != nil {
+		return err
+	}
+
+	goal, err := convertGoalV2(g, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeInvalidGoal, nil)
+	}
+
+	ctx := c.Request().Context()
+	err = h.gc.SetGoal(ctx, goal)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeGoalSetFailed, problem.Codes{domain.ErrInvalidGoal: problem.CodeInvalidGoal})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ユーザーごとに目標を削除
+// (DELETE /goals/{authUserId}/{goalId})
+func (h *HandlerV2) DeleteGoalsAuthUserIdGoalId(c echo.Context, authUserId string, goalId int64) error {
+	ctx := c.Request().Context()
+
+	err := h.gc.DeleteGoal(ctx, authUserId, goalId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeGoalDeleteFailed, problem.Codes{domain.ErrNotFound: problem.CodeGoalNotFound})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// サーバーの監視
+// (GET /health)
+func (h *HandlerV2) GetHealth(c echo.Context) error {
+	return c.JSON(http.StatusOK, &HealthV2{Message: "ok"})
+}
+
+// DBサーバーの監視
+// (GET /health/db)
+func (h *HandlerV2) GetHealthDb(c echo.Context) error {
+	if !h.hc.IsActive() {
+		return echo.NewHTTPError(http.StatusInternalServerError, problem.CodeDBUnavailable)
+	}
+	return c.JSON(http.StatusOK, &HealthV2{Message: "ok"})
+}
+
+// 為替レートの一覧を返す
+// (GET /rates)
+func (h *HandlerV2) GetRates(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	rates, err := h.rtc.GetRates(ctx)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeRateGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, newRatesV2(rates))
+}
+
+// ユーザーごとに記録を返す。If-None-Matchが一致する場合は304。
+// (GET /records/{authUserId})
+func (h *HandlerV2) GetRecordsAuthUserId(c echo.Context, authUserId string, params apigenv2.GetRecordsAuthUserIdParams) error {
+	ctx := c.Request().Context()
+
+	record, err := h.rc.GetRecord(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeRecordGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeRecordNotFound})
+	}
+
+	return jsonWithETag(c, params.IfNoneMatch, newRecordV2(record))
+}
+
+// 書籍の検索結果を取得
+// (GET /search)
+func (h *HandlerV2) GetSearch(c echo.Context, params apigenv2.GetSearchParams) error {
+	if params.Q == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingQuery)
+	}
+
+	ctx := c.Request().Context()
+	results, err := h.sbc.SearchBooks(ctx, params.Q)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeSearchFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, newSearchResultsV2(results))
+}
+
+// ユーザーごとに本棚の本を複数削除
+// (DELETE /shelf/{authUserId})
+func (h *HandlerV2) DeleteShelfAuthUserId(c echo.Context, authUserId string, params apigenv2.DeleteShelfAuthUserIdParams) error {
+	if len(params.BookId) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingBookId)
+	}
+
+	ctx := c.Request().Context()
+	err := h.sc.DeleteShelf(ctx, authUserId, formatIds(params.BookId))
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBookDeleteFailed, problem.Codes{domain.ErrNotFound: problem.CodeBookNotFound})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ユーザーごとに本棚を取得。If-None-Matchが一致する場合は304。
+// (GET /shelf/{authUserId})
+func (h *HandlerV2) GetShelfAuthUserId(c echo.Context, authUserId string, params apigenv2.GetShelfAuthUserIdParams) error {
+	ctx := c.Request().Context()
+
+	books, err := h.sc.GetShelf(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeShelfGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeShelfNotFound})
+	}
+
+	return jsonWithETag(c, params.IfNoneMatch, newBooksV2(books))
+}
+
+// ユーザーごとに本を本棚に1冊ずつ作成。作成した本と購入額の上限の超過状況を返す。
+// (POST /shelf/{authUserId})
+func (h *HandlerV2) PostShelfAuthUserId(c echo.Context, authUserId string) error {
+	var b BookV2
+	if err := c.Bind(&b); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(&b); err != nil {
+		return err
+	}
+
+	book, err := convertBookV2(&b, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeInvalidBook, nil)
+	}
+
+	ctx := c.Request().Context()
+	err = h.sc.PostBookWithCharts(ctx, book)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBookCreateFailed, nil)
+	}
+	(*Handler)(h).recordReachedGoals(c, authUserId)
+
+	//購入額の上限の確認に失敗しても、本の作成自体は成功扱い
+	created := &BookCreatedV2{Book: newBookV2(book), Goals: []GoalProgressV2{}}
+	exceeded, err := h.gc.ExceededSpendCaps(ctx, authUserId)
+	if err != nil {
+		c.Logger().Error(err)
+		return c.JSON(http.StatusCreated, created)
+	}
+	if len(exceeded) > 0 {
+		created.SpendCapExceeded = true
+		created.Goals = newGoalProgressV2(exceeded)
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+// 本棚の本を1冊更新。更新後の本を返す。If-Matchがある場合はバージョンが一致する場合のみ更新する。
+// (PUT /shelf/{authUserId}/{bookId})
+func (h *HandlerV2) PutShelfAuthUserIdBookId(c echo.Context, authUserId string, bookId int64, params apigenv2.PutShelfAuthUserIdBookIdParams) error {
+	var b BookV2
+	if err := c.Bind(&b); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(&b); err != nil {
+		return err
+	}
+
+	book, err := convertBookV2(&b, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeInvalidBook, nil)
+	}
+	book.Version, err = ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return problem.Wrap(err, problem.CodePreconditionFailed, nil)
+	}
+
+	//登録日時はクライアントから受け取らず、登録済みの本の値を引き継ぐ
+	ctx := c.Request().Context()
+	current, err := h.findBook(ctx, authUserId, bookId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBookGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeBookNotFound})
+	}
+	book.ID = current.ID
+	book.CreatedAt = current.CreatedAt
+
+	err = h.sc.UpdateShelf(ctx, book)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBookUpdateFailed, problem.Codes{
+			domain.ErrNotFound:           problem.CodeBookNotFound,
+			domain.ErrPreconditionFailed: problem.CodePreconditionFailed,
+		})
+	}
+	(*Handler)(h).recordReachedGoals(c, authUserId)
+
+	setVersionETag(c, book.Version)
+	return c.JSON(http.StatusOK, newBookV2(book))
+}
+
+// ユーザーごとにゴミ箱の中身を返す
+// (GET /trash/{authUserId})
+func (h *HandlerV2) GetTrashAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	trash, err := h.tc.GetTrash(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeTrashGetFailed, nil)
+	}
+
+	return c.JSON(http.StatusOK, newTrashV2(trash))
+}
+
+// ゴミ箱の本を複数復元
+// (POST /trash/{authUserId}/books/restore)
+func (h *HandlerV2) PostTrashAuthUserIdBooksRestore(c echo.Context, authUserId string, params apigenv2.PostTrashAuthUserIdBooksRestoreParams) error {
+	if len(params.BookId) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeMissingBookId)
+	}
+
+	ctx := c.Request().Context()
+	err := h.tc.RestoreBooks(ctx, authUserId, formatIds(params.BookId))
+	if err != nil {
+		return problem.Wrap(err, problem.CodeBookRestoreFailed, problem.Codes{domain.ErrNotFound: problem.CodeTrashBookNotFound})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ゴミ箱のユーザーを復元
+// (POST /trash/{authUserId}/user/restore)
+func (h *HandlerV2) PostTrashAuthUserIdUserRestore(c echo.Context, authUserId string) error {
+	//レスポンスのボディがないためv1と同じ
+	return (*Handler)(h).PostTrashAuthUserIdUserRestore(c, authUserId)
+}
+
+// ユーザーを削除
+// (DELETE /users/{authUserId})
+func (h *HandlerV2) DeleteUsersAuthUserId(c echo.Context, authUserId string, params apigenv2.DeleteUsersAuthUserIdParams) error {
+	//レスポンスはzipアーカイブのためv1と同じ
+	return (*Handler)(h).DeleteUsersAuthUserId(c, authUserId, apigen.DeleteUsersAuthUserIdParams{Immediate: params.Immediate})
+}
+
+// ユーザー情報を返す
+// (GET /users/{authUserId})
+func (h *HandlerV2) GetUsersAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	user, err := h.uc.GetUser(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeUserNotFound})
+	}
+
+	setVersionETag(c, user.Version)
+	return c.JSON(http.StatusOK, newUserV2(user))
+}
+
+// ユーザー情報を更新。passwordは指定した場合のみ更新し、更新後のユーザーを返す。
+// If-Matchがある場合はバージョンが一致する場合のみ更新する。
+// (PUT /users/{authUserId})
+func (h *HandlerV2) PutUsersAuthUserId(c echo.Context, authUserId string, params apigenv2.PutUsersAuthUserIdParams) error {
+	u := new(UserV2)
+	if err := c.Bind(u); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(u); err != nil {
+		return err
+	}
+	if u.AuthUserId != authUserId {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeUserIdMismatch)
+	}
+
+	user, err := convertUserV2(u)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeInvalidUser, nil)
+	}
+	user.Version, err = ifMatchVersion(params.IfMatch)
+	if err != nil {
+		return problem.Wrap(err, problem.CodePreconditionFailed, nil)
+	}
+
+	ctx := c.Request().Context()
+	current, err := h.uc.GetUser(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserGetFailed, problem.Codes{domain.ErrNotFound: problem.CodeUserNotFound})
+	}
+	user.ID = current.ID
+	user.CreatedAt = current.CreatedAt
+	if user.Password == "" {
+		user.Password = current.Password
+	}
+
+	err = h.uc.UpdateUser(ctx, user)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeUserUpdateFailed, problem.Codes{
+			domain.ErrNotFound:           problem.CodeUserNotFound,
+			domain.ErrPreconditionFailed: problem.CodePreconditionFailed,
+			domain.ErrPasswordHashed:     problem.CodeInvalidUser,
+			domain.ErrAccountDisabled:    problem.CodeAccountDisabled,
+		})
+	}
+
+	setVersionETag(c, user.Version)
+	return c.JSON(http.StatusOK, newUserV2(user))
+}
+
+// 本棚から1冊を探す。見つからない場合はdomain.ErrNotFoundを返す。
+func (h *HandlerV2) findBook(ctx context.Context, authUserId string, bookId int64) (*domain.Book, error) {
+	books, err := h.sc.GetShelf(ctx, authUserId)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range books {
+		if b.ID == bookId {
+			return b, nil
+		}
+	}
+	return nil, utils.NewErrChains(domain.ErrNotFound, nil)
+}
+
+// コントローラは文字列のidを受け取るため、数値のidを文字列に変換
+func formatIds(ids []int64) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.FormatInt(id, 10)
+	}
+	return s
+}
@@ -0,0 +1,257 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigenv2"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestGetShelfV2WithAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	book := &domain.Book{
+		ID:         int64(1),
+		ISBN10:     "4167110121",
+		ImageURL:   "http://books.google.com/books/content?id=TL3APAAACAAJ&printsec=frontcover&img=1&zoom=1&source=gbs_api",
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       2470,
+		Price:      9800,
+		BookStatus: domain.Read,
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+
+	//request, resposeの準備
+	h, e := testutils.SetupHandler(bundb)
+	sut := h.V2()
+	r := httptest.NewRequest(http.MethodGet, "/v2/shelf/:authUserId", nil)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
+
+	//Act ***************
+	err = sut.GetShelfAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", apigenv2.GetShelfAuthUserIdParams{})
+
+	//Assert ***************
+	resBody := testutils.IndentForJSON(t, w.Body.String())
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	g.Assert(t, t.Name(), resBody)
+}
+
+func TestGetRecordsV2WithAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	books := []*domain.Book{
+		{
+			ID:         int64(1),
+			Title:      "容疑者Xの献身",
+			Author:     "東野圭吾",
+			Page:       330,
+			Price:      1640,
+			BookStatus: domain.Read,
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+		{
+			ID:         int64(2),
+			Title:      "ガリレオの苦悩",
+			Author:     "東野圭吾",
+			Page:       2340,
+			Price:      7700,
+			BookStatus: domain.Bought,
+			AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			CreatedAt:  cl.Now(),
+			UpdatedAt:  cl.Now(),
+		},
+	}
+	testutils.InsertTestData(ctx, t, bundb, books...)
+
+	h, e := testutils.SetupHandler(bundb)
+	sut := h.V2()
+	r := httptest.NewRequest(http.MethodGet, "/v2/records/:authUserId", nil)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+	g := goldie.New(t, goldie.WithDiffEngine(goldie.ColoredDiff))
+
+	//Act ***************
+	err = sut.GetRecordsAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", apigenv2.GetRecordsAuthUserIdParams{})
+
+	//Assert ***************
+	resBody := testutils.IndentForJSON(t, w.Body.String())
+	a.Nil(err)
+	a.Equal(http.StatusOK, w.Code)
+	g.Assert(t, t.Name(), resBody)
+}
+
+func TestPostShelfV2AuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//リクエストボディの準備
+	book := &handler.BookV2{
+		Author:     "東野圭吾",
+		BookStatus: "read",
+		Isbn10:     "4167110121",
+		Page:       2470,
+		Price:      9800,
+		Title:      "容疑者Xの献身",
+	}
+	jb := testutils.ConvertToJSON(t, book)
+
+	h, e := testutils.SetupHandler(bundb)
+	sut := h.V2()
+	r := httptest.NewRequest(http.MethodPost, "/v2/shelf/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", &jb)
+	c, w := testutils.EchoContextWithRecorder(r, e)
+
+	a := assert.New(t)
+
+	//Act ***************
+	err = sut.PostShelfAuthUserId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058")
+
+	//Assert ***************
+	a.Nil(err)
+	a.Equal(http.StatusCreated, w.Code)
+	var got handler.BookCreatedV2
+	a.Nil(json.Unmarshal(w.Body.Bytes(), &got))
+	a.NotZero(got.Book.Id)
+	a.Equal(2470, got.Book.Page)
+	a.Equal(9800, got.Book.Price)
+	a.Equal("c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", got.Book.AuthUserId)
+	a.False(got.SpendCapExceeded)
+	a.Empty(got.Goals)
+}
+
+func TestPutShelfV2WithAuthUserIdBookId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	book := &domain.Book{
+		ID:         int64(1),
+		Title:      "容疑者Xの献身",
+		Author:     "東野圭吾",
+		Page:       330,
+		Price:      1640,
+		BookStatus: domain.Bought,
+		AuthUserId: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		CreatedAt:  cl.Now(),
+		UpdatedAt:  cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, book)
+	charts := domain.NewChartsFromBook(book)
+	for _, c := range charts {
+		c.BookId = book.ID
+	}
+	testutils.InsertTestData(ctx, t, bundb, charts...)
+
+	tests := map[string]struct {
+		bookId     int64
+		statusWant int
+	}{
+		"OK:本を更新": {bookId: 1, statusWant: http.StatusOK},
+		"NG:本がない": {bookId: 100, statusWant: http.StatusNotFound},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//リクエストボディの準備
+			body := &handler.BookV2{
+				Author:     "東野圭吾",
+				BookStatus: "read",
+				Page:       330,
+				Price:      1800,
+				Title:      "容疑者Xの献身",
+			}
+			jb := testutils.ConvertToJSON(t, body)
+
+			h, e := testutils.SetupHandler(bundb)
+			sut := h.V2()
+			r := httptest.NewRequest(http.MethodPut, "/v2/shelf/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058/1", &jb)
+			c, w := testutils.EchoContextWithRecorder(r, e)
+
+			a := assert.New(t)
+
+			//Act ***************
+			err := sut.PutShelfAuthUserIdBookId(c, "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", tt.bookId, apigenv2.PutShelfAuthUserIdBookIdParams{})
+
+			//Assert ***************
+			if tt.statusWant != http.StatusOK {
+				var he *echo.HTTPError
+				a.ErrorAs(err, &he)
+				a.Equal(tt.statusWant, he.Code)
+				return
+			}
+			a.Nil(err)
+			a.Equal(tt.statusWant, w.Code)
+			var got handler.BookV2
+			a.Nil(json.Unmarshal(w.Body.Bytes(), &got))
+			a.Equal(int64(1), got.Id)
+			a.Equal(1800, got.Price)
+			a.Equal("read", string(got.BookStatus))
+			a.True(cl.Now().Equal(got.CreatedAt), "登録日時は引き継ぐ")
+		})
+	}
+}
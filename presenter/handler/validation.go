@@ -2,22 +2,34 @@ package handler
 
 import (
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/presenter/problem"
 )
 
 type customValidator struct {
 	validator *validator.Validate
 }
 
+// 項目のエラーはjsonのキー名（例."authUserId"）で返す
 func NewCustomValidator(v *validator.Validate) *customValidator {
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
 	return &customValidator{validator: v}
 }
 
+// 検証に失敗した場合は、項目ごとのエラー（validator.ValidationErrors）を内部に持つ400のエラーを返す
 func (cv *customValidator) Validate(s any) error {
 	if err := cv.validator.Struct(s); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "不正なリクエストです")
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeValidationFailed).SetInternal(err)
 	}
 
 	return nil
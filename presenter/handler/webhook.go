@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// ユーザーごとに登録したWebhookを返す
+// (GET /webhooks/{authUserId})
+func (h *Handler) GetWebhooksAuthUserId(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	webhooks, err := h.wc.GetWebhooks(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeWebhookGetFailed, nil)
+	}
+
+	res := make([]*Webhook, len(webhooks))
+	for i, w := range webhooks {
+		res[i] = tweakWebhookForJSON(w)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Webhookを登録し、署名の鍵を返す
+// (POST /webhooks/{authUserId})
+func (h *Handler) PostWebhooksAuthUserId(c echo.Context, authUserId string) error {
+	w := new(Webhook)
+	if err := c.Bind(w); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody)
+	}
+	if err := c.Validate(w); err != nil {
+		return err
+	}
+
+	webhook := &domain.Webhook{AuthUserId: authUserId, URL: w.Url}
+	for _, ev := range w.Events {
+		webhook.Events = append(webhook.Events, domain.EventType(ev))
+	}
+
+	ctx := c.Request().Context()
+	err := h.wc.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeWebhookCreateFailed, problem.Codes{domain.ErrInvalidWebhook: problem.CodeInvalidWebhook})
+	}
+
+	res := tweakWebhookForJSON(webhook)
+	res.Secret = webhook.Secret
+	return c.JSON(http.StatusCreated, res)
+}
+
+// Webhookを削除
+// (DELETE /webhooks/{authUserId}/{webhookId})
+func (h *Handler) DeleteWebhooksAuthUserIdWebhookId(c echo.Context, authUserId string, webhookId string) error {
+	id, err := strconv.ParseInt(webhookId, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeBadRequest)
+	}
+
+	ctx := c.Request().Context()
+	err = h.wc.DeleteWebhook(ctx, authUserId, id)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeWebhookDeleteFailed, problem.Codes{domain.ErrNotFound: problem.CodeWebhookNotFound})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// 再試行の上限に達した配信（デッドレター）を返す
+// (GET /webhooks/{authUserId}/deadletters)
+func (h *Handler) GetWebhooksAuthUserIdDeadletters(c echo.Context, authUserId string) error {
+	ctx := c.Request().Context()
+
+	deliveries, err := h.wc.GetDeadLetters(ctx, authUserId)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeDeadLetterGetFailed, nil)
+	}
+
+	res := make([]*WebhookDelivery, len(deliveries))
+	for i, d := range deliveries {
+		res[i] = tweakWebhookDeliveryForJSON(d)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// デッドレターを配信待ちに戻す。配信はディスパッチャが非同期に行う。
+// (POST /webhooks/{authUserId}/deadletters/{deliveryId}/retry)
+func (h *Handler) PostWebhooksAuthUserIdDeadlettersDeliveryIdRetry(c echo.Context, authUserId string, deliveryId string) error {
+	id, err := strconv.ParseInt(deliveryId, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, problem.CodeBadRequest)
+	}
+
+	ctx := c.Request().Context()
+	err = h.wc.RetryDeadLetter(ctx, authUserId, id)
+	if err != nil {
+		return problem.Wrap(err, problem.CodeDeadLetterRetryFailed, problem.Codes{domain.ErrNotFound: problem.CodeDeadLetterNotFound})
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
@@ -0,0 +1,152 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/testutils"
+)
+
+func TestWebhooksAuthUserId(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/webhooks/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	a := assert.New(t)
+
+	//Act ***************
+	created := serve(e, http.MethodPost, target, `{"url":"https://example.com/hook","events":["book.finished"]}`, nil)
+	list := serve(e, http.MethodGet, target, "", nil)
+	var hook handler.Webhook
+	if err := json.Unmarshal(created.Body.Bytes(), &hook); err != nil {
+		t.Fatal(err)
+	}
+	deleted := serve(e, http.MethodDelete, target+"/"+hook.Id, "", nil)
+	after := serve(e, http.MethodGet, target, "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusCreated, created.Code)
+	a.Equal("https://example.com/hook", hook.Url)
+	a.Equal([]string{"book.finished"}, hook.Events)
+	a.NotEmpty(hook.Secret)
+	a.Equal(http.StatusOK, list.Code)
+	a.Contains(list.Body.String(), `"url":"https://example.com/hook"`)
+	a.NotContains(list.Body.String(), hook.Secret)
+	a.Equal(http.StatusNoContent, deleted.Code)
+	a.JSONEq(`[]`, after.Body.String())
+}
+
+func TestWebhooksAuthUserIdDeadletters(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	//テストデータの挿入
+	authUserId := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	delivery := &domain.WebhookDelivery{
+		ID:            1,
+		WebhookId:     1,
+		OutboxId:      1,
+		AuthUserId:    authUserId,
+		Type:          domain.EventBookFinished,
+		Payload:       []byte(`{"type":"book.finished","authUserId":"c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"}`),
+		Status:        domain.DeliveryDead,
+		Attempts:      domain.MaxWebhookAttempts,
+		NextAttemptAt: cl.Now(),
+		LastStatus:    http.StatusServiceUnavailable,
+		LastError:     "503 Service Unavailable",
+		CreatedAt:     cl.Now(),
+		UpdatedAt:     cl.Now(),
+	}
+	testutils.InsertTestData(ctx, t, bundb, delivery)
+
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/webhooks/" + authUserId + "/deadletters"
+	a := assert.New(t)
+
+	//Act ***************
+	dead := serve(e, http.MethodGet, target, "", nil)
+	retry := serve(e, http.MethodPost, target+"/"+strconv.FormatInt(delivery.ID, 10)+"/retry", "", nil)
+	retryAgain := serve(e, http.MethodPost, target+"/"+strconv.FormatInt(delivery.ID, 10)+"/retry", "", nil)
+	after := serve(e, http.MethodGet, target, "", nil)
+
+	//Assert ***************
+	a.Equal(http.StatusOK, dead.Code)
+	a.Contains(dead.Body.String(), `"lastStatus":503`)
+	a.Contains(dead.Body.String(), `"payload":{"authUserId":"c0cc3f0c-9a02-45ba-9de7-7d7276bb6058","type":"book.finished"}`)
+	a.Equal(http.StatusAccepted, retry.Code)
+	a.Equal(http.StatusNotFound, retryAgain.Code)
+	a.Contains(retryAgain.Body.String(), string(problem.CodeDeadLetterNotFound))
+	a.JSONEq(`[]`, after.Body.String())
+}
+
+func TestWebhooksAuthUserIdWithError(t *testing.T) {
+	//Arrange ***************
+	ctx := context.Background()
+	dbctr.Restore(ctx, t)
+	bundb, err := infra.NewBunDB(dbctr.Dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := bundb.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	_, e := testutils.SetupHandler(bundb)
+	target := "/v1/webhooks/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	tests := map[string]struct {
+		method string
+		target string
+		body   string
+		status int
+		code   problem.Code
+	}{
+		"NG:httpでないURL":   {method: http.MethodPost, target: target, body: `{"url":"ftp://example.com/hook"}`, status: http.StatusBadRequest, code: problem.CodeInvalidWebhook},
+		"NG:未対応のイベント":     {method: http.MethodPost, target: target, body: `{"url":"https://example.com/hook","events":["book.read"]}`, status: http.StatusBadRequest, code: problem.CodeInvalidWebhook},
+		"NG:内部のアドレス":      {method: http.MethodPost, target: target, body: `{"url":"http://169.254.169.254/latest/meta-data"}`, status: http.StatusBadRequest, code: problem.CodeInvalidWebhook},
+		"NG:未登録のWebhook":  {method: http.MethodDelete, target: target + "/1", status: http.StatusNotFound, code: problem.CodeWebhookNotFound},
+		"NG:数値でないWebhook": {method: http.MethodDelete, target: target + "/abc", status: http.StatusBadRequest, code: problem.CodeBadRequest},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act ***************
+			w := serve(e, test.method, test.target, test.body, nil)
+
+			//Assert ***************
+			assert.Equal(t, test.status, w.Code)
+			assert.Contains(t, w.Body.String(), string(test.code))
+		})
+	}
+}
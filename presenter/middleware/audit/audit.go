@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/middleware/auth"
+)
+
+type Config struct {
+	Skipper middleware.Skipper
+}
+
+// 監査ログに記録するリクエストの情報（操作したユーザー、リクエストID、IPアドレス）をリクエストのcontextに設定するmiddlewareを返す。
+// 認証（KeyAuth）とリクエストID（middleware.RequestID）の後に置く。
+//   - 個人用APIキーの場合はキーの持ち主を操作したユーザーとする
+//   - アプリのキーの場合はパスのauthUserIdを操作したユーザーとする（アプリはユーザーの代わりに呼び出す）
+func WithConfig(cfg Config) echo.MiddlewareFunc {
+	if cfg.Skipper == nil {
+		cfg.Skipper = middleware.DefaultSkipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper(c) {
+				return next(c)
+			}
+			meta := domain.AuditMeta{
+				Actor:     c.Param("authUserId"),
+				Via:       domain.AuditViaApp,
+				RequestId: c.Response().Header().Get(echo.HeaderXRequestID),
+				IP:        c.RealIP(),
+			}
+			if k, ok := auth.APIKeyFrom(c); ok {
+				meta.Actor = k.AuthUserId
+				meta.Via = domain.AuditViaAPIKey
+				meta.APIKeyId = k.ID
+			}
+			req := c.Request()
+			c.SetRequest(req.WithContext(domain.WithAuditMeta(req.Context(), meta)))
+			return next(c)
+		}
+	}
+}
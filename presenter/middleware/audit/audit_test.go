@@ -0,0 +1,56 @@
+package audit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/middleware/audit"
+	"github.com/taimats/bhapi/presenter/middleware/auth"
+)
+
+func TestWithConfig(t *testing.T) {
+	//Arrange
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	//個人用APIキーで認証した状態（ヘッダーで切り替える）
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("X-Test-API-Key") != "" {
+				c.Set(auth.ContextKeyAPIKey, &domain.APIKey{ID: 3, AuthUserId: "owner"})
+			}
+			return next(c)
+		}
+	})
+	e.Use(audit.WithConfig(audit.Config{}))
+	var got domain.AuditMeta
+	e.GET("/shelf/:authUserId", func(c echo.Context) error {
+		got = domain.AuditMetaFrom(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+	serve := func(header map[string]string) domain.AuditMeta {
+		r := httptest.NewRequest(http.MethodGet, "/shelf/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", nil)
+		r.Header.Set(echo.HeaderXRealIP, "192.0.2.1")
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		e.ServeHTTP(httptest.NewRecorder(), r)
+		return got
+	}
+	a := assert.New(t)
+
+	//Act
+	app := serve(map[string]string{echo.HeaderXRequestID: "req-1"})
+	key := serve(map[string]string{"X-Test-API-Key": "1"})
+
+	//Assert
+	a.Equal(domain.AuditMeta{Actor: "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", Via: domain.AuditViaApp, RequestId: "req-1", IP: "192.0.2.1"}, app)
+	a.Equal("owner", key.Actor)
+	a.Equal(domain.AuditViaAPIKey, key.Via)
+	a.Equal(int64(3), key.APIKeyId)
+	a.NotEmpty(key.RequestId) //リクエストIDがない場合は生成する
+}
@@ -1,10 +1,17 @@
 package auth
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
-	"os"
+	"log/slog"
+	"net/http"
+	"strings"
 
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
 	"github.com/taimats/bhapi/utils"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -15,8 +22,8 @@ var (
 	ErrAuthInvalidKey = errors.New("不正なトークンです")
 )
 
-// Bearerのapikeyを検証
-func Authenticate(apikey string) (bool, error) {
+// Bearerのapikeyを検証。seedはキーの照合に使う種（設定のTOKEN_SEED）
+func Authenticate(apikey string, seed string) (bool, error) {
 	if apikey == "" {
 		return false, utils.NewErrChains(ErrAuthEmty, nil)
 	}
@@ -26,11 +33,220 @@ func Authenticate(apikey string) (bool, error) {
 		return false, utils.NewErrChains(ErrAuthDecFail, err)
 	}
 
-	src := os.Getenv("TOKEN_SEED")
-	err = bcrypt.CompareHashAndPassword(decodedKey, []byte(src))
+	err = bcrypt.CompareHashAndPassword(decodedKey, []byte(seed))
 	if err != nil {
 		return false, utils.NewErrChains(ErrAuthInvalidKey, err)
 	}
 
 	return true, nil
 }
+
+// APIキーの認証の失敗を数える先（controller.Lockout）
+type Guard interface {
+	Check(ctx context.Context, scope domain.LockoutScope, key string) error
+	Fail(ctx context.Context, scope domain.LockoutScope, key string) (*domain.AuthFailure, bool, error)
+}
+
+// 個人用APIキーの認証先（controller.APIKey）
+type KeyStore interface {
+	Authenticate(ctx context.Context, token string) (*domain.APIKey, error)
+}
+
+// 認証した個人用APIキーを保存するecho.Contextのキー
+const ContextKeyAPIKey = "auth.apikey"
+
+type Config struct {
+	// アプリのキーの照合に使う種（設定のTOKEN_SEED）
+	TokenSeed string
+
+	// APIキーの認証の失敗を数える先
+	Guard Guard
+
+	// 個人用APIキー（domain.APIKeyTokenPrefixで始まるキー）の認証先
+	Keys KeyStore
+
+	// 個人用APIキーで呼び出せるルート（"メソッド echoのパス"）と、必要なスコープ。含まないルートは拒否する。
+	Scopes map[string]domain.APIKeyScope
+
+	// 記録の読み書きの失敗の出力先。nilの場合はslog.Default()
+	Logger *slog.Logger
+}
+
+// KeyAuthのValidatorを返す。Authenticateの失敗をIPアドレスごとにGuardに記録し、ロック中のIPアドレスは照合せずに*domain.LockedErrorを返す。
+// 記録の読み書きに失敗した場合はLoggerに出力し、キーの照合のみで判定する（DBの障害で正しいキーを拒否しない）。
+// 個人用APIキーはKeysで照合し、ルートのスコープとパスのauthUserId（キーの持ち主のみ）を確認する。
+func NewValidator(config Config) middleware.KeyAuthValidator {
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(key string, c echo.Context) (bool, error) {
+		ctx := c.Request().Context()
+		ip := c.RealIP()
+		if err := config.Guard.Check(ctx, domain.LockoutAPIKey, ip); err != nil {
+			if errors.Is(err, domain.ErrTooManyRequests) {
+				return false, err
+			}
+			logger.Error(err.Error())
+		}
+
+		var ok bool
+		var err error
+		if domain.IsAPIKeyToken(key) {
+			ok, err = authorizeAPIKey(c, config, key)
+		} else {
+			ok, err = Authenticate(key, config.TokenSeed)
+		}
+		if ok {
+			return true, nil
+		}
+		switch {
+		case isKeyMismatch(err):
+			if _, _, ferr := config.Guard.Fail(ctx, domain.LockoutAPIKey, ip); ferr != nil {
+				logger.Error(ferr.Error())
+			}
+		case !errors.Is(err, domain.ErrForbidden):
+			//キーを照合できない（DBの障害）。失敗には数えない
+			logger.Error(err.Error())
+		}
+		return false, err
+	}
+}
+
+// キーの誤りによる失敗か（スコープの不足、照合できない場合は含まない）
+func isKeyMismatch(err error) bool {
+	return errors.Is(err, domain.ErrUnauthorized) ||
+		errors.Is(err, ErrAuthEmty) ||
+		errors.Is(err, ErrAuthDecFail) ||
+		errors.Is(err, ErrAuthInvalidKey)
+}
+
+// 個人用APIキーを照合し、ルートのスコープと持ち主を確認する。認証したキーはContextKeyAPIKeyに保存する。
+func authorizeAPIKey(c echo.Context, config Config, token string) (bool, error) {
+	if config.Keys == nil {
+		return false, utils.NewErrChains(ErrAuthInvalidKey, nil)
+	}
+	k, err := config.Keys.Authenticate(c.Request().Context(), token)
+	if err != nil {
+		return false, err
+	}
+
+	scope, ok := config.Scopes[c.Request().Method+" "+c.Path()]
+	if !ok || !k.HasScope(scope) {
+		return false, domain.ErrAPIKeyScope
+	}
+	//管理APIは他のユーザーを対象にする（役割はRequireRoleで確認する）
+	if id := c.Param("authUserId"); scope != domain.ScopeAdmin && id != "" && id != k.AuthUserId {
+		return false, domain.ErrAPIKeyOwner
+	}
+
+	c.Set(ContextKeyAPIKey, k)
+	return true, nil
+}
+
+// ユーザーの役割の確認先（controller.Admin）
+type RoleChecker interface {
+	HasRole(ctx context.Context, authUserId string, role domain.Role) (bool, error)
+}
+
+type RoleConfig struct {
+	// Skipperがtrueを返すリクエストは確認しない
+	Skipper middleware.Skipper
+
+	Roles RoleChecker
+
+	// 必要な役割
+	Role domain.Role
+}
+
+// 個人用APIキーの持ち主が役割（config.Role）を持つ場合のみ許可するmiddlewareを返す。
+// 役割はリクエストごとに確認する（キーの発行後に役割を外した、無効化したユーザーを拒否する）。アプリのキーは403。
+func RequireRole(config RoleConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			k, ok := APIKeyFrom(c)
+			if !ok || !k.HasScope(domain.ScopeAdmin) {
+				return problem.Wrap(domain.ErrAdminRequired, problem.CodeAdminRequired, nil)
+			}
+			ok, err := config.Roles.HasRole(c.Request().Context(), k.AuthUserId, config.Role)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return problem.Wrap(domain.ErrAdminRequired, problem.CodeAdminRequired, nil)
+			}
+			return next(c)
+		}
+	}
+}
+
+// ユーザーの無効化の確認先（controller.Admin）
+type UserChecker interface {
+	Disabled(ctx context.Context, authUserId string) (bool, error)
+}
+
+type DisabledConfig struct {
+	// Skipperがtrueを返すリクエストは確認しない
+	Skipper middleware.Skipper
+
+	Users UserChecker
+}
+
+// パスのauthUserIdのユーザーが無効化されている場合に403（account_disabled）を返すmiddlewareを返す。
+// アプリのキーはパスのユーザーの操作として扱うため、キーの種類によらずリクエストごとに確認する。
+func RejectDisabled(config DisabledConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Param("authUserId")
+			if id == "" || config.Skipper(c) {
+				return next(c)
+			}
+			disabled, err := config.Users.Disabled(c.Request().Context(), id)
+			if err != nil {
+				return err
+			}
+			if disabled {
+				return problem.Wrap(domain.ErrAccountDisabled, problem.CodeAccountDisabled, nil)
+			}
+			return next(c)
+		}
+	}
+}
+
+// pathPrefixで始まるルート以外をスキップするSkipperを返す
+func PrefixSkipper(pathPrefix string) middleware.Skipper {
+	return func(c echo.Context) bool {
+		return !strings.HasPrefix(c.Path(), pathPrefix+"/")
+	}
+}
+
+// 認証した個人用APIキーを返す。アプリのキーで認証したリクエストはfalse
+func APIKeyFrom(c echo.Context) (*domain.APIKey, bool) {
+	k, ok := c.Get(ContextKeyAPIKey).(*domain.APIKey)
+	return k, ok
+}
+
+// KeyAuthのErrorHandler。ロック中は429（Retry-After付き）、個人用APIキーのスコープの不足、他のユーザーのデータは403、
+// キーがない場合は400、それ以外は401を返す
+func ErrorHandler(err error, c echo.Context) error {
+	if errors.Is(err, domain.ErrTooManyRequests) {
+		return problem.Wrap(err, problem.CodeAuthLocked, nil)
+	}
+	if errors.Is(err, domain.ErrForbidden) {
+		return problem.Wrap(err, problem.CodeAPIKeyForbidden, nil)
+	}
+	var me *middleware.ErrKeyAuthMissing
+	if errors.As(err, &me) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return &echo.HTTPError{Code: http.StatusUnauthorized, Message: "Unauthorized", Internal: err}
+}
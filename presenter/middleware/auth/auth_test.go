@@ -1,12 +1,22 @@
 package auth_test
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/presenter/middleware/auth"
+	"github.com/taimats/bhapi/presenter/problem"
 	"github.com/taimats/bhapi/testutils"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -54,7 +64,7 @@ func TestAuthenticate(t *testing.T) {
 		test := test
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			got, err := auth.Authenticate(test.key)
+			got, err := auth.Authenticate(test.key, os.Getenv("TOKEN_SEED"))
 			if test.isErr {
 				a.Equal(false, got)
 				a.ErrorIs(err, test.errWant)
@@ -76,3 +86,257 @@ func testAPIKey(t *testing.T) string {
 	}
 	return base64.URLEncoding.EncodeToString([]byte(hashed))
 }
+
+func TestNewValidator(t *testing.T) {
+	//Arrange
+	g := &fakeGuard{threshold: 2, failures: map[string]int{}}
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+	e.Use(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		KeyLookup:    "header:" + echo.HeaderAuthorization,
+		AuthScheme:   "Bearer",
+		Validator:    auth.NewValidator(auth.Config{Guard: g, TokenSeed: "test"}),
+		ErrorHandler: auth.ErrorHandler,
+	}))
+	e.GET("/", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	serve := func(key string, ip string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if key != "" {
+			r.Header.Set(echo.HeaderAuthorization, "Bearer "+key)
+		}
+		r.Header.Set(echo.HeaderXRealIP, ip)
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, r)
+		return w
+	}
+	valid := testAPIKey(t)
+	a := assert.New(t)
+
+	//Act
+	ok := serve(valid, "192.0.2.1")
+	missing := serve("", "192.0.2.2")
+	invalid := []*httptest.ResponseRecorder{serve("invalid", "192.0.2.2"), serve("invalid", "192.0.2.2")}
+	locked := serve(valid, "192.0.2.2") //正しいキーでもロック中は照合しない
+	other := serve(valid, "192.0.2.3")
+
+	//Assert
+	a.Equal(http.StatusOK, ok.Code)
+	a.Equal(http.StatusBadRequest, missing.Code)
+	for _, w := range invalid {
+		a.Equal(http.StatusUnauthorized, w.Code)
+	}
+	a.Equal(2, g.failures["192.0.2.2"]) //キーがない場合は数えない
+	a.Equal(http.StatusTooManyRequests, locked.Code)
+	a.Equal("60", locked.Header().Get(echo.HeaderRetryAfter))
+	a.Contains(locked.Body.String(), string(problem.CodeAuthLocked))
+	a.Equal(http.StatusOK, other.Code)
+}
+
+func TestNewValidatorAPIKey(t *testing.T) {
+	//Arrange
+	owner := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	g := &fakeGuard{threshold: 10, failures: map[string]int{}}
+	ks := fakeKeys{
+		"bhk_read":   {AuthUserId: owner, Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}},
+		"bhk_charts": {AuthUserId: owner, Scopes: []domain.APIKeyScope{domain.ScopeChartsRead}},
+	}
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+	e.Use(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		KeyLookup:  "header:" + echo.HeaderAuthorization,
+		AuthScheme: "Bearer",
+		Validator: auth.NewValidator(auth.Config{
+			Guard:  g,
+			Keys:   ks,
+			Scopes: map[string]domain.APIKeyScope{http.MethodGet + " /shelf/:authUserId": domain.ScopeShelfRead},
+		}),
+		ErrorHandler: auth.ErrorHandler,
+	}))
+	handle := func(c echo.Context) error {
+		k, ok := auth.APIKeyFrom(c)
+		if !ok {
+			return c.NoContent(http.StatusNoContent)
+		}
+		return c.String(http.StatusOK, k.AuthUserId)
+	}
+	e.GET("/shelf/:authUserId", handle)
+	e.GET("/users/:authUserId", handle)
+	serve := func(key string, target string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set(echo.HeaderAuthorization, "Bearer "+key)
+		r.Header.Set(echo.HeaderXRealIP, "192.0.2.1")
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, r)
+		return w
+	}
+	a := assert.New(t)
+
+	//Act
+	ok := serve("bhk_read", "/shelf/"+owner)
+	other := serve("bhk_read", "/shelf/other")     //他のユーザーのデータ
+	unscoped := serve("bhk_read", "/users/"+owner) //スコープのないルート
+	noScope := serve("bhk_charts", "/shelf/"+owner)
+	unknown := serve("bhk_unknown", "/shelf/"+owner)
+	storeErr := serve("bhk_error", "/shelf/"+owner)
+
+	//Assert
+	a.Equal(http.StatusOK, ok.Code)
+	a.Equal(owner, ok.Body.String())
+	for _, w := range []*httptest.ResponseRecorder{other, unscoped, noScope} {
+		a.Equal(http.StatusForbidden, w.Code)
+		a.Contains(w.Body.String(), string(problem.CodeAPIKeyForbidden))
+	}
+	a.Equal(http.StatusUnauthorized, unknown.Code)
+	a.Equal(http.StatusUnauthorized, storeErr.Code)
+	a.Equal(1, g.failures["192.0.2.1"]) //不正なキーのみ数える（スコープの不足、照合できない場合は数えない）
+}
+
+func TestRequireRole(t *testing.T) {
+	//Arrange
+	admin := "9b0b1f4e-4e0c-4b8e-8f3a-1f7f0c2d3e4a"
+	user := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	ks := fakeKeys{
+		"bhk_admin":    {AuthUserId: admin, Scopes: []domain.APIKeyScope{domain.ScopeAdmin}},
+		"bhk_demoted":  {AuthUserId: user, Scopes: []domain.APIKeyScope{domain.ScopeAdmin}}, //発行後に管理者でなくなった
+		"bhk_shelf":    {AuthUserId: admin, Scopes: []domain.APIKeyScope{domain.ScopeShelfRead}},
+		"bhk_rolefail": {AuthUserId: "error", Scopes: []domain.APIKeyScope{domain.ScopeAdmin}},
+	}
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+	e.Use(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		KeyLookup:  "header:" + echo.HeaderAuthorization,
+		AuthScheme: "Bearer",
+		Validator: auth.NewValidator(auth.Config{
+			Guard: &fakeGuard{threshold: 10, failures: map[string]int{}},
+			Keys:  ks,
+			Scopes: map[string]domain.APIKeyScope{
+				http.MethodGet + " /admin/users/:authUserId": domain.ScopeAdmin,
+				http.MethodGet + " /shelf/:authUserId":       domain.ScopeShelfRead,
+			},
+		}),
+		ErrorHandler: auth.ErrorHandler,
+	}))
+	e.Use(auth.RequireRole(auth.RoleConfig{Skipper: auth.PrefixSkipper("/admin"), Roles: fakeRoles{admin: domain.RoleAdmin}, Role: domain.RoleAdmin}))
+	handle := func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}
+	e.GET("/admin/users/:authUserId", handle)
+	e.GET("/shelf/:authUserId", handle)
+	serve := func(key string, target string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set(echo.HeaderAuthorization, "Bearer "+key)
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, r)
+		return w
+	}
+	a := assert.New(t)
+
+	//Act
+	ok := serve("bhk_admin", "/admin/users/"+user) //adminスコープは他のユーザーのデータも操作できる
+	demoted := serve("bhk_demoted", "/admin/users/"+user)
+	shelf := serve("bhk_shelf", "/admin/users/"+user)
+	skipped := serve("bhk_shelf", "/shelf/"+admin)
+	roleFail := serve("bhk_rolefail", "/admin/users/"+user)
+
+	//Assert
+	a.Equal(http.StatusNoContent, ok.Code)
+	a.Equal(http.StatusForbidden, demoted.Code)
+	a.Contains(demoted.Body.String(), string(problem.CodeAdminRequired))
+	a.Equal(http.StatusForbidden, shelf.Code) //スコープの不足はValidatorで拒否する
+	a.Equal(http.StatusNoContent, skipped.Code)
+	a.Equal(http.StatusInternalServerError, roleFail.Code)
+}
+
+func TestRejectDisabled(t *testing.T) {
+	//Arrange
+	disabled := "c0cc3f0c-9a02-45ba-9de7-7d7276bb6058"
+	active := "9b0b1f4e-4e0c-4b8e-8f3a-1f7f0c2d3e4a"
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+	e.Use(auth.RejectDisabled(auth.DisabledConfig{
+		Skipper: func(c echo.Context) bool {
+			return strings.HasPrefix(c.Path(), "/admin/")
+		},
+		Users: fakeUsers{disabled: true},
+	}))
+	handle := func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}
+	e.GET("/shelf/:authUserId", handle)
+	e.GET("/admin/users/:authUserId", handle)
+	e.GET("/health", handle)
+	serve := func(target string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, r)
+		return w
+	}
+	a := assert.New(t)
+
+	//Act
+	rejected := serve("/shelf/" + disabled)
+	ok := serve("/shelf/" + active)
+	admin := serve("/admin/users/" + disabled) //管理APIは無効化したユーザーも対象にする
+	health := serve("/health")
+	failed := serve("/shelf/error")
+
+	//Assert
+	a.Equal(http.StatusForbidden, rejected.Code)
+	a.Contains(rejected.Body.String(), string(problem.CodeAccountDisabled))
+	a.Equal(http.StatusNoContent, ok.Code)
+	a.Equal(http.StatusNoContent, admin.Code)
+	a.Equal(http.StatusNoContent, health.Code)
+	a.Equal(http.StatusInternalServerError, failed.Code)
+}
+
+// ユーザーごとの無効化の有無。"error"は取得に失敗する
+type fakeUsers map[string]bool
+
+func (us fakeUsers) Disabled(ctx context.Context, authUserId string) (bool, error) {
+	if authUserId == "error" {
+		return false, errors.New("DBに接続できません")
+	}
+	return us[authUserId], nil
+}
+
+// ユーザーごとの役割。"error"は取得に失敗する
+type fakeRoles map[string]domain.Role
+
+func (rs fakeRoles) HasRole(ctx context.Context, authUserId string, role domain.Role) (bool, error) {
+	if authUserId == "error" {
+		return false, errors.New("DBに接続できません")
+	}
+	return rs[authUserId] == role, nil
+}
+
+// キーごとの個人用APIキー。"bhk_error"は照合に失敗する
+type fakeKeys map[string]*domain.APIKey
+
+func (ks fakeKeys) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	if token == "bhk_error" {
+		return nil, errors.New("DBに接続できません")
+	}
+	k, ok := ks[token]
+	if !ok {
+		return nil, domain.ErrAPIKeyRejected
+	}
+	return k, nil
+}
+
+// IPアドレスごとにthreshold回失敗すると1分ロックするGuard
+type fakeGuard struct {
+	threshold int
+	failures  map[string]int
+}
+
+func (g *fakeGuard) Check(ctx context.Context, scope domain.LockoutScope, key string) error {
+	if g.failures[key] >= g.threshold {
+		return &domain.LockedError{RetryAfter: time.Minute}
+	}
+	return nil
+}
+
+func (g *fakeGuard) Fail(ctx context.Context, scope domain.LockoutScope, key string) (*domain.AuthFailure, bool, error) {
+	g.failures[key]++
+	return &domain.AuthFailure{Scope: scope, Key: key, Failures: g.failures[key]}, g.failures[key] >= g.threshold, nil
+}
@@ -0,0 +1,188 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/middleware/auth"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+const (
+	// 再送を判別するためのリクエストヘッダー
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// 保存したレスポンスを返した場合に付けるレスポンスヘッダー
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// キーの最大の長さ
+	maxKeyLength = 255
+)
+
+// キーとレスポンスの保存先（controller.Idempotency）
+type Store interface {
+	Begin(ctx context.Context, key string, fingerprint string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, status int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+type Config struct {
+	Skipper middleware.Skipper
+
+	Store Store
+
+	// 保存に失敗した場合の出力先。nilの場合はslog.Default()
+	Logger *slog.Logger
+}
+
+// Idempotency-Keyヘッダーのあるリクエストについて、初回のレスポンスを保存し、再送には保存したレスポンスを返すmiddlewareを返す。
+//   - キーは認証した主体（個人用APIキー、アプリのキーはパスのユーザー）とルートごとに保存する
+//   - 同じキーで異なるリクエスト（メソッド、パス、ボディ）の場合は409
+//   - 同じキーのリクエストを処理中の場合は409（Retry-After付き）
+//   - 500以上のレスポンスは保存せず、同じキーで再試行できる
+//
+// ヘッダーのないリクエストはそのまま通す。
+func WithConfig(cfg Config) echo.MiddlewareFunc {
+	if cfg.Skipper == nil {
+		cfg.Skipper = middleware.DefaultSkipper
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.Store == nil {
+		panic("idempotency: Storeの指定が必要です")
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper(c) {
+				return next(c)
+			}
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > maxKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidIdempotencyKey)
+			}
+
+			//指紋の作成のためにボディを読み込み、ハンドラ用に戻す
+			key = domain.IdempotencyStoreKey(principal(c), c.Request().Method+" "+c.Path(), key)
+
+			req := c.Request()
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, problem.CodeInvalidBody).SetInternal(err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := domain.IdempotencyFingerprint(req.Method, req.URL.Path, body)
+
+			ctx := req.Context()
+			rec, err := cfg.Store.Begin(ctx, key, fingerprint)
+			if err != nil {
+				if errors.Is(err, controller.ErrIdempotencyInProgress) {
+					c.Response().Header().Set(echo.HeaderRetryAfter, "1")
+				}
+				return problem.Wrap(err, problem.CodeInternal, problem.Codes{
+					controller.ErrIdempotencyMismatch:   problem.CodeIdempotencyKeyMismatch,
+					controller.ErrIdempotencyInProgress: problem.CodeIdempotencyKeyInProgress,
+				})
+			}
+			if rec != nil {
+				return replay(c, rec)
+			}
+
+			//ハンドラの結果に関わらずキーを解放または完了させるため、キャンセルされないctxを使う
+			ctx = context.WithoutCancel(ctx)
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				if err := cfg.Store.Release(ctx, key); err != nil {
+					cfg.Logger.Error(err.Error())
+				}
+			}()
+
+			res := c.Response()
+			w := &responseRecorder{ResponseWriter: res.Writer}
+			res.Writer = w
+
+			//エラーレスポンスも保存するため、ここでエラーハンドラを呼び出す
+			err = next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			res.Writer = w.ResponseWriter
+
+			if res.Status >= http.StatusInternalServerError {
+				return err
+			}
+			if cerr := cfg.Store.Complete(ctx, key, res.Status, res.Header().Get(echo.HeaderContentType), w.body.Bytes()); cerr != nil {
+				cfg.Logger.Error(cerr.Error())
+				return err
+			}
+			completed = true
+			return err
+		}
+	}
+}
+
+// キーを保存する主体。個人用APIキーはキーごと、アプリのキーはパスのユーザーごと
+func principal(c echo.Context) string {
+	if k, ok := auth.APIKeyFrom(c); ok {
+		return "api_key:" + strconv.FormatInt(k.ID, 10)
+	}
+	return "app:" + c.Param("authUserId")
+}
+
+// routes（"メソッド echoのパス"）以外のリクエストをスキップするSkipperを返す
+func RouteSkipper(routes ...string) middleware.Skipper {
+	m := make(map[string]struct{}, len(routes))
+	for _, r := range routes {
+		m[r] = struct{}{}
+	}
+	return func(c echo.Context) bool {
+		_, ok := m[c.Request().Method+" "+c.Path()]
+		return !ok
+	}
+}
+
+// 保存したレスポンスを返す
+func replay(c echo.Context, rec *domain.IdempotencyRecord) error {
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	if len(rec.Body) == 0 {
+		return c.NoContent(rec.Status)
+	}
+	return c.Blob(rec.Status, rec.ContentType, rec.Body)
+}
+
+// レスポンスを書き出しつつ、ボディを記録するResponseWriter
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
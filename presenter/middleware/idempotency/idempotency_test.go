@@ -0,0 +1,241 @@
+package idempotency_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/middleware/idempotency"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// テスト用のメモリ上の保存先（controller.Idempotencyと同じ振る舞い）
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (s *memoryStore) Begin(ctx context.Context, key string, fingerprint string) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[key]
+	if !ok {
+		s.records[key] = &domain.IdempotencyRecord{Key: key, Fingerprint: fingerprint}
+		return nil, nil
+	}
+	if rec.Fingerprint != fingerprint {
+		return nil, controller.ErrIdempotencyMismatch
+	}
+	if rec.InProgress() {
+		return nil, controller.ErrIdempotencyInProgress
+	}
+	return rec, nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, key string, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := s.records[key]
+	rec.Status = status
+	rec.ContentType = contentType
+	rec.Body = body
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// 呼び出し回数を数えるハンドラとechoインスタンスを返す
+func setup(store idempotency.Store, h echo.HandlerFunc) (*echo.Echo, *atomic.Int32) {
+	calls := new(atomic.Int32)
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+	e.Use(idempotency.WithConfig(idempotency.Config{
+		Skipper: idempotency.RouteSkipper(http.MethodPost + " /v1/shelf/:authUserId"),
+		Store:   store,
+	}))
+	e.POST("/v1/shelf/:authUserId", func(c echo.Context) error {
+		calls.Add(1)
+		return h(c)
+	})
+	return e, calls
+}
+
+func post(e *echo.Echo, key string, body string) *httptest.ResponseRecorder {
+	return postTo(e, "/v1/shelf/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", key, body)
+}
+
+func postTo(e *echo.Echo, target string, key string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		r.Header.Set(idempotency.HeaderIdempotencyKey, key)
+	}
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+	return w
+}
+
+func created(c echo.Context) error {
+	return c.JSON(http.StatusCreated, echo.Map{"id": 1})
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	//Arrange
+	e, calls := setup(newMemoryStore(), created)
+	a := assert.New(t)
+
+	//Act
+	first := post(e, "key-1", `{"title":"容疑者Xの献身"}`)
+	second := post(e, "key-1", `{"title":"容疑者Xの献身"}`)
+
+	//Assert
+	a.Equal(int32(1), calls.Load())
+	a.Equal(http.StatusCreated, first.Code)
+	a.Equal(http.StatusCreated, second.Code)
+	a.JSONEq(first.Body.String(), second.Body.String())
+	a.Equal(echo.MIMEApplicationJSON, second.Header().Get(echo.HeaderContentType))
+	a.Empty(first.Header().Get(idempotency.HeaderIdempotentReplayed))
+	a.Equal("true", second.Header().Get(idempotency.HeaderIdempotentReplayed))
+}
+
+func TestIdempotencyConflict(t *testing.T) {
+	//Arrange
+	store := newMemoryStore()
+	e, calls := setup(store, created)
+	//処理中のキー
+	inProgress := domain.IdempotencyStoreKey("app:c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", http.MethodPost+" /v1/shelf/:authUserId", "key-2")
+	store.records[inProgress] = &domain.IdempotencyRecord{
+		Key:         inProgress,
+		Fingerprint: domain.IdempotencyFingerprint(http.MethodPost, "/v1/shelf/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", []byte(`{"title":"予知夢"}`)),
+	}
+	post(e, "key-1", `{"title":"容疑者Xの献身"}`)
+
+	tests := map[string]struct {
+		key            string
+		body           string
+		codeWant       problem.Code
+		retryAfterWant string
+	}{
+		"異なるボディ": {key: "key-1", body: `{"title":"予知夢"}`, codeWant: problem.CodeIdempotencyKeyMismatch},
+		"処理中":    {key: "key-2", body: `{"title":"予知夢"}`, codeWant: problem.CodeIdempotencyKeyInProgress, retryAfterWant: "1"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act
+			w := post(e, tt.key, tt.body)
+
+			//Assert
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Contains(t, w.Body.String(), string(tt.codeWant))
+			assert.Equal(t, tt.retryAfterWant, w.Header().Get(echo.HeaderRetryAfter))
+		})
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestIdempotencyServerErrorNotStored(t *testing.T) {
+	//Arrange
+	failed := true
+	e, calls := setup(newMemoryStore(), func(c echo.Context) error {
+		if failed {
+			failed = false
+			return echo.NewHTTPError(http.StatusInternalServerError, problem.CodeBookCreateFailed)
+		}
+		return created(c)
+	})
+	a := assert.New(t)
+
+	//Act
+	first := post(e, "key-1", `{"title":"容疑者Xの献身"}`)
+	second := post(e, "key-1", `{"title":"容疑者Xの献身"}`)
+
+	//Assert
+	a.Equal(int32(2), calls.Load())
+	a.Equal(http.StatusInternalServerError, first.Code)
+	a.Equal(http.StatusCreated, second.Code)
+	a.Empty(second.Header().Get(idempotency.HeaderIdempotentReplayed))
+}
+
+func TestIdempotencyConcurrent(t *testing.T) {
+	//Arrange
+	e, calls := setup(newMemoryStore(), func(c echo.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return created(c)
+	})
+	const n = 10
+
+	//Act
+	codes := make([]int, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i] = post(e, "key-1", `{"title":"容疑者Xの献身"}`).Code
+		}()
+	}
+	wg.Wait()
+
+	//Assert
+	assert.Equal(t, int32(1), calls.Load())
+	for _, code := range codes {
+		assert.Contains(t, []int{http.StatusCreated, http.StatusConflict}, code)
+	}
+}
+
+func TestIdempotencyScope(t *testing.T) {
+	//Arrange
+	store := newMemoryStore()
+	e, calls := setup(store, created)
+	a := assert.New(t)
+
+	//Act
+	first := post(e, "key-1", `{"title":"容疑者Xの献身"}`)
+	//他のユーザーが同じキーを指定しても、保存したレスポンスを返さない
+	other := postTo(e, "/v1/shelf/2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e", "key-1", `{"title":"容疑者Xの献身"}`)
+
+	//Assert
+	a.Equal(int32(2), calls.Load())
+	a.Equal(http.StatusCreated, first.Code)
+	a.Equal(http.StatusCreated, other.Code)
+	a.Empty(other.Header().Get(idempotency.HeaderIdempotentReplayed))
+	a.Len(store.records, 2)
+	a.Contains(store.records, domain.IdempotencyStoreKey("app:2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e", http.MethodPost+" /v1/shelf/:authUserId", "key-1"))
+}
+
+func TestIdempotencySkip(t *testing.T) {
+	//Arrange
+	e, calls := setup(newMemoryStore(), created)
+	a := assert.New(t)
+
+	//Act
+	post(e, "", `{"title":"容疑者Xの献身"}`)
+	post(e, "", `{"title":"容疑者Xの献身"}`)
+	tooLong := post(e, strings.Repeat("k", 256), `{"title":"容疑者Xの献身"}`)
+
+	//Assert
+	a.Equal(int32(2), calls.Load())
+	a.Equal(http.StatusBadRequest, tooLong.Code)
+}
@@ -11,12 +11,13 @@ import (
 
 func NewRequestLoggerConfig(logger *slog.Logger) middleware.RequestLoggerConfig {
 	return middleware.RequestLoggerConfig{
-		LogRemoteIP: true,
-		LogMethod:   true,
-		LogURI:      true,
-		LogStatus:   true,
-		LogError:    true,
-		LogLatency:  true,
+		LogRemoteIP:  true,
+		LogMethod:    true,
+		LogURI:       true,
+		LogStatus:    true,
+		LogError:     true,
+		LogLatency:   true,
+		LogRequestID: true,
 
 		HandleError: true,
 
@@ -24,6 +25,7 @@ func NewRequestLoggerConfig(logger *slog.Logger) middleware.RequestLoggerConfig
 			if v.Error != nil {
 				logger.LogAttrs(context.Background(), slog.LevelError, "REQUEST_ERROR",
 					slog.String("remote_ip", v.RemoteIP),
+					slog.String("request_id", v.RequestID),
 					slog.String("method", v.Method),
 					slog.String("uri", v.URI),
 					slog.Int("status", v.Status),
@@ -34,6 +36,7 @@ func NewRequestLoggerConfig(logger *slog.Logger) middleware.RequestLoggerConfig
 			}
 			logger.LogAttrs(context.Background(), slog.LevelInfo, "REQUEST",
 				slog.String("remote_ip", v.RemoteIP),
+				slog.String("request_id", v.RequestID),
 				slog.String("method", v.Method),
 				slog.String("uri", v.URI),
 				slog.Int("status", v.Status),
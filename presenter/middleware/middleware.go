@@ -5,30 +5,71 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/apigenv2"
+	"github.com/taimats/bhapi/config"
+	"github.com/taimats/bhapi/domain"
 	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/middleware/audit"
 	"github.com/taimats/bhapi/presenter/middleware/auth"
+	"github.com/taimats/bhapi/presenter/middleware/idempotency"
 	"github.com/taimats/bhapi/presenter/middleware/loggers"
-	"golang.org/x/time/rate"
+	"github.com/taimats/bhapi/presenter/middleware/oapi"
+	"github.com/taimats/bhapi/presenter/middleware/ratelimit"
+	"github.com/taimats/bhapi/presenter/problem"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var (
-	allowedOrigins = []string{os.Getenv("FRONT_API_BASE_URL")}
-	allowedMethods = []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete}
-	allowedHeaders = []string{echo.HeaderContentType, echo.HeaderAuthorization}
+	allowedMethods = []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete}
+	allowedHeaders = []string{
+		echo.HeaderContentType,
+		echo.HeaderAuthorization,
+		idempotency.HeaderIdempotencyKey,
+		handler.HeaderIfMatch,
+		handler.HeaderIfNoneMatch,
+		handler.HeaderLastEventID,
+	}
+	exposedHeaders = []string{
+		idempotency.HeaderIdempotentReplayed,
+		echo.HeaderRetryAfter,
+		handler.HeaderETag,
+		ratelimit.HeaderRateLimitLimit,
+		ratelimit.HeaderRateLimitRemaining,
+		ratelimit.HeaderRateLimitReset,
+		ratelimit.HeaderRateLimitPolicy,
+		echo.HeaderXRequestID,
+	}
 
-	authSkippedPaths = map[string]struct{}{"/v1/health": {}, "/v1/health/db": {}}
+	authSkippedPaths = map[string]struct{}{
+		"/v1/health":    {},
+		"/v1/health/db": {},
+		"/v2/health":    {},
+		"/v2/health/db": {},
+		//メールのリンクから開くため、トークンで確認する
+		"/v1/mail/unsubscribe": {},
+	}
 )
 
 // echoインスタンスに対して必要なすべてのmiddlewareを設定する。
+// cfgは起動時に読み込んだ設定（CORSで許可するフロントエンドのURL、アプリのキーの照合に使う種）。isはIdempotency-Keyのキーとレスポンスの保存先。agはAPIキーの認証の失敗を数える先、ksは個人用APIキーの認証先、
+// rcは管理APIを呼び出すユーザーの役割の確認先、ucはパスのユーザーの無効化の確認先。
+// rsはレート制限のリクエスト数の保存先、policiesはレート制限の方針（RateLimitPolicies）。
 // *lumberjack.Loggerは io.WriteCloserなので、呼び出しもとでCloseする。
-func SetAll(e *echo.Echo) (*echo.Echo, *lumberjack.Logger) {
+func SetAll(e *echo.Echo, cfg *config.Config, is idempotency.Store, ag auth.Guard, ks auth.KeyStore, rc auth.RoleChecker, uc auth.UserChecker, rs ratelimit.Store, policies []ratelimit.Policy) (*echo.Echo, *lumberjack.Logger) {
 	e.Use(middleware.Recover())
 
+	//監査ログ、ログでリクエストを特定するため、X-Request-Idがない場合は生成する
+	e.Use(middleware.RequestID())
+
+	//X-Forwarded-Forは内部（ロードバランサー）からのもののみ信頼する（ロックのIPアドレスを偽装させない）
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		log.Println(err)
@@ -39,9 +80,10 @@ func SetAll(e *echo.Echo) (*echo.Echo, *lumberjack.Logger) {
 	))
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: allowedOrigins,
-		AllowMethods: allowedMethods,
-		AllowHeaders: allowedHeaders,
+		AllowOrigins:  []string{cfg.FrontURL},
+		AllowMethods:  allowedMethods,
+		AllowHeaders:  allowedHeaders,
+		ExposeHeaders: exposedHeaders,
 	}))
 
 	e.Use(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
@@ -52,15 +94,75 @@ func SetAll(e *echo.Echo) (*echo.Echo, *lumberjack.Logger) {
 
 		KeyLookup:  "header:" + echo.HeaderAuthorization,
 		AuthScheme: "Bearer",
-		Validator: func(key string, c echo.Context) (bool, error) {
-			ok, err := auth.Authenticate(key)
-			return ok, err
+		//失敗が続いたIPアドレスは一時的にロックする（複数のAPIサーバーで共有するため、DBで数える）。
+		//個人用APIキーはhandler.RouteScopesのルートのみ、スコープがある場合に許可する
+		Validator: auth.NewValidator(auth.Config{
+			TokenSeed: cfg.TokenSeed.Value(),
+			Guard:     ag,
+			Keys:      ks,
+			Scopes:    handler.RouteScopes,
+			Logger:    l,
+		}),
+		ErrorHandler: auth.ErrorHandler,
+	}))
+
+	//管理APIは管理者のみ（役割はリクエストごとにDBで確認する）
+	e.Use(auth.RequireRole(auth.RoleConfig{
+		Skipper: auth.PrefixSkipper(handler.AdminBaseURL),
+		Roles:   rc,
+		Role:    domain.RoleAdmin,
+	}))
+
+	//無効化したユーザーのデータはアプリのキーでも操作させない（管理APIは無効化したユーザーも対象にする）
+	e.Use(auth.RejectDisabled(auth.DisabledConfig{
+		Skipper: func(c echo.Context) bool {
+			return strings.HasPrefix(c.Path(), handler.AdminBaseURL+"/")
 		},
+		Users: uc,
 	}))
 
-	e.Use(middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(rate.Limit(3))))
+	//監査ログに操作したユーザー、リクエストID、IPアドレスを記録する
+	e.Use(audit.WithConfig(audit.Config{}))
+
+	//複数のAPIサーバーで同じ上限を共有するため、DBで数える
+	e.Use(ratelimit.WithConfig(ratelimit.Config{
+		Store:    rs,
+		Policies: policies,
+		Logger:   l,
+	}))
+
+	//再送による重複作成を防ぐ（保存したレスポンスを返すため、API仕様の検証より前に置く）
+	e.Use(idempotency.WithConfig(idempotency.Config{
+		Skipper: idempotency.RouteSkipper(handler.IdempotentRoutes...),
+		Store:   is,
+		Logger:  l,
+	}))
+
+	//本番ではAPI仕様との不一致をログに出力するのみ
+	e.Use(oapi.ValidatorWithConfig(oapi.Config{
+		Specs: []oapi.Spec{
+			{BaseURL: handler.BaseURL, Load: apigen.GetSwagger},
+			{BaseURL: handler.BaseURLV2, Load: apigenv2.GetSwagger},
+		},
+		Logger: l,
+	}))
 
 	e.Validator = handler.NewCustomValidator(validator.New())
+	//エラーはすべてapplication/problem+jsonで返す
+	e.HTTPErrorHandler = problem.NewErrorHandler(l)
 
 	return e, w
 }
+
+// レート制限の方針を返す。
+//   - defaultLimit: 書籍の検索以外のリクエスト（ユーザー、分からない場合はIPアドレスごと）
+//   - searchLimit: 書籍の検索（外部のAPIを呼び出すため、他より低くする）
+//   - apiKeyLimit: 個人用APIキーごとのすべてのリクエスト（アプリのキーは数えない）
+func RateLimitPolicies(defaultLimit domain.RateLimit, searchLimit domain.RateLimit, apiKeyLimit domain.RateLimit) []ratelimit.Policy {
+	byUser := ratelimit.FirstOf(ratelimit.ByUser, ratelimit.ByIP)
+	return []ratelimit.Policy{
+		{Name: "default", Skipper: ratelimit.ExceptRoutes(handler.SearchRoutes...), Key: byUser, RateLimit: defaultLimit},
+		{Name: "search", Skipper: ratelimit.OnlyRoutes(handler.SearchRoutes...), Key: byUser, RateLimit: searchLimit},
+		{Name: "api_key", Key: ratelimit.ByAPIKey, RateLimit: apiKeyLimit},
+	}
+}
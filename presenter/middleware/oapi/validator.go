@@ -0,0 +1,272 @@
+package oapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+const (
+	msgInvalidRequest  = "リクエストがAPI仕様に一致しません"
+	msgInvalidResponse = "レスポンスがAPI仕様に一致しません"
+)
+
+func init() {
+	//部分更新（PATCH）のJSON Merge Patch（RFC 7396）はjsonとして検証する
+	openapi3filter.RegisterBodyDecoder("application/merge-patch+json", openapi3filter.JSONBodyDecoder)
+}
+
+type Config struct {
+	Skipper middleware.Skipper
+
+	// trueの場合は仕様に一致しないリクエストを400、レスポンスを500で返す（テスト用）。
+	// falseの場合はログを出力するのみで、リクエスト、レスポンスはそのまま通す（本番用）。
+	Strict bool
+
+	// 検証に使う仕様。nilの場合はopenapi.yaml（接頭辞なし）のみ
+	Specs []Spec
+
+	// 仕様に一致しない場合の出力先。nilの場合はslog.Default()
+	Logger *slog.Logger
+}
+
+// APIのバージョンごとの仕様
+type Spec struct {
+	// ルーティングの接頭辞（例."/v1"）。仕様のpathsには含まれない。
+	BaseURL string
+
+	// 埋め込みの仕様を読み込む関数（例.apigen.GetSwagger）
+	Load func() (*openapi3.T, error)
+}
+
+// 仕様（apigen、apigenv2に埋め込み済み）に対して、リクエストとレスポンスを検証するmiddlewareを返す。
+// ルートの接頭辞から、どの仕様で検証するかを決める。
+// レスポンスはContent-Typeがjsonのもののみ検証する（zipなどはそのまま通す）。
+// 埋め込みの仕様の読み込みに失敗した場合はpanicする。
+func ValidatorWithConfig(cfg Config) echo.MiddlewareFunc {
+	if cfg.Skipper == nil {
+		cfg.Skipper = middleware.DefaultSkipper
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	if cfg.Specs == nil {
+		cfg.Specs = []Spec{{Load: apigen.GetSwagger}}
+	}
+
+	routes := make(map[string]*routers.Route)
+	for _, s := range cfg.Specs {
+		spec, err := s.Load()
+		if err != nil {
+			panic(fmt.Sprintf("API仕様の読み込みに失敗:%s", err))
+		}
+		addRoutes(routes, spec, s.BaseURL)
+	}
+
+	options := &openapi3filter.Options{
+		//認証はKeyAuthで実施済みのため、ここでは検証しない
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: cfg.Strict,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper(c) {
+				return next(c)
+			}
+			//仕様にないルート（404、405など）は検証しない
+			route, ok := routes[c.Request().Method+" "+specPath(c.Path())]
+			if !ok {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			reqInput := &openapi3filter.RequestValidationInput{
+				Request:    c.Request(),
+				PathParams: pathParams(c),
+				Route:      route,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(ctx, reqInput); err != nil {
+				if cfg.Strict {
+					return echo.NewHTTPError(http.StatusBadRequest, problem.CodeSpecMismatch).SetInternal(err)
+				}
+				logMismatch(ctx, cfg.Logger, msgInvalidRequest, c, err)
+			}
+
+			res := c.Response()
+			orig := res.Writer
+			rec := &responseRecorder{ResponseWriter: orig, hold: cfg.Strict}
+			res.Writer = rec
+
+			//エラーレスポンスも検証するため、ここでエラーハンドラを呼び出す
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			res.Writer = orig
+
+			if !rec.captured {
+				return err
+			}
+
+			resInput := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: reqInput,
+				Status:                 rec.status,
+				Header:                 res.Header(),
+				Options:                options,
+			}
+			resInput.SetBodyBytes(rec.body.Bytes())
+			verr := openapi3filter.ValidateResponse(ctx, resInput)
+			if verr != nil {
+				logMismatch(ctx, cfg.Logger, msgInvalidResponse, c, verr)
+			}
+			if !cfg.Strict {
+				return err
+			}
+
+			//strictの場合は保留していたレスポンスを書き出す（仕様に一致しなければ500に差し替える）
+			if verr != nil {
+				p := problem.New(http.StatusInternalServerError, problem.CodeResponseMismatch, problem.Language(c.Request()))
+				p.Instance = c.Request().URL.Path
+				body, jerr := json.Marshal(p)
+				if jerr != nil {
+					return jerr
+				}
+				res.Status = http.StatusInternalServerError
+				orig.Header().Del(echo.HeaderContentLength)
+				orig.Header().Set(echo.HeaderContentType, problem.MIMEApplicationProblemJSON)
+				orig.WriteHeader(http.StatusInternalServerError)
+				if _, werr := orig.Write(body); werr != nil {
+					return werr
+				}
+				if err == nil {
+					err = verr
+				}
+				return err
+			}
+			orig.WriteHeader(rec.status)
+			if _, werr := orig.Write(rec.body.Bytes()); werr != nil {
+				return werr
+			}
+			return err
+		}
+	}
+}
+
+// "メソッド 接頭辞+パス"をキーに、仕様のルートをマップに追加
+func addRoutes(routes map[string]*routers.Route, spec *openapi3.T, baseURL string) {
+	for path, item := range spec.Paths.Map() {
+		for method, op := range item.Operations() {
+			routes[method+" "+baseURL+path] = &routers.Route{
+				Spec:      spec,
+				Path:      path,
+				PathItem:  item,
+				Method:    method,
+				Operation: op,
+			}
+		}
+	}
+}
+
+// echoのルート（例."/v1/shelf/:authUserId"）を仕様の形式のパス（例."/v1/shelf/{authUserId}"）に変換
+func specPath(echoPath string) string {
+	segments := strings.Split(echoPath, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParams(c echo.Context) map[string]string {
+	names := c.ParamNames()
+	values := c.ParamValues()
+	params := make(map[string]string, len(names))
+	for i, name := range names {
+		if i < len(values) {
+			params[name] = values[i]
+		}
+	}
+	return params
+}
+
+func logMismatch(ctx context.Context, l *slog.Logger, msg string, c echo.Context, err error) {
+	l.LogAttrs(ctx, slog.LevelWarn, msg,
+		slog.String("method", c.Request().Method),
+		slog.String("path", c.Path()),
+		slog.String("err", err.Error()),
+	)
+}
+
+// jsonのレスポンスを記録するResponseWriter。
+// holdがtrueの場合は、検証が済むまでjsonのレスポンスを書き出さずに保留する。
+type responseRecorder struct {
+	http.ResponseWriter
+	hold     bool
+	status   int
+	captured bool
+	body     bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status != 0 {
+		return
+	}
+	r.status = code
+	r.captured = isJSON(r.Header().Get(echo.HeaderContentType))
+	if r.hold && r.captured {
+		return
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if !r.captured {
+		return r.ResponseWriter.Write(b)
+	}
+	r.body.Write(b)
+	if r.hold {
+		return len(b), nil
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if r.hold && r.captured {
+		return
+	}
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func isJSON(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mt == echo.MIMEApplicationJSON || strings.HasSuffix(mt, "+json")
+}
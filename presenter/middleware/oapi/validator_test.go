@@ -0,0 +1,149 @@
+package oapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/apigenv2"
+	"github.com/taimats/bhapi/presenter/middleware/oapi"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+func TestValidatorWithConfig(t *testing.T) {
+	//Arrange
+	tests := map[string]struct {
+		strict      bool
+		method      string
+		target      string
+		contentType string
+		body        string
+		response    any
+		statusWant  int
+		bodyWant    string
+	}{
+		"OK:仕様に一致": {
+			strict:     true,
+			method:     http.MethodGet,
+			target:     "/v1/records/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			response:   map[string]string{"costs": "3,870", "currency": "JPY"},
+			statusWant: http.StatusOK,
+			bodyWant:   `{"costs":"3,870","currency":"JPY"}`,
+		},
+		"NG:必須のクエリがない（strict）": {
+			strict:     true,
+			method:     http.MethodGet,
+			target:     "/v1/search",
+			response:   []any{},
+			statusWant: http.StatusBadRequest,
+			bodyWant:   `{"type":"urn:bhapi:problem:spec_mismatch","title":"不正なリクエスト","status":400,"detail":"リクエストがAPI仕様に一致しません","instance":"/v1/search","code":"spec_mismatch","errors":[{"field":"q","code":"required","message":"必須です"}],"message":"リクエストがAPI仕様に一致しません"}`,
+		},
+		"NG:リクエストボディの型が不一致（strict）": {
+			strict:     true,
+			method:     http.MethodPut,
+			target:     "/v1/admin/rates",
+			body:       `{"currency":"USD","rate":"150"}`,
+			statusWant: http.StatusBadRequest,
+		},
+		"NG:レスポンスの型が不一致（strict）": {
+			strict:     true,
+			method:     http.MethodGet,
+			target:     "/v1/records/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			response:   []map[string]string{{"costs": "3,870"}},
+			statusWant: http.StatusInternalServerError,
+			bodyWant:   `{"type":"urn:bhapi:problem:response_mismatch","title":"サーバーエラー","status":500,"detail":"レスポンスがAPI仕様に一致しません","instance":"/v1/records/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058","code":"response_mismatch","message":"レスポンスがAPI仕様に一致しません"}`,
+		},
+		"OK:レスポンスの型が不一致でもログのみ": {
+			strict:     false,
+			method:     http.MethodGet,
+			target:     "/v1/records/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			response:   []map[string]string{{"costs": "3,870"}},
+			statusWant: http.StatusOK,
+			bodyWant:   `[{"costs":"3,870"}]`,
+		},
+		"OK:v2の仕様に一致": {
+			strict:     true,
+			method:     http.MethodGet,
+			target:     "/v2/records/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			response:   map[string]any{"costs": 3870, "costsRead": 0, "volumes": 2, "volumesRead": 0, "pages": 500, "pagesRead": 0, "currency": "JPY"},
+			statusWant: http.StatusOK,
+		},
+		"NG:v2で数値を文字列で返す（strict）": {
+			strict:     true,
+			method:     http.MethodGet,
+			target:     "/v2/records/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+			response:   map[string]string{"costs": "3,870", "costsRead": "0", "volumes": "2", "volumesRead": "0", "pages": "500", "pagesRead": "0", "currency": "JPY"},
+			statusWant: http.StatusInternalServerError,
+			bodyWant:   `{"type":"urn:bhapi:problem:response_mismatch","title":"サーバーエラー","status":500,"detail":"レスポンスがAPI仕様に一致しません","instance":"/v2/records/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058","code":"response_mismatch","message":"レスポンスがAPI仕様に一致しません"}`,
+		},
+		"OK:JSON Merge Patch（nullを含む）": {
+			strict:      true,
+			method:      http.MethodPatch,
+			target:      "/v1/shelf/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058/1",
+			contentType: "application/merge-patch+json",
+			body:        `{"bookStatus":"read","price":null}`,
+			response:    map[string]string{"id": "1", "bookStatus": "read", "version": "2"},
+			statusWant:  http.StatusOK,
+		},
+		"NG:JSON Merge Patchで変更できない項目（strict）": {
+			strict:      true,
+			method:      http.MethodPatch,
+			target:      "/v1/shelf/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058/1",
+			contentType: "application/merge-patch+json",
+			body:        `{"authUserId":"2b0a5f5e-3f4c-4a8e-9a39-0c2d3a6b1f7e"}`,
+			statusWant:  http.StatusBadRequest,
+		},
+		"OK:必須のクエリがなくてもログのみ": {
+			strict:     false,
+			method:     http.MethodGet,
+			target:     "/v1/search",
+			response:   []any{},
+			statusWant: http.StatusOK,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+			e.Use(oapi.ValidatorWithConfig(oapi.Config{
+				Strict: tt.strict,
+				Specs: []oapi.Spec{
+					{BaseURL: "/v1", Load: apigen.GetSwagger},
+					{BaseURL: "/v2", Load: apigenv2.GetSwagger},
+				},
+			}))
+			h := func(c echo.Context) error {
+				if tt.response == nil {
+					return c.NoContent(http.StatusOK)
+				}
+				return c.JSON(http.StatusOK, tt.response)
+			}
+			e.GET("/v1/records/:authUserId", h)
+			e.GET("/v1/search", h)
+			e.PUT("/v1/admin/rates", h)
+			e.GET("/v2/records/:authUserId", h)
+			e.PATCH("/v1/shelf/:authUserId/:bookId", h)
+
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType == "" {
+				tt.contentType = echo.MIMEApplicationJSON
+			}
+			r.Header.Set(echo.HeaderContentType, tt.contentType)
+			w := httptest.NewRecorder()
+
+			//Act
+			e.ServeHTTP(w, r)
+
+			//Assert
+			assert.Equal(t, tt.statusWant, w.Code)
+			if tt.bodyWant != "" {
+				assert.JSONEq(t, tt.bodyWant, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/problem"
+)
+
+// レート制限の状態を返すレスポンスヘッダー（IETFのRateLimitヘッダーの草案）
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// リクエスト数の保存先（controller.RateLimiter）
+type Store interface {
+	Allow(ctx context.Context, key string, rl domain.RateLimit) (*domain.RateLimitResult, error)
+}
+
+// リクエストを数える対象（例."user:..."）を返す。空の場合は数えない
+type KeyFunc func(c echo.Context) string
+
+// パスのauthUserIdのユーザーごとに数える
+func ByUser(c echo.Context) string {
+	if id := c.Param("authUserId"); id != "" {
+		return "user:" + id
+	}
+	return ""
+}
+
+// 個人用APIキー（domain.APIKeyTokenPrefixで始まるキー）ごとに数える（キー自体は保存しないため、ハッシュの先頭を使う）。
+// アプリのキーはすべてのユーザーで共有するため数えない（ユーザーごとの方針で数える）。
+func ByAPIKey(c echo.Context) string {
+	key, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !ok || !domain.IsAPIKeyToken(key) {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "key:" + hex.EncodeToString(sum[:8])
+}
+
+// IPアドレスごとに数える
+func ByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// 先頭から順に、空でない対象を返すKeyFuncを返す（例.ユーザーが分からない場合はIPアドレス）
+func FirstOf(fns ...KeyFunc) KeyFunc {
+	return func(c echo.Context) string {
+		for _, fn := range fns {
+			if key := fn(c); key != "" {
+				return key
+			}
+		}
+		return ""
+	}
+}
+
+// 方針。Skipperでスキップしないリクエストを、Keyの対象ごとにRateLimitの上限まで受け付ける。
+// Nameは保存するキーの接頭辞で、方針ごとに別に数える。
+type Policy struct {
+	Name      string
+	Skipper   middleware.Skipper
+	Key       KeyFunc
+	RateLimit domain.RateLimit
+}
+
+type Config struct {
+	Skipper middleware.Skipper
+
+	Store Store
+
+	// 該当するすべての方針の上限以内の場合のみ受け付ける
+	Policies []Policy
+
+	// 保存先のエラーの出力先。nilの場合はslog.Default()
+	Logger *slog.Logger
+}
+
+// 方針ごとにリクエストを数え、上限を超えた場合は429（Retry-After付き）を返すmiddlewareを返す。
+// レスポンスにはRateLimit-*ヘッダーで、残りの件数が最も少ない方針の状態を付ける。
+// 保存先のエラーはログに出力し、リクエストは受け付ける（DBの障害ですべてのリクエストを拒否しない）。
+func WithConfig(cfg Config) echo.MiddlewareFunc {
+	if cfg.Skipper == nil {
+		cfg.Skipper = middleware.DefaultSkipper
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.Store == nil {
+		panic("ratelimit: Storeの指定が必要です")
+	}
+	for i := range cfg.Policies {
+		if cfg.Policies[i].Skipper == nil {
+			cfg.Policies[i].Skipper = middleware.DefaultSkipper
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper(c) {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			var tightest *domain.RateLimitResult
+			var denied *domain.RateLimitResult
+			for _, p := range cfg.Policies {
+				if p.Skipper(c) {
+					continue
+				}
+				key := p.Key(c)
+				if key == "" {
+					continue
+				}
+				res, err := cfg.Store.Allow(ctx, p.Name+":"+key, p.RateLimit)
+				if err != nil {
+					cfg.Logger.Error(err.Error())
+					continue
+				}
+				if tightest == nil || res.Remaining() < tightest.Remaining() {
+					tightest = res
+				}
+				//複数の方針で超えた場合は、最も長く待つ方針を返す
+				if !res.Allowed() && (denied == nil || res.Reset > denied.Reset) {
+					denied = res
+				}
+			}
+			if denied != nil {
+				tightest = denied
+			}
+			if tightest == nil {
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set(HeaderRateLimitLimit, strconv.Itoa(tightest.Limit))
+			h.Set(HeaderRateLimitRemaining, strconv.Itoa(tightest.Remaining()))
+			h.Set(HeaderRateLimitReset, strconv.Itoa(tightest.ResetSeconds()))
+			h.Set(HeaderRateLimitPolicy, tightest.RateLimit.String())
+			if denied != nil {
+				h.Set(echo.HeaderRetryAfter, strconv.Itoa(denied.ResetSeconds()))
+				return echo.NewHTTPError(http.StatusTooManyRequests, problem.CodeTooManyRequests)
+			}
+			return next(c)
+		}
+	}
+}
+
+// routes（"メソッド echoのパス"）以外のリクエストをスキップするSkipperを返す
+func OnlyRoutes(routes ...string) middleware.Skipper {
+	m := routeSet(routes)
+	return func(c echo.Context) bool {
+		_, ok := m[c.Request().Method+" "+c.Path()]
+		return !ok
+	}
+}
+
+// routes（"メソッド echoのパス"）のリクエストをスキップするSkipperを返す
+func ExceptRoutes(routes ...string) middleware.Skipper {
+	m := routeSet(routes)
+	return func(c echo.Context) bool {
+		_, ok := m[c.Request().Method+" "+c.Path()]
+		return ok
+	}
+}
+
+func routeSet(routes []string) map[string]struct{} {
+	m := make(map[string]struct{}, len(routes))
+	for _, r := range routes {
+		m[r] = struct{}{}
+	}
+	return m
+}
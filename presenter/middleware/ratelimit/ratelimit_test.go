@@ -0,0 +1,160 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/middleware/ratelimit"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/utils"
+)
+
+// テスト用のメモリ上の保存先（controller.RateLimiterと同じ振る舞い）
+type memoryStore struct {
+	mu     sync.Mutex
+	counts map[string]int
+	err    error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{counts: make(map[string]int)}
+}
+
+func (s *memoryStore) Allow(ctx context.Context, key string, rl domain.RateLimit) (*domain.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.counts[key]++
+	now := time.Date(2024, 2, 5, 14, 43, 30, 0, utils.JST)
+	return domain.NewRateLimitResult(rl, s.counts[key], now), nil
+}
+
+// 検索（1分に1件）とそれ以外（1分に2件）の方針を設定したechoインスタンスを返す
+func setup(store ratelimit.Store) *echo.Echo {
+	search := []string{http.MethodGet + " /v1/search"}
+	byUser := ratelimit.FirstOf(ratelimit.ByUser, ratelimit.ByIP)
+
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+	e.Use(ratelimit.WithConfig(ratelimit.Config{
+		Store: store,
+		Policies: []ratelimit.Policy{
+			{Name: "default", Skipper: ratelimit.ExceptRoutes(search...), Key: byUser, RateLimit: domain.RateLimit{Limit: 2, Window: time.Minute}},
+			{Name: "search", Skipper: ratelimit.OnlyRoutes(search...), Key: byUser, RateLimit: domain.RateLimit{Limit: 1, Window: time.Minute}},
+			{Name: "api_key", Key: ratelimit.ByAPIKey, RateLimit: domain.RateLimit{Limit: 100, Window: time.Minute}},
+		},
+	}))
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/v1/users/:authUserId", ok)
+	e.GET("/v1/search", ok)
+	return e
+}
+
+func serve(e *echo.Echo, target string, ip string) *httptest.ResponseRecorder {
+	return serveWithKey(e, target, ip, "app-key")
+}
+
+func serveWithKey(e *echo.Echo, target string, ip string, key string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.Header.Set(echo.HeaderAuthorization, "Bearer "+key)
+	r.Header.Set(echo.HeaderXRealIP, ip)
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+	return w
+}
+
+func TestRateLimitByUser(t *testing.T) {
+	//Arrange
+	store := newMemoryStore()
+	e := setup(store)
+	a := assert.New(t)
+
+	//Act
+	first := serve(e, "/v1/users/user1", "192.0.2.1")
+	second := serve(e, "/v1/users/user1", "192.0.2.1")
+	limited := serve(e, "/v1/users/user1", "192.0.2.1")
+	otherUser := serve(e, "/v1/users/user2", "192.0.2.1") //同じIPアドレス（NAT）の他のユーザーは別に数える
+
+	//Assert
+	a.Equal(http.StatusOK, first.Code)
+	a.Equal("2", first.Header().Get(ratelimit.HeaderRateLimitLimit))
+	a.Equal("1", first.Header().Get(ratelimit.HeaderRateLimitRemaining))
+	a.Equal("30", first.Header().Get(ratelimit.HeaderRateLimitReset))
+	a.Equal("2;w=60", first.Header().Get(ratelimit.HeaderRateLimitPolicy))
+	a.Equal(http.StatusOK, second.Code)
+	a.Equal("0", second.Header().Get(ratelimit.HeaderRateLimitRemaining))
+	a.Equal(http.StatusTooManyRequests, limited.Code)
+	a.Equal("30", limited.Header().Get(echo.HeaderRetryAfter))
+	a.Contains(limited.Body.String(), string(problem.CodeTooManyRequests))
+	a.Equal(http.StatusOK, otherUser.Code)
+	for key := range store.counts {
+		a.NotContains(key, "api_key:") //アプリのキーはすべてのユーザーで共有するため、キーごとには数えない
+	}
+}
+
+func TestRateLimitByAPIKey(t *testing.T) {
+	//Arrange
+	store := newMemoryStore()
+	e := setup(store)
+	personal := domain.APIKeyTokenPrefix + "personal"
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(echo.HeaderAuthorization, "Bearer "+personal)
+	apiKey := ratelimit.ByAPIKey(e.NewContext(r, nil))
+	app := httptest.NewRequest(http.MethodGet, "/", nil)
+	app.Header.Set(echo.HeaderAuthorization, "Bearer app-key")
+	a := assert.New(t)
+
+	//Act
+	serveWithKey(e, "/v1/users/user1", "192.0.2.1", personal)
+	serveWithKey(e, "/v1/search?q=go", "192.0.2.1", personal)
+
+	//Assert
+	a.NotEmpty(apiKey)
+	a.Empty(ratelimit.ByAPIKey(e.NewContext(app, nil)))
+	a.Equal(2, store.counts["api_key:"+apiKey]) //個人用APIキーはすべてのリクエストを数える
+}
+
+func TestRateLimitSearch(t *testing.T) {
+	//Arrange
+	e := setup(newMemoryStore())
+	a := assert.New(t)
+
+	//Act
+	first := serve(e, "/v1/search?q=go", "192.0.2.1")
+	limited := serve(e, "/v1/search?q=go", "192.0.2.1")
+	otherIP := serve(e, "/v1/search?q=go", "192.0.2.2")
+	user := serve(e, "/v1/users/user1", "192.0.2.1") //検索の上限は他のルートに影響しない
+
+	//Assert
+	a.Equal(http.StatusOK, first.Code)
+	a.Equal("1", first.Header().Get(ratelimit.HeaderRateLimitLimit))
+	a.Equal(http.StatusTooManyRequests, limited.Code)
+	a.Equal(http.StatusOK, otherIP.Code)
+	a.Equal(http.StatusOK, user.Code)
+	a.Equal("2", user.Header().Get(ratelimit.HeaderRateLimitLimit))
+}
+
+func TestRateLimitStoreError(t *testing.T) {
+	//Arrange
+	store := newMemoryStore()
+	store.err = errors.New("connection refused")
+	e := setup(store)
+
+	//Act
+	w := serve(e, "/v1/users/user1", "192.0.2.1")
+
+	//Assert
+	assert.Equal(t, http.StatusOK, w.Code) //保存先の障害ではリクエストを拒否しない
+	assert.Empty(t, w.Header().Get(ratelimit.HeaderRateLimitLimit))
+}
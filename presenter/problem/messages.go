@@ -0,0 +1,323 @@
+package problem
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// 機械可読なエラーコード。クライアントはメッセージではなくこの値で分岐する。
+// 一度公開した値は変更しないこと。
+type Code string
+
+const (
+	// リクエスト全般
+	CodeBadRequest           Code = "bad_request"
+	CodeInvalidBody          Code = "invalid_body"
+	CodeInvalidPatch         Code = "invalid_patch"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeValidationFailed     Code = "validation_failed"
+	CodeSpecMismatch         Code = "spec_mismatch"
+	CodeMissingBookId        Code = "missing_book_id"
+	CodeMissingGoalId        Code = "missing_goal_id"
+	CodeMissingQuery         Code = "missing_query"
+	CodeInvalidLastEventId   Code = "invalid_last_event_id"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodeAlreadyExists        Code = "already_exists"
+	CodePreconditionFailed   Code = "precondition_failed"
+	CodeTooManyRequests      Code = "too_many_requests"
+	CodeInternal             Code = "internal_error"
+	CodeResponseMismatch     Code = "response_mismatch"
+
+	// 冪等キー
+	CodeInvalidIdempotencyKey    Code = "invalid_idempotency_key"
+	CodeIdempotencyKeyMismatch   Code = "idempotency_key_mismatch"
+	CodeIdempotencyKeyInProgress Code = "idempotency_key_in_progress"
+
+	// ユーザー
+	CodeInvalidUser        Code = "invalid_user"
+	CodeUserIdMismatch     Code = "user_id_mismatch"
+	CodeUserAlreadyExists  Code = "user_already_exists"
+	CodeUserNotFound       Code = "user_not_found"
+	CodeUserRegisterFailed Code = "user_register_failed"
+	CodeUserGetFailed      Code = "user_get_failed"
+	CodeUserUpdateFailed   Code = "user_update_failed"
+	CodeUserDeleteFailed   Code = "user_delete_failed"
+	CodeUserRestoreFailed  Code = "user_restore_failed"
+	CodeExportFailed       Code = "export_failed"
+
+	// 本棚、本
+	CodeInvalidBook       Code = "invalid_book"
+	CodeBookNotFound      Code = "book_not_found"
+	CodeShelfNotFound     Code = "shelf_not_found"
+	CodeShelfGetFailed    Code = "shelf_get_failed"
+	CodeBookGetFailed     Code = "book_get_failed"
+	CodeBookCreateFailed  Code = "book_create_failed"
+	CodeBookUpdateFailed  Code = "book_update_failed"
+	CodeBookDeleteFailed  Code = "book_delete_failed"
+	CodeBookRestoreFailed Code = "book_restore_failed"
+	CodeSearchFailed      Code = "search_failed"
+	CodeBatchFailed       Code = "batch_failed"
+	CodeBatchAborted      Code = "batch_aborted"
+	CodeEventGetFailed    Code = "event_get_failed"
+	CodeRevisionNotFound  Code = "revision_not_found"
+	CodeHistoryGetFailed  Code = "history_get_failed"
+
+	// 記録、図表
+	CodeRecordNotFound  Code = "record_not_found"
+	CodeRecordGetFailed Code = "record_get_failed"
+	CodeChartNotFound   Code = "chart_not_found"
+	CodeChartGetFailed  Code = "chart_get_failed"
+
+	// 為替レート
+	CodeInvalidRate      Code = "invalid_rate"
+	CodeRateGetFailed    Code = "rate_get_failed"
+	CodeRateUpdateFailed Code = "rate_update_failed"
+
+	// 目標、積読
+	CodeInvalidGoal      Code = "invalid_goal"
+	CodeGoalNotFound     Code = "goal_not_found"
+	CodeGoalGetFailed    Code = "goal_get_failed"
+	CodeGoalSetFailed    Code = "goal_set_failed"
+	CodeGoalDeleteFailed Code = "goal_delete_failed"
+	CodeBacklogGetFailed Code = "backlog_get_failed"
+
+	// ゴミ箱
+	CodeTrashBookNotFound Code = "trash_book_not_found"
+	CodeTrashUserNotFound Code = "trash_user_not_found"
+	CodeTrashGetFailed    Code = "trash_get_failed"
+
+	// Webhook
+	CodeInvalidWebhook        Code = "invalid_webhook"
+	CodeWebhookNotFound       Code = "webhook_not_found"
+	CodeWebhookGetFailed      Code = "webhook_get_failed"
+	CodeWebhookCreateFailed   Code = "webhook_create_failed"
+	CodeWebhookDeleteFailed   Code = "webhook_delete_failed"
+	CodeDeadLetterNotFound    Code = "dead_letter_not_found"
+	CodeDeadLetterGetFailed   Code = "dead_letter_get_failed"
+	CodeDeadLetterRetryFailed Code = "dead_letter_retry_failed"
+
+	// メール
+	CodeInvalidMailSetting      Code = "invalid_mail_setting"
+	CodeInvalidUnsubscribeToken Code = "invalid_unsubscribe_token"
+	CodeMailSettingGetFailed    Code = "mail_setting_get_failed"
+	CodeMailSettingUpdateFailed Code = "mail_setting_update_failed"
+	CodeUnsubscribeFailed       Code = "unsubscribe_failed"
+
+	// パスワード再設定、メールアドレス確認
+	CodeInvalidToken            Code = "invalid_token"
+	CodeEmailAlreadyVerified    Code = "email_already_verified"
+	CodePasswordResetFailed     Code = "password_reset_failed"
+	CodeEmailVerificationFailed Code = "email_verification_failed"
+
+	// ログイン、認証の失敗によるロック
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeAuthLocked         Code = "auth_locked"
+	CodeLoginFailed        Code = "login_failed"
+
+	// 個人用APIキー
+	CodeInvalidAPIKey      Code = "invalid_api_key"
+	CodeAPIKeyLimit        Code = "api_key_limit"
+	CodeAPIKeyNotFound     Code = "api_key_not_found"
+	CodeAPIKeyForbidden    Code = "api_key_forbidden"
+	CodeAPIKeyGetFailed    Code = "api_key_get_failed"
+	CodeAPIKeyCreateFailed Code = "api_key_create_failed"
+	CodeAPIKeyDeleteFailed Code = "api_key_delete_failed"
+	CodeAdminScope         Code = "admin_scope"
+
+	// 管理API
+	CodeAdminRequired     Code = "admin_required"
+	CodeAccountDisabled   Code = "account_disabled"
+	CodeCannotDisableSelf Code = "cannot_disable_self"
+	CodeUserSearchFailed  Code = "user_search_failed"
+	CodeStatsGetFailed    Code = "stats_get_failed"
+	CodeUserDisableFailed Code = "user_disable_failed"
+	CodeUserEnableFailed  Code = "user_enable_failed"
+	CodeRevokeFailed      Code = "revoke_failed"
+
+	// 監査ログ
+	CodeInvalidAuditQuery Code = "invalid_audit_query"
+	CodeAuditGetFailed    Code = "audit_get_failed"
+
+	// 監視
+	CodeDBUnavailable Code = "db_unavailable"
+)
+
+// 言語ごとのメッセージ
+type message struct {
+	ja string
+	en string
+}
+
+func (m message) in(lang language.Tag) string {
+	if lang == language.English {
+		return m.en
+	}
+	return m.ja
+}
+
+var messages = map[Code]message{
+	CodeBadRequest:           {"不正なリクエストです", "The request is invalid."},
+	CodeInvalidBody:          {"リクエストボディの読み込みに失敗", "Failed to read the request body."},
+	CodeInvalidPatch:         {"部分更新の内容に誤りがあります", "The merge patch is invalid."},
+	CodeUnsupportedMediaType: {"Content-Typeに対応していません", "The Content-Type is not supported."},
+	CodeValidationFailed:     {"入力内容に誤りがあります", "Some fields are invalid."},
+	CodeSpecMismatch:         {"リクエストがAPI仕様に一致しません", "The request does not match the API specification."},
+	CodeMissingBookId:        {"bookIdが必要です", "bookId is required."},
+	CodeMissingGoalId:        {"goalIdが必要です", "goalId is required."},
+	CodeMissingQuery:         {"検索文字を入力ください", "Enter a search query."},
+	CodeInvalidLastEventId:   {"Last-Event-IDは数値で指定ください", "Last-Event-ID must be a number."},
+	CodeUnauthorized:         {"認証に失敗しました", "Authentication failed."},
+	CodeForbidden:            {"操作が許可されていません", "The operation is not allowed."},
+	CodeNotFound:             {"対象がありません", "The resource was not found."},
+	CodeMethodNotAllowed:     {"許可されていないメソッドです", "The method is not allowed."},
+	CodeAlreadyExists:        {"すでに存在します", "The resource already exists."},
+	CodePreconditionFailed:   {"他の更新と競合しました。最新の内容を取得し直してください", "The resource was modified by another request. Fetch the latest version and retry."},
+	CodeTooManyRequests:      {"リクエストが多すぎます", "Too many requests."},
+	CodeInternal:             {"サーバーでエラーが発生しました", "An internal server error occurred."},
+	CodeResponseMismatch:     {"レスポンスがAPI仕様に一致しません", "The response does not match the API specification."},
+
+	CodeInvalidIdempotencyKey:    {"Idempotency-Keyは255文字以内で指定ください", "Idempotency-Key must be at most 255 characters."},
+	CodeIdempotencyKeyMismatch:   {"同じIdempotency-Keyで異なるリクエストが送信されました", "The Idempotency-Key was reused with a different request."},
+	CodeIdempotencyKeyInProgress: {"同じIdempotency-Keyのリクエストを処理中です", "A request with the same Idempotency-Key is in progress."},
+
+	CodeInvalidUser:        {"不正なユーザーです", "The user is invalid."},
+	CodeUserIdMismatch:     {"authUserIdがパスと一致しません", "authUserId does not match the path."},
+	CodeUserAlreadyExists:  {"すでにユーザーが存在します", "The user already exists."},
+	CodeUserNotFound:       {"ユーザーがありません", "The user was not found."},
+	CodeUserRegisterFailed: {"ユーザー登録に失敗", "Failed to register the user."},
+	CodeUserGetFailed:      {"ユーザーの取得に失敗", "Failed to get the user."},
+	CodeUserUpdateFailed:   {"ユーザーの更新に失敗", "Failed to update the user."},
+	CodeUserDeleteFailed:   {"ユーザーの削除に失敗", "Failed to delete the user."},
+	CodeUserRestoreFailed:  {"ユーザーの復元に失敗", "Failed to restore the user."},
+	CodeExportFailed:       {"エクスポートの作成に失敗", "Failed to create the export."},
+
+	CodeInvalidBook:       {"不正な本です", "The book is invalid."},
+	CodeBookNotFound:      {"本がありません", "The book was not found."},
+	CodeShelfNotFound:     {"本棚がありません", "The shelf was not found."},
+	CodeShelfGetFailed:    {"本棚の取得に失敗", "Failed to get the shelf."},
+	CodeBookGetFailed:     {"本の取得に失敗", "Failed to get the book."},
+	CodeBookCreateFailed:  {"本の作成に失敗", "Failed to create the book."},
+	CodeBookUpdateFailed:  {"本の更新に失敗", "Failed to update the book."},
+	CodeBookDeleteFailed:  {"本の削除に失敗", "Failed to delete the books."},
+	CodeBookRestoreFailed: {"本の復元に失敗", "Failed to restore the books."},
+	CodeSearchFailed:      {"書籍の検索に失敗", "Failed to search for books."},
+	CodeBatchFailed:       {"一括操作に失敗", "Failed to run the batch."},
+	CodeBatchAborted:      {"他の操作が失敗したため取り消しました", "Rolled back because another operation in the batch failed."},
+	CodeEventGetFailed:    {"イベントの取得に失敗", "Failed to get the events."},
+	CodeRevisionNotFound:  {"本の変更履歴の版がありません", "The revision of the book was not found."},
+	CodeHistoryGetFailed:  {"本の変更履歴の取得に失敗", "Failed to get the history of the book."},
+
+	CodeRecordNotFound:  {"記録がありません", "The record was not found."},
+	CodeRecordGetFailed: {"記録の取得に失敗", "Failed to get the record."},
+	CodeChartNotFound:   {"図表がありません", "The charts were not found."},
+	CodeChartGetFailed:  {"図表の取得に失敗", "Failed to get the charts."},
+
+	CodeInvalidRate:      {"不正な為替レートです", "The exchange rate is invalid."},
+	CodeRateGetFailed:    {"為替レートの取得に失敗", "Failed to get the exchange rates."},
+	CodeRateUpdateFailed: {"為替レートの更新に失敗", "Failed to update the exchange rates."},
+
+	CodeInvalidGoal:      {"不正な目標です", "The goal is invalid."},
+	CodeGoalNotFound:     {"目標がありません", "The goal was not found."},
+	CodeGoalGetFailed:    {"目標の取得に失敗", "Failed to get the goals."},
+	CodeGoalSetFailed:    {"目標の登録に失敗", "Failed to set the goal."},
+	CodeGoalDeleteFailed: {"目標の削除に失敗", "Failed to delete the goal."},
+	CodeBacklogGetFailed: {"積読の取得に失敗", "Failed to get the backlog."},
+
+	CodeTrashBookNotFound: {"ゴミ箱に本がありません", "The books were not found in the trash."},
+	CodeTrashUserNotFound: {"ゴミ箱にユーザーがありません", "The user was not found in the trash."},
+	CodeTrashGetFailed:    {"ゴミ箱の取得に失敗", "Failed to get the trash."},
+
+	CodeInvalidWebhook:        {"不正なWebhookです（urlは内部のアドレスでないhttp、https、eventsは対応するイベントの種類）", "The webhook is invalid. The url must be http or https to a public address and the events must be supported."},
+	CodeWebhookNotFound:       {"Webhookがありません", "The webhook was not found."},
+	CodeWebhookGetFailed:      {"Webhookの取得に失敗", "Failed to get the webhooks."},
+	CodeWebhookCreateFailed:   {"Webhookの登録に失敗", "Failed to register the webhook."},
+	CodeWebhookDeleteFailed:   {"Webhookの削除に失敗", "Failed to delete the webhook."},
+	CodeDeadLetterNotFound:    {"デッドレターがありません", "The dead letter was not found."},
+	CodeDeadLetterGetFailed:   {"デッドレターの取得に失敗", "Failed to get the dead letters."},
+	CodeDeadLetterRetryFailed: {"デッドレターの再試行に失敗", "Failed to retry the dead letter."},
+
+	CodeInvalidMailSetting:      {"不正なメールの設定です（languageはja、en）", "The mail setting is invalid. The language must be ja or en."},
+	CodeInvalidUnsubscribeToken: {"配信停止のトークンが不正です", "The unsubscribe token is invalid."},
+	CodeMailSettingGetFailed:    {"メールの設定の取得に失敗", "Failed to get the mail setting."},
+	CodeMailSettingUpdateFailed: {"メールの設定の更新に失敗", "Failed to update the mail setting."},
+	CodeUnsubscribeFailed:       {"配信停止に失敗", "Failed to unsubscribe."},
+
+	CodeInvalidToken:            {"トークンが不正、期限切れ、または使用済みです", "The token is invalid, expired or already used."},
+	CodeEmailAlreadyVerified:    {"メールアドレスは確認済みです", "The email address is already verified."},
+	CodePasswordResetFailed:     {"パスワードの再設定に失敗", "Failed to reset the password."},
+	CodeEmailVerificationFailed: {"メールアドレスの確認に失敗", "Failed to verify the email address."},
+
+	CodeInvalidCredentials: {"メールアドレスまたはパスワードが違います", "The email or password is incorrect."},
+	CodeAuthLocked:         {"認証の失敗が続いたため、一時的にロックしています（Retry-Afterの秒数の後に再試行ください）", "Too many failed attempts. Retry after the number of seconds in Retry-After."},
+	CodeLoginFailed:        {"ログインに失敗", "Failed to sign in."},
+
+	CodeInvalidAPIKey:      {"不正なAPIキーです（nameは必須、scopesはshelf:read、shelf:write、charts:read、expiresAtは未来の日時）", "The API key is invalid. The name is required, the scopes must be shelf:read, shelf:write or charts:read and expiresAt must be in the future."},
+	CodeAPIKeyLimit:        {"発行できるAPIキーの上限に達しています（不要なキーを削除してください）", "Too many API keys. Delete the keys you no longer use."},
+	CodeAPIKeyNotFound:     {"APIキーがありません", "The API key was not found."},
+	CodeAPIKeyForbidden:    {"APIキーにこの操作のスコープがない、または他のユーザーのデータです", "The API key does not have the scope for this operation or the data belongs to another user."},
+	CodeAPIKeyGetFailed:    {"APIキーの取得に失敗", "Failed to get the API keys."},
+	CodeAPIKeyCreateFailed: {"APIキーの発行に失敗", "Failed to create the API key."},
+	CodeAPIKeyDeleteFailed: {"APIキーの削除に失敗", "Failed to delete the API key."},
+	CodeAdminScope:         {"adminスコープのAPIキーは管理者のみ発行できます", "Only administrators can create API keys with the admin scope."},
+
+	CodeAdminRequired:     {"管理者のadminスコープのAPIキーが必要です", "An API key with the admin scope owned by an administrator is required."},
+	CodeAccountDisabled:   {"アカウントは無効化されています", "The account is disabled."},
+	CodeCannotDisableSelf: {"自分自身は無効化できません", "You cannot disable your own account."},
+	CodeUserSearchFailed:  {"ユーザーの検索に失敗", "Failed to search the users."},
+	CodeStatsGetFailed:    {"集計の取得に失敗", "Failed to get the statistics."},
+	CodeUserDisableFailed: {"ユーザーの無効化に失敗", "Failed to disable the user."},
+	CodeUserEnableFailed:  {"ユーザーの有効化に失敗", "Failed to enable the user."},
+	CodeRevokeFailed:      {"強制ログアウトに失敗", "Failed to revoke the tokens."},
+
+	CodeInvalidAuditQuery: {"監査ログの検索条件が不正です", "The audit log query is invalid."},
+	CodeAuditGetFailed:    {"監査ログの取得に失敗", "Failed to get the audit logs."},
+
+	CodeDBUnavailable: {"DBに異常があります", "The database is unavailable."},
+}
+
+// ステータスごとの概要（title）
+var titles = map[int]message{
+	http.StatusBadRequest:           {"不正なリクエスト", "Bad Request"},
+	http.StatusUnauthorized:         {"認証エラー", "Unauthorized"},
+	http.StatusForbidden:            {"権限エラー", "Forbidden"},
+	http.StatusNotFound:             {"対象なし", "Not Found"},
+	http.StatusMethodNotAllowed:     {"許可されていないメソッド", "Method Not Allowed"},
+	http.StatusConflict:             {"競合", "Conflict"},
+	http.StatusPreconditionFailed:   {"前提条件の不一致", "Precondition Failed"},
+	http.StatusUnsupportedMediaType: {"未対応のメディアタイプ", "Unsupported Media Type"},
+	http.StatusFailedDependency:     {"依存する操作の失敗", "Failed Dependency"},
+	http.StatusTooManyRequests:      {"リクエスト過多", "Too Many Requests"},
+	http.StatusInternalServerError:  {"サーバーエラー", "Internal Server Error"},
+}
+
+// ステータスのみ分かる場合（echoのルーティング、KeyAuthなど）のコード
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:           CodeBadRequest,
+	http.StatusUnauthorized:         CodeUnauthorized,
+	http.StatusForbidden:            CodeForbidden,
+	http.StatusNotFound:             CodeNotFound,
+	http.StatusMethodNotAllowed:     CodeMethodNotAllowed,
+	http.StatusConflict:             CodeAlreadyExists,
+	http.StatusPreconditionFailed:   CodePreconditionFailed,
+	http.StatusUnsupportedMediaType: CodeUnsupportedMediaType,
+	http.StatusTooManyRequests:      CodeTooManyRequests,
+	http.StatusInternalServerError:  CodeInternal,
+}
+
+// validatorのタグごとの項目のメッセージ。%sにはタグのパラメータ（例.oneofの候補）が入る。
+var fieldMessages = map[string]message{
+	"required": {"必須です", "is required"},
+	"email":    {"メールアドレスの形式ではありません", "must be a valid email address"},
+	"oneof":    {"%sのいずれかを指定してください", "must be one of %s"},
+	"gt":       {"%sより大きい値を指定してください", "must be greater than %s"},
+	"gte":      {"%s以上を指定してください", "must be greater than or equal to %s"},
+	"lt":       {"%sより小さい値を指定してください", "must be less than %s"},
+	"lte":      {"%s以下を指定してください", "must be less than or equal to %s"},
+	"schema":   {"型または形式がAPI仕様に一致しません", "does not match the type or format in the API specification"},
+}
+
+var fieldMessageDefault = message{"不正な値です", "is invalid"}
@@ -0,0 +1,274 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/domain"
+	"golang.org/x/text/language"
+)
+
+// RFC 7807のContent-Type
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// typeの接頭辞。コードごとに一意のURIになる（例."urn:bhapi:problem:user_not_found"）。
+const typePrefix = "urn:bhapi:problem:"
+
+// RFC 7807形式のエラーレスポンス
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     Code         `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+
+	// v1との互換のため、detailと同じ値を返す
+	Message string `json:"message,omitempty"`
+}
+
+// 項目ごとの入力エラー
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// 対応する言語（先頭が既定）
+var languages = []language.Tag{language.Japanese, language.English}
+
+var matcher = language.NewMatcher(languages)
+
+// Accept-Languageから返却するメッセージの言語を決める。対応していない場合は日本語。
+func Language(r *http.Request) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return language.Japanese
+	}
+	_, i, conf := matcher.Match(tags...)
+	if conf == language.No {
+		return language.Japanese
+	}
+	return languages[i]
+}
+
+// コードと言語からProblemを作成
+func New(status int, code Code, lang language.Tag) *Problem {
+	title, ok := titles[status]
+	if !ok {
+		title = message{http.StatusText(status), http.StatusText(status)}
+	}
+	detail := title.in(lang)
+	if m, ok := messages[code]; ok {
+		detail = m.in(lang)
+	}
+	return &Problem{
+		Type:    typePrefix + string(code),
+		Title:   title.in(lang),
+		Status:  status,
+		Detail:  detail,
+		Code:    code,
+		Message: detail,
+	}
+}
+
+// エラーをProblemに変換する（中央のエラーマッピング）。
+//   - echo.HTTPError: Messageがコードの場合はそのコード、それ以外はステータスから決める
+//   - validatorとAPI仕様の検証のエラー: 項目ごとのエラーを含める
+//   - domainのエラー: 種類（kinds）に対応するステータスとコード
+//   - それ以外: 500
+func FromError(err error, lang language.Tag) *Problem {
+	var p *Problem
+
+	var he *echo.HTTPError
+	switch {
+	case errors.As(err, &he):
+		code, ok := he.Message.(Code)
+		if !ok {
+			code = statusCode(he.Code)
+		}
+		p = New(he.Code, code, lang)
+	case errors.Is(err, domain.ErrInvalidGoal):
+		p = New(http.StatusBadRequest, CodeInvalidGoal, lang)
+	default:
+		p = New(Status(err), kindCode(err), lang)
+	}
+
+	p.Errors = fieldErrors(err, lang)
+	return p
+}
+
+// エラーの種類ごとのステータスとコード（エラーの種類とHTTPステータスの唯一の対応表）
+var kinds = []struct {
+	kind   error
+	status int
+	code   Code
+}{
+	{domain.ErrValidation, http.StatusBadRequest, CodeValidationFailed},
+	{domain.ErrUnauthorized, http.StatusUnauthorized, CodeUnauthorized},
+	{domain.ErrForbidden, http.StatusForbidden, CodeForbidden},
+	{domain.ErrNotFound, http.StatusNotFound, CodeNotFound},
+	{domain.ErrConflict, http.StatusConflict, CodeAlreadyExists},
+	{domain.ErrPreconditionFailed, http.StatusPreconditionFailed, CodePreconditionFailed},
+	{domain.ErrTooManyRequests, http.StatusTooManyRequests, CodeTooManyRequests},
+}
+
+// エラーの種類からHTTPステータスを返す。種類のないエラーは500。
+func Status(err error) int {
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		return http.StatusBadRequest
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.kind) {
+			return k.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// エラーの種類に対応する汎用のコードを返す。種類のないエラーはCodeInternal。
+func kindCode(err error) Code {
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		return CodeValidationFailed
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.kind) {
+			return k.code
+		}
+	}
+	return CodeInternal
+}
+
+// エラーの種類ごとのコード（例.domain.ErrNotFoundにCodeUserNotFound）
+type Codes map[error]Code
+
+// ハンドラの返すエラーを作成する。ステータスはStatusでエラーの種類から決める。
+// コードはcodesのうちエラーの種類に一致するもの、一致しない場合はcodeを使う。
+// 元のエラーはInternalに保持する（ログ出力用）。
+func Wrap(err error, code Code, codes Codes) *echo.HTTPError {
+	for kind, c := range codes {
+		if errors.Is(err, kind) {
+			code = c
+			break
+		}
+	}
+	return echo.NewHTTPError(Status(err), code).SetInternal(err)
+}
+
+// ステータスに対応する汎用のコードを返す
+func statusCode(status int) Code {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// validator、API仕様の検証のエラーから項目ごとのエラーを取り出す
+func fieldErrors(err error, lang language.Tag) []FieldError {
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		fes := make([]FieldError, len(ve))
+		for i, fe := range ve {
+			fes[i] = FieldError{
+				Field:   fe.Field(),
+				Code:    fe.Tag(),
+				Message: fieldMessage(fe.Tag(), fe.Param(), lang),
+			}
+		}
+		return fes
+	}
+
+	var re *openapi3filter.RequestError
+	if errors.As(err, &re) {
+		field := ""
+		if re.Parameter != nil {
+			field = re.Parameter.Name
+		}
+		var se *openapi3.SchemaError
+		if errors.As(err, &se) {
+			if ptr := se.JSONPointer(); len(ptr) > 0 {
+				field = strings.Join(ptr, ".")
+			}
+			//必須の項目がない場合、SchemaFieldは"required"になる
+			if se.SchemaField == "required" {
+				return []FieldError{{Field: field, Code: "required", Message: fieldMessage("required", "", lang)}}
+			}
+		}
+		if field == "" {
+			return nil
+		}
+		if errors.Is(err, openapi3filter.ErrInvalidRequired) {
+			return []FieldError{{Field: field, Code: "required", Message: fieldMessage("required", "", lang)}}
+		}
+		return []FieldError{{Field: field, Code: "schema", Message: fieldMessage("schema", "", lang)}}
+	}
+
+	return nil
+}
+
+func fieldMessage(tag string, param string, lang language.Tag) string {
+	m, ok := fieldMessages[tag]
+	if !ok {
+		return fieldMessageDefault.in(lang)
+	}
+	msg := m.in(lang)
+	if strings.Contains(msg, "%s") {
+		return fmt.Sprintf(msg, param)
+	}
+	return msg
+}
+
+// Problemをapplication/problem+jsonで書き出す
+func Write(c echo.Context, p *Problem) error {
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(p.Status)
+	}
+	return c.JSON(p.Status, p)
+}
+
+// すべてのエラーをapplication/problem+jsonで返すecho.HTTPErrorHandlerを返す。
+// 500以上のエラーは原因をloggerに出力する（nilの場合はslog.Default()）。
+func NewErrorHandler(logger *slog.Logger) echo.HTTPErrorHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		p := FromError(err, Language(c.Request()))
+		p.Instance = c.Request().URL.Path
+		var le *domain.LockedError
+		if errors.As(err, &le) {
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(le.Seconds()))
+		}
+		if p.Status >= http.StatusInternalServerError {
+			logger.LogAttrs(c.Request().Context(), slog.LevelError, "INTERNAL_ERROR",
+				slog.String("method", c.Request().Method),
+				slog.String("path", c.Path()),
+				slog.String("code", string(p.Code)),
+				slog.String("err", err.Error()),
+			)
+		}
+
+		if werr := Write(c, p); werr != nil {
+			logger.Error(werr.Error())
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package problem_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/problem"
+	"github.com/taimats/bhapi/utils"
+	"golang.org/x/text/language"
+)
+
+func TestLanguage(t *testing.T) {
+	//Arrange
+	tests := map[string]struct {
+		acceptLanguage string
+		want           language.Tag
+	}{
+		"指定なし":      {acceptLanguage: "", want: language.Japanese},
+		"日本語":       {acceptLanguage: "ja-JP,ja;q=0.9", want: language.Japanese},
+		"英語":        {acceptLanguage: "en-US,en;q=0.9,ja;q=0.8", want: language.English},
+		"英語の優先度が低い": {acceptLanguage: "ja;q=0.9,en;q=0.5", want: language.Japanese},
+		"未対応の言語":    {acceptLanguage: "fr-FR", want: language.Japanese},
+		"不正な値":      {acceptLanguage: ";;;", want: language.Japanese},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			//Act
+			got := problem.Language(r)
+
+			//Assert
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	//Arrange
+	tests := map[string]struct {
+		err        error
+		lang       language.Tag
+		statusWant int
+		codeWant   problem.Code
+		detailWant string
+	}{
+		"コード付きのHTTPError": {
+			err:        echo.NewHTTPError(http.StatusNotFound, problem.CodeUserNotFound),
+			lang:       language.Japanese,
+			statusWant: http.StatusNotFound,
+			codeWant:   problem.CodeUserNotFound,
+			detailWant: "ユーザーがありません",
+		},
+		"コード付きのHTTPError（英語）": {
+			err:        echo.NewHTTPError(http.StatusNotFound, problem.CodeUserNotFound),
+			lang:       language.English,
+			statusWant: http.StatusNotFound,
+			codeWant:   problem.CodeUserNotFound,
+			detailWant: "The user was not found.",
+		},
+		"echoのHTTPError（ルートなし）": {
+			err:        echo.ErrNotFound,
+			lang:       language.Japanese,
+			statusWant: http.StatusNotFound,
+			codeWant:   problem.CodeNotFound,
+			detailWant: "対象がありません",
+		},
+		"echoのHTTPError（レート制限）": {
+			err:        echo.ErrTooManyRequests,
+			lang:       language.English,
+			statusWant: http.StatusTooManyRequests,
+			codeWant:   problem.CodeTooManyRequests,
+			detailWant: "Too many requests.",
+		},
+		"domain.ErrNotFound": {
+			err:        utils.NewErrChains(domain.ErrNotFound, nil),
+			lang:       language.Japanese,
+			statusWant: http.StatusNotFound,
+			codeWant:   problem.CodeNotFound,
+			detailWant: "対象がありません",
+		},
+		"domain.ErrConflict": {
+			err:        fmt.Errorf("登録に失敗:%w", domain.ErrConflict),
+			lang:       language.Japanese,
+			statusWant: http.StatusConflict,
+			codeWant:   problem.CodeAlreadyExists,
+			detailWant: "すでに存在します",
+		},
+		"domain.ErrForbidden": {
+			err:        domain.ErrForbidden,
+			lang:       language.English,
+			statusWant: http.StatusForbidden,
+			codeWant:   problem.CodeForbidden,
+			detailWant: "The operation is not allowed.",
+		},
+		"domain.ErrPreconditionFailed": {
+			err:        utils.NewErrChains(domain.ErrPreconditionFailed, nil),
+			lang:       language.Japanese,
+			statusWant: http.StatusPreconditionFailed,
+			codeWant:   problem.CodePreconditionFailed,
+			detailWant: "他の更新と競合しました。最新の内容を取得し直してください",
+		},
+		"domain.ErrValidationの種類のエラー": {
+			err:        fmt.Errorf("priceの変換に失敗:%w", domain.ErrInvalidPrice),
+			lang:       language.Japanese,
+			statusWant: http.StatusBadRequest,
+			codeWant:   problem.CodeValidationFailed,
+			detailWant: "入力内容に誤りがあります",
+		},
+		"不明なエラー": {
+			err:        errors.New("connection refused"),
+			lang:       language.English,
+			statusWant: http.StatusInternalServerError,
+			codeWant:   problem.CodeInternal,
+			detailWant: "An internal server error occurred.",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act
+			got := problem.FromError(tt.err, tt.lang)
+
+			//Assert
+			assert.Equal(t, tt.statusWant, got.Status)
+			assert.Equal(t, tt.codeWant, got.Code)
+			assert.Equal(t, "urn:bhapi:problem:"+string(tt.codeWant), got.Type)
+			assert.Equal(t, tt.detailWant, got.Detail)
+			assert.Empty(t, got.Errors)
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	//Arrange
+	codes := problem.Codes{domain.ErrNotFound: problem.CodeUserNotFound}
+
+	tests := map[string]struct {
+		err        error
+		statusWant int
+		codeWant   problem.Code
+	}{
+		"種類に一致するコード": {
+			err:        utils.NewErrChains(domain.ErrNotFound, errors.New("sql: no rows in result set")),
+			statusWant: http.StatusNotFound,
+			codeWant:   problem.CodeUserNotFound,
+		},
+		"種類に一致するコードなし": {
+			err:        utils.NewErrChains(domain.ErrConflict, nil),
+			statusWant: http.StatusConflict,
+			codeWant:   problem.CodeUserGetFailed,
+		},
+		"種類のないエラー": {
+			err:        errors.New("connection refused"),
+			statusWant: http.StatusInternalServerError,
+			codeWant:   problem.CodeUserGetFailed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act
+			got := problem.Wrap(tt.err, problem.CodeUserGetFailed, codes)
+
+			//Assert
+			assert.Equal(t, tt.statusWant, got.Code)
+			assert.Equal(t, tt.codeWant, got.Message)
+			assert.ErrorIs(t, got, tt.err)
+		})
+	}
+}
+
+func TestFromErrorWithValidation(t *testing.T) {
+	//Arrange
+	e := echo.New()
+	e.Validator = handler.NewCustomValidator(validator.New())
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	u := &handler.RegisterInfo{Email: "example", Password: "pass"}
+
+	tests := map[string]struct {
+		lang language.Tag
+		want []problem.FieldError
+	}{
+		"日本語": {
+			lang: language.Japanese,
+			want: []problem.FieldError{
+				{Field: "authUserId", Code: "required", Message: "必須です"},
+				{Field: "email", Code: "email", Message: "メールアドレスの形式ではありません"},
+				{Field: "password", Code: "gte", Message: "8以上を指定してください"},
+			},
+		},
+		"英語": {
+			lang: language.English,
+			want: []problem.FieldError{
+				{Field: "authUserId", Code: "required", Message: "is required"},
+				{Field: "email", Code: "email", Message: "must be a valid email address"},
+				{Field: "password", Code: "gte", Message: "must be greater than or equal to 8"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			//Act
+			got := problem.FromError(c.Validate(u), tt.lang)
+
+			//Assert
+			assert.Equal(t, http.StatusBadRequest, got.Status)
+			assert.Equal(t, problem.CodeValidationFailed, got.Code)
+			assert.Equal(t, tt.want, got.Errors)
+		})
+	}
+}
+
+func TestNewErrorHandler(t *testing.T) {
+	//Arrange
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+	e.GET("/v1/users/:authUserId", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, problem.CodeUserNotFound)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/users/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058", nil)
+	r.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+
+	//Act
+	e.ServeHTTP(w, r)
+
+	//Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, problem.MIMEApplicationProblemJSON, w.Header().Get(echo.HeaderContentType))
+	assert.JSONEq(t, `{
+		"type": "urn:bhapi:problem:user_not_found",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "The user was not found.",
+		"instance": "/v1/users/c0cc3f0c-9a02-45ba-9de7-7d7276bb6058",
+		"code": "user_not_found",
+		"message": "The user was not found."
+	}`, w.Body.String())
+}
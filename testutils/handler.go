@@ -5,38 +5,96 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/taimats/bhapi/apigen"
+	"github.com/taimats/bhapi/apigenv2"
 	"github.com/taimats/bhapi/controller"
+	"github.com/taimats/bhapi/domain"
+	"github.com/taimats/bhapi/infra/mailer"
 	"github.com/taimats/bhapi/infra/repository"
 	"github.com/taimats/bhapi/presenter/handler"
+	"github.com/taimats/bhapi/presenter/middleware/idempotency"
+	"github.com/taimats/bhapi/presenter/middleware/oapi"
+	"github.com/taimats/bhapi/presenter/problem"
 	"github.com/taimats/bhapi/utils"
 	"github.com/uptrace/bun"
 )
 
+// テスト用の配信停止、パスワード再設定などのトークンの署名の鍵
+const MailTokenSecret = "test-mail-token-secret"
+
 // テスト用のハンドラーとバリデーション登録済みのechoインスタンスを返す。
+// echoインスタンスにはルートと、Idempotency-Keyの処理、API仕様に一致しないリクエスト、レスポンスをエラーにする（strict）検証を登録済み。
+// メールは送信せずにログへ出力する。
 func SetupHandler(db *bun.DB) (*handler.Handler, *echo.Echo) {
+	return SetupHandlerWithMailer(db, &mailer.Log{})
+}
+
+// SetupHandlerと同じ。メールはmで送る（送ったメールを確認する場合はmailer.Fileを指定する）
+func SetupHandlerWithMailer(db *bun.DB, m mailer.Mailer) (*handler.Handler, *echo.Echo) {
 	//repositoryインスタンスの生成
 	cl := utils.NewTestClocker()
 	cr := repository.NewChart(db, cl)
 	sr := repository.NewShelf(db, cl)
 	ur := repository.NewUser(db, cl)
+	rr := repository.NewRate(db, cl)
+	gr := repository.NewGoal(db, cl)
+	ir := repository.NewIdempotency(db, cl)
+	er := repository.NewEvent(db, cl)
+	wr := repository.NewWebhook(db, cl)
+	jr := repository.NewJob(db, cl)
+	mr := repository.NewMail(db, cl)
+	tkr := repository.NewToken(db, cl)
+	lr := repository.NewLockout(db, cl)
+	kr := repository.NewAPIKey(db, cl)
+	adr := repository.NewAdmin(db, cl)
+	aur := repository.NewAudit(db, cl)
 
 	//controllerインスタンスの生成
-	cc := controller.NewChart(cr)
+	cc := controller.NewChart(cr, ur, rr)
 	sc := controller.NewShelf(sr)
 	uc := controller.NewUser(ur)
-	rc := controller.NewRecord(sr)
-	sbc := controller.NewSearchBooks()
+	rc := controller.NewRecord(sr, ur, rr)
+	sbc := controller.NewSearchBooks(os.Getenv("GOOGLE_BOOKS_API_URL"), "")
 	hc := controller.NewHealthDB(db)
+	rtc := controller.NewRate(rr)
+	gc := controller.NewGoal(gr, sr, ur, rr, er, cl)
+	bc := controller.NewBacklog(cr, sr, ur, rr, cl)
+	tc := controller.NewTrash(sr, ur, cl, domain.DefaultTrashRetention)
+	ic := controller.NewIdempotency(ir, cl, domain.DefaultIdempotencyTTL)
+	ec := controller.NewEvent(er, cl, domain.DefaultEventRetention)
+	wc := controller.NewWebhook(wr, cl, nil, domain.DefaultOutboxRetention)
+	jc := controller.NewJobs(jr, cl, 1, domain.DefaultJobVisibilityTimeout, domain.DefaultJobRetention)
+	mc := controller.NewMail(mr, ur, rc, cc, bc, jc, m, cl, MailTokenSecret, "", "")
+	lc := controller.NewLockout(lr, cl, domain.DefaultAuthFailureRetention)
+	kc := controller.NewAPIKey(kr, ur, cl)
+	adc := controller.NewAdmin(adr, ur)
+	auc := controller.NewAudit(aur)
+	ac := controller.NewAccount(ur, tkr, mr, lc, m, cl, MailTokenSecret, "https://example.com/password-reset", "https://example.com/verify-email")
 
 	e := echo.New()
 	e.Validator = handler.NewCustomValidator(validator.New())
+	e.HTTPErrorHandler = problem.NewErrorHandler(nil)
+	e.Use(idempotency.WithConfig(idempotency.Config{
+		Skipper: idempotency.RouteSkipper(handler.IdempotentRoutes...),
+		Store:   ic,
+	}))
+	e.Use(oapi.ValidatorWithConfig(oapi.Config{
+		Strict: true,
+		Specs: []oapi.Spec{
+			{BaseURL: handler.BaseURL, Load: apigen.GetSwagger},
+			{BaseURL: handler.BaseURLV2, Load: apigenv2.GetSwagger},
+		},
+	}))
 
 	//hanlderの設定
-	h := handler.NewHandler(uc, cc, rc, sc, sbc, hc)
+	h := handler.NewHandler(uc, cc, rc, sc, sbc, hc, rtc, gc, bc, tc, ec, wc, mc, ac, kc, adc, auc)
+	apigen.RegisterHandlersWithBaseURL(e, h, handler.BaseURL)
+	apigenv2.RegisterHandlersWithBaseURL(e, h.V2(), handler.BaseURLV2)
 
 	return h, e
 }
@@ -1,14 +1,6 @@
 package utils
 
-import (
-	"errors"
-	"fmt"
-)
-
-var (
-	ErrNotFound  = errors.New("リソースがありません")
-	ErrAlrExists = errors.New("リソースはすでに存在します")
-)
+import "fmt"
 
 // エラー元（＝ origin）を最新エラー（= errNow）でwrapする。
 // errNowには現時点で発生したerrorを入れる。originにはラップしたい元errorを入れる。